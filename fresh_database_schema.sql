@@ -7,6 +7,10 @@
 -- 1. DROP ALL EXISTING TABLES (CASCADE to handle dependencies)
 -- =====================================================
 
+DROP TABLE IF EXISTS signal_outbox CASCADE;
+DROP TABLE IF EXISTS rejected_signals CASCADE;
+DROP TABLE IF EXISTS watchlist_overrides CASCADE;
+DROP TABLE IF EXISTS strategy_profiles CASCADE;
 DROP TABLE IF EXISTS learning_data CASCADE;
 DROP TABLE IF EXISTS signal_performance CASCADE;
 DROP TABLE IF EXISTS notification_logs CASCADE;
@@ -34,14 +38,19 @@ CREATE TABLE cryptocurrencies (
     platform VARCHAR(50),
     coingecko_id VARCHAR(100),
     is_active BOOLEAN DEFAULT true,
+    consecutive_failures INTEGER DEFAULT 0, -- analysis cycles in a row without market data
+    price_precision SMALLINT, -- price decimals from the exchange tick size; NULL uses SIGNAL_PRICE_PRECISION
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW()
 );
 
+-- Deleting a cryptocurrency keeps its history: snapshots, signals and
+-- notification logs are detached (cryptocurrency_id set to NULL)
+
 -- Create market_snapshots table
 CREATE TABLE market_snapshots (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    cryptocurrency_id UUID NOT NULL REFERENCES cryptocurrencies(id),
+    cryptocurrency_id UUID REFERENCES cryptocurrencies(id) ON DELETE SET NULL,
     price DECIMAL(20,8) NOT NULL,
     volume_24h DECIMAL(20,2),
     market_cap DECIMAL(20,2),
@@ -66,7 +75,10 @@ CREATE TABLE market_snapshots (
 -- Create trading_signals table
 CREATE TABLE trading_signals (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    cryptocurrency_id UUID NOT NULL REFERENCES cryptocurrencies(id),
+    cryptocurrency_id UUID REFERENCES cryptocurrencies(id) ON DELETE SET NULL,
+    ref_code VARCHAR(32) UNIQUE,
+    source VARCHAR(20) NOT NULL DEFAULT 'internal',
+    priority VARCHAR(10) CHECK (priority IN ('low', 'medium', 'high')),
     action VARCHAR(10) NOT NULL CHECK (action IN ('BUY', 'SELL', 'HOLD')),
     confidence_score DECIMAL(3,2) NOT NULL CHECK (confidence_score >= 0 AND confidence_score <= 1),
     entry_price DECIMAL(20,8) NOT NULL,
@@ -90,6 +102,12 @@ CREATE TABLE trading_signals (
     fear_greed_index INTEGER,
     market_cap DECIMAL(20,2),
     market_conditions JSONB DEFAULT '{}',
+    context JSONB, -- recent candles and indicator series, when STORE_SIGNAL_CONTEXT is on
+    telegram_message_id BIGINT, -- original Telegram notification, lifecycle updates reply to it
+    user_action VARCHAR(10) CHECK (user_action IN ('taken', 'ignored')), -- set from the notification buttons
+    entry_low DECIMAL(20,8), -- entry zone bounds; NULL for a single entry price
+    entry_high DECIMAL(20,8),
+    data_quality DECIMAL(3,2), -- 0-1 trust in the input data (candles, source, enrichment, freshness)
     status VARCHAR(20) DEFAULT 'active' CHECK (status IN ('active', 'triggered', 'expired', 'cancelled')),
     created_at TIMESTAMPTZ DEFAULT NOW(),
     triggered_at TIMESTAMPTZ,
@@ -130,7 +148,7 @@ CREATE TABLE notification_logs (
     recipient VARCHAR(50) NOT NULL,
     message TEXT NOT NULL,
     signal_id UUID REFERENCES trading_signals(id),
-    cryptocurrency_id UUID REFERENCES cryptocurrencies(id),
+    cryptocurrency_id UUID REFERENCES cryptocurrencies(id) ON DELETE SET NULL,
     status VARCHAR(20) DEFAULT 'sent' CHECK (status IN ('sent', 'failed', 'pending')),
     error_message TEXT,
     sent_at TIMESTAMPTZ DEFAULT NOW(),
@@ -159,6 +177,57 @@ CREATE TABLE bot_settings (
     updated_at TIMESTAMPTZ DEFAULT NOW()
 );
 
+-- Per-coin overrides of the global strategy settings; NULL columns use the config
+CREATE TABLE strategy_profiles (
+    symbol VARCHAR(10) PRIMARY KEY REFERENCES cryptocurrencies(symbol) ON DELETE CASCADE,
+    min_confidence DECIMAL(5,4),
+    rsi_oversold DECIMAL(5,2),
+    rsi_overbought DECIMAL(5,2),
+    stop_loss_percentage DECIMAL(6,3),
+    take_profit_levels JSONB, -- TP distances overriding TAKE_PROFIT_LEVELS
+    enabled_indicators JSONB, -- subset of rsi, macd, bollinger, fear_greed, trend
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Default coins the user removed; startup seeding skips them until /resetwatchlist.
+-- No foreign key: the row must outlive a permanently deleted coin.
+CREATE TABLE watchlist_overrides (
+    symbol VARCHAR(10) PRIMARY KEY,
+    removed_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Setups dropped by a filter, stored when LOG_REJECTED_SIGNALS is on
+CREATE TABLE rejected_signals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    crypto_id UUID REFERENCES cryptocurrencies(id) ON DELETE SET NULL,
+    symbol VARCHAR(10) NOT NULL,
+    reason VARCHAR(30) NOT NULL, -- confidence, confidence_smoothing, daily_limit, invalid_indicators, indicator_conflict, htf_trend, volatility_window, source_divergence, cycle_limit
+    details TEXT,
+    action VARCHAR(10), -- direction before the filter; NULL when no decision was made
+    confidence_score DECIMAL(5,4),
+    entry_price DECIMAL(20,8),
+    stop_loss DECIMAL(20,8),
+    take_profit_1 DECIMAL(20,8),
+    take_profit_2 DECIMAL(20,8),
+    reasoning TEXT,
+    market_conditions JSONB DEFAULT '{}',
+    timeframe VARCHAR(10),
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Pending signal notifications, written in the same transaction as the signal
+-- and delivered by the outbox worker
+CREATE TABLE signal_outbox (
+    signal_id UUID PRIMARY KEY REFERENCES trading_signals(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ DEFAULT NOW(),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    sent_at TIMESTAMPTZ
+);
+
 -- =====================================================
 -- 3. CREATE INDEXES FOR PERFORMANCE
 -- =====================================================
@@ -173,6 +242,8 @@ CREATE INDEX idx_system_logs_level_created ON system_logs(log_level, created_at)
 CREATE INDEX idx_cryptocurrencies_symbol ON cryptocurrencies(symbol);
 CREATE INDEX idx_cryptocurrencies_cmc_id ON cryptocurrencies(cmc_id) WHERE cmc_id IS NOT NULL;
 CREATE INDEX idx_bot_settings_key ON bot_settings(setting_key);
+CREATE INDEX idx_rejected_signals_reason_created ON rejected_signals(reason, created_at);
+CREATE INDEX idx_signal_outbox_pending ON signal_outbox(next_attempt_at) WHERE status = 'pending';
 
 -- =====================================================
 -- 4. INSERT INITIAL DATA
@@ -218,7 +289,7 @@ SELECT
     COUNT(*) as column_count
 FROM information_schema.columns 
 WHERE table_schema = 'public' 
-AND table_name IN ('cryptocurrencies', 'market_snapshots', 'trading_signals', 'signal_performance', 'learning_data', 'notification_logs', 'system_logs', 'bot_settings')
+AND table_name IN ('cryptocurrencies', 'market_snapshots', 'trading_signals', 'signal_performance', 'learning_data', 'notification_logs', 'system_logs', 'bot_settings', 'strategy_profiles', 'watchlist_overrides', 'rejected_signals', 'signal_outbox')
 GROUP BY table_name
 ORDER BY table_name;
 
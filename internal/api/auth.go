@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"crypto-signal-bot/internal/models"
+	"crypto/subtle"
+	"net/http"
+)
+
+// API authentication. A caller presents at most one credential, the
+// X-API-Key header, which apiAuthMiddleware resolves to a role:
+//
+//   - roleAdmin: the key is ADMIN_API_TOKEN
+//   - roleClient: the key is API_KEY
+//   - roleAnonymous: no key or an unknown one
+//
+// With API_KEY set, /api/v1 writes (and reads with API_KEY_PROTECT_READS)
+// need roleClient or higher; without it they are open. Routes wrapped in
+// requireAdmin need roleAdmin whatever API_KEY is, and stay disabled while
+// ADMIN_API_TOKEN is empty. The TradingView webhook is exempt and checks its
+// own secret, since TradingView can't set headers.
+
+// apiKeyHeader carries the API_KEY or ADMIN_API_TOKEN
+const apiKeyHeader = "X-API-Key"
+
+type apiRole int
+
+const (
+	roleAnonymous apiRole = iota
+	roleClient
+	roleAdmin // Also passes every roleClient check
+)
+
+const apiRoleKey contextKey = "api_role"
+
+// callerRole matches the X-API-Key header against ADMIN_API_TOKEN and API_KEY
+func (s *Server) callerRole(r *http.Request) apiRole {
+	key := []byte(r.Header.Get(apiKeyHeader))
+	if s.cfg.AdminAPIToken != "" && subtle.ConstantTimeCompare(key, []byte(s.cfg.AdminAPIToken)) == 1 {
+		return roleAdmin
+	}
+	if s.cfg.APIKey != "" && subtle.ConstantTimeCompare(key, []byte(s.cfg.APIKey)) == 1 {
+		return roleClient
+	}
+	return roleAnonymous
+}
+
+// roleFromContext returns the role apiAuthMiddleware resolved for the request
+func roleFromContext(ctx context.Context) apiRole {
+	role, _ := ctx.Value(apiRoleKey).(apiRole)
+	return role
+}
+
+// apiAuthMiddleware resolves the caller's role and enforces API_KEY on /api/v1
+func (s *Server) apiAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role := s.callerRole(r)
+		r = r.WithContext(context.WithValue(r.Context(), apiRoleKey, role))
+
+		read := r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+		if s.cfg.APIKey == "" || (read && !s.cfg.APIKeyProtectReads) || r.URL.Path == "/api/v1/webhook/tradingview" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !s.requireRole(w, r, roleClient) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAdmin restricts a route to callers holding ADMIN_API_TOKEN
+func (s *Server) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.requireRole(w, r, roleAdmin) {
+			return
+		}
+		next(w, r)
+	}
+}
+
+// requireRole reports whether the caller has at least role, answering 401
+// for a missing or unknown key and 403 for a valid key without the role
+func (s *Server) requireRole(w http.ResponseWriter, r *http.Request, role apiRole) bool {
+	caller := roleFromContext(r.Context())
+	switch {
+	case caller >= role:
+		return true
+	case caller == roleAnonymous:
+		s.writeJSON(w, http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "Unauthorized: missing or invalid " + apiKeyHeader,
+		})
+	default:
+		s.writeJSON(w, http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "Forbidden: this endpoint needs the ADMIN_API_TOKEN in " + apiKeyHeader,
+		})
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"crypto-signal-bot/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// authRouter mirrors the route kinds of setupRoutes with handlers that only
+// answer 200
+func authRouter(cfg *config.Config) *mux.Router {
+	s := &Server{cfg: cfg}
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+
+	router := mux.NewRouter()
+	api := router.PathPrefix("/api/v1").Subrouter()
+	api.HandleFunc("/status", ok).Methods("GET")
+	api.HandleFunc("/bot/stop", ok).Methods("POST")
+	api.HandleFunc("/bot/killswitch", s.requireAdmin(ok)).Methods("POST")
+	api.HandleFunc("/logs", s.requireAdmin(ok)).Methods("GET")
+	api.HandleFunc("/webhook/tradingview", ok).Methods("POST")
+	api.Use(s.apiAuthMiddleware)
+	return router
+}
+
+func TestAPIAuthRoles(t *testing.T) {
+	const apiKey, adminKey = "client-key", "admin-key"
+
+	tests := []struct {
+		name   string
+		cfg    config.Config
+		method string
+		path   string
+		key    string
+		want   int
+	}{
+		{"open read", config.Config{APIKey: apiKey}, "GET", "/api/v1/status", "", 200},
+		{"protected read", config.Config{APIKey: apiKey, APIKeyProtectReads: true}, "GET", "/api/v1/status", "", 401},
+		{"write without key", config.Config{APIKey: apiKey}, "POST", "/api/v1/bot/stop", "", 401},
+		{"write with wrong key", config.Config{APIKey: apiKey}, "POST", "/api/v1/bot/stop", "nope", 401},
+		{"write with API key", config.Config{APIKey: apiKey}, "POST", "/api/v1/bot/stop", apiKey, 200},
+		{"write with admin key", config.Config{APIKey: apiKey, AdminAPIToken: adminKey}, "POST", "/api/v1/bot/stop", adminKey, 200},
+		{"write without API_KEY", config.Config{}, "POST", "/api/v1/bot/stop", "", 200},
+		{"admin without key", config.Config{APIKey: apiKey, AdminAPIToken: adminKey}, "POST", "/api/v1/bot/killswitch", "", 401},
+		{"admin with API key", config.Config{APIKey: apiKey, AdminAPIToken: adminKey}, "POST", "/api/v1/bot/killswitch", apiKey, 403},
+		{"admin with admin key", config.Config{APIKey: apiKey, AdminAPIToken: adminKey}, "POST", "/api/v1/bot/killswitch", adminKey, 200},
+		{"admin without API_KEY", config.Config{AdminAPIToken: adminKey}, "POST", "/api/v1/bot/killswitch", adminKey, 200},
+		{"admin read with API key", config.Config{APIKey: apiKey, AdminAPIToken: adminKey}, "GET", "/api/v1/logs", apiKey, 403},
+		{"admin disabled", config.Config{}, "POST", "/api/v1/bot/killswitch", "", 401},
+		{"webhook without key", config.Config{APIKey: apiKey}, "POST", "/api/v1/webhook/tradingview", "", 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(apiKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			authRouter(&cfg).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// requestIDHeader carries the correlation ID in both directions
+const requestIDHeader = "X-Request-ID"
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// validRequestID limits caller-supplied IDs to something safe to log and echo back
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDFromContext returns the correlation ID of the request being served, or ""
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// withRequestID reuses a well-formed incoming X-Request-ID or generates one,
+// then stores it on the context and the response header
+func withRequestID(w http.ResponseWriter, r *http.Request) *http.Request {
+	id := r.Header.Get(requestIDHeader)
+	if !validRequestID.MatchString(id) {
+		id = uuid.New().String()
+	}
+
+	w.Header().Set(requestIDHeader, id)
+	return r.WithContext(context.WithValue(r.Context(), requestIDKey, id))
+}
+
+// requestLogger returns a log entry tagged with the request's correlation ID
+func requestLogger(r *http.Request) *logrus.Entry {
+	return logrus.WithField("request_id", RequestIDFromContext(r.Context()))
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to hijack it for WebSockets
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Hijack hands the connection to gorilla/websocket, which needs an http.Hijacker
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
@@ -6,34 +6,51 @@ import (
 	"crypto-signal-bot/internal/models"
 	"crypto-signal-bot/internal/scheduler"
 	"crypto-signal-bot/internal/services"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
 
 type Server struct {
 	cfg        *config.Config
-	db         *database.SupabaseClient
+	db         database.Store
 	botService *services.BotService
 	scheduler  *scheduler.Scheduler
 	router     *mux.Router
 	server     *http.Server
+	startTime  time.Time
+	signalHub  *signalHub // WebSocket clients of /api/v1/ws/signals
+	wsUpgrader websocket.Upgrader
 }
 
-func NewServer(cfg *config.Config, db *database.SupabaseClient, botService *services.BotService, scheduler *scheduler.Scheduler) *Server {
+func NewServer(cfg *config.Config, db database.Store, botService *services.BotService, scheduler *scheduler.Scheduler) *Server {
 	s := &Server{
 		cfg:        cfg,
 		db:         db,
 		botService: botService,
 		scheduler:  scheduler,
 		router:     mux.NewRouter(),
+		startTime:  time.Now(),
+		signalHub:  newSignalHub(),
 	}
 
+	s.wsUpgrader = websocket.Upgrader{
+		CheckOrigin: s.checkStreamOrigin,
+		Error:       s.writeUpgradeError,
+	}
+
+	botService.OnSignalSent(s.signalHub.broadcast)
 	s.setupRoutes()
 	return s
 }
@@ -42,35 +59,70 @@ func (s *Server) setupRoutes() {
 	// Root endpoint
 	s.router.HandleFunc("/", s.handleRoot).Methods("GET")
 
+	// Orchestrator probes
+	s.router.HandleFunc("/healthz", s.handleLiveness).Methods("GET")
+	s.router.HandleFunc("/readyz", s.handleReadiness).Methods("GET")
+
 	// API prefix
 	api := s.router.PathPrefix("/api/v1").Subrouter()
 	api.HandleFunc("/", s.handleRoot).Methods("GET")
+	api.HandleFunc("/health", s.handleHealth).Methods("GET")
 
 	// Bot status and control
 	api.HandleFunc("/bot/status", s.handleBotStatus).Methods("GET")
 	api.HandleFunc("/bot/start", s.handleBotStart).Methods("POST")
 	api.HandleFunc("/bot/stop", s.handleBotStop).Methods("POST")
+	api.HandleFunc("/bot/killswitch", s.requireAdmin(s.handleKillSwitch)).Methods("POST")
+	api.HandleFunc("/bot/killswitch", s.requireAdmin(s.handleRearmKillSwitch)).Methods("DELETE")
+	api.HandleFunc("/bot/dryrun", s.handleGetDryRun).Methods("GET")
+	api.HandleFunc("/bot/dryrun", s.requireAdmin(s.handleSetDryRun)).Methods("POST")
 
 	// Manual operations
 	api.HandleFunc("/bot/analyze", s.handleManualAnalysis).Methods("POST")
 	api.HandleFunc("/bot/summary", s.handleDailySummary).Methods("POST")
+	api.HandleFunc("/bot/test-notify-burst", s.requireAdmin(s.handleTestNotifyBurst)).Methods("POST")
 
 	// Signals
 	api.HandleFunc("/signals", s.handleGetSignals).Methods("GET")
+	api.HandleFunc("/signals/ref/{code}", s.handleGetSignalByRef).Methods("GET")
+	api.HandleFunc("/signals/rejected", s.handleGetRejectedSignals).Methods("GET")
 	api.HandleFunc("/signals/{id}", s.handleGetSignal).Methods("GET")
 	api.HandleFunc("/signals/analytics", s.handleSignalAnalytics).Methods("GET")
+	api.HandleFunc("/analytics/refresh", s.handleRefreshAnalytics).Methods("POST")
 
 	// Performance
 	api.HandleFunc("/performance/metrics", s.handlePerformanceMetrics).Methods("GET")
 	api.HandleFunc("/performance/learning", s.handleLearningInsights).Methods("GET")
+	api.HandleFunc("/performance/rollup", s.handlePerformanceRollup).Methods("GET")
+	api.HandleFunc("/exposure", s.handleExposure).Methods("GET")
+
+	// Learning
+	api.HandleFunc("/learning/optimize", s.handleLearningOptimize).Methods("POST")
+	api.HandleFunc("/backtest", s.handleBacktest).Methods("POST")
+
+	// System logs
+	api.HandleFunc("/logs", s.requireAdmin(s.handleGetSystemLogs)).Methods("GET")
 
 	// Scheduler
 	api.HandleFunc("/scheduler/status", s.handleSchedulerStatus).Methods("GET")
 	api.HandleFunc("/scheduler/jobs/{job}/run", s.handleRunJob).Methods("POST")
 
 	// Market data
+	api.HandleFunc("/market", s.handleGetMarketOverview).Methods("GET")
 	api.HandleFunc("/market/{symbol}", s.handleGetMarketData).Methods("GET")
+	api.HandleFunc("/market/{symbol}/klines", s.handleGetKlines).Methods("GET")
 	api.HandleFunc("/cryptocurrencies", s.handleGetCryptocurrencies).Methods("GET")
+	api.HandleFunc("/cryptocurrencies/{symbol}", s.requireAdmin(s.handleDeleteCryptocurrency)).Methods("DELETE")
+	api.HandleFunc("/cryptocurrencies/{symbol}/strategy", s.handleGetStrategyProfile).Methods("GET")
+	api.HandleFunc("/cryptocurrencies/{symbol}/strategy", s.requireAdmin(s.handleSetStrategyProfile)).Methods("PUT")
+
+	// Live signal stream
+	api.HandleFunc("/ws/signals", s.handleSignalStream).Methods("GET")
+
+	// External signal webhooks
+	api.HandleFunc("/webhook/tradingview", s.handleTradingViewWebhook).Methods("POST")
+
+	api.Use(s.apiAuthMiddleware)
 
 	// Static files (for simple dashboard)
 	s.router.PathPrefix("/").Handler(http.FileServer(http.Dir("./web/static/")))
@@ -94,11 +146,15 @@ func (s *Server) Start() error {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	if s.cfg.APIKey == "" {
+		logrus.Warn("⚠️ API_KEY is not set: /api/v1 write endpoints such as /bot/stop are open to anyone who can reach the port")
+	}
 	logrus.Info("🌐 Starting API server on port ", port)
 	return s.server.ListenAndServe()
 }
 
 func (s *Server) Stop() error {
+	s.signalHub.closeAll()
 	if s.server != nil {
 		return s.server.Close()
 	}
@@ -111,7 +167,7 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 		Success: true,
 		Message: "🤖 Crypto Signal Bot API",
 		Data: map[string]interface{}{
-			"version":   "1.0.0",
+			"version":   services.Version,
 			"status":    "running",
 			"endpoints": []string{"/api/v1/bot/status", "/api/v1/bot/start", "/api/v1/bot/stop"},
 			"timestamp": time.Now().Format(time.RFC3339),
@@ -119,7 +175,44 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Liveness probe - the process is up and serving HTTP
+func (s *Server) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// Readiness probe - dependencies are confirmed and an analysis has completed
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	if !s.botService.IsReady() {
+		status = http.StatusServiceUnavailable
+	}
+
+	s.writeJSON(w, status, models.APIResponse{
+		Success: status == http.StatusOK,
+		Data:    s.botService.GetReadiness(),
+	})
+}
+
+// Health endpoint with component details for humans
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	overall := "healthy"
+	if !s.botService.IsReady() {
+		overall = "degraded"
+	}
 
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"status":     overall,
+			"components": s.botService.GetReadiness(),
+			"bot":        s.botService.GetStatus(),
+			"uptime":     time.Since(s.startTime).String(),
+			"timestamp":  time.Now().Format(time.RFC3339),
+		},
+	})
+}
 
 // Bot status endpoint
 func (s *Server) handleBotStatus(w http.ResponseWriter, r *http.Request) {
@@ -162,9 +255,108 @@ func (s *Server) handleBotStop(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleKillSwitch engages the emergency stop. Besides the admin token it
+// requires ?confirm=true so a stray request can't trigger it.
+func (s *Server) handleKillSwitch(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("confirm") != "true" {
+		s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Kill switch requires confirm=true",
+		})
+		return
+	}
+
+	requestLogger(r).Warn("Kill switch requested via API")
+	result, err := s.botService.EngageKillSwitch()
+	if err != nil {
+		s.writeJSON(w, http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    result,
+		Message: "Kill switch engaged",
+	})
+}
+
+// handleRearmKillSwitch clears the emergency stop
+func (s *Server) handleRearmKillSwitch(w http.ResponseWriter, r *http.Request) {
+	if err := s.botService.RearmKillSwitch(); err != nil {
+		s.writeJSON(w, http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Kill switch re-armed",
+	})
+}
+
+// handleGetDryRun reports whether signals are only being logged
+func (s *Server) handleGetDryRun(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    s.botService.DryRunStatus(),
+	})
+}
+
+// handleSetDryRun turns dry run on or off from a {"enabled": bool} body
+func (s *Server) handleSetDryRun(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&body); err != nil || body.Enabled == nil {
+		s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   `Body must be {"enabled": true|false}`,
+		})
+		return
+	}
+
+	requestLogger(r).Info("Dry run set to ", *body.Enabled, " via API")
+	status := s.botService.SetDryRun(*body.Enabled)
+
+	message := "Dry run disabled"
+	if status.Enabled {
+		message = "Dry run enabled"
+	}
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    status,
+		Message: message,
+	})
+}
+
 // Manual analysis endpoint
 func (s *Server) handleManualAnalysis(w http.ResponseWriter, r *http.Request) {
-	if err := s.botService.RunAnalysis(); err != nil {
+	// ?force=true bypasses the daily signal cap and needs the admin token
+	force := false
+	if value := r.URL.Query().Get("force"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "force must be true or false",
+			})
+			return
+		}
+		force = parsed
+	}
+
+	if force && !s.requireRole(w, r, roleAdmin) {
+		return
+	}
+
+	limitsBypassed := force && s.botService.DailyLimitReached()
+	requestLogger(r).Info("Manual analysis requested via API (force=", force, ")")
+	if err := s.botService.RunManualAnalysis(force); err != nil {
 		s.writeJSON(w, http.StatusInternalServerError, models.APIResponse{
 			Success: false,
 			Error:   err.Error(),
@@ -172,9 +364,18 @@ func (s *Server) handleManualAnalysis(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	message := "Manual analysis completed"
+	if limitsBypassed {
+		message += " (daily signal limit bypassed)"
+	}
+
 	s.writeJSON(w, http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Manual analysis completed",
+		Data: map[string]interface{}{
+			"forced":          force,
+			"limits_bypassed": limitsBypassed,
+		},
+		Message: message,
 	})
 }
 
@@ -194,18 +395,220 @@ func (s *Server) handleDailySummary(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Get signals endpoint
+// handleTestNotifyBurst sends ?count= synthetic signal notifications (default
+// 10) to load test the notification channels; refused in production
+func (s *Server) handleTestNotifyBurst(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.Environment == "production" {
+		s.writeJSON(w, http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "Notification burst test is disabled in production",
+		})
+		return
+	}
+
+	count := 10 // default
+	if countStr := r.URL.Query().Get("count"); countStr != "" {
+		c, err := strconv.Atoi(countStr)
+		if err != nil || c < 1 || c > services.MaxNotifyBurst {
+			s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf("count must be between 1 and %d", services.MaxNotifyBurst),
+			})
+			return
+		}
+		count = c
+	}
+
+	requestLogger(r).Info("Notification burst test of ", count, " via API")
+	result := s.botService.TestNotifyBurst(count)
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    result,
+		Message: fmt.Sprintf("%d of %d notifications sent", result.Succeeded, result.Requested),
+	})
+}
+
+// Get signals endpoint: one page of signals, newest first, narrowed by
+// symbol, action, status, priority and a created_at range
 func (s *Server) handleGetSignals(w http.ResponseWriter, r *http.Request) {
-	// Parse query parameters
-	limitStr := r.URL.Query().Get("limit")
-	limit := 50 // default
-	if limitStr != "" {
+	filter, err := parseSignalFilter(r.URL.Query())
+	if err != nil {
+		s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	signals, total, err := s.db.GetSignalsFiltered(filter)
+	if err != nil {
+		s.writeJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// min_quality filters the fetched page, so fewer than limit may come back
+	// and total doesn't account for it
+	if minQualityStr := r.URL.Query().Get("min_quality"); minQualityStr != "" {
+		minQuality, parseErr := decimal.NewFromString(minQualityStr)
+		if parseErr != nil || minQuality.IsNegative() || minQuality.GreaterThan(decimal.NewFromInt(1)) {
+			s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "min_quality must be a number between 0 and 1",
+			})
+			return
+		}
+		signals = services.FilterByDataQuality(signals, minQuality)
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    signals,
+		Pagination: &models.Pagination{
+			Total:  total,
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+		},
+	})
+}
+
+// parseSignalFilter reads the signal listing parameters. from and to take
+// RFC 3339 times or YYYY-MM-DD dates; a date for to includes that whole day.
+func parseSignalFilter(query url.Values) (models.SignalFilter, error) {
+	filter := models.SignalFilter{Limit: 50}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > 1000 {
+			return filter, fmt.Errorf("limit must be between 1 and 1000")
+		}
+		filter.Limit = limit
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return filter, fmt.Errorf("offset must be a non-negative integer")
+		}
+		filter.Offset = offset
+	}
+
+	filter.Symbol = strings.ToUpper(strings.TrimSpace(query.Get("symbol")))
+
+	if action := strings.ToUpper(strings.TrimSpace(query.Get("action"))); action != "" {
+		if action != "BUY" && action != "SELL" && action != "HOLD" {
+			return filter, fmt.Errorf("action must be BUY, SELL or HOLD, got %q", query.Get("action"))
+		}
+		filter.Action = action
+	}
+
+	if status := strings.ToLower(strings.TrimSpace(query.Get("status"))); status != "" {
+		switch status {
+		case "active", "triggered", "expired", "cancelled":
+			filter.Status = status
+		default:
+			return filter, fmt.Errorf("status must be active, triggered, expired or cancelled, got %q", query.Get("status"))
+		}
+	}
+
+	if priorityStr := query.Get("priority"); priorityStr != "" {
+		priority, err := services.ParseSignalPriority(priorityStr)
+		if err != nil {
+			return filter, err
+		}
+		filter.Priority = priority
+	}
+
+	parseTime := func(name string, endOfDay bool) (time.Time, error) {
+		value := strings.TrimSpace(query.Get(name))
+		if value == "" {
+			return time.Time{}, nil
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s must be a YYYY-MM-DD date or an RFC 3339 time, got %q", name, value)
+		}
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	var err error
+	if filter.From, err = parseTime("from", false); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTime("to", true); err != nil {
+		return filter, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+
+	return filter, nil
+}
+
+// handleGetRejectedSignals lists setups dropped by a filter, see LOG_REJECTED_SIGNALS
+func (s *Server) handleGetRejectedSignals(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 1000 {
+			limit = l
+		}
+	}
+
+	var reason string
+	if reasonStr := r.URL.Query().Get("reason"); reasonStr != "" {
+		parsed, err := services.ParseRejectionReason(reasonStr)
+		if err != nil {
+			s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		reason = parsed
+	}
+
+	rejected, err := s.db.GetRejectedSignals(reason, limit)
+	if err != nil {
+		s.writeJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    rejected,
+	})
+}
+
+// handleGetSystemLogs lists system_logs entries newest first; ?level= filters
+// by level and ?limit= defaults to 50. Requires the admin token.
+func (s *Server) handleGetSystemLogs(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 1000 {
 			limit = l
 		}
 	}
 
-	signals, err := s.db.GetRecentSignals(limit)
+	level, err := services.ParseSystemLogLevel(r.URL.Query().Get("level"))
+	if err != nil {
+		s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logs, err := s.botService.GetSystemLogs(level, limit)
 	if err != nil {
 		s.writeJSON(w, http.StatusInternalServerError, models.APIResponse{
 			Success: false,
@@ -216,7 +619,7 @@ func (s *Server) handleGetSignals(w http.ResponseWriter, r *http.Request) {
 
 	s.writeJSON(w, http.StatusOK, models.APIResponse{
 		Success: true,
-		Data:    signals,
+		Data:    logs,
 	})
 }
 
@@ -240,6 +643,26 @@ func (s *Server) handleGetSignal(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Get signal by reference code endpoint
+func (s *Server) handleGetSignalByRef(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	code := strings.ToUpper(vars["code"])
+
+	signal, err := s.db.GetSignalByRefCode(code)
+	if err != nil {
+		s.writeJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Signal not found",
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    signal,
+	})
+}
+
 // Signal analytics endpoint
 func (s *Server) handleSignalAnalytics(w http.ResponseWriter, r *http.Request) {
 	analytics, err := s.db.GetSignalAnalytics()
@@ -257,6 +680,24 @@ func (s *Server) handleSignalAnalytics(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Force recomputation of cached signal analytics
+func (s *Server) handleRefreshAnalytics(w http.ResponseWriter, r *http.Request) {
+	analytics, err := s.db.RefreshSignalAnalytics()
+	if err != nil {
+		s.writeJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    analytics,
+		Message: "Analytics refreshed",
+	})
+}
+
 // Performance metrics endpoint
 func (s *Server) handlePerformanceMetrics(w http.ResponseWriter, r *http.Request) {
 	metrics, err := s.botService.GetPerformanceMetrics()
@@ -291,6 +732,102 @@ func (s *Server) handleLearningInsights(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// Performance rollup endpoint, e.g. ?period=weekly
+func (s *Server) handlePerformanceRollup(w http.ResponseWriter, r *http.Request) {
+	period := strings.ToLower(r.URL.Query().Get("period"))
+	if period == "" {
+		period = "weekly"
+	}
+
+	rollups, err := s.botService.GetPerformanceRollup(period)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, database.ErrInvalidRollupPeriod) {
+			status = http.StatusBadRequest
+		}
+		s.writeJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    rollups,
+	})
+}
+
+// Active signal exposure endpoint
+func (s *Server) handleExposure(w http.ResponseWriter, r *http.Request) {
+	exposure, err := s.botService.GetExposure()
+	if err != nil {
+		s.writeJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    exposure,
+	})
+}
+
+// Learning optimization endpoint
+func (s *Server) handleLearningOptimize(w http.ResponseWriter, r *http.Request) {
+	result, err := s.botService.RunLearningOptimization()
+	if err != nil {
+		s.writeJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Learning optimization completed with %d changes", len(result.Changes)),
+		Data:    result,
+	})
+}
+
+// handleBacktest replays historical candles through the strategy and
+// reports how its signals would have performed
+func (s *Server) handleBacktest(w http.ResponseWriter, r *http.Request) {
+	var req services.BacktestRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&req); err != nil {
+		s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid backtest JSON: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := s.botService.RunBacktest(req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrInvalidBacktest):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrBacktestDisabled):
+			status = http.StatusForbidden
+		}
+		s.writeJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Backtest of %s over %d candles produced %d trades", result.Symbol, result.Candles, result.TotalSignals),
+		Data:    result,
+	})
+}
+
 // Scheduler status endpoint
 func (s *Server) handleSchedulerStatus(w http.ResponseWriter, r *http.Request) {
 	status := s.scheduler.GetStatus()
@@ -320,14 +857,94 @@ func (s *Server) handleRunJob(w http.ResponseWriter, r *http.Request) {
 }
 
 // Get market data endpoint
+// Get OHLCV candles endpoint
+func (s *Server) handleGetKlines(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = s.cfg.KlineInterval
+	}
+
+	limit := 100 // default
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 {
+			s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "limit must be a positive integer",
+			})
+			return
+		}
+		if l > services.MaxCandleLimit {
+			l = services.MaxCandleLimit
+		}
+		limit = l
+	}
+
+	candles, err := s.botService.GetCandles(symbol, interval, limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidCandleRequest) {
+			status = http.StatusBadRequest
+		}
+		s.writeJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"symbol":   strings.ToUpper(symbol),
+			"interval": interval,
+			"candles":  candles,
+		},
+	})
+}
+
+// handleGetMarketOverview returns price, change and key indicators for
+// several coins at once, e.g. ?symbols=BTC,ETH,SOL
+func (s *Server) handleGetMarketOverview(w http.ResponseWriter, r *http.Request) {
+	symbols, err := services.ParseMarketSymbols(r.URL.Query().Get("symbols"))
+	if err != nil {
+		s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    s.botService.GetMarketOverview(symbols),
+	})
+}
+
+// handleGetMarketData returns a watched coin's quote, Fear & Greed and
+// technical indicators, cached for a minute
 func (s *Server) handleGetMarketData(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	symbol := vars["symbol"]
+	symbol := strings.ToUpper(mux.Vars(r)["symbol"])
+
+	detail, err := s.botService.GetMarketDetail(symbol)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, database.ErrCryptocurrencyNotFound) {
+			status = http.StatusNotFound
+			err = fmt.Errorf("%s is not a monitored cryptocurrency", symbol)
+		}
+		s.writeJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
 
-	// TODO: Implement market data retrieval
 	s.writeJSON(w, http.StatusOK, models.APIResponse{
 		Success: true,
-		Data:    map[string]string{"symbol": symbol, "status": "not_implemented"},
+		Data:    detail,
 	})
 }
 
@@ -348,23 +965,185 @@ func (s *Server) handleGetCryptocurrencies(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+// Delete cryptocurrency endpoint (requires admin token)
+func (s *Server) handleDeleteCryptocurrency(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.ToUpper(mux.Vars(r)["symbol"])
+
+	if err := s.botService.DeleteCryptocurrency(symbol); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, database.ErrCryptocurrencyNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Cryptocurrency %s deleted", symbol),
+	})
+}
+
+// handleGetStrategyProfile returns a coin's strategy overrides and the
+// settings in effect for it
+func (s *Server) handleGetStrategyProfile(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.ToUpper(mux.Vars(r)["symbol"])
+
+	profile, effective, err := s.botService.GetStrategyProfile(symbol)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, database.ErrCryptocurrencyNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"profile":   profile,
+			"effective": effective,
+		},
+	})
+}
+
+// handleSetStrategyProfile replaces a coin's strategy overrides; omitted
+// fields fall back to the global settings. Takes effect on the next analysis.
+func (s *Server) handleSetStrategyProfile(w http.ResponseWriter, r *http.Request) {
+	var profile models.StrategyProfile
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&profile); err != nil {
+		s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid strategy profile JSON: " + err.Error(),
+		})
+		return
+	}
+	profile.Symbol = strings.ToUpper(mux.Vars(r)["symbol"])
+
+	effective, err := s.botService.SetStrategyProfile(&profile)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, database.ErrCryptocurrencyNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrInvalidStrategyProfile):
+			status = http.StatusBadRequest
+		}
+		s.writeJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"profile":   profile,
+			"effective": effective,
+		},
+		Message: fmt.Sprintf("Strategy profile for %s updated", profile.Symbol),
+	})
+}
+
+// handleTradingViewWebhook ingests a TradingView alert as an externally-sourced signal.
+// TradingView can't set headers, so the shared secret may come in the body.
+func (s *Server) handleTradingViewWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.TradingViewWebhookSecret == "" {
+		s.writeJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "TradingView webhook is disabled",
+		})
+		return
+	}
+
+	var alert services.TradingViewAlert
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&alert); err != nil {
+		s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid alert JSON: " + err.Error(),
+		})
+		return
+	}
+
+	secret := alert.Secret
+	if header := r.Header.Get("X-Webhook-Secret"); header != "" {
+		secret = header
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(s.cfg.TradingViewWebhookSecret)) != 1 {
+		s.writeJSON(w, http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "Unauthorized",
+		})
+		return
+	}
+
+	signal, err := s.botService.IngestTradingViewAlert(&alert)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidTradingViewAlert) {
+			status = http.StatusBadRequest
+		}
+		s.writeJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    signal,
+		Message: fmt.Sprintf("Signal %s created from TradingView alert", signal.RefCode),
+	})
+}
+
 // Helper methods
+
 func (s *Server) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	// Failed responses are logged with the request's correlation ID, which
+	// loggingMiddleware has already put on the response header
+	if resp, ok := data.(models.APIResponse); ok && !resp.Success {
+		logrus.WithField("request_id", w.Header().Get(requestIDHeader)).Warnf("API error %d: %s", status, resp.Error)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
 
+// loggingMiddleware tags every request with a correlation ID and logs it at
+// REQUEST_LOG_LEVEL ("off" disables the access log, not the ID)
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
+	level, err := logrus.ParseLevel(s.cfg.RequestLogLevel)
+	logEnabled := s.cfg.RequestLogLevel != "off"
+	if err != nil && logEnabled {
+		logrus.Warn("Invalid REQUEST_LOG_LEVEL ", s.cfg.RequestLogLevel, ", using debug")
+		level = logrus.DebugLevel
+	}
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		next.ServeHTTP(w, r)
-		logrus.Debug(
-			"API Request: ",
-			r.Method, " ",
-			r.RequestURI, " ",
-			time.Since(start),
-		)
+		r = withRequestID(w, r)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		if logEnabled {
+			requestLogger(r).WithFields(logrus.Fields{
+				"method":   r.Method,
+				"uri":      r.RequestURI,
+				"status":   recorder.status,
+				"duration": time.Since(start).String(),
+			}).Log(level, "API Request")
+		}
 	})
 }
 
@@ -372,7 +1151,8 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Request-ID, X-API-Key")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
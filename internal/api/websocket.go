@@ -0,0 +1,222 @@
+package api
+
+import (
+	"crypto-signal-bot/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	wsSendBuffer     = 16 // Messages queued per client before it is dropped as a slow consumer
+	wsWriteTimeout   = 10 * time.Second
+	wsPingInterval   = 30 * time.Second
+	wsReadTimeout    = 2 * wsPingInterval // A client that answers no ping within this is gone
+	wsMaxMessageSize = 4096               // Clients have nothing to send; larger messages end the connection
+)
+
+// streamMessage is the JSON pushed to stream clients
+type streamMessage struct {
+	Type string      `json:"type"` // "signal"
+	Data interface{} `json:"data"`
+}
+
+// wsClient is one connected stream client. Messages are written only by its
+// write loop; pong and close replies go out through WriteControl, which
+// gorilla/websocket allows concurrently.
+type wsClient struct {
+	conn      *websocket.Conn
+	send      chan []byte
+	done      chan struct{}
+	closeCode int // Sent in the close frame; set once before done is closed
+	closeOnce sync.Once
+}
+
+func newWSClient(conn *websocket.Conn) *wsClient {
+	return &wsClient{
+		conn: conn,
+		send: make(chan []byte, wsSendBuffer),
+		done: make(chan struct{}),
+	}
+}
+
+// close asks the write loop to send a close frame with code and end the
+// connection; safe to call more than once, the first code wins
+func (c *wsClient) close(code int) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		close(c.done)
+	})
+}
+
+// writeLoop sends queued messages and keepalive pings until the client
+// closes, then says goodbye and closes the connection
+func (c *wsClient) writeLoop() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	defer c.conn.Close()
+
+	for {
+		select {
+		case message := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				c.close(websocket.CloseAbnormalClosure)
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				c.close(websocket.CloseAbnormalClosure)
+				return
+			}
+		case <-c.done:
+			// Fails harmlessly when the client already closed or the connection is gone
+			c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(c.closeCode, ""),
+				time.Now().Add(wsWriteTimeout))
+			return
+		}
+	}
+}
+
+// readLoop discards client messages until the connection ends. Pongs and
+// messages extend the read deadline; gorilla/websocket answers pings and
+// close frames itself.
+func (c *wsClient) readLoop() {
+	defer c.close(websocket.CloseNormalClosure)
+
+	c.conn.SetReadLimit(wsMaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+		c.conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+	}
+}
+
+// signalHub tracks the stream clients and fans signals out to them
+type signalHub struct {
+	mu      sync.Mutex
+	clients map[*wsClient]struct{}
+}
+
+func newSignalHub() *signalHub {
+	return &signalHub{clients: make(map[*wsClient]struct{})}
+}
+
+func (h *signalHub) register(client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.clients[client] = struct{}{}
+}
+
+func (h *signalHub) unregister(client *wsClient) {
+	h.mu.Lock()
+	delete(h.clients, client)
+	h.mu.Unlock()
+
+	client.close(websocket.CloseNormalClosure)
+}
+
+// broadcast queues a signal for every client without blocking; a client
+// whose queue is full is disconnected so it can't hold up the others
+func (h *signalHub) broadcast(signal *models.TradingSignal) {
+	message, err := json.Marshal(streamMessage{Type: "signal", Data: signal})
+	if err != nil {
+		logrus.Error("Failed to encode signal for the stream: ", err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients {
+		select {
+		case client.send <- message:
+		default:
+			logrus.Warn("Dropping slow WebSocket client ", client.conn.RemoteAddr())
+			delete(h.clients, client)
+			client.close(websocket.ClosePolicyViolation)
+		}
+	}
+}
+
+// closeAll disconnects every client; hijacked connections outlive http.Server.Close
+func (h *signalHub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients {
+		delete(h.clients, client)
+		client.close(websocket.CloseGoingAway)
+	}
+}
+
+// checkStreamOrigin lets a browser open the stream only from the API's own
+// host or an origin listed in WS_ALLOWED_ORIGINS. Requests without an Origin
+// header come from non-browser clients and are allowed.
+func (s *Server) checkStreamOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range s.cfg.WSAllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+
+	requestLogger(r).Warn("Rejected signal stream from origin ", origin)
+	return false
+}
+
+// writeUpgradeError reports a failed handshake in the API's JSON shape
+func (s *Server) writeUpgradeError(w http.ResponseWriter, r *http.Request, status int, reason error) {
+	s.writeJSON(w, status, models.APIResponse{
+		Success: false,
+		Error:   reason.Error(),
+	})
+}
+
+// handleSignalStream streams every sent signal to a WebSocket client as
+// {"type":"signal","data":{...}} until it disconnects
+func (s *Server) handleSignalStream(w http.ResponseWriter, r *http.Request) {
+	if !websocket.IsWebSocketUpgrade(r) {
+		s.writeJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Expected a WebSocket upgrade request",
+		})
+		return
+	}
+
+	// The upgrader reports handshake failures to the client itself
+	conn, err := s.wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		requestLogger(r).Warn("WebSocket upgrade failed: ", err)
+		return
+	}
+
+	client := newWSClient(conn)
+	s.signalHub.register(client)
+	requestLogger(r).Info("Signal stream client connected from ", r.RemoteAddr)
+
+	go client.writeLoop()
+	client.readLoop()
+
+	s.signalHub.unregister(client)
+	requestLogger(r).Info("Signal stream client disconnected from ", r.RemoteAddr)
+}
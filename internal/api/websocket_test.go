@@ -0,0 +1,158 @@
+package api
+
+import (
+	"crypto-signal-bot/internal/config"
+	"crypto-signal-bot/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// streamServer serves handleSignalStream behind the same status recorder the
+// request logging middleware uses
+func streamServer(t *testing.T, allowedOrigins []string) (*Server, string) {
+	t.Helper()
+
+	s := &Server{
+		cfg:       &config.Config{WSAllowedOrigins: allowedOrigins},
+		signalHub: newSignalHub(),
+	}
+	s.wsUpgrader = websocket.Upgrader{
+		CheckOrigin: s.checkStreamOrigin,
+		Error:       s.writeUpgradeError,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.handleSignalStream(&statusRecorder{ResponseWriter: w, status: http.StatusOK}, r)
+	}))
+	t.Cleanup(func() {
+		s.signalHub.closeAll()
+		ts.Close()
+	})
+	return s, "ws" + strings.TrimPrefix(ts.URL, "http")
+}
+
+// waitForClients waits for the handler to register want clients, which
+// happens just after the handshake completes on the client side
+func waitForClients(t *testing.T, hub *signalHub, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		hub.mu.Lock()
+		registered := len(hub.clients)
+		hub.mu.Unlock()
+		if registered == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d clients registered, want %d", registered, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSignalStreamDeliversSignals(t *testing.T) {
+	s, url := streamServer(t, nil)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	waitForClients(t, s.signalHub, 1)
+
+	signal := &models.TradingSignal{ID: uuid.New(), Action: "BUY"}
+	s.signalHub.broadcast(signal)
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var message struct {
+		Type string               `json:"type"`
+		Data models.TradingSignal `json:"data"`
+	}
+	if err := json.Unmarshal(data, &message); err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	if message.Type != "signal" || message.Data.ID != signal.ID {
+		t.Errorf("got %s, want signal %s", data, signal.ID)
+	}
+}
+
+func TestSignalStreamClosesOnShutdown(t *testing.T) {
+	s, url := streamServer(t, nil)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	waitForClients(t, s.signalHub, 1)
+	s.signalHub.closeAll()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err = conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("got %v, want a going away close frame", err)
+	}
+}
+
+func TestSignalStreamOrigins(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{"no origin", nil, "", true},
+		{"other site", nil, "https://evil.example", false},
+		{"listed origin", []string{"https://dashboard.example/"}, "https://dashboard.example", true},
+		{"any origin", []string{"*"}, "https://evil.example", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, url := streamServer(t, tt.allowed)
+
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+			conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+			if conn != nil {
+				conn.Close()
+			}
+			if got := err == nil; got != tt.want {
+				t.Fatalf("connected = %v, want %v (err %v)", got, tt.want, err)
+			}
+			if !tt.want && resp.StatusCode != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+			}
+		})
+	}
+}
+
+func TestSignalStreamRejectsPlainRequests(t *testing.T) {
+	_, url := streamServer(t, nil)
+
+	resp, err := http.Get("http" + strings.TrimPrefix(url, "ws"))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
@@ -1,105 +1,429 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
 )
 
 type Config struct {
+	// Storage
+	StorageBackend string // "supabase" or "memory" (non-persistent, for local dev)
+
 	// Supabase
 	SupabaseURL        string
 	SupabaseAnonKey    string
 	SupabaseServiceKey string
 
+	// Database connection retries at startup
+	DBConnectRetries           int
+	DBRetryBaseDelaySeconds    int // Doubles after each failed attempt
+	DBRetryMaxWaitSeconds      int // Give up on startup retries after this long in total
+	DBReconnectIntervalSeconds int // Background reconnect attempts while on REST/unreachable; 0 disables
+
 	// Telegram
-	TelegramBotToken string
-	TelegramChatID   string
+	TelegramBotToken       string
+	TelegramChatID         string   // Owner chat: receives replies and may use the owner-only commands
+	TelegramChatIDs        []string // Chats that receive signals, system notifications and daily summaries
+	AuthorizedChatIDs      []string // Chats besides the owner allowed to use commands and menu buttons
+	TelegramPublicCommands []string // Read-only commands any chat may use
+
+	// Notifications
+	SparklineEnabled bool
+	SparklinePoints  int
+	NotificationMode      string // "instant" or "digest"
+	DigestIntervalMinutes int    // How often a digest of queued signals is sent
+	DigestInstantPriority string // Lowest priority still sent instantly in digest mode; empty queues all
+	OutboxMaxAttempts     int    // Delivery attempts of a signal notification before its outbox entry is marked failed
 
 	// WhatsApp
-	WhatsAppEnabled bool
-	WhatsAppAPIURL  string
-	WhatsAppToken   string
+	WhatsAppEnabled          bool
+	WhatsAppAPIURL           string // Cloud API messages endpoint, https://graph.facebook.com/<version>/<phone-number-id>/messages
+	WhatsAppToken            string
+	WhatsAppRecipient        string // Recipient phone number in international format without "+"
+	WhatsAppMessageType      string // "text", or "template" to send WhatsAppTemplateName
+	WhatsAppTemplateName     string // Approved template with one body parameter that receives the message
+	WhatsAppTemplateLanguage string
 
-	// API Keys
+	// Discord
+	DiscordEnabled    bool
+	DiscordWebhookURL string // Channel webhook that receives every sent signal as a rich embed
+
+	// Signal priority
+	PriorityHighConfidence   float64 // Minimum confidence for "high"
+	PriorityHighAgreement    float64 // Minimum share of indicators agreeing with the action for "high"
+	PriorityMediumConfidence float64 // Minimum confidence for "medium"; anything below is "low"
+	PriorityWebhookURL       string  // Discord-compatible webhook that also receives priority signals; empty disables
+	PriorityWebhookMin       string  // Lowest priority routed to the webhook: low, medium or high
+
+	// API Keys. Each *_API_KEY variable accepts a comma-separated list that is
+	// rotated on quota/rate-limit errors; the single-key fields hold the first.
 	CoinMarketCapAPIKey string
 	CoinGeckoAPIKey     string
+	CoinGeckoProAPIKey  string
+	CoinMarketCapAPIKeys []string
+	CoinGeckoAPIKeys     []string
+	CoinGeckoProAPIKeys  []string
+	CoinGeckoBaseURL    string
+	CMCMonthlyCreditLimit int // Used to estimate remaining CMC credits; 0 disables the estimate
 	BinanceAPIKey       string
 	BinanceSecret       string
 
+	// Market Data
+	KlineSources []string // Kline providers tried in order: binance, coingecko
+
 	// Bot Settings
-	MinConfidenceThreshold   float64
+	MinConfidenceThreshold   float64 // Floor for recording a signal
+	NotifyConfidenceThreshold float64 // Bar for pushing a signal notification
+	MinDataQuality           float64 // Don't notify signals scored below this data quality (0-1); 0 disables
+	ConfidenceNormalization  string // "ratio" or "directional"
+	ConfidenceSmoothingAlpha float64 // EMA weight of the latest cycle's confidence; 1 disables smoothing
+	MaxSignalAgeMinutes      int     // Suppress notifications for older signals; 0 disables
+	MaxEntryDeviationPercent float64 // Suppress when live price moved this far from entry; 0 disables
 	MaxSignalsPerDay         int
+	MaxSignalsPerCycle       int     // Keep only the strongest N signals of one analysis cycle; 0 keeps all
+	DryRun                   bool    // Log signals without storing or sending them; toggled at runtime with /dryrun
+	MaxDrawdownPercent       float64 // Pause signal generation past this drawdown from the equity peak; 0 disables
+	DrawdownAutoResumeHours  int     // Resume a drawdown pause automatically after this long; 0 requires /resume
 	AnalysisIntervalMinutes  int
 	AnalysisIntervalSeconds  int
+	KlineInterval            string   // Kline timeframe of the regular analysis cycle, e.g. "15m"
+	AnalysisCandleCloses     []string // Run analysis on these timeframes' candle closes instead of the fixed interval
+	AnalysisCandleCloseDelaySeconds int // Wait after a close so providers have finalized the candle
+	WarmupMaxSeconds         int
+	MaxDataAgeSeconds        int  // Skip signal generation when the provider quote is older; 0 disables
+	MarketDataCacheSeconds   int  // Reuse a coin's fetched market data for this long; 0 disables
+	HTTPMaxRetries           int  // Retries of a failed provider request (network error, 429, 5xx); 0 disables
+	HTTPRetryBaseDelayMS     int  // Backoff before the first retry, doubled per retry and jittered
+	PruneFailureThreshold    int  // Flag coins without market data for this many cycles in a row; 0 disables
+	AutoPrune                bool // Deactivate flagged coins instead of only notifying
+	SkipRemovedDefaults      bool // Don't re-seed default coins the user removed; /resetwatchlist restores them
+	ExcludedSymbols          []string // Stablecoins and wrapped tokens that can't be added to the watchlist
+	CycleSlowMultiplier      float64 // Alert when a cycle takes this many times the rolling average; 0 disables
+	CycleMaxSeconds          int     // Alert when a cycle takes longer than this; 0 disables
+	AnalysisRetryAttempts    int
+	AnalysisRetryBackoffSeconds int
+	AnalyticsCacheTTLSeconds int
+	EntryZoneMode            string  // "off", "percent" or "atr"
+	EntryZonePercent         float64 // Half-width of a percent entry zone around the signal price
+	EntryZoneATRMultiplier   float64 // Half-width of an ATR entry zone, in ATRs
 	StopLossPercentage       float64
 	TakeProfit1Percentage    float64
 	TakeProfit2Percentage    float64
+	TakeProfit3Percentage    float64
+	TakeProfitCount          int
+	TakeProfitMode           string    // "percent" or "absolute" (price distance from entry)
+	TakeProfitLevels         []float64 // Distance of each TP from entry, interpreted per TakeProfitMode
+	TakeProfitAllocations    []float64 // Share of the position closed at each TP, in percent
+	BuyStopLossPercentage    float64   // Stop loss % of BUY signals; 0 uses StopLossPercentage
+	SellStopLossPercentage   float64   // Stop loss % of SELL signals; 0 uses StopLossPercentage
+	BuyTakeProfitLevels      []float64 // TP levels of BUY signals; empty uses TakeProfitLevels
+	SellTakeProfitLevels     []float64 // TP levels of SELL signals; empty uses TakeProfitLevels
+	SignalMaxHoldHours       int       // Close tracked signals at market after this long; 0 waits for SL or the last TP
+
+	// Rounding of stored signal values (decimal places)
+	SignalPricePrecision      int
+	SignalIndicatorPrecision  int
+	SignalConfidencePrecision int
 
 	// Technical Analysis
+	CandleType              string // "normal" or "heikin_ashi" candles for the indicators
+	IndicatorMinKlines      map[string]int // Candles required per indicator, raised to what its periods need
+	RSIPeriod               int
+	MACDFastPeriod          int // Also the fast EMA of the trend check
+	MACDSlowPeriod          int // Also the slow EMA of the trend check
+	MACDSignalPeriod        int
+	BBPeriod                int // Also the SMA of the trend check
+	BBStdDev                float64
 	RSIOversoldThreshold    float64
 	RSIOverboughtThreshold  float64
 	FearGreedMinThreshold   int
 	FearGreedMaxThreshold   int
+	FearGreedSources        []string // Tried in order: alternative_me, coinmarketcap
+	FearGreedRequireLive    bool     // Drop the Fear & Greed factor when every source failed
+	UseVolumeProfile        bool // Snap SL/TP to high-volume nodes instead of fixed percentages
+	VolumeProfileBins       int
+	VolumeProfileNodes      int  // Maximum number of high-volume nodes kept as S/R levels
+	UseSwingLevels          bool    // Place SL/TP beyond recent swing lows/highs instead of fixed percentages
+	SwingLookback           int     // Candles scanned for swing pivots
+	SwingPivotStrength      int     // Candles on each side a pivot must exceed
+	SwingBufferPercent      float64 // Distance kept from a swing level, in percent
+	UseFibTargets           bool      // Place TPs at Fibonacci extensions and SL beyond a retracement of the recent swing
+	FibExtensionLevels      []float64 // Extensions of the swing range for TP1, TP2, ..., ascending
+	FibStopRetracement      float64   // Retracement of the swing range the stop is placed beyond
+	UseATRStops             bool    // Place SL/TP at multiples of ATR(14) instead of fixed percentages
+	ATRStopMultiplier       float64 // Stop distance from entry, in ATRs
+	ATRTakeProfitMultiplier float64 // Distance between entry and each take-profit level, in ATRs
+	MinListingAgeDays       int  // Skip signals for coins listed more recently; 0 disables
+	ConflictMode            string  // "reduce", "hold" or "off" when buy and sell indicators are close in weight
+	ConflictRatio           float64 // Minority/majority weight ratio at which indicators count as conflicting
+	ConflictPenalty         float64 // Confidence multiplier in "reduce" mode
+	HTFConfirmationEnabled  bool
+	HTFInterval             string  // Higher timeframe used for trend confirmation, e.g. "4h"
+	HTFConflictMode         string  // "suppress" or "reduce" when the HTF trend disagrees; "require" also needs the base trend to agree
+	HTFConfidencePenalty    float64 // Confidence multiplier in "reduce" mode
+	HTFAlignmentBonus       float64 // Confidence added when the base and HTF trends both agree with the signal
+	DailyTrendEnabled       bool
+	DailyTrendEMAPeriod     int     // Daily EMA the last daily close is compared with
+	DailyTrendBonus         float64 // Confidence added to signals with the daily trend
+	DailyTrendPenalty       float64 // Confidence subtracted from counter-trend signals
+	SourceAgreementEnabled          bool
+	SourceAgreementTolerancePercent float64 // Max price spread across providers that counts as agreement
+	SourceAgreementChangeTolerance  float64 // Max 24h change spread, in percentage points, that counts as agreement
+	SourceAgreementBonus            float64 // Confidence added when the providers agree
+	SourceDivergencePercent         float64 // Price spread at which the providers are considered diverged
+	SourceDivergenceMode            string  // "penalty" or "suppress" diverged signals
+	SourceDivergencePenalty         float64 // Confidence subtracted in "penalty" mode
+	VolatilityWindowEnabled bool
+	VolatilityWindowStarts  []string // UTC "HH:MM" resets/session opens that start a window
+	VolatilityWindowMinutes int
+	VolatilityWindowMode    string  // "skip" or "reduce" signals inside a window
+	VolatilityWindowPenalty float64 // Confidence multiplier in "reduce" mode
+	StoreSignalContext      bool // Persist recent candles and indicator series with each signal
+	SignalContextCandles    int
+	LogRejectedSignals      bool // Persist setups dropped by a filter to rejected_signals
 
 	// Learning
 	LearningEnabled  bool
 	BacktestEnabled  bool
+	BacktestMaxCandles int // Most candles one backtest may replay, warmup included
+	LearningHalfLifeDays float64 // Age at which an outcome counts half as much; 0 disables decay
 
 	// Server
 	Port     string
 	APIPort  int
 	LogLevel string
+	RequestLogLevel string // Level for the API access log: debug, info, ... or "off"
+	DebugHTTP       bool   // Log outbound provider/REST requests and raw responses at debug level
 	Environment string
+	AdminAPIToken string // X-API-Key granting the admin role that destructive API endpoints require
+	APIKey        string // X-API-Key required by /api/v1 write requests; empty leaves the API open
+	APIKeyProtectReads bool // Require the API key on GET requests too
+	WSAllowedOrigins []string // Browser origins allowed on the signal stream besides the API's own host; "*" allows any
+	StartupProbeTimeoutSeconds int      // Time allowed for the parallel startup connection probes
+	StartupCriticalProbes      []string // Probes whose failure aborts startup: telegram, database, data_sources
+	TradingViewWebhookSecret string // Shared secret for TradingView alerts; empty disables the webhook
 }
 
 func Load() *Config {
-	return &Config{
+	cfg := &Config{
+		// Storage
+		StorageBackend: getEnv("STORAGE_BACKEND", "supabase"),
+
 		// Supabase
 		SupabaseURL:        getEnv("SUPABASE_URL", ""),
 		SupabaseAnonKey:    getEnv("SUPABASE_ANON_KEY", ""),
 		SupabaseServiceKey: getEnv("SUPABASE_SERVICE_KEY", ""),
 
+		DBConnectRetries:           getEnvInt("DB_CONNECT_RETRIES", 3),
+		DBRetryBaseDelaySeconds:    getEnvInt("DB_RETRY_BASE_DELAY_SECONDS", 2),
+		DBRetryMaxWaitSeconds:      getEnvInt("DB_RETRY_MAX_WAIT_SECONDS", 60),
+		DBReconnectIntervalSeconds: getEnvInt("DB_RECONNECT_INTERVAL_SECONDS", 60),
+
 		// Telegram
 		TelegramBotToken: getEnv("TELEGRAM_BOT_TOKEN", ""),
 		TelegramChatID:   getEnv("TELEGRAM_CHAT_ID", ""),
 
+		// Notifications
+		SparklineEnabled: getEnvBool("SPARKLINE_ENABLED", false),
+		SparklinePoints:  getEnvInt("SPARKLINE_POINTS", 24),
+		NotificationMode:      getEnv("NOTIFICATION_MODE", "instant"),
+		DigestIntervalMinutes: getEnvInt("DIGEST_INTERVAL_MINUTES", 60),
+		DigestInstantPriority: getEnv("DIGEST_INSTANT_PRIORITY", "high"),
+		OutboxMaxAttempts:     getEnvInt("OUTBOX_MAX_ATTEMPTS", 5),
+
 		// WhatsApp
-		WhatsAppEnabled: getEnvBool("WHATSAPP_ENABLED", false),
-		WhatsAppAPIURL:  getEnv("WHATSAPP_API_URL", ""),
-		WhatsAppToken:   getEnv("WHATSAPP_API_TOKEN", ""),
+		WhatsAppEnabled:          getEnvBool("WHATSAPP_ENABLED", false),
+		WhatsAppAPIURL:           getEnv("WHATSAPP_API_URL", ""),
+		WhatsAppToken:            getEnv("WHATSAPP_API_TOKEN", ""),
+		WhatsAppRecipient:        getEnv("WHATSAPP_RECIPIENT", ""),
+		WhatsAppMessageType:      getEnv("WHATSAPP_MESSAGE_TYPE", "text"),
+		WhatsAppTemplateName:     getEnv("WHATSAPP_TEMPLATE_NAME", ""),
+		WhatsAppTemplateLanguage: getEnv("WHATSAPP_TEMPLATE_LANGUAGE", "en_US"),
+
+		// Discord
+		DiscordEnabled:    getEnvBool("DISCORD_ENABLED", false),
+		DiscordWebhookURL: getEnv("DISCORD_WEBHOOK_URL", ""),
+
+		// Signal priority
+		PriorityHighConfidence:   getEnvFloat("PRIORITY_HIGH_CONFIDENCE", 0.85),
+		PriorityHighAgreement:    getEnvFloat("PRIORITY_HIGH_AGREEMENT", 0.75),
+		PriorityMediumConfidence: getEnvFloat("PRIORITY_MEDIUM_CONFIDENCE", 0.75),
+		PriorityWebhookURL:       getEnv("PRIORITY_WEBHOOK_URL", ""),
+		PriorityWebhookMin:       getEnv("PRIORITY_WEBHOOK_MIN", "high"),
 
 		// API Keys
-		CoinMarketCapAPIKey: getEnv("COINMARKETCAP_API_KEY", ""),
-		CoinGeckoAPIKey:     getEnv("COINGECKO_API_KEY", ""),
+		CoinMarketCapAPIKeys: getEnvList("COINMARKETCAP_API_KEY", nil),
+		CoinGeckoAPIKeys:     getEnvList("COINGECKO_API_KEY", nil),
+		CoinGeckoProAPIKeys:  getEnvList("COINGECKO_PRO_API_KEY", nil),
+		CoinGeckoBaseURL:    getEnv("COINGECKO_BASE_URL", ""),
+		CMCMonthlyCreditLimit: getEnvInt("CMC_MONTHLY_CREDIT_LIMIT", 10000),
 		BinanceAPIKey:       getEnv("BINANCE_API_KEY", ""),
 		BinanceSecret:       getEnv("BINANCE_SECRET_KEY", ""),
 
+		// Market Data
+		KlineSources: getEnvList("KLINE_SOURCES", []string{"binance", "coingecko"}),
+
 		// Bot Settings
 		MinConfidenceThreshold:  getEnvFloat("MIN_CONFIDENCE_THRESHOLD", 0.70),
+		ConfidenceNormalization: getEnv("CONFIDENCE_NORMALIZATION", "ratio"),
+		ConfidenceSmoothingAlpha: getEnvFloat("CONFIDENCE_SMOOTHING_ALPHA", 1),
+		MinDataQuality:          getEnvFloat("MIN_DATA_QUALITY", 0),
+		MaxSignalAgeMinutes:      getEnvInt("MAX_SIGNAL_AGE_MINUTES", 30),
+		MaxEntryDeviationPercent: getEnvFloat("MAX_ENTRY_DEVIATION_PERCENT", 1.5),
 		MaxSignalsPerDay:        getEnvInt("MAX_SIGNALS_PER_DAY", 10),
+		MaxSignalsPerCycle:      getEnvInt("MAX_SIGNALS_PER_CYCLE", 0),
+		DryRun:                  getEnvBool("DRY_RUN", false),
+		MaxDrawdownPercent:      getEnvFloat("MAX_DRAWDOWN_PERCENT", 20),
+		DrawdownAutoResumeHours: getEnvInt("DRAWDOWN_AUTO_RESUME_HOURS", 0),
 		AnalysisIntervalMinutes: getEnvInt("ANALYSIS_INTERVAL_MINUTES", 15),
 		AnalysisIntervalSeconds: getEnvInt("ANALYSIS_INTERVAL_SECONDS", 900), // 15 minutes
+		KlineInterval:           getEnv("KLINE_INTERVAL", "15m"),
+		AnalysisCandleCloses:    getEnvList("ANALYSIS_CANDLE_CLOSES", nil),
+		AnalysisCandleCloseDelaySeconds: getEnvInt("ANALYSIS_CANDLE_CLOSE_DELAY_SECONDS", 5),
+		WarmupMaxSeconds:        getEnvInt("WARMUP_MAX_SECONDS", 60),
+		MaxDataAgeSeconds:       getEnvInt("MAX_DATA_AGE_SECONDS", 600),
+		MarketDataCacheSeconds:  getEnvInt("MARKET_DATA_CACHE_SECONDS", 60),
+		HTTPMaxRetries:          getEnvInt("HTTP_MAX_RETRIES", 3),
+		HTTPRetryBaseDelayMS:    getEnvInt("HTTP_RETRY_BASE_DELAY_MS", 500),
+		PruneFailureThreshold:   getEnvInt("PRUNE_FAILURE_THRESHOLD", 10),
+		AutoPrune:               getEnvBool("AUTO_PRUNE", false),
+		SkipRemovedDefaults:     getEnvBool("SKIP_REMOVED_DEFAULTS", true),
+		ExcludedSymbols:         getEnvList("EXCLUDED_SYMBOLS", []string{
+			"USDT", "USDC", "BUSD", "DAI", "TUSD", "USDP", "FDUSD", "USDD", "PYUSD", "GUSD", "FRAX", "LUSD", "USDE", "EURC",
+			"WBTC", "WETH", "WBNB", "STETH", "WSTETH", "CBETH", "RETH", "WEETH",
+		}),
+		CycleSlowMultiplier:     getEnvFloat("CYCLE_SLOW_MULTIPLIER", 2),
+		CycleMaxSeconds:         getEnvInt("CYCLE_MAX_SECONDS", 0),
+		AnalysisRetryAttempts:   getEnvInt("ANALYSIS_RETRY_ATTEMPTS", 2),
+		AnalysisRetryBackoffSeconds: getEnvInt("ANALYSIS_RETRY_BACKOFF_SECONDS", 30),
+		AnalyticsCacheTTLSeconds: getEnvInt("ANALYTICS_CACHE_TTL_SECONDS", 300),
+		EntryZoneMode:           getEnv("ENTRY_ZONE_MODE", "off"),
+		EntryZonePercent:        getEnvFloat("ENTRY_ZONE_PERCENT", 0.5),
+		EntryZoneATRMultiplier:  getEnvFloat("ENTRY_ZONE_ATR_MULTIPLIER", 0.5),
 		StopLossPercentage:      getEnvFloat("STOP_LOSS_PERCENTAGE", 5.0),
 		TakeProfit1Percentage:   getEnvFloat("TAKE_PROFIT_1_PERCENTAGE", 3.0),
 		TakeProfit2Percentage:   getEnvFloat("TAKE_PROFIT_2_PERCENTAGE", 6.0),
+		TakeProfit3Percentage:   getEnvFloat("TAKE_PROFIT_3_PERCENTAGE", 9.0),
+		TakeProfitCount:         getEnvInt("TAKE_PROFIT_COUNT", 2),
+		TakeProfitMode:          getEnv("TAKE_PROFIT_MODE", "percent"),
+		TakeProfitAllocations:   getEnvFloatList("TAKE_PROFIT_ALLOCATIONS", nil),
+		BuyStopLossPercentage:   getEnvFloat("BUY_STOP_LOSS_PERCENTAGE", 0),
+		SellStopLossPercentage:  getEnvFloat("SELL_STOP_LOSS_PERCENTAGE", 0),
+		BuyTakeProfitLevels:     getEnvFloatList("BUY_TAKE_PROFIT_LEVELS", nil),
+		SellTakeProfitLevels:    getEnvFloatList("SELL_TAKE_PROFIT_LEVELS", nil),
+		SignalMaxHoldHours:      getEnvInt("SIGNAL_MAX_HOLD_HOURS", 72),
+
+		// Rounding of stored signal values
+		SignalPricePrecision:      getEnvInt("SIGNAL_PRICE_PRECISION", 8),
+		SignalIndicatorPrecision:  getEnvInt("SIGNAL_INDICATOR_PRECISION", 2),
+		SignalConfidencePrecision: getEnvInt("SIGNAL_CONFIDENCE_PRECISION", 4),
 
 		// Technical Analysis
+		CandleType:             getEnv("CANDLE_TYPE", "normal"),
+		IndicatorMinKlines:     getEnvIntMap("INDICATOR_MIN_KLINES", map[string]int{}),
+		RSIPeriod:              getEnvInt("RSI_PERIOD", 14),
+		MACDFastPeriod:         getEnvInt("MACD_FAST_PERIOD", 12),
+		MACDSlowPeriod:         getEnvInt("MACD_SLOW_PERIOD", 26),
+		MACDSignalPeriod:       getEnvInt("MACD_SIGNAL_PERIOD", 9),
+		BBPeriod:               getEnvInt("BB_PERIOD", 20),
+		BBStdDev:               getEnvFloat("BB_STD_DEV", 2),
 		RSIOversoldThreshold:   getEnvFloat("RSI_OVERSOLD_THRESHOLD", 30),
 		RSIOverboughtThreshold: getEnvFloat("RSI_OVERBOUGHT_THRESHOLD", 70),
 		FearGreedMinThreshold:  getEnvInt("FEAR_GREED_MIN_THRESHOLD", 20),
 		FearGreedMaxThreshold:  getEnvInt("FEAR_GREED_MAX_THRESHOLD", 80),
+		FearGreedSources:       getEnvList("FEAR_GREED_SOURCES", []string{"alternative_me", "coinmarketcap"}),
+		FearGreedRequireLive:   getEnvBool("FEAR_GREED_REQUIRE_LIVE", false),
+		UseVolumeProfile:       getEnvBool("USE_VOLUME_PROFILE", false),
+		VolumeProfileBins:      getEnvInt("VOLUME_PROFILE_BINS", 24),
+		VolumeProfileNodes:     getEnvInt("VOLUME_PROFILE_NODES", 5),
+		UseSwingLevels:         getEnvBool("USE_SWING_LEVELS", false),
+		SwingLookback:          getEnvInt("SWING_LOOKBACK", 50),
+		SwingPivotStrength:     getEnvInt("SWING_PIVOT_STRENGTH", 3),
+		SwingBufferPercent:     getEnvFloat("SWING_BUFFER_PERCENT", 0.2),
+		UseFibTargets:          getEnvBool("USE_FIB_TARGETS", false),
+		FibExtensionLevels:     getEnvFloatList("FIB_EXTENSION_LEVELS", []float64{1.272, 1.618}),
+		FibStopRetracement:     getEnvFloat("FIB_STOP_RETRACEMENT", 0.786),
+		UseATRStops:             getEnvBool("USE_ATR_STOPS", false),
+		ATRStopMultiplier:       getEnvFloat("ATR_STOP_MULTIPLIER", 1.5),
+		ATRTakeProfitMultiplier: getEnvFloat("ATR_TP_MULTIPLIER", 2),
+		MinListingAgeDays:      getEnvInt("MIN_LISTING_AGE_DAYS", 30),
+		ConflictMode:           getEnv("CONFLICT_MODE", "reduce"),
+		ConflictRatio:          getEnvFloat("CONFLICT_RATIO", 0.6),
+		ConflictPenalty:        getEnvFloat("CONFLICT_PENALTY", 0.7),
+		HTFConfirmationEnabled: getEnvBool("HTF_CONFIRMATION_ENABLED", false),
+		HTFInterval:            getEnv("HTF_INTERVAL", "4h"),
+		HTFConflictMode:        getEnv("HTF_CONFLICT_MODE", "reduce"),
+		HTFConfidencePenalty:   getEnvFloat("HTF_CONFIDENCE_PENALTY", 0.7),
+		HTFAlignmentBonus:      getEnvFloat("HTF_ALIGNMENT_BONUS", 0.05),
+		DailyTrendEnabled:      getEnvBool("DAILY_TREND_ENABLED", false),
+		DailyTrendEMAPeriod:    getEnvInt("DAILY_TREND_EMA_PERIOD", 20),
+		DailyTrendBonus:        getEnvFloat("DAILY_TREND_BONUS", 0.05),
+		DailyTrendPenalty:      getEnvFloat("DAILY_TREND_PENALTY", 0.1),
+		SourceAgreementEnabled:          getEnvBool("SOURCE_AGREEMENT_ENABLED", false),
+		SourceAgreementTolerancePercent: getEnvFloat("SOURCE_AGREEMENT_TOLERANCE_PERCENT", 0.5),
+		SourceAgreementChangeTolerance:  getEnvFloat("SOURCE_AGREEMENT_CHANGE_TOLERANCE", 1),
+		SourceAgreementBonus:            getEnvFloat("SOURCE_AGREEMENT_BONUS", 0.05),
+		SourceDivergencePercent:         getEnvFloat("SOURCE_DIVERGENCE_PERCENT", 2),
+		SourceDivergenceMode:            getEnv("SOURCE_DIVERGENCE_MODE", "penalty"),
+		SourceDivergencePenalty:         getEnvFloat("SOURCE_DIVERGENCE_PENALTY", 0.1),
+		VolatilityWindowEnabled: getEnvBool("VOLATILITY_WINDOW_ENABLED", false),
+		VolatilityWindowStarts:  getEnvList("VOLATILITY_WINDOW_STARTS", []string{"00:00"}),
+		VolatilityWindowMinutes: getEnvInt("VOLATILITY_WINDOW_MINUTES", 15),
+		VolatilityWindowMode:    getEnv("VOLATILITY_WINDOW_MODE", "skip"),
+		VolatilityWindowPenalty: getEnvFloat("VOLATILITY_WINDOW_PENALTY", 0.8),
+		StoreSignalContext:     getEnvBool("STORE_SIGNAL_CONTEXT", false),
+		SignalContextCandles:   getEnvInt("SIGNAL_CONTEXT_CANDLES", 50),
+		LogRejectedSignals:     getEnvBool("LOG_REJECTED_SIGNALS", false),
 
 		// Learning
 		LearningEnabled: getEnvBool("LEARNING_ENABLED", true),
 		BacktestEnabled: getEnvBool("BACKTEST_ENABLED", true),
+		BacktestMaxCandles: getEnvInt("BACKTEST_MAX_CANDLES", 5000),
+		LearningHalfLifeDays: getEnvFloat("LEARNING_HALF_LIFE_DAYS", 14),
 
 		// Server
 		Port:        getEnv("PORT", "8080"),
 		APIPort:     getEnvInt("API_PORT", 8080),
 		LogLevel:    getEnv("LOG_LEVEL", "info"),
+		RequestLogLevel: getEnv("REQUEST_LOG_LEVEL", "debug"),
+		DebugHTTP:   getEnvBool("DEBUG_HTTP", false),
 		Environment: getEnv("ENVIRONMENT", "development"),
+		AdminAPIToken: getEnv("ADMIN_API_TOKEN", ""),
+		APIKey:        getEnv("API_KEY", ""),
+		APIKeyProtectReads: getEnvBool("API_KEY_PROTECT_READS", false),
+		WSAllowedOrigins: getEnvList("WS_ALLOWED_ORIGINS", nil),
+		StartupProbeTimeoutSeconds: getEnvInt("STARTUP_PROBE_TIMEOUT_SECONDS", 10),
+		StartupCriticalProbes:      getEnvList("STARTUP_CRITICAL_PROBES", nil),
+		TradingViewWebhookSecret: getEnv("TRADINGVIEW_WEBHOOK_SECRET", ""),
 	}
+
+	// Broadcast to TELEGRAM_CHAT_IDS, falling back to the single TELEGRAM_CHAT_ID;
+	// without a TELEGRAM_CHAT_ID the first listed chat is the owner
+	cfg.TelegramChatIDs = getEnvList("TELEGRAM_CHAT_IDS", getEnvList("TELEGRAM_CHAT_ID", nil))
+	if cfg.TelegramChatID == "" {
+		cfg.TelegramChatID = firstOrEmpty(cfg.TelegramChatIDs)
+	}
+	// Chats that receive the notifications may use the bot unless narrowed down
+	cfg.AuthorizedChatIDs = getEnvList("AUTHORIZED_CHAT_IDS", cfg.TelegramChatIDs)
+	cfg.TelegramPublicCommands = getEnvList("TELEGRAM_PUBLIC_COMMANDS", []string{"start", "help"})
+
+	cfg.CoinMarketCapAPIKey = firstOrEmpty(cfg.CoinMarketCapAPIKeys)
+	cfg.CoinGeckoAPIKey = firstOrEmpty(cfg.CoinGeckoAPIKeys)
+	cfg.CoinGeckoProAPIKey = firstOrEmpty(cfg.CoinGeckoProAPIKeys)
+
+	// Notify on everything recorded unless a higher bar is configured
+	cfg.NotifyConfidenceThreshold = getEnvFloat("NOTIFY_CONFIDENCE_THRESHOLD", cfg.MinConfidenceThreshold)
+
+	// Take-profit levels default to the individual TP percentages
+	cfg.TakeProfitLevels = getEnvFloatList("TAKE_PROFIT_LEVELS", []float64{
+		cfg.TakeProfit1Percentage,
+		cfg.TakeProfit2Percentage,
+		cfg.TakeProfit3Percentage,
+	})
+
+	return cfg
 }
 
 func getEnv(key, defaultValue string) string {
@@ -127,6 +451,64 @@ func getEnvFloat(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func getEnvFloatList(key string, defaultValue []float64) []float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var values []float64
+	for _, part := range strings.Split(value, ",") {
+		floatValue, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return defaultValue
+		}
+		values = append(values, floatValue)
+	}
+	return values
+}
+
+// getEnvIntMap parses "key:value" pairs separated by commas, e.g. "rsi:30,macd:50"
+func getEnvIntMap(key string, defaultValue map[string]int) map[string]int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	values := make(map[string]int)
+	for _, part := range strings.Split(value, ",") {
+		name, number, found := strings.Cut(part, ":")
+		intValue, err := strconv.Atoi(strings.TrimSpace(number))
+		if !found || err != nil {
+			return defaultValue
+		}
+		values[strings.ToLower(strings.TrimSpace(name))] = intValue
+	}
+	return values
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		return strings.ToLower(value) == "true"
@@ -134,7 +516,145 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// klineIntervals are the kline intervals Binance serves
+var klineIntervals = map[string]bool{
+	"1m": true, "3m": true, "5m": true, "15m": true, "30m": true,
+	"1h": true, "2h": true, "4h": true, "6h": true, "8h": true, "12h": true,
+	"1d": true, "3d": true, "1w": true, "1M": true,
+}
+
 func (c *Config) Validate() error {
-	// Add validation logic here
+	// Indicator periods
+	if c.RSIPeriod < 2 {
+		return fmt.Errorf("RSI_PERIOD must be at least 2, got %d", c.RSIPeriod)
+	}
+	if c.MACDFastPeriod < 1 || c.MACDSignalPeriod < 1 {
+		return fmt.Errorf("MACD_FAST_PERIOD and MACD_SIGNAL_PERIOD must be at least 1, got %d and %d", c.MACDFastPeriod, c.MACDSignalPeriod)
+	}
+	if c.MACDFastPeriod >= c.MACDSlowPeriod {
+		return fmt.Errorf("MACD_FAST_PERIOD (%d) must be less than MACD_SLOW_PERIOD (%d)", c.MACDFastPeriod, c.MACDSlowPeriod)
+	}
+	if c.BBPeriod < 2 {
+		return fmt.Errorf("BB_PERIOD must be at least 2, got %d", c.BBPeriod)
+	}
+	if c.BBStdDev <= 0 {
+		return fmt.Errorf("BB_STD_DEV must be positive, got %g", c.BBStdDev)
+	}
+
+	// Modes
+	switch c.ConfidenceNormalization {
+	case "ratio", "directional":
+	default:
+		return fmt.Errorf("CONFIDENCE_NORMALIZATION must be ratio or directional, got %q", c.ConfidenceNormalization)
+	}
+	switch c.TakeProfitMode {
+	case "percent", "absolute":
+	default:
+		return fmt.Errorf("TAKE_PROFIT_MODE must be percent or absolute, got %q", c.TakeProfitMode)
+	}
+	switch c.ConflictMode {
+	case "reduce", "hold", "off":
+	default:
+		return fmt.Errorf("CONFLICT_MODE must be reduce, hold or off, got %q", c.ConflictMode)
+	}
+	switch c.NotificationMode {
+	case "instant", "digest":
+	default:
+		return fmt.Errorf("NOTIFICATION_MODE must be instant or digest, got %q", c.NotificationMode)
+	}
+
+	// Timeframes
+	if !klineIntervals[c.KlineInterval] {
+		return fmt.Errorf("KLINE_INTERVAL must be a Binance kline interval (1m ... 1M), got %q", c.KlineInterval)
+	}
+	if c.HTFConfirmationEnabled {
+		if !klineIntervals[c.HTFInterval] {
+			return fmt.Errorf("HTF_INTERVAL must be a Binance kline interval (1m ... 1M), got %q", c.HTFInterval)
+		}
+		switch c.HTFConflictMode {
+		case "reduce", "suppress", "require":
+		default:
+			return fmt.Errorf("HTF_CONFLICT_MODE must be reduce, suppress or require, got %q", c.HTFConflictMode)
+		}
+		if c.HTFAlignmentBonus < 0 || c.HTFAlignmentBonus > 1 {
+			return fmt.Errorf("HTF_ALIGNMENT_BONUS must be between 0 and 1, got %g", c.HTFAlignmentBonus)
+		}
+	}
+
+	if c.MarketDataCacheSeconds < 0 {
+		return fmt.Errorf("MARKET_DATA_CACHE_SECONDS must not be negative, got %d", c.MarketDataCacheSeconds)
+	}
+	if c.HTTPMaxRetries < 0 {
+		return fmt.Errorf("HTTP_MAX_RETRIES must not be negative, got %d", c.HTTPMaxRetries)
+	}
+	if c.HTTPRetryBaseDelayMS <= 0 {
+		return fmt.Errorf("HTTP_RETRY_BASE_DELAY_MS must be positive, got %d", c.HTTPRetryBaseDelayMS)
+	}
+
+	// WhatsApp
+	if c.WhatsAppEnabled {
+		if c.WhatsAppAPIURL == "" || c.WhatsAppToken == "" || c.WhatsAppRecipient == "" {
+			return fmt.Errorf("WHATSAPP_ENABLED needs WHATSAPP_API_URL, WHATSAPP_API_TOKEN and WHATSAPP_RECIPIENT")
+		}
+		switch c.WhatsAppMessageType {
+		case "text":
+		case "template":
+			if c.WhatsAppTemplateName == "" {
+				return fmt.Errorf("WHATSAPP_MESSAGE_TYPE=template needs WHATSAPP_TEMPLATE_NAME")
+			}
+		default:
+			return fmt.Errorf("WHATSAPP_MESSAGE_TYPE must be text or template, got %q", c.WhatsAppMessageType)
+		}
+	}
+
+	if c.SignalMaxHoldHours < 0 {
+		return fmt.Errorf("SIGNAL_MAX_HOLD_HOURS must not be negative, got %d", c.SignalMaxHoldHours)
+	}
+
+	// Take profits; a SELL percentage of 100 or more puts the target at or below zero
+	if c.TakeProfitMode != "absolute" {
+		sellLevels := c.SellTakeProfitLevels
+		if len(sellLevels) == 0 {
+			sellLevels = c.TakeProfitLevels
+		}
+		for _, level := range sellLevels {
+			if level >= 100 {
+				return fmt.Errorf("SELL take profit percentages must be below 100, got %g", level)
+			}
+		}
+	}
+
+	// Fibonacci targets
+	if c.UseFibTargets {
+		if len(c.FibExtensionLevels) == 0 {
+			return fmt.Errorf("USE_FIB_TARGETS needs at least one FIB_EXTENSION_LEVELS value")
+		}
+		for i, level := range c.FibExtensionLevels {
+			if level <= 1 || (i > 0 && level <= c.FibExtensionLevels[i-1]) {
+				return fmt.Errorf("FIB_EXTENSION_LEVELS must be ascending and above 1, got %v", c.FibExtensionLevels)
+			}
+		}
+		if c.FibStopRetracement <= 0 || c.FibStopRetracement >= 1 {
+			return fmt.Errorf("FIB_STOP_RETRACEMENT must be between 0 and 1, got %g", c.FibStopRetracement)
+		}
+	}
+
+	// Discord
+	if c.DiscordEnabled && c.DiscordWebhookURL == "" {
+		return fmt.Errorf("DISCORD_ENABLED needs DISCORD_WEBHOOK_URL")
+	}
+
+	// API authentication
+	if c.APIKeyProtectReads && c.APIKey == "" {
+		return fmt.Errorf("API_KEY_PROTECT_READS needs API_KEY")
+	}
+	if c.AdminAPIToken != "" && c.AdminAPIToken == c.APIKey {
+		return fmt.Errorf("ADMIN_API_TOKEN must differ from API_KEY")
+	}
+
+	// Backtesting
+	if c.BacktestEnabled && c.BacktestMaxCandles <= 0 {
+		return fmt.Errorf("BACKTEST_MAX_CANDLES must be positive, got %d", c.BacktestMaxCandles)
+	}
 	return nil
 }
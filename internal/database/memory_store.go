@@ -0,0 +1,764 @@
+package database
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// MemoryStore is an in-process Store for local development and tests.
+// Nothing is persisted across restarts. It stands in for the SQLite backend
+// once planned: that needs a driver dependency the module doesn't take on, so
+// persisting locally means a Postgres reached through the DB_* variables.
+type MemoryStore struct {
+	mu           sync.RWMutex
+	signals      []*models.TradingSignal
+	performance  []*models.SignalPerformance
+	snapshots    []*models.MarketSnapshot
+	learningData []*models.LearningData
+	cryptos      map[string]*models.Cryptocurrency // keyed by symbol
+	profiles     map[string]*models.StrategyProfile // keyed by symbol
+	removed      map[string]bool                    // default coins the user removed
+	rejected     []*models.RejectedSignal
+	outbox       []*models.OutboxEntry
+	systemLogs   []*models.SystemLog
+	notifyLogs   []*models.NotificationLog
+	settings     map[string]*models.BotSetting // keyed by setting_key
+}
+
+// memorySystemLogLimit caps the log entries kept in memory, oldest dropped
+// first; notification logs are capped the same way
+const memorySystemLogLimit = 500
+
+// Compile-time check that MemoryStore satisfies Store
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		cryptos:  make(map[string]*models.Cryptocurrency),
+		profiles: make(map[string]*models.StrategyProfile),
+		removed:  make(map[string]bool),
+		settings: make(map[string]*models.BotSetting),
+	}
+}
+
+func (m *MemoryStore) Close() error          { return nil }
+func (m *MemoryStore) Ping() error           { return nil }
+func (m *MemoryStore) TestConnection() error { return nil }
+func (m *MemoryStore) Mode() string          { return "memory" }
+
+// Signal operations
+func (m *MemoryStore) CreateSignal(signal *models.TradingSignal, outbox *models.OutboxEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if signal.RefCode != "" {
+		for _, existing := range m.signals {
+			if existing.RefCode == signal.RefCode {
+				return fmt.Errorf("signal with ref code %s already exists", signal.RefCode)
+			}
+		}
+	}
+
+	stored := *signal
+	m.signals = append(m.signals, &stored)
+	if outbox != nil {
+		entry := *outbox
+		m.outbox = append(m.outbox, &entry)
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetPendingOutbox(limit int) ([]*models.OutboxEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	var pending []*models.OutboxEntry
+	for _, entry := range m.outbox {
+		if entry.Status != "pending" || entry.NextAttemptAt.After(now) {
+			continue
+		}
+		copied := *entry
+		pending = append(pending, &copied)
+		if limit > 0 && len(pending) >= limit {
+			break
+		}
+	}
+	return pending, nil
+}
+
+func (m *MemoryStore) UpdateOutboxEntry(entry *models.OutboxEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.outbox {
+		if existing.SignalID == entry.SignalID {
+			existing.Status = entry.Status
+			existing.Attempts = entry.Attempts
+			existing.LastError = entry.LastError
+			existing.NextAttemptAt = entry.NextAttemptAt
+			existing.SentAt = entry.SentAt
+			return nil
+		}
+	}
+	return fmt.Errorf("outbox entry for signal %s not found", entry.SignalID)
+}
+
+func (m *MemoryStore) GetActiveSignals() ([]*models.TradingSignal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var active []*models.TradingSignal
+	for _, signal := range m.signals {
+		if signal.Status == "active" {
+			copied := *signal
+			active = append(active, &copied)
+		}
+	}
+	return active, nil
+}
+
+func (m *MemoryStore) UpdateSignalStatus(signalID uuid.UUID, status string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, signal := range m.signals {
+		if signal.ID == signalID {
+			signal.Status = status
+			return nil
+		}
+	}
+	return fmt.Errorf("signal %s not found", signalID)
+}
+
+func (m *MemoryStore) SetSignalTelegramMessageID(signalID uuid.UUID, messageID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, signal := range m.signals {
+		if signal.ID == signalID {
+			signal.TelegramMessageID = &messageID
+			return nil
+		}
+	}
+	return fmt.Errorf("signal %s not found", signalID)
+}
+
+func (m *MemoryStore) SetSignalUserAction(signalID uuid.UUID, action string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, signal := range m.signals {
+		if signal.ID == signalID {
+			signal.UserAction = action
+			return nil
+		}
+	}
+	return fmt.Errorf("signal %s not found", signalID)
+}
+
+func (m *MemoryStore) GetRecentSignals(limit int) ([]models.TradingSignal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	signals := make([]models.TradingSignal, 0, len(m.signals))
+	for i := len(m.signals) - 1; i >= 0 && len(signals) < limit; i-- {
+		signals = append(signals, *m.signals[i])
+	}
+	return signals, nil
+}
+
+func (m *MemoryStore) GetSignalsByPriority(priority string, limit int) ([]models.TradingSignal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var signals []models.TradingSignal
+	for i := len(m.signals) - 1; i >= 0 && len(signals) < limit; i-- {
+		if m.signals[i].Priority == priority {
+			signals = append(signals, *m.signals[i])
+		}
+	}
+	return signals, nil
+}
+
+func (m *MemoryStore) GetSignalsFiltered(filter models.SignalFilter) ([]models.TradingSignal, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var cryptoID uuid.UUID
+	if filter.Symbol != "" {
+		crypto, exists := m.cryptos[filter.Symbol]
+		if !exists {
+			return []models.TradingSignal{}, 0, nil
+		}
+		cryptoID = crypto.ID
+	}
+
+	signals := []models.TradingSignal{}
+	total := 0
+	for i := len(m.signals) - 1; i >= 0; i-- {
+		signal := m.signals[i]
+		if (filter.Symbol != "" && signal.CryptoID != cryptoID) ||
+			(filter.Action != "" && signal.Action != filter.Action) ||
+			(filter.Status != "" && signal.Status != filter.Status) ||
+			(filter.Priority != "" && signal.Priority != filter.Priority) ||
+			(!filter.From.IsZero() && signal.CreatedAt.Before(filter.From)) ||
+			(!filter.To.IsZero() && !signal.CreatedAt.Before(filter.To)) {
+			continue
+		}
+		if total >= filter.Offset && len(signals) < filter.Limit {
+			signals = append(signals, *signal)
+		}
+		total++
+	}
+	return signals, total, nil
+}
+
+func (m *MemoryStore) SaveRejectedSignal(rejected *models.RejectedSignal) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	copied := *rejected
+	m.rejected = append(m.rejected, &copied)
+	return nil
+}
+
+func (m *MemoryStore) GetRejectedSignals(reason string, limit int) ([]models.RejectedSignal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var rejected []models.RejectedSignal
+	for i := len(m.rejected) - 1; i >= 0 && len(rejected) < limit; i-- {
+		if reason == "" || m.rejected[i].Reason == reason {
+			rejected = append(rejected, *m.rejected[i])
+		}
+	}
+	return rejected, nil
+}
+
+func (m *MemoryStore) GetSignalByID(id string) (*models.TradingSignal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, signal := range m.signals {
+		if signal.ID.String() == id {
+			copied := *signal
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get signal: %s not found", id)
+}
+
+func (m *MemoryStore) GetSignalByRefCode(code string) (*models.TradingSignal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, signal := range m.signals {
+		if signal.RefCode == code {
+			copied := *signal
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("signal with ref code %s not found", code)
+}
+
+func (m *MemoryStore) SignalRefCodeExists(code string) (bool, error) {
+	_, err := m.GetSignalByRefCode(code)
+	return err == nil, nil
+}
+
+// Performance and learning
+func (m *MemoryStore) CreatePerformanceRecord(perf *models.SignalPerformance) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *perf
+	m.performance = append(m.performance, &stored)
+	return nil
+}
+
+func (m *MemoryStore) GetPerformanceOutcomes(since time.Time) ([]*models.SignalPerformance, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var outcomes []*models.SignalPerformance
+	for _, perf := range m.performance {
+		if perf.EntryTime.Before(since) {
+			continue
+		}
+		switch perf.Outcome {
+		case "profit", "loss", "breakeven":
+			copied := *perf
+			for _, signal := range m.signals {
+				if signal.ID == perf.SignalID {
+					signalCopy := *signal
+					copied.Signal = &signalCopy
+					break
+				}
+			}
+			outcomes = append(outcomes, &copied)
+		}
+	}
+
+	sort.Slice(outcomes, func(i, j int) bool {
+		return outcomes[i].EntryTime.Before(outcomes[j].EntryTime)
+	})
+	return outcomes, nil
+}
+
+func (m *MemoryStore) GetPerformanceRollup(period string) ([]*models.PerformanceRollup, error) {
+	since, err := rollupSince(period, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes, err := m.GetPerformanceOutcomes(since)
+	if err != nil {
+		return nil, err
+	}
+	return aggregateRollup(outcomes, period), nil
+}
+
+func (m *MemoryStore) SaveMarketSnapshot(snapshot *models.MarketSnapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *snapshot
+	m.snapshots = append(m.snapshots, &stored)
+	return nil
+}
+
+func (m *MemoryStore) SaveLearningData(data *models.LearningData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *data
+	m.learningData = append(m.learningData, &stored)
+	return nil
+}
+
+func (m *MemoryStore) GetLearningDataBySignal(signalID uuid.UUID) (*models.LearningData, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var latest *models.LearningData
+	for _, data := range m.learningData {
+		if data.SignalID == nil || *data.SignalID != signalID {
+			continue
+		}
+		if latest == nil || data.CreatedAt.After(latest.CreatedAt) {
+			latest = data
+		}
+	}
+	if latest == nil {
+		return nil, ErrLearningDataNotFound
+	}
+
+	copied := *latest
+	return &copied, nil
+}
+
+func (m *MemoryStore) UpdateLearningDataOutcome(signalID uuid.UUID, outcome string, pnl decimal.Decimal, durationMinutes int, accuracy decimal.Decimal) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updated := false
+	for _, data := range m.learningData {
+		if data.SignalID == nil || *data.SignalID != signalID {
+			continue
+		}
+		data.ActualOutcome = outcome
+		data.ActualPnLPercentage = pnl
+		data.ActualDurationMinutes = durationMinutes
+		data.PredictionAccuracy = accuracy
+		updated = true
+	}
+
+	if !updated {
+		return ErrLearningDataNotFound
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetLearningOutcomes(since time.Time) ([]*models.LearningData, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var records []*models.LearningData
+	for _, data := range m.learningData {
+		if data.ActualOutcome == "" || data.CreatedAt.Before(since) {
+			continue
+		}
+		copied := *data
+		records = append(records, &copied)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].CreatedAt.Before(records[j].CreatedAt) })
+	return records, nil
+}
+
+func (m *MemoryStore) GetLearningInsights() (map[string]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cutoff := time.Now().AddDate(0, 0, -30)
+	total, wins, losses := 0, 0, 0
+	evaluated := 0
+	accuracySum := decimal.Zero
+	for _, data := range m.learningData {
+		if data.CreatedAt.Before(cutoff) {
+			continue
+		}
+		total++
+		if data.PredictedOutcome != "" && data.ActualOutcome != "" {
+			evaluated++
+			accuracySum = accuracySum.Add(data.PredictionAccuracy)
+		}
+		switch data.ActualOutcome {
+		case "profit":
+			wins++
+		case "loss":
+			losses++
+		}
+	}
+
+	winRate := 0.0
+	if total > 0 {
+		winRate = float64(wins) / float64(total) * 100
+	}
+
+	accuracy := 0.0
+	if evaluated > 0 {
+		accuracy = accuracySum.Div(decimal.NewFromInt(int64(evaluated))).InexactFloat64() * 100
+	}
+
+	return map[string]interface{}{
+		"total_learning_records": total,
+		"win_rate":               winRate,
+		"total_wins":             wins,
+		"total_losses":           losses,
+		"evaluated_predictions":  evaluated,
+		"prediction_accuracy":    accuracy,
+		"period":                 "30 days",
+	}, nil
+}
+
+// Analytics are computed on every call; there is no view to cache
+func (m *MemoryStore) GetSignalAnalytics() ([]*models.SignalAnalytics, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	symbols := make(map[uuid.UUID]string)
+	for _, crypto := range m.cryptos {
+		symbols[crypto.ID] = crypto.Symbol
+	}
+
+	signalSymbols := make(map[uuid.UUID]string)
+	bySymbol := make(map[string]*models.SignalAnalytics)
+	confidenceSums := make(map[string]decimal.Decimal)
+	for _, signal := range m.signals {
+		symbol := symbols[signal.CryptoID]
+		if symbol == "" && signal.Crypto != nil {
+			symbol = signal.Crypto.Symbol
+		}
+		signalSymbols[signal.ID] = symbol
+
+		analytic, exists := bySymbol[symbol]
+		if !exists {
+			analytic = &models.SignalAnalytics{Symbol: symbol}
+			bySymbol[symbol] = analytic
+		}
+		analytic.TotalSignals++
+		confidenceSums[symbol] = confidenceSums[symbol].Add(signal.ConfidenceScore)
+	}
+
+	pnlSums := make(map[string]decimal.Decimal)
+	pnlCounts := make(map[string]int)
+	for _, perf := range m.performance {
+		analytic, exists := bySymbol[signalSymbols[perf.SignalID]]
+		if !exists || perf.PnLPercentage == nil {
+			continue
+		}
+		symbol := analytic.Symbol
+		pnl := *perf.PnLPercentage
+
+		switch perf.Outcome {
+		case "profit":
+			analytic.ProfitableSignals++
+		case "loss":
+			analytic.LossSignals++
+		}
+		if pnlCounts[symbol] == 0 || pnl.GreaterThan(analytic.BestSignalPnL) {
+			analytic.BestSignalPnL = pnl
+		}
+		if pnlCounts[symbol] == 0 || pnl.LessThan(analytic.WorstSignalPnL) {
+			analytic.WorstSignalPnL = pnl
+		}
+		pnlSums[symbol] = pnlSums[symbol].Add(pnl)
+		pnlCounts[symbol]++
+	}
+
+	analytics := make([]*models.SignalAnalytics, 0, len(bySymbol))
+	for symbol, analytic := range bySymbol {
+		if analytic.TotalSignals > 0 {
+			analytic.WinRatePercentage = decimal.NewFromInt(int64(analytic.ProfitableSignals)).
+				Div(decimal.NewFromInt(int64(analytic.TotalSignals))).Mul(decimal.NewFromInt(100))
+			analytic.AvgConfidence = confidenceSums[symbol].Div(decimal.NewFromInt(int64(analytic.TotalSignals)))
+		}
+		if pnlCounts[symbol] > 0 {
+			analytic.AvgPnLPercentage = pnlSums[symbol].Div(decimal.NewFromInt(int64(pnlCounts[symbol])))
+		}
+		analytics = append(analytics, analytic)
+	}
+
+	sort.Slice(analytics, func(i, j int) bool {
+		return analytics[i].WinRatePercentage.GreaterThan(analytics[j].WinRatePercentage)
+	})
+	return analytics, nil
+}
+
+func (m *MemoryStore) RefreshSignalAnalytics() ([]*models.SignalAnalytics, error) {
+	return m.GetSignalAnalytics()
+}
+
+func (m *MemoryStore) InvalidateSignalAnalytics() {}
+
+// Cryptocurrency operations
+func (m *MemoryStore) GetCryptoBySymbol(symbol string) (*models.Cryptocurrency, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	crypto, exists := m.cryptos[strings.ToUpper(symbol)]
+	if !exists {
+		return nil, ErrCryptocurrencyNotFound
+	}
+	copied := *crypto
+	return &copied, nil
+}
+
+func (m *MemoryStore) GetCryptocurrencies() ([]models.Cryptocurrency, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cryptos := make([]models.Cryptocurrency, 0, len(m.cryptos))
+	for _, crypto := range m.cryptos {
+		cryptos = append(cryptos, *crypto)
+	}
+	sort.Slice(cryptos, func(i, j int) bool {
+		return cryptos[i].Symbol < cryptos[j].Symbol
+	})
+	return cryptos, nil
+}
+
+func (m *MemoryStore) CreateCryptocurrency(crypto *models.Cryptocurrency) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Mirror the database upsert: an existing symbol yields the stored row
+	if existing, exists := m.cryptos[crypto.Symbol]; exists {
+		*crypto = *existing
+		return nil
+	}
+
+	crypto.ID = uuid.New()
+	crypto.CreatedAt = time.Now()
+	now := time.Now()
+	crypto.UpdatedAt = &now
+
+	stored := *crypto
+	m.cryptos[crypto.Symbol] = &stored
+	return nil
+}
+
+func (m *MemoryStore) UpdateCryptocurrency(crypto *models.Cryptocurrency) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for symbol, existing := range m.cryptos {
+		if existing.ID == crypto.ID {
+			now := time.Now()
+			crypto.UpdatedAt = &now
+			stored := *crypto
+			stored.Symbol = symbol
+			m.cryptos[symbol] = &stored
+			return nil
+		}
+	}
+	return ErrCryptocurrencyNotFound
+}
+
+func (m *MemoryStore) UpdateCryptoFailureCount(id uuid.UUID, failures int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, crypto := range m.cryptos {
+		if crypto.ID == id {
+			crypto.ConsecutiveFailures = failures
+			return nil
+		}
+	}
+	return ErrCryptocurrencyNotFound
+}
+
+// DeleteCryptocurrency removes a coin and detaches its signals, mirroring the SQL store
+func (m *MemoryStore) DeleteCryptocurrency(symbol string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	crypto, exists := m.cryptos[symbol]
+	if !exists {
+		return ErrCryptocurrencyNotFound
+	}
+
+	for _, signal := range m.signals {
+		if signal.CryptoID == crypto.ID {
+			signal.CryptoID = uuid.Nil
+		}
+	}
+	for _, snapshot := range m.snapshots {
+		if snapshot.CryptocurrencyID == crypto.ID {
+			snapshot.CryptocurrencyID = uuid.Nil
+		}
+	}
+
+	delete(m.cryptos, symbol)
+	delete(m.profiles, symbol)
+	return nil
+}
+
+func (m *MemoryStore) GetStrategyProfiles() ([]*models.StrategyProfile, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	profiles := make([]*models.StrategyProfile, 0, len(m.profiles))
+	for _, profile := range m.profiles {
+		stored := *profile
+		profiles = append(profiles, &stored)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Symbol < profiles[j].Symbol })
+	return profiles, nil
+}
+
+// UpsertStrategyProfile mirrors the foreign key on symbol: the coin must exist
+func (m *MemoryStore) UpsertStrategyProfile(profile *models.StrategyProfile) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.cryptos[profile.Symbol]; !exists {
+		return ErrCryptocurrencyNotFound
+	}
+
+	profile.UpdatedAt = time.Now()
+	stored := *profile
+	m.profiles[profile.Symbol] = &stored
+	return nil
+}
+
+func (m *MemoryStore) GetBotSetting(key string) (*models.BotSetting, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	setting, exists := m.settings[key]
+	if !exists {
+		return nil, ErrBotSettingNotFound
+	}
+	stored := *setting
+	return &stored, nil
+}
+
+func (m *MemoryStore) UpsertBotSetting(setting *models.BotSetting) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	setting.UpdatedAt = now
+	if existing, exists := m.settings[setting.SettingKey]; exists {
+		setting.ID, setting.CreatedAt = existing.ID, existing.CreatedAt
+	} else {
+		setting.ID, setting.CreatedAt = uuid.New(), now
+	}
+	stored := *setting
+	m.settings[setting.SettingKey] = &stored
+	return nil
+}
+
+func (m *MemoryStore) GetRemovedDefaults() ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	symbols := make([]string, 0, len(m.removed))
+	for symbol := range m.removed {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+func (m *MemoryStore) SetDefaultRemoved(symbol string, removed bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if removed {
+		m.removed[symbol] = true
+	} else {
+		delete(m.removed, symbol)
+	}
+	return nil
+}
+
+func (m *MemoryStore) ClearRemovedDefaults() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.removed = make(map[string]bool)
+	return nil
+}
+
+func (m *MemoryStore) LogSystem(level, component, message string, context map[string]interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.systemLogs = append(m.systemLogs, &models.SystemLog{
+		ID:        uuid.New(),
+		LogLevel:  level,
+		Component: component,
+		Message:   message,
+		Context:   context,
+		CreatedAt: time.Now(),
+	})
+	if len(m.systemLogs) > memorySystemLogLimit {
+		m.systemLogs = m.systemLogs[len(m.systemLogs)-memorySystemLogLimit:]
+	}
+	return nil
+}
+
+func (m *MemoryStore) LogNotification(entry *models.NotificationLog) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *entry
+	m.notifyLogs = append(m.notifyLogs, &stored)
+	if len(m.notifyLogs) > memorySystemLogLimit {
+		m.notifyLogs = m.notifyLogs[len(m.notifyLogs)-memorySystemLogLimit:]
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetSystemLogs(level string, limit int) ([]models.SystemLog, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var logs []models.SystemLog
+	for i := len(m.systemLogs) - 1; i >= 0 && len(logs) < limit; i-- {
+		if level == "" || m.systemLogs[i].LogLevel == level {
+			logs = append(logs, *m.systemLogs[i])
+		}
+	}
+	return logs, nil
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"crypto-signal-bot/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMemoryStoreGetSignalsFiltered(t *testing.T) {
+	store := NewMemoryStore()
+	btc := &models.Cryptocurrency{ID: uuid.New(), Symbol: "BTC"}
+	eth := &models.Cryptocurrency{ID: uuid.New(), Symbol: "ETH"}
+	for _, crypto := range []*models.Cryptocurrency{btc, eth} {
+		if err := store.CreateCryptocurrency(crypto); err != nil {
+			t.Fatalf("CreateCryptocurrency: %v", err)
+		}
+	}
+
+	// One signal per hour, stored oldest first
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	seeds := []struct {
+		crypto   *models.Cryptocurrency
+		action   string
+		status   string
+		priority string
+	}{
+		{btc, "BUY", "active", "high"},
+		{eth, "SELL", "triggered", "low"},
+		{btc, "SELL", "active", "medium"},
+		{eth, "BUY", "expired", "high"},
+		{btc, "BUY", "triggered", "low"},
+	}
+	ids := make([]uuid.UUID, len(seeds))
+	for i, seed := range seeds {
+		ids[i] = uuid.New()
+		signal := &models.TradingSignal{
+			ID:        ids[i],
+			CryptoID:  seed.crypto.ID,
+			Action:    seed.action,
+			Status:    seed.status,
+			Priority:  seed.priority,
+			CreatedAt: start.Add(time.Duration(i) * time.Hour),
+		}
+		if err := store.CreateSignal(signal, nil); err != nil {
+			t.Fatalf("CreateSignal: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		filter    models.SignalFilter
+		wantIDs   []int // Indexes into seeds, newest first
+		wantTotal int
+	}{
+		{name: "first page", filter: models.SignalFilter{Limit: 2}, wantIDs: []int{4, 3}, wantTotal: 5},
+		{name: "middle page", filter: models.SignalFilter{Limit: 2, Offset: 2}, wantIDs: []int{2, 1}, wantTotal: 5},
+		{name: "last partial page", filter: models.SignalFilter{Limit: 2, Offset: 4}, wantIDs: []int{0}, wantTotal: 5},
+		{name: "offset past the end", filter: models.SignalFilter{Limit: 2, Offset: 10}, wantIDs: []int{}, wantTotal: 5},
+		{name: "symbol", filter: models.SignalFilter{Symbol: "ETH", Limit: 10}, wantIDs: []int{3, 1}, wantTotal: 2},
+		{name: "unknown symbol", filter: models.SignalFilter{Symbol: "DOGE", Limit: 10}, wantIDs: []int{}, wantTotal: 0},
+		{name: "action and status", filter: models.SignalFilter{Action: "BUY", Status: "triggered", Limit: 10}, wantIDs: []int{4}, wantTotal: 1},
+		{name: "priority paged", filter: models.SignalFilter{Priority: "high", Limit: 1}, wantIDs: []int{3}, wantTotal: 2},
+		{
+			name:      "time window excludes its end",
+			filter:    models.SignalFilter{From: start.Add(time.Hour), To: start.Add(3 * time.Hour), Limit: 10},
+			wantIDs:   []int{2, 1},
+			wantTotal: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signals, total, err := store.GetSignalsFiltered(tt.filter)
+			if err != nil {
+				t.Fatalf("GetSignalsFiltered: %v", err)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", total, tt.wantTotal)
+			}
+			if len(signals) != len(tt.wantIDs) {
+				t.Fatalf("got %d signals, want %d", len(signals), len(tt.wantIDs))
+			}
+			for i, index := range tt.wantIDs {
+				if signals[i].ID != ids[index] {
+					t.Errorf("signal %d is seed %v, want seed %d", i, signals[i].ID, index)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"crypto-signal-bot/internal/models"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidRollupPeriod is returned for periods other than daily, weekly, monthly or all
+var ErrInvalidRollupPeriod = errors.New("period must be daily, weekly, monthly or all")
+
+// rollupPeriods maps each rollup period to its date_trunc unit and how many
+// recent buckets are returned; "all" is a single bucket over every outcome
+var rollupPeriods = map[string]struct {
+	unit    string
+	buckets int
+}{
+	"daily":   {"day", 30},
+	"weekly":  {"week", 12},
+	"monthly": {"month", 12},
+	"all":     {"", 1},
+}
+
+// truncatePeriod mirrors Postgres date_trunc in UTC; weeks start on Monday
+func truncatePeriod(t time.Time, unit string) time.Time {
+	t = t.UTC()
+	switch unit {
+	case "day":
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case "week":
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Time{}
+	}
+}
+
+// rollupSince returns the start of the oldest bucket included for a period
+func rollupSince(period string, now time.Time) (time.Time, error) {
+	spec, ok := rollupPeriods[period]
+	if !ok {
+		return time.Time{}, ErrInvalidRollupPeriod
+	}
+
+	start := truncatePeriod(now, spec.unit)
+	switch spec.unit {
+	case "day":
+		return start.AddDate(0, 0, -(spec.buckets - 1)), nil
+	case "week":
+		return start.AddDate(0, 0, -7*(spec.buckets-1)), nil
+	case "month":
+		return start.AddDate(0, -(spec.buckets - 1), 0), nil
+	default:
+		return time.Time{}, nil
+	}
+}
+
+// aggregateRollup groups outcomes into period buckets, newest first. It backs
+// the REST and in-memory stores; direct SQL groups with date_trunc instead.
+func aggregateRollup(outcomes []*models.SignalPerformance, period string) []*models.PerformanceRollup {
+	unit := rollupPeriods[period].unit
+
+	buckets := make(map[time.Time]*models.PerformanceRollup)
+	for _, perf := range outcomes {
+		start := truncatePeriod(perf.EntryTime, unit)
+		if unit == "" {
+			start = time.Time{}
+		}
+
+		rollup, exists := buckets[start]
+		if !exists {
+			rollup = &models.PerformanceRollup{Period: period, PeriodStart: start}
+			buckets[start] = rollup
+		}
+		if unit == "" && (rollup.PeriodStart.IsZero() || perf.EntryTime.Before(rollup.PeriodStart)) {
+			rollup.PeriodStart = perf.EntryTime.UTC()
+		}
+
+		switch perf.Outcome {
+		case "profit":
+			rollup.Wins++
+		case "loss":
+			rollup.Losses++
+		}
+
+		pnl := decimal.Zero
+		if perf.PnLPercentage != nil {
+			pnl = *perf.PnLPercentage
+		}
+		if rollup.Signals == 0 || pnl.GreaterThan(rollup.BestPnL) {
+			rollup.BestPnL = pnl
+		}
+		if rollup.Signals == 0 || pnl.LessThan(rollup.WorstPnL) {
+			rollup.WorstPnL = pnl
+		}
+		rollup.TotalPnL = rollup.TotalPnL.Add(pnl)
+		rollup.Signals++
+	}
+
+	rollups := make([]*models.PerformanceRollup, 0, len(buckets))
+	for _, rollup := range buckets {
+		finishRollup(rollup)
+		rollups = append(rollups, rollup)
+	}
+	sort.Slice(rollups, func(i, j int) bool {
+		return rollups[i].PeriodStart.After(rollups[j].PeriodStart)
+	})
+	return rollups
+}
+
+// finishRollup derives win rate and average PnL from the bucket totals
+func finishRollup(rollup *models.PerformanceRollup) {
+	if rollup.Signals == 0 {
+		return
+	}
+	signals := decimal.NewFromInt(int64(rollup.Signals))
+	rollup.WinRate = decimal.NewFromInt(int64(rollup.Wins)).Div(signals).Mul(decimal.NewFromInt(100))
+	rollup.AvgPnL = rollup.TotalPnL.Div(signals)
+}
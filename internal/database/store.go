@@ -0,0 +1,87 @@
+package database
+
+import (
+	"crypto-signal-bot/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Store is the persistence surface the services depend on. SupabaseClient is
+// the production implementation; MemoryStore backs local development and tests.
+// There is no SQLite implementation.
+type Store interface {
+	Close() error
+	Ping() error
+	TestConnection() error
+	Mode() string // How the store is reached: direct, rest or memory
+
+	// Signals
+	CreateSignal(signal *models.TradingSignal, outbox *models.OutboxEntry) error // outbox may be nil
+	GetActiveSignals() ([]*models.TradingSignal, error)
+	UpdateSignalStatus(signalID uuid.UUID, status string) error
+	SetSignalTelegramMessageID(signalID uuid.UUID, messageID int) error
+	SetSignalUserAction(signalID uuid.UUID, action string) error
+	GetRecentSignals(limit int) ([]models.TradingSignal, error)
+	GetSignalsByPriority(priority string, limit int) ([]models.TradingSignal, error)
+	GetSignalsFiltered(filter models.SignalFilter) ([]models.TradingSignal, int, error) // Page newest first and the total matching count
+	GetSignalByID(id string) (*models.TradingSignal, error)
+	GetSignalByRefCode(code string) (*models.TradingSignal, error)
+	SignalRefCodeExists(code string) (bool, error)
+
+	// Performance and learning
+	CreatePerformanceRecord(perf *models.SignalPerformance) error
+	GetPerformanceOutcomes(since time.Time) ([]*models.SignalPerformance, error)
+	GetPerformanceRollup(period string) ([]*models.PerformanceRollup, error)
+	SaveMarketSnapshot(snapshot *models.MarketSnapshot) error
+	SaveLearningData(data *models.LearningData) error
+	GetLearningDataBySignal(signalID uuid.UUID) (*models.LearningData, error)
+	GetLearningOutcomes(since time.Time) ([]*models.LearningData, error) // Records with an actual outcome
+	UpdateLearningDataOutcome(signalID uuid.UUID, outcome string, pnl decimal.Decimal, durationMinutes int, accuracy decimal.Decimal) error
+	GetLearningInsights() (map[string]interface{}, error)
+
+	// Analytics
+	GetSignalAnalytics() ([]*models.SignalAnalytics, error)
+	RefreshSignalAnalytics() ([]*models.SignalAnalytics, error)
+	InvalidateSignalAnalytics()
+
+	// Cryptocurrencies
+	GetCryptoBySymbol(symbol string) (*models.Cryptocurrency, error)
+	GetCryptocurrencies() ([]models.Cryptocurrency, error)
+	CreateCryptocurrency(crypto *models.Cryptocurrency) error
+	UpdateCryptocurrency(crypto *models.Cryptocurrency) error
+	DeleteCryptocurrency(symbol string) error
+	UpdateCryptoFailureCount(id uuid.UUID, failures int) error
+
+	// Notification outbox; pending entries are due once next_attempt_at passed
+	GetPendingOutbox(limit int) ([]*models.OutboxEntry, error)
+	UpdateOutboxEntry(entry *models.OutboxEntry) error
+
+	// Setups dropped by a filter; reason "" lists every reason
+	SaveRejectedSignal(rejected *models.RejectedSignal) error
+	GetRejectedSignals(reason string, limit int) ([]models.RejectedSignal, error)
+
+	// Per-coin strategy overrides
+	GetStrategyProfiles() ([]*models.StrategyProfile, error)
+	UpsertStrategyProfile(profile *models.StrategyProfile) error
+
+	// Key-value settings in bot_settings; ErrBotSettingNotFound for a key never saved
+	GetBotSetting(key string) (*models.BotSetting, error)
+	UpsertBotSetting(setting *models.BotSetting) error
+
+	// Default coins the user removed, which seeding must not re-add
+	GetRemovedDefaults() ([]string, error)
+	SetDefaultRemoved(symbol string, removed bool) error
+	ClearRemovedDefaults() error
+
+	// Operational log entries; level "" lists every level, newest first
+	LogSystem(level, component, message string, context map[string]interface{}) error
+	GetSystemLogs(level string, limit int) ([]models.SystemLog, error)
+
+	// Delivery record of one notification on one channel
+	LogNotification(entry *models.NotificationLog) error
+}
+
+// Compile-time check that SupabaseClient satisfies Store
+var _ Store = (*SupabaseClient)(nil)
@@ -3,31 +3,116 @@ package database
 import (
 	"crypto-signal-bot/internal/config"
 	"crypto-signal-bot/internal/models"
+	"crypto-signal-bot/internal/utils"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
 
+// ErrCryptocurrencyNotFound is returned when a cryptocurrency symbol has no record
+var ErrCryptocurrencyNotFound = errors.New("cryptocurrency not found")
+
+// ErrLearningDataNotFound is returned when a signal has no learning_data record
+var ErrLearningDataNotFound = errors.New("learning data not found")
+
+// ErrBotSettingNotFound is returned when a bot_settings key has no row
+var ErrBotSettingNotFound = errors.New("bot setting not found")
+
 type SupabaseClient struct {
 	db        *sql.DB
 	restClient *SupabaseRestClient
 	cfg       *config.Config
-	useRest   bool
+	useRest   atomic.Bool // Flips to false once, when a direct connection takes over
+
+	// Background upgrade from the REST API to a direct connection
+	connStr       string // Empty when no direct connection can be built or it was rejected
+	deferred      bool   // Started without a reachable REST API
+	stopReconnect chan struct{}
+	closeOnce     sync.Once
+
+	// Cached signal_analytics view results, shared by the API and Telegram
+	analyticsMu       sync.Mutex
+	analyticsCache    []*models.SignalAnalytics
+	analyticsCachedAt time.Time
 }
 
+// ErrMisconfigured marks connection failures that retrying cannot fix, such as
+// a missing Supabase URL or rejected credentials
+var ErrMisconfigured = errors.New("database misconfigured")
+
 func NewSupabaseClient(cfg *config.Config) (*SupabaseClient, error) {
+	if cfg.SupabaseURL == "" || cfg.SupabaseServiceKey == "" {
+		return nil, fmt.Errorf("%w: SUPABASE_URL and SUPABASE_SERVICE_KEY are required", ErrMisconfigured)
+	}
+
 	// Initialize REST client as fallback
-	restClient := NewSupabaseRestClient(cfg)
+	client := newRestModeClient(cfg)
 
 	// Try direct database connection first
 	logrus.Info("Attempting direct database connection...")
 
+	if client.connStr == "" {
+		logrus.Warn("Invalid Supabase URL and no DB_HOST provided, using REST API only")
+		return client, nil
+	}
+
+	db, err := openDirectConnection(client.connStr)
+	if err == nil {
+		logrus.Info("✅ Successfully connected to Supabase database via direct connection")
+		client.db = db
+		client.useRest.Store(false)
+		return client, nil
+	}
+
+	logrus.Warnf("Direct database connection failed: %v, falling back to REST API", err)
+	if isPermanentConnectError(err) {
+		// Retrying the same credentials in the background can't succeed
+		client.connStr = ""
+	}
+
+	// Test REST API connection
+	if restErr := client.restClient.TestConnection(); restErr != nil {
+		return nil, fmt.Errorf("both database and REST API connections failed: %w", restErr)
+	}
+
+	return client, nil
+}
+
+// NewDeferredSupabaseClient returns a REST-mode client without testing the
+// connection, for when Supabase is only temporarily unreachable at startup.
+// Calls fail until it is back; StartReconnect reports when it is.
+func NewDeferredSupabaseClient(cfg *config.Config) *SupabaseClient {
+	client := newRestModeClient(cfg)
+	client.deferred = true
+	return client
+}
+
+func newRestModeClient(cfg *config.Config) *SupabaseClient {
+	client := &SupabaseClient{
+		restClient:    NewSupabaseRestClient(cfg),
+		cfg:           cfg,
+		connStr:       directConnString(cfg),
+		stopReconnect: make(chan struct{}),
+	}
+	client.useRest.Store(true)
+	return client
+}
+
+// directConnString builds the Postgres connection string from DB_* variables,
+// defaulting to the Supabase project's database. Empty when neither DB_HOST
+// nor a usable Supabase URL is set.
+func directConnString(cfg *config.Config) string {
 	// Get database connection details from environment variables
 	dbHost := os.Getenv("DB_HOST")
 	dbPort := os.Getenv("DB_PORT")
@@ -41,12 +126,7 @@ func NewSupabaseClient(cfg *config.Config) (*SupabaseClient, error) {
 		// Fallback to extracting from Supabase URL
 		projectID := extractProjectID(cfg.SupabaseURL)
 		if projectID == "" {
-			logrus.Warn("Invalid Supabase URL and no DB_HOST provided, using REST API only")
-			return &SupabaseClient{
-				restClient: restClient,
-				cfg:        cfg,
-				useRest:    true,
-			}, nil
+			return ""
 		}
 		dbHost = fmt.Sprintf("db.%s.supabase.co", projectID)
 	}
@@ -67,21 +147,18 @@ func NewSupabaseClient(cfg *config.Config) (*SupabaseClient, error) {
 		dbSSLMode = "require"
 	}
 
-	logrus.Debugf("Connecting to database: host=%s port=%s user=%s dbname=%s sslmode=%s",
+	logrus.Debugf("Database connection: host=%s port=%s user=%s dbname=%s sslmode=%s",
 		dbHost, dbPort, dbUser, dbName, dbSSLMode)
 
 	// Build connection string for PostgreSQL
-	connStr := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=%s password=%s connect_timeout=10",
+	return fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=%s password=%s connect_timeout=10",
 		dbHost, dbPort, dbUser, dbName, dbSSLMode, dbPassword)
+}
 
+func openDirectConnection(connStr string) (*sql.DB, error) {
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
-		logrus.Warnf("Failed to open database connection: %v, falling back to REST API", err)
-		return &SupabaseClient{
-			restClient: restClient,
-			cfg:        cfg,
-			useRest:    true,
-		}, nil
+		return nil, err
 	}
 
 	// Set connection pool settings for production stability
@@ -91,49 +168,116 @@ func NewSupabaseClient(cfg *config.Config) (*SupabaseClient, error) {
 
 	// Test the connection
 	if err := db.Ping(); err != nil {
-		logrus.Warnf("Failed to ping database: %v, falling back to REST API", err)
 		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
 
-		// Test REST API connection
-		if err := restClient.TestConnection(); err != nil {
-			return nil, fmt.Errorf("both database and REST API connections failed: %w", err)
-		}
+// isPermanentConnectError reports Postgres errors that mean the connection
+// settings are wrong (authentication, unknown database) rather than that the
+// server is still starting or unreachable
+func isPermanentConnectError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	class := pqErr.Code.Class()
+	return class == "28" || class == "3D" // invalid authorization, invalid catalog name
+}
 
-		return &SupabaseClient{
-			restClient: restClient,
-			cfg:        cfg,
-			useRest:    true,
-		}, nil
+// StartReconnect keeps trying in the background until the client is on a
+// direct connection, switching over as soon as one succeeds. A deferred client
+// also reports when its REST API first answers. onConnected receives "direct"
+// or "rest". It does nothing for a client that can't improve.
+func (s *SupabaseClient) StartReconnect(interval time.Duration, onConnected func(mode string)) {
+	if interval <= 0 || !s.usingRest() || (s.connStr == "" && !s.deferred) {
+		return
 	}
 
-	logrus.Info("✅ Successfully connected to Supabase database via direct connection")
+	logrus.Info("🔁 Reconnecting to the database in the background every ", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopReconnect:
+				return
+			case <-ticker.C:
+			}
 
-	return &SupabaseClient{
-		db:         db,
-		restClient: restClient,
-		cfg:        cfg,
-		useRest:    false,
-	}, nil
+			if s.connStr != "" {
+				db, err := openDirectConnection(s.connStr)
+				if err == nil {
+					// s.db is published before the flag; readers only touch it after seeing the flag
+					s.db = db
+					s.useRest.Store(false)
+					logrus.Info("✅ Switched to a direct database connection")
+					if onConnected != nil {
+						onConnected("direct")
+					}
+					return
+				}
+				if isPermanentConnectError(err) {
+					logrus.Warn("Direct database connection rejected, staying on the REST API: ", err)
+					s.connStr = ""
+				} else {
+					logrus.Debug("Direct database connection still unavailable: ", err)
+				}
+			}
+
+			if s.deferred {
+				if err := s.restClient.TestConnection(); err != nil {
+					logrus.Debug("Supabase REST API still unreachable: ", err)
+					continue
+				}
+				s.deferred = false
+				if onConnected != nil {
+					onConnected("rest")
+				}
+			}
+
+			if s.connStr == "" && !s.deferred {
+				return
+			}
+		}
+	}()
+}
+
+// Mode reports whether queries currently go over a direct connection or REST
+func (s *SupabaseClient) Mode() string {
+	if s.usingRest() {
+		return "rest"
+	}
+	return "direct"
+}
+
+func (s *SupabaseClient) usingRest() bool {
+	return s.useRest.Load()
 }
 
 func (s *SupabaseClient) Close() error {
-	if s.useRest {
+	s.closeOnce.Do(func() { close(s.stopReconnect) })
+	if s.usingRest() {
 		return s.restClient.Close()
 	}
 	return s.db.Close()
 }
 
 func (s *SupabaseClient) Ping() error {
-	if s.useRest {
+	if s.usingRest() {
 		return s.restClient.Ping()
 	}
 	return s.db.Ping()
 }
 
 // Signal operations
-func (s *SupabaseClient) CreateSignal(signal *models.TradingSignal) error {
-	if s.useRest {
-		return s.restClient.CreateSignal(signal)
+
+// CreateSignal stores a signal and, when given, its outbox entry in one transaction
+func (s *SupabaseClient) CreateSignal(signal *models.TradingSignal, outbox *models.OutboxEntry) error {
+	if s.usingRest() {
+		return s.restClient.CreateSignal(signal, outbox)
 	}
 	query := `
 		INSERT INTO trading_signals (
@@ -141,15 +285,28 @@ func (s *SupabaseClient) CreateSignal(signal *models.TradingSignal) error {
 			take_profit_1, take_profit_2, reasoning, rsi, macd_line, macd_signal,
 			macd_histogram, bb_upper, bb_middle, bb_lower, sma_20, ema_12, ema_26,
 			volume_24h, price_change_24h, fear_greed_index, market_cap,
-			market_conditions, timeframe, created_at, status
+			market_conditions, timeframe, created_at, status, ref_code, source, context,
+			priority, data_quality, entry_low, entry_high
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
-			$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27
+			$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30,
+			$31, $32, $33, $34
 		)`
 
 	marketConditionsJSON, _ := json.Marshal(signal.MarketConditions)
 
-	_, err := s.db.Exec(query,
+	var contextJSON []byte
+	if signal.Context != nil {
+		contextJSON, _ = json.Marshal(signal.Context)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query,
 		signal.ID, signal.CryptoID, signal.Action, signal.ConfidenceScore,
 		signal.EntryPrice, signal.StopLoss, signal.TakeProfit1, signal.TakeProfit2,
 		signal.Reasoning, signal.RSI, signal.MACDLine, signal.MACDSignal,
@@ -157,6 +314,9 @@ func (s *SupabaseClient) CreateSignal(signal *models.TradingSignal) error {
 		signal.SMA20, signal.EMA12, signal.EMA26, signal.Volume24h,
 		signal.PriceChange24h, signal.FearGreedIndex, signal.MarketCap,
 		marketConditionsJSON, signal.Timeframe, signal.CreatedAt, signal.Status,
+		utils.StringPtr(signal.RefCode), signalSource(signal), contextJSON,
+		utils.StringPtr(signal.Priority), signal.DataQuality, signal.EntryLow,
+		signal.EntryHigh,
 	)
 
 	if err != nil {
@@ -164,17 +324,38 @@ func (s *SupabaseClient) CreateSignal(signal *models.TradingSignal) error {
 		return err
 	}
 
+	if outbox != nil {
+		payloadJSON, err := json.Marshal(outbox.Payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode outbox payload: %w", err)
+		}
+		_, err = tx.Exec(`
+			INSERT INTO signal_outbox (signal_id, payload, status, attempts, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			outbox.SignalID, payloadJSON, outbox.Status, outbox.Attempts, outbox.NextAttemptAt, outbox.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error("Failed to create signal outbox entry: ", err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit signal: %w", err)
+	}
+
 	logrus.Info("✅ Signal created successfully: ", signal.ID)
 	return nil
 }
 
 func (s *SupabaseClient) GetActiveSignals() ([]*models.TradingSignal, error) {
-	if s.useRest {
+	if s.usingRest() {
 		return s.restClient.GetActiveSignals()
 	}
 	query := `
 		SELECT id, crypto_id, action, confidence_score, entry_price, stop_loss,
-			   take_profit_1, take_profit_2, reasoning, created_at, status
+			   take_profit_1, take_profit_2, reasoning, created_at, status, telegram_message_id,
+			   entry_low, entry_high, market_conditions
 		FROM trading_signals 
 		WHERE status = 'active' 
 		ORDER BY created_at DESC`
@@ -188,15 +369,25 @@ func (s *SupabaseClient) GetActiveSignals() ([]*models.TradingSignal, error) {
 	var signals []*models.TradingSignal
 	for rows.Next() {
 		signal := &models.TradingSignal{}
+		var messageID sql.NullInt64
+		var marketConditionsJSON []byte
 		err := rows.Scan(
 			&signal.ID, &signal.CryptoID, &signal.Action, &signal.ConfidenceScore,
 			&signal.EntryPrice, &signal.StopLoss, &signal.TakeProfit1,
 			&signal.TakeProfit2, &signal.Reasoning, &signal.CreatedAt, &signal.Status,
+			&messageID, &signal.EntryLow, &signal.EntryHigh, &marketConditionsJSON,
 		)
 		if err != nil {
 			logrus.Error("Failed to scan signal: ", err)
 			continue
 		}
+		signal.TelegramMessageID = nullableMessageID(messageID)
+		if len(marketConditionsJSON) > 0 {
+			if err := json.Unmarshal(marketConditionsJSON, &signal.MarketConditions); err != nil {
+				logrus.Warn("Failed to parse market conditions: ", err)
+			}
+		}
+		restoreTakeProfits(signal)
 		signals = append(signals, signal)
 	}
 
@@ -204,7 +395,7 @@ func (s *SupabaseClient) GetActiveSignals() ([]*models.TradingSignal, error) {
 }
 
 func (s *SupabaseClient) UpdateSignalStatus(signalID uuid.UUID, status string) error {
-	if s.useRest {
+	if s.usingRest() {
 		return s.restClient.UpdateSignalStatus(signalID, status)
 	}
 	query := `UPDATE trading_signals SET status = $1 WHERE id = $2`
@@ -212,6 +403,35 @@ func (s *SupabaseClient) UpdateSignalStatus(signalID uuid.UUID, status string) e
 	return err
 }
 
+// SetSignalTelegramMessageID records the Telegram message a signal was announced in
+func (s *SupabaseClient) SetSignalTelegramMessageID(signalID uuid.UUID, messageID int) error {
+	if s.usingRest() {
+		return s.restClient.SetSignalTelegramMessageID(signalID, messageID)
+	}
+	query := `UPDATE trading_signals SET telegram_message_id = $1 WHERE id = $2`
+	_, err := s.db.Exec(query, messageID, signalID)
+	return err
+}
+
+// SetSignalUserAction records whether the user took or ignored a signal
+func (s *SupabaseClient) SetSignalUserAction(signalID uuid.UUID, action string) error {
+	if s.usingRest() {
+		return s.restClient.SetSignalUserAction(signalID, action)
+	}
+	query := `UPDATE trading_signals SET user_action = $1 WHERE id = $2`
+	_, err := s.db.Exec(query, action, signalID)
+	return err
+}
+
+// nullableMessageID converts a nullable telegram_message_id column
+func nullableMessageID(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	messageID := int(value.Int64)
+	return &messageID
+}
+
 // Performance tracking
 func (s *SupabaseClient) CreatePerformanceRecord(perf *models.SignalPerformance) error {
 	query := `
@@ -231,13 +451,128 @@ func (s *SupabaseClient) CreatePerformanceRecord(perf *models.SignalPerformance)
 		perf.HitStopLoss, perf.HitTakeProfit1, perf.HitTakeProfit2,
 		perf.MaxProfitPercentage, perf.MaxLossPercentage, perf.ExitReason,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// New outcomes change the aggregates
+	s.InvalidateSignalAnalytics()
+	return nil
+}
+
+// GetPerformanceOutcomes returns closed performance records with an entry time
+// at or after since, oldest first
+func (s *SupabaseClient) GetPerformanceOutcomes(since time.Time) ([]*models.SignalPerformance, error) {
+	if s.usingRest() {
+		return s.restClient.GetPerformanceOutcomes(since)
+	}
+	query := `
+		SELECT sp.id, sp.signal_id, sp.entry_price, sp.pnl_percentage, sp.entry_time, sp.exit_time, sp.outcome,
+		       ts.confidence_score, ts.data_quality
+		FROM signal_performance sp
+		LEFT JOIN trading_signals ts ON ts.id = sp.signal_id
+		WHERE sp.entry_time >= $1 AND sp.outcome IN ('profit', 'loss', 'breakeven')
+		ORDER BY sp.entry_time`
+
+	rows, err := s.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query performance outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	var outcomes []*models.SignalPerformance
+	for rows.Next() {
+		perf := &models.SignalPerformance{}
+		var confidence decimal.NullDecimal
+		var dataQuality *decimal.Decimal
+		if err := rows.Scan(
+			&perf.ID, &perf.SignalID, &perf.EntryPrice, &perf.PnLPercentage,
+			&perf.EntryTime, &perf.ExitTime, &perf.Outcome,
+			&confidence, &dataQuality,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan performance outcome: %w", err)
+		}
+		// Attach the signal's scores so outcomes can be replayed against thresholds
+		if confidence.Valid {
+			perf.Signal = &models.TradingSignal{
+				ID:              perf.SignalID,
+				ConfidenceScore: confidence.Decimal,
+				DataQuality:     dataQuality,
+			}
+		}
+		outcomes = append(outcomes, perf)
+	}
+
+	return outcomes, nil
+}
+
+// GetPerformanceRollup aggregates closed outcomes per daily, weekly or monthly
+// bucket (newest first), or as a single "all" bucket
+func (s *SupabaseClient) GetPerformanceRollup(period string) ([]*models.PerformanceRollup, error) {
+	since, err := rollupSince(period, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if s.usingRest() {
+		outcomes, err := s.restClient.GetPerformanceOutcomes(since)
+		if err != nil {
+			return nil, err
+		}
+		return aggregateRollup(outcomes, period), nil
+	}
+
+	bucket := "MIN(entry_time)"
+	groupBy := ""
+	if unit := rollupPeriods[period].unit; unit != "" {
+		bucket = fmt.Sprintf("date_trunc('%s', entry_time AT TIME ZONE 'UTC')", unit)
+		groupBy = "GROUP BY 1"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s AS period_start,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE outcome = 'profit'),
+		       COUNT(*) FILTER (WHERE outcome = 'loss'),
+		       COALESCE(SUM(pnl_percentage), 0),
+		       COALESCE(MAX(pnl_percentage), 0),
+		       COALESCE(MIN(pnl_percentage), 0)
+		FROM signal_performance
+		WHERE entry_time >= $1 AND outcome IN ('profit', 'loss', 'breakeven')
+		%s
+		ORDER BY 1 DESC`, bucket, groupBy)
+
+	rows, err := s.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query performance rollup: %w", err)
+	}
+	defer rows.Close()
+
+	var rollups []*models.PerformanceRollup
+	for rows.Next() {
+		rollup := &models.PerformanceRollup{Period: period}
+		var periodStart sql.NullTime
+		if err := rows.Scan(
+			&periodStart, &rollup.Signals, &rollup.Wins, &rollup.Losses,
+			&rollup.TotalPnL, &rollup.BestPnL, &rollup.WorstPnL,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan performance rollup: %w", err)
+		}
+		// "all" over an empty table still yields one row with no data
+		if rollup.Signals == 0 {
+			continue
+		}
+		rollup.PeriodStart = periodStart.Time.UTC()
+		finishRollup(rollup)
+		rollups = append(rollups, rollup)
+	}
+
+	return rollups, nil
 }
 
 // Market data
 func (s *SupabaseClient) SaveMarketSnapshot(snapshot *models.MarketSnapshot) error {
-	if s.useRest {
+	if s.usingRest() {
 		return s.restClient.SaveMarketSnapshot(snapshot)
 	}
 	query := `
@@ -262,6 +597,141 @@ func (s *SupabaseClient) SaveMarketSnapshot(snapshot *models.MarketSnapshot) err
 	return err
 }
 
+// GetPendingOutbox lists outbox entries due for delivery, oldest first
+func (s *SupabaseClient) GetPendingOutbox(limit int) ([]*models.OutboxEntry, error) {
+	if s.usingRest() {
+		return s.restClient.GetPendingOutbox(limit)
+	}
+	query := `
+		SELECT signal_id, payload, status, attempts, last_error, next_attempt_at, created_at, sent_at
+		FROM signal_outbox
+		WHERE status = 'pending' AND next_attempt_at <= NOW()
+		ORDER BY created_at
+		LIMIT $1`
+
+	rows, err := s.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query signal outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.OutboxEntry
+	for rows.Next() {
+		var entry models.OutboxEntry
+		var payloadJSON []byte
+		var lastError sql.NullString
+		var sentAt sql.NullTime
+
+		err := rows.Scan(
+			&entry.SignalID, &payloadJSON, &entry.Status, &entry.Attempts,
+			&lastError, &entry.NextAttemptAt, &entry.CreatedAt, &sentAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox entry: %w", err)
+		}
+		entry.LastError = lastError.String
+		if sentAt.Valid {
+			entry.SentAt = &sentAt.Time
+		}
+		if err := json.Unmarshal(payloadJSON, &entry.Payload); err != nil {
+			logrus.Warn("Failed to parse outbox payload of signal ", entry.SignalID, ": ", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// UpdateOutboxEntry stores the delivery state of an outbox entry
+func (s *SupabaseClient) UpdateOutboxEntry(entry *models.OutboxEntry) error {
+	if s.usingRest() {
+		return s.restClient.UpdateOutboxEntry(entry)
+	}
+	query := `
+		UPDATE signal_outbox
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, sent_at = $6
+		WHERE signal_id = $1`
+
+	_, err := s.db.Exec(query,
+		entry.SignalID, entry.Status, entry.Attempts,
+		sql.NullString{String: entry.LastError, Valid: entry.LastError != ""},
+		entry.NextAttemptAt, entry.SentAt,
+	)
+	return err
+}
+
+// SaveRejectedSignal stores a setup that a filter kept from becoming a signal
+func (s *SupabaseClient) SaveRejectedSignal(rejected *models.RejectedSignal) error {
+	if s.usingRest() {
+		return s.restClient.SaveRejectedSignal(rejected)
+	}
+	query := `
+		INSERT INTO rejected_signals (
+			id, crypto_id, symbol, reason, details, action, confidence_score, entry_price,
+			stop_loss, take_profit_1, take_profit_2, reasoning, market_conditions, timeframe, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+		)`
+
+	marketConditionsJSON, _ := json.Marshal(rejected.MarketConditions)
+
+	_, err := s.db.Exec(query,
+		rejected.ID, rejected.CryptoID, rejected.Symbol, rejected.Reason, rejected.Details,
+		sql.NullString{String: rejected.Action, Valid: rejected.Action != ""}, rejected.ConfidenceScore, rejected.EntryPrice,
+		rejected.StopLoss, rejected.TakeProfit1, rejected.TakeProfit2, rejected.Reasoning,
+		marketConditionsJSON, rejected.Timeframe, rejected.CreatedAt,
+	)
+	return err
+}
+
+// GetRejectedSignals lists rejected setups newest first, optionally of one reason
+func (s *SupabaseClient) GetRejectedSignals(reason string, limit int) ([]models.RejectedSignal, error) {
+	if s.usingRest() {
+		return s.restClient.GetRejectedSignals(reason, limit)
+	}
+	query := `
+		SELECT id, crypto_id, symbol, reason, details, action, confidence_score, entry_price,
+		       stop_loss, take_profit_1, take_profit_2, reasoning, market_conditions, timeframe, created_at
+		FROM rejected_signals
+		WHERE $2 = '' OR reason = $2
+		ORDER BY created_at DESC
+		LIMIT $1`
+
+	rows, err := s.db.Query(query, limit, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rejected signals: %w", err)
+	}
+	defer rows.Close()
+
+	var rejected []models.RejectedSignal
+	for rows.Next() {
+		var r models.RejectedSignal
+		var details, action, reasoning, timeframe sql.NullString
+		var marketConditionsJSON []byte
+
+		err := rows.Scan(
+			&r.ID, &r.CryptoID, &r.Symbol, &r.Reason, &details, &action,
+			&r.ConfidenceScore, &r.EntryPrice, &r.StopLoss, &r.TakeProfit1, &r.TakeProfit2,
+			&reasoning, &marketConditionsJSON, &timeframe, &r.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rejected signal: %w", err)
+		}
+		r.Details = details.String
+		r.Action = action.String
+		r.Reasoning = reasoning.String
+		r.Timeframe = timeframe.String
+		if len(marketConditionsJSON) > 0 {
+			if err := json.Unmarshal(marketConditionsJSON, &r.MarketConditions); err != nil {
+				logrus.Warn("Failed to parse market conditions: ", err)
+			}
+		}
+		rejected = append(rejected, r)
+	}
+
+	return rejected, rows.Err()
+}
+
 // Learning data
 func (s *SupabaseClient) SaveLearningData(data *models.LearningData) error {
 	query := `
@@ -285,8 +755,165 @@ func (s *SupabaseClient) SaveLearningData(data *models.LearningData) error {
 	return err
 }
 
+// GetLearningDataBySignal returns the newest learning record of a signal
+func (s *SupabaseClient) GetLearningDataBySignal(signalID uuid.UUID) (*models.LearningData, error) {
+	if s.usingRest() {
+		return s.restClient.GetLearningDataBySignal(signalID)
+	}
+
+	query := `
+		SELECT id, signal_id, features, actual_outcome, predicted_outcome,
+		       predicted_confidence, created_at
+		FROM learning_data
+		WHERE signal_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	data := &models.LearningData{}
+	var featuresJSON []byte
+	var actualOutcome, predictedOutcome sql.NullString
+	var predictedConfidence decimal.NullDecimal
+
+	err := s.db.QueryRow(query, signalID).Scan(
+		&data.ID,
+		&data.SignalID,
+		&featuresJSON,
+		&actualOutcome,
+		&predictedOutcome,
+		&predictedConfidence,
+		&data.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrLearningDataNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get learning data: %w", err)
+	}
+
+	if len(featuresJSON) > 0 {
+		json.Unmarshal(featuresJSON, &data.Features)
+	}
+	data.ActualOutcome = actualOutcome.String
+	data.PredictedOutcome = predictedOutcome.String
+	data.PredictedConfidence = predictedConfidence.Decimal
+
+	return data, nil
+}
+
+// UpdateLearningDataOutcome records the actual result of a signal and the
+// accuracy score of its prediction on the signal's learning_data rows
+func (s *SupabaseClient) UpdateLearningDataOutcome(signalID uuid.UUID, outcome string, pnl decimal.Decimal, durationMinutes int, accuracy decimal.Decimal) error {
+	if s.usingRest() {
+		return s.restClient.UpdateLearningDataOutcome(signalID, outcome, pnl, durationMinutes, accuracy)
+	}
+
+	query := `
+		UPDATE learning_data SET
+			actual_outcome = $1,
+			actual_pnl_percentage = $2,
+			actual_duration_minutes = $3,
+			prediction_accuracy = $4
+		WHERE signal_id = $5`
+
+	result, err := s.db.Exec(query, outcome, pnl, durationMinutes, accuracy, signalID)
+	if err != nil {
+		return fmt.Errorf("failed to update learning data: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update learning data: %w", err)
+	}
+	if updated == 0 {
+		return ErrLearningDataNotFound
+	}
+
+	return nil
+}
+
+// GetLearningOutcomes loads the learning records created since the given
+// time that have an actual outcome, oldest first
+func (s *SupabaseClient) GetLearningOutcomes(since time.Time) ([]*models.LearningData, error) {
+	if s.usingRest() {
+		return s.restClient.GetLearningOutcomes(since)
+	}
+	query := `
+		SELECT id, signal_id, features, actual_outcome, actual_pnl_percentage, created_at
+		FROM learning_data
+		WHERE created_at >= $1 AND actual_outcome IS NOT NULL
+		ORDER BY created_at`
+
+	rows, err := s.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query learning outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*models.LearningData
+	for rows.Next() {
+		data := &models.LearningData{}
+		var featuresJSON []byte
+		var pnl decimal.NullDecimal
+		if err := rows.Scan(&data.ID, &data.SignalID, &featuresJSON, &data.ActualOutcome, &pnl, &data.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan learning outcome: %w", err)
+		}
+		if len(featuresJSON) > 0 {
+			json.Unmarshal(featuresJSON, &data.Features)
+		}
+		data.ActualPnLPercentage = pnl.Decimal
+		records = append(records, data)
+	}
+
+	return records, nil
+}
+
 // Analytics
+
+// GetSignalAnalytics returns the signal_analytics view, served from cache
+// while it is younger than ANALYTICS_CACHE_TTL_SECONDS
 func (s *SupabaseClient) GetSignalAnalytics() ([]*models.SignalAnalytics, error) {
+	s.analyticsMu.Lock()
+	defer s.analyticsMu.Unlock()
+
+	ttl := time.Duration(s.cfg.AnalyticsCacheTTLSeconds) * time.Second
+	if s.analyticsCache != nil && time.Since(s.analyticsCachedAt) < ttl {
+		return s.analyticsCache, nil
+	}
+
+	return s.refreshSignalAnalyticsLocked()
+}
+
+// RefreshSignalAnalytics recomputes analytics from the view and replaces the cache
+func (s *SupabaseClient) RefreshSignalAnalytics() ([]*models.SignalAnalytics, error) {
+	s.analyticsMu.Lock()
+	defer s.analyticsMu.Unlock()
+
+	return s.refreshSignalAnalyticsLocked()
+}
+
+// InvalidateSignalAnalytics drops the cached analytics so the next read recomputes them
+func (s *SupabaseClient) InvalidateSignalAnalytics() {
+	s.analyticsMu.Lock()
+	defer s.analyticsMu.Unlock()
+
+	s.analyticsCache = nil
+}
+
+func (s *SupabaseClient) refreshSignalAnalyticsLocked() ([]*models.SignalAnalytics, error) {
+	analytics, err := s.querySignalAnalytics()
+	if err != nil {
+		return nil, err
+	}
+
+	if analytics == nil {
+		analytics = []*models.SignalAnalytics{}
+	}
+	s.analyticsCache = analytics
+	s.analyticsCachedAt = time.Now()
+	return analytics, nil
+}
+
+func (s *SupabaseClient) querySignalAnalytics() ([]*models.SignalAnalytics, error) {
 	query := `SELECT * FROM signal_analytics ORDER BY win_rate_percentage DESC`
 
 	rows, err := s.db.Query(query)
@@ -329,22 +956,83 @@ func (s *SupabaseClient) GetCryptoBySymbol(symbol string) (*models.Cryptocurrenc
 }
 
 func (s *SupabaseClient) LogSystem(level, component, message string, context map[string]interface{}) error {
+	if s.usingRest() {
+		return s.restClient.LogSystem(level, component, message, context)
+	}
 	query := `
-		INSERT INTO system_logs (level, component, message, context, created_at)
+		INSERT INTO system_logs (log_level, component, message, context, created_at)
 		VALUES ($1, $2, $3, $4, $5)`
 
+	if context == nil {
+		context = map[string]interface{}{}
+	}
 	contextJSON, _ := json.Marshal(context)
 
 	_, err := s.db.Exec(query, level, component, message, contextJSON, time.Now())
 	return err
 }
 
+// LogNotification records the delivery status of a notification
+func (s *SupabaseClient) LogNotification(entry *models.NotificationLog) error {
+	if s.usingRest() {
+		return s.restClient.LogNotification(entry)
+	}
+	query := `
+		INSERT INTO notification_logs (id, notification_type, recipient, message, signal_id, cryptocurrency_id, status, error_message, sent_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := s.db.Exec(query, entry.ID, entry.NotificationType, entry.Recipient, entry.Message, entry.SignalID,
+		entry.CryptocurrencyID, entry.Status, entry.ErrorMessage, entry.SentAt, entry.CreatedAt)
+	return err
+}
+
+// GetSystemLogs lists log entries newest first, optionally of one level
+func (s *SupabaseClient) GetSystemLogs(level string, limit int) ([]models.SystemLog, error) {
+	if s.usingRest() {
+		return s.restClient.GetSystemLogs(level, limit)
+	}
+	query := `
+		SELECT id, log_level, component, message, context, error_stack, created_at
+		FROM system_logs
+		WHERE $2 = '' OR log_level = $2
+		ORDER BY created_at DESC
+		LIMIT $1`
+
+	rows, err := s.db.Query(query, limit, level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query system logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []models.SystemLog
+	for rows.Next() {
+		var entry models.SystemLog
+		var contextJSON []byte
+		var errorStack sql.NullString
+
+		if err := rows.Scan(&entry.ID, &entry.LogLevel, &entry.Component, &entry.Message, &contextJSON, &errorStack, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan system log: %w", err)
+		}
+		if errorStack.Valid {
+			entry.ErrorStack = &errorStack.String
+		}
+		if len(contextJSON) > 0 {
+			if err := json.Unmarshal(contextJSON, &entry.Context); err != nil {
+				logrus.Warn("Failed to parse system log context: ", err)
+			}
+		}
+		logs = append(logs, entry)
+	}
+
+	return logs, rows.Err()
+}
+
 // GetCryptocurrencies retrieves all cryptocurrencies from database
 func (s *SupabaseClient) GetCryptocurrencies() ([]models.Cryptocurrency, error) {
-	if s.useRest {
+	if s.usingRest() {
 		return s.restClient.GetCryptocurrencies()
 	}
-	query := `SELECT id, symbol, name, cmc_id, contract_address, platform, slug, coingecko_id, is_active, created_at, updated_at FROM cryptocurrencies ORDER BY symbol`
+	query := `SELECT id, symbol, name, cmc_id, contract_address, platform, slug, coingecko_id, is_active, created_at, updated_at, consecutive_failures, price_precision FROM cryptocurrencies ORDER BY symbol`
 
 	rows, err := s.db.Query(query)
 	if err != nil {
@@ -367,6 +1055,8 @@ func (s *SupabaseClient) GetCryptocurrencies() ([]models.Cryptocurrency, error)
 			&crypto.IsActive,
 			&crypto.CreatedAt,
 			&crypto.UpdatedAt,
+			&crypto.ConsecutiveFailures,
+			&crypto.PricePrecision,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan cryptocurrency: %w", err)
@@ -378,13 +1068,17 @@ func (s *SupabaseClient) GetCryptocurrencies() ([]models.Cryptocurrency, error)
 }
 
 // CreateCryptocurrency creates a new cryptocurrency record
+// CreateCryptocurrency inserts a coin. If the symbol already exists (e.g. a
+// concurrent add), crypto is filled with the stored row and no error is returned.
 func (s *SupabaseClient) CreateCryptocurrency(crypto *models.Cryptocurrency) error {
-	if s.useRest {
+	if s.usingRest() {
 		return s.restClient.CreateCryptocurrency(crypto)
 	}
 	query := `
-		INSERT INTO cryptocurrencies (id, symbol, name, cmc_id, contract_address, platform, slug, coingecko_id, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO cryptocurrencies (id, symbol, name, cmc_id, contract_address, platform, slug, coingecko_id, is_active, created_at, updated_at, price_precision)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (symbol) DO NOTHING
+		RETURNING id
 	`
 
 	crypto.ID = uuid.New()
@@ -392,7 +1086,8 @@ func (s *SupabaseClient) CreateCryptocurrency(crypto *models.Cryptocurrency) err
 	now := time.Now()
 	crypto.UpdatedAt = &now
 
-	_, err := s.db.Exec(query,
+	var insertedID uuid.UUID
+	err := s.db.QueryRow(query,
 		crypto.ID,
 		crypto.Symbol,
 		crypto.Name,
@@ -404,8 +1099,12 @@ func (s *SupabaseClient) CreateCryptocurrency(crypto *models.Cryptocurrency) err
 		crypto.IsActive,
 		crypto.CreatedAt,
 		crypto.UpdatedAt,
-	)
+		crypto.PricePrecision,
+	).Scan(&insertedID)
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.loadExistingCryptocurrency(crypto)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create cryptocurrency: %w", err)
 	}
@@ -413,20 +1112,433 @@ func (s *SupabaseClient) CreateCryptocurrency(crypto *models.Cryptocurrency) err
 	return nil
 }
 
+// loadExistingCryptocurrency overwrites crypto with the stored row of its symbol
+func (s *SupabaseClient) loadExistingCryptocurrency(crypto *models.Cryptocurrency) error {
+	query := `
+		SELECT id, symbol, name, cmc_id, contract_address, platform, slug, coingecko_id, is_active, created_at, updated_at, consecutive_failures, price_precision
+		FROM cryptocurrencies
+		WHERE symbol = $1
+	`
+
+	var existing models.Cryptocurrency
+	err := s.db.QueryRow(query, crypto.Symbol).Scan(
+		&existing.ID,
+		&existing.Symbol,
+		&existing.Name,
+		&existing.CmcID,
+		&existing.ContractAddress,
+		&existing.Platform,
+		&existing.Slug,
+		&existing.CoingeckoID,
+		&existing.IsActive,
+		&existing.CreatedAt,
+		&existing.UpdatedAt,
+		&existing.ConsecutiveFailures,
+		&existing.PricePrecision,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to load existing cryptocurrency %s: %w", crypto.Symbol, err)
+	}
+
+	logrus.Info("Cryptocurrency ", crypto.Symbol, " already exists, using stored row")
+	*crypto = existing
+	return nil
+}
+
+// UpdateCryptocurrency updates the metadata of an existing cryptocurrency record
+func (s *SupabaseClient) UpdateCryptocurrency(crypto *models.Cryptocurrency) error {
+	if s.usingRest() {
+		return s.restClient.UpdateCryptocurrency(crypto)
+	}
+	query := `
+		UPDATE cryptocurrencies
+		SET name = $2, cmc_id = $3, contract_address = $4, platform = $5, slug = $6,
+		    coingecko_id = $7, is_active = $8, updated_at = $9, price_precision = $10
+		WHERE id = $1
+	`
+
+	now := time.Now()
+	crypto.UpdatedAt = &now
+
+	_, err := s.db.Exec(query,
+		crypto.ID,
+		crypto.Name,
+		crypto.CmcID,
+		crypto.ContractAddress,
+		crypto.Platform,
+		crypto.Slug,
+		crypto.CoingeckoID,
+		crypto.IsActive,
+		crypto.UpdatedAt,
+		crypto.PricePrecision,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update cryptocurrency: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCryptoFailureCount stores how many analysis cycles in a row a coin
+// has returned no market data
+func (s *SupabaseClient) UpdateCryptoFailureCount(id uuid.UUID, failures int) error {
+	if s.usingRest() {
+		return s.restClient.UpdateCryptoFailureCount(id, failures)
+	}
+	_, err := s.db.Exec(`UPDATE cryptocurrencies SET consecutive_failures = $2 WHERE id = $1`, id, failures)
+	if err != nil {
+		return fmt.Errorf("failed to update failure count: %w", err)
+	}
+	return nil
+}
+
+// DeleteCryptocurrency permanently removes a cryptocurrency record. Historical
+// signals, snapshots and notification logs are kept but detached from the coin
+// (their cryptocurrency_id is set to NULL).
+func (s *SupabaseClient) DeleteCryptocurrency(symbol string) error {
+	if s.usingRest() {
+		return s.restClient.DeleteCryptocurrency(symbol)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id uuid.UUID
+	err = tx.QueryRow(`SELECT id FROM cryptocurrencies WHERE symbol = $1`, symbol).Scan(&id)
+	if err == sql.ErrNoRows {
+		return ErrCryptocurrencyNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up cryptocurrency: %w", err)
+	}
+
+	for _, table := range []string{"trading_signals", "market_snapshots", "notification_logs"} {
+		query := fmt.Sprintf(`UPDATE %s SET cryptocurrency_id = NULL WHERE cryptocurrency_id = $1`, table)
+		if _, err := tx.Exec(query, id); err != nil {
+			return fmt.Errorf("failed to detach %s: %w", table, err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM cryptocurrencies WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete cryptocurrency: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetStrategyProfiles loads every per-coin strategy override
+func (s *SupabaseClient) GetStrategyProfiles() ([]*models.StrategyProfile, error) {
+	if s.usingRest() {
+		return s.restClient.GetStrategyProfiles()
+	}
+	query := `
+		SELECT symbol, min_confidence, rsi_oversold, rsi_overbought, stop_loss_percentage,
+		       take_profit_levels, enabled_indicators, updated_at
+		FROM strategy_profiles
+		ORDER BY symbol
+	`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query strategy profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []*models.StrategyProfile
+	for rows.Next() {
+		var profile models.StrategyProfile
+		var minConfidence, rsiOversold, rsiOverbought, stopLoss sql.NullFloat64
+		var takeProfitJSON, indicatorsJSON []byte
+		if err := rows.Scan(
+			&profile.Symbol,
+			&minConfidence,
+			&rsiOversold,
+			&rsiOverbought,
+			&stopLoss,
+			&takeProfitJSON,
+			&indicatorsJSON,
+			&profile.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan strategy profile: %w", err)
+		}
+
+		profile.MinConfidence = nullableFloat(minConfidence)
+		profile.RSIOversold = nullableFloat(rsiOversold)
+		profile.RSIOverbought = nullableFloat(rsiOverbought)
+		profile.StopLossPercentage = nullableFloat(stopLoss)
+		if len(takeProfitJSON) > 0 {
+			if err := json.Unmarshal(takeProfitJSON, &profile.TakeProfitLevels); err != nil {
+				return nil, fmt.Errorf("invalid take_profit_levels for %s: %w", profile.Symbol, err)
+			}
+		}
+		if len(indicatorsJSON) > 0 {
+			if err := json.Unmarshal(indicatorsJSON, &profile.EnabledIndicators); err != nil {
+				return nil, fmt.Errorf("invalid enabled_indicators for %s: %w", profile.Symbol, err)
+			}
+		}
+		profiles = append(profiles, &profile)
+	}
+
+	return profiles, rows.Err()
+}
+
+// UpsertStrategyProfile creates or replaces the strategy override of a coin
+func (s *SupabaseClient) UpsertStrategyProfile(profile *models.StrategyProfile) error {
+	if s.usingRest() {
+		return s.restClient.UpsertStrategyProfile(profile)
+	}
+	query := `
+		INSERT INTO strategy_profiles (
+			symbol, min_confidence, rsi_oversold, rsi_overbought, stop_loss_percentage,
+			take_profit_levels, enabled_indicators, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (symbol) DO UPDATE SET
+			min_confidence = EXCLUDED.min_confidence,
+			rsi_oversold = EXCLUDED.rsi_oversold,
+			rsi_overbought = EXCLUDED.rsi_overbought,
+			stop_loss_percentage = EXCLUDED.stop_loss_percentage,
+			take_profit_levels = EXCLUDED.take_profit_levels,
+			enabled_indicators = EXCLUDED.enabled_indicators,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	profile.UpdatedAt = time.Now()
+
+	var takeProfitJSON, indicatorsJSON []byte
+	if len(profile.TakeProfitLevels) > 0 {
+		takeProfitJSON, _ = json.Marshal(profile.TakeProfitLevels)
+	}
+	if len(profile.EnabledIndicators) > 0 {
+		indicatorsJSON, _ = json.Marshal(profile.EnabledIndicators)
+	}
+
+	_, err := s.db.Exec(query,
+		profile.Symbol,
+		profile.MinConfidence,
+		profile.RSIOversold,
+		profile.RSIOverbought,
+		profile.StopLossPercentage,
+		takeProfitJSON,
+		indicatorsJSON,
+		profile.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save strategy profile: %w", err)
+	}
+
+	return nil
+}
+
+// GetBotSetting loads one bot_settings row by key
+func (s *SupabaseClient) GetBotSetting(key string) (*models.BotSetting, error) {
+	if s.usingRest() {
+		return s.restClient.GetBotSetting(key)
+	}
+	query := `
+		SELECT id, setting_key, setting_value, description, data_type, created_at, updated_at
+		FROM bot_settings
+		WHERE setting_key = $1`
+
+	setting := &models.BotSetting{}
+	err := s.db.QueryRow(query, key).Scan(
+		&setting.ID,
+		&setting.SettingKey,
+		&setting.SettingValue,
+		&setting.Description,
+		&setting.DataType,
+		&setting.CreatedAt,
+		&setting.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrBotSettingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bot setting %s: %w", key, err)
+	}
+	return setting, nil
+}
+
+// UpsertBotSetting inserts a setting or replaces the value of an existing key
+func (s *SupabaseClient) UpsertBotSetting(setting *models.BotSetting) error {
+	if s.usingRest() {
+		return s.restClient.UpsertBotSetting(setting)
+	}
+	query := `
+		INSERT INTO bot_settings (setting_key, setting_value, description, data_type, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (setting_key) DO UPDATE SET
+			setting_value = EXCLUDED.setting_value,
+			description = EXCLUDED.description,
+			data_type = EXCLUDED.data_type,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	setting.UpdatedAt = time.Now()
+	_, err := s.db.Exec(query,
+		setting.SettingKey,
+		setting.SettingValue,
+		setting.Description,
+		setting.DataType,
+		setting.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save bot setting %s: %w", setting.SettingKey, err)
+	}
+	return nil
+}
+
+// GetRemovedDefaults lists the default coins the user removed from the watchlist
+func (s *SupabaseClient) GetRemovedDefaults() ([]string, error) {
+	if s.usingRest() {
+		return s.restClient.GetRemovedDefaults()
+	}
+
+	rows, err := s.db.Query(`SELECT symbol FROM watchlist_overrides ORDER BY symbol`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watchlist overrides: %w", err)
+	}
+	defer rows.Close()
+
+	var symbols []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist override: %w", err)
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, rows.Err()
+}
+
+// SetDefaultRemoved records (or forgets) that the user removed a default coin
+func (s *SupabaseClient) SetDefaultRemoved(symbol string, removed bool) error {
+	if s.usingRest() {
+		return s.restClient.SetDefaultRemoved(symbol, removed)
+	}
+
+	var err error
+	if removed {
+		_, err = s.db.Exec(`
+			INSERT INTO watchlist_overrides (symbol, removed_at) VALUES ($1, $2)
+			ON CONFLICT (symbol) DO NOTHING
+		`, symbol, time.Now())
+	} else {
+		_, err = s.db.Exec(`DELETE FROM watchlist_overrides WHERE symbol = $1`, symbol)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update watchlist override: %w", err)
+	}
+
+	return nil
+}
+
+// ClearRemovedDefaults forgets every removed default so seeding restores them
+func (s *SupabaseClient) ClearRemovedDefaults() error {
+	if s.usingRest() {
+		return s.restClient.ClearRemovedDefaults()
+	}
+
+	if _, err := s.db.Exec(`DELETE FROM watchlist_overrides`); err != nil {
+		return fmt.Errorf("failed to clear watchlist overrides: %w", err)
+	}
+
+	return nil
+}
+
+func nullableFloat(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
+
 // GetRecentSignals retrieves recent trading signals
 func (s *SupabaseClient) GetRecentSignals(limit int) ([]models.TradingSignal, error) {
-	if s.useRest {
+	if s.usingRest() {
 		return s.restClient.GetRecentSignals(limit)
 	}
-	query := `
+	return s.queryRecentSignals("", limit, 0)
+}
+
+// GetSignalsByPriority retrieves the most recent signals of one priority level
+func (s *SupabaseClient) GetSignalsByPriority(priority string, limit int) ([]models.TradingSignal, error) {
+	if s.usingRest() {
+		return s.restClient.GetSignalsByPriority(priority, limit)
+	}
+	return s.queryRecentSignals("WHERE priority = $1", limit, 0, priority)
+}
+
+// GetSignalsFiltered retrieves one page of the signals matching filter,
+// newest first, and how many match in total
+func (s *SupabaseClient) GetSignalsFiltered(filter models.SignalFilter) ([]models.TradingSignal, int, error) {
+	if s.usingRest() {
+		return s.restClient.GetSignalsFiltered(filter)
+	}
+
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Symbol != "" {
+		add("crypto_id IN (SELECT id FROM cryptocurrencies WHERE symbol = $%d)", filter.Symbol)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.Priority != "" {
+		add("priority = $%d", filter.Priority)
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("created_at < $%d", filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM trading_signals "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count signals: %w", err)
+	}
+	if total == 0 || filter.Offset >= total {
+		return []models.TradingSignal{}, total, nil
+	}
+
+	signals, err := s.queryRecentSignals(where, filter.Limit, filter.Offset, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return signals, total, nil
+}
+
+// queryRecentSignals lists signals newest first; where may reference args
+// from $1, limit and offset follow them
+func (s *SupabaseClient) queryRecentSignals(where string, limit, offset int, args ...interface{}) ([]models.TradingSignal, error) {
+	query := fmt.Sprintf(`
 		SELECT id, crypto_id, action, confidence_score, entry_price, stop_loss,
-		       take_profit_1, take_profit_2, market_conditions, created_at
+		       take_profit_1, take_profit_2, market_conditions, created_at, priority, ref_code,
+		       data_quality, status
 		FROM trading_signals
+		%s
 		ORDER BY created_at DESC
-		LIMIT $1
-	`
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
 
-	rows, err := s.db.Query(query, limit)
+	rows, err := s.db.Query(query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query recent signals: %w", err)
 	}
@@ -436,6 +1548,7 @@ func (s *SupabaseClient) GetRecentSignals(limit int) ([]models.TradingSignal, er
 	for rows.Next() {
 		var signal models.TradingSignal
 		var marketConditionsJSON []byte
+		var priority, refCode, status sql.NullString
 
 		err := rows.Scan(
 			&signal.ID,
@@ -448,10 +1561,17 @@ func (s *SupabaseClient) GetRecentSignals(limit int) ([]models.TradingSignal, er
 			&signal.TakeProfit2,
 			&marketConditionsJSON,
 			&signal.CreatedAt,
+			&priority,
+			&refCode,
+			&signal.DataQuality,
+			&status,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan signal: %w", err)
 		}
+		signal.Priority = priority.String
+		signal.RefCode = refCode.String
+		signal.Status = status.String
 
 		// Parse market conditions JSON
 		if len(marketConditionsJSON) > 0 {
@@ -459,6 +1579,7 @@ func (s *SupabaseClient) GetRecentSignals(limit int) ([]models.TradingSignal, er
 				logrus.Warn("Failed to parse market conditions: ", err)
 			}
 		}
+		restoreTakeProfits(&signal)
 
 		signals = append(signals, signal)
 	}
@@ -468,15 +1589,20 @@ func (s *SupabaseClient) GetRecentSignals(limit int) ([]models.TradingSignal, er
 
 // GetSignalByID retrieves a specific trading signal by ID
 func (s *SupabaseClient) GetSignalByID(id string) (*models.TradingSignal, error) {
+	if s.usingRest() {
+		return s.restClient.GetSignalByID(id)
+	}
 	query := `
 		SELECT id, crypto_id, action, confidence_score, entry_price, stop_loss,
-		       take_profit_1, take_profit_2, market_conditions, created_at
+		       take_profit_1, take_profit_2, market_conditions, created_at, context,
+		       telegram_message_id, data_quality, entry_low, entry_high
 		FROM trading_signals
 		WHERE id = $1
 	`
 
 	var signal models.TradingSignal
-	var marketConditionsJSON []byte
+	var marketConditionsJSON, contextJSON []byte
+	var messageID sql.NullInt64
 
 	err := s.db.QueryRow(query, id).Scan(
 		&signal.ID,
@@ -489,11 +1615,124 @@ func (s *SupabaseClient) GetSignalByID(id string) (*models.TradingSignal, error)
 		&signal.TakeProfit2,
 		&marketConditionsJSON,
 		&signal.CreatedAt,
+		&contextJSON,
+		&messageID,
+		&signal.DataQuality,
+		&signal.EntryLow,
+		&signal.EntryHigh,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get signal: %w", err)
 	}
+	signal.TelegramMessageID = nullableMessageID(messageID)
+
+	// Parse market conditions JSON
+	if len(marketConditionsJSON) > 0 {
+		if err := json.Unmarshal(marketConditionsJSON, &signal.MarketConditions); err != nil {
+			logrus.Warn("Failed to parse market conditions: ", err)
+		}
+	}
+	restoreTakeProfits(&signal)
+	signal.Context = parseSignalContext(contextJSON)
+
+	return &signal, nil
+}
+
+// parseSignalContext decodes the optional context column; NULL yields nil
+func parseSignalContext(data []byte) *models.SignalContext {
+	if len(data) == 0 {
+		return nil
+	}
+	var context models.SignalContext
+	if err := json.Unmarshal(data, &context); err != nil {
+		logrus.Warn("Failed to parse signal context: ", err)
+		return nil
+	}
+	return &context
+}
+
+// restoreTakeProfits fills in a signal's scaled targets, which are persisted
+// in market_conditions rather than in a column of their own
+func restoreTakeProfits(signal *models.TradingSignal) {
+	raw, exists := signal.MarketConditions["take_profits"]
+	if !exists || len(signal.TakeProfits) > 0 {
+		return
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return
+	}
+	var targets []models.TakeProfitTarget
+	if err := json.Unmarshal(data, &targets); err != nil {
+		logrus.Warn("Failed to parse take profit targets: ", err)
+		return
+	}
+	signal.TakeProfits = targets
+}
+
+// signalSource defaults signals without an explicit source to internal analysis
+func signalSource(signal *models.TradingSignal) string {
+	if signal.Source == "" {
+		return "internal"
+	}
+	return signal.Source
+}
+
+// GetSignalByRefCode retrieves a trading signal by its shareable reference code
+func (s *SupabaseClient) GetSignalByRefCode(code string) (*models.TradingSignal, error) {
+	if s.usingRest() {
+		return s.restClient.GetSignalByRefCode(code)
+	}
+	query := `
+		SELECT id, crypto_id, ref_code, source, action, confidence_score, entry_price, stop_loss,
+		       take_profit_1, take_profit_2, reasoning, market_conditions, created_at, status, context,
+		       priority, telegram_message_id, data_quality, entry_low, entry_high, user_action
+		FROM trading_signals
+		WHERE ref_code = $1
+	`
+
+	var signal models.TradingSignal
+	var refCode, source, reasoning, status, priority, userAction sql.NullString
+	var marketConditionsJSON, contextJSON []byte
+	var messageID sql.NullInt64
+
+	err := s.db.QueryRow(query, code).Scan(
+		&signal.ID,
+		&signal.CryptoID,
+		&refCode,
+		&source,
+		&signal.Action,
+		&signal.ConfidenceScore,
+		&signal.EntryPrice,
+		&signal.StopLoss,
+		&signal.TakeProfit1,
+		&signal.TakeProfit2,
+		&reasoning,
+		&marketConditionsJSON,
+		&signal.CreatedAt,
+		&status,
+		&contextJSON,
+		&priority,
+		&messageID,
+		&signal.DataQuality,
+		&signal.EntryLow,
+		&signal.EntryHigh,
+		&userAction,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signal by ref code: %w", err)
+	}
+	signal.TelegramMessageID = nullableMessageID(messageID)
+
+	signal.RefCode = refCode.String
+	signal.Source = source.String
+	signal.Priority = priority.String
+	signal.Reasoning = reasoning.String
+	signal.Status = status.String
+	signal.UserAction = userAction.String
 
 	// Parse market conditions JSON
 	if len(marketConditionsJSON) > 0 {
@@ -501,10 +1740,26 @@ func (s *SupabaseClient) GetSignalByID(id string) (*models.TradingSignal, error)
 			logrus.Warn("Failed to parse market conditions: ", err)
 		}
 	}
+	restoreTakeProfits(&signal)
+	signal.Context = parseSignalContext(contextJSON)
 
 	return &signal, nil
 }
 
+// SignalRefCodeExists reports whether a reference code is already taken
+func (s *SupabaseClient) SignalRefCodeExists(code string) (bool, error) {
+	if s.usingRest() {
+		return s.restClient.SignalRefCodeExists(code)
+	}
+
+	var exists bool
+	err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM trading_signals WHERE ref_code = $1)`, code).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check ref code: %w", err)
+	}
+	return exists, nil
+}
+
 // GetLearningInsights retrieves learning insights from analytics view
 func (s *SupabaseClient) GetLearningInsights() (map[string]interface{}, error) {
 	query := `
@@ -533,6 +1788,44 @@ func (s *SupabaseClient) GetLearningInsights() (map[string]interface{}, error) {
 		"period":                "30 days",
 	}
 
+	// Prediction accuracy over records whose prediction has been scored
+	accuracyQuery := `
+		SELECT COUNT(*), COALESCE(AVG(prediction_accuracy), 0)
+		FROM learning_data
+		WHERE created_at >= NOW() - INTERVAL '30 days'
+		  AND predicted_outcome IS NOT NULL AND predicted_outcome <> ''
+		  AND actual_outcome IS NOT NULL AND actual_outcome <> ''
+	`
+
+	var evaluated int
+	var accuracy float64
+	if err := s.db.QueryRow(accuracyQuery).Scan(&evaluated, &accuracy); err != nil {
+		return nil, fmt.Errorf("failed to get prediction accuracy: %w", err)
+	}
+	insights["evaluated_predictions"] = evaluated
+	insights["prediction_accuracy"] = accuracy * 100
+
+	// Time-decayed win rate alongside the raw one, weighting each record by
+	// 0.5^(age / half-life)
+	if s.cfg.LearningHalfLifeDays > 0 {
+		weightedQuery := `
+			SELECT COALESCE(
+				SUM(CASE WHEN outcome = 'WIN' THEN w ELSE 0 END) / NULLIF(SUM(w), 0), 0)
+			FROM (
+				SELECT outcome, POWER(0.5, EXTRACT(EPOCH FROM NOW() - created_at) / ($1 * 86400)) AS w
+				FROM learning_data
+				WHERE created_at >= NOW() - INTERVAL '30 days'
+			) weighted
+		`
+
+		var weightedWinRate float64
+		if err := s.db.QueryRow(weightedQuery, s.cfg.LearningHalfLifeDays).Scan(&weightedWinRate); err != nil {
+			return nil, fmt.Errorf("failed to get weighted learning insights: %w", err)
+		}
+		insights["time_weighted_win_rate"] = weightedWinRate * 100
+		insights["half_life_days"] = s.cfg.LearningHalfLifeDays
+	}
+
 	return insights, nil
 }
 
@@ -4,13 +4,18 @@ import (
 	"bytes"
 	"crypto-signal-bot/internal/config"
 	"crypto-signal-bot/internal/models"
+	"crypto-signal-bot/internal/utils"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
 
@@ -24,11 +29,24 @@ func NewSupabaseRestClient(cfg *config.Config) *SupabaseRestClient {
 	return &SupabaseRestClient{
 		baseURL:    cfg.SupabaseURL,
 		serviceKey: cfg.SupabaseServiceKey,
-		client:     &http.Client{Timeout: 30 * time.Second},
+		client:     utils.NewHTTPClient(30*time.Second, cfg.DebugHTTP),
 	}
 }
 
 func (s *SupabaseRestClient) makeRequest(method, endpoint string, data interface{}) (*http.Response, error) {
+	prefer := ""
+	if method == "POST" {
+		prefer = "return=minimal"
+	}
+	if method == "DELETE" {
+		// Return deleted rows so callers can tell whether anything matched
+		prefer = "return=representation"
+	}
+	return s.makeRequestWithPrefer(method, endpoint, data, prefer)
+}
+
+// makeRequestWithPrefer sends a request with an explicit PostgREST Prefer header
+func (s *SupabaseRestClient) makeRequestWithPrefer(method, endpoint string, data interface{}, prefer string) (*http.Response, error) {
 	url := fmt.Sprintf("%s/rest/v1/%s", s.baseURL, endpoint)
 	
 	var body io.Reader
@@ -48,9 +66,8 @@ func (s *SupabaseRestClient) makeRequest(method, endpoint string, data interface
 	req.Header.Set("apikey", s.serviceKey)
 	req.Header.Set("Authorization", "Bearer "+s.serviceKey)
 	req.Header.Set("Content-Type", "application/json")
-	
-	if method == "POST" {
-		req.Header.Set("Prefer", "return=minimal")
+	if prefer != "" {
+		req.Header.Set("Prefer", prefer)
 	}
 
 	return s.client.Do(req)
@@ -63,6 +80,9 @@ func (s *SupabaseRestClient) TestConnection() error {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return fmt.Errorf("%w: REST API rejected the service key: %s", ErrMisconfigured, resp.Status)
+	}
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("REST API test failed: %s - %s", resp.Status, string(body))
@@ -72,7 +92,10 @@ func (s *SupabaseRestClient) TestConnection() error {
 	return nil
 }
 
-func (s *SupabaseRestClient) CreateSignal(signal *models.TradingSignal) error {
+// CreateSignal stores a signal and then its outbox entry. PostgREST can't
+// wrap both in one transaction, so when the outbox write fails the signal is
+// deleted again and the error returned, as the SQL path's rollback would.
+func (s *SupabaseRestClient) CreateSignal(signal *models.TradingSignal, outbox *models.OutboxEntry) error {
 	data := map[string]interface{}{
 		"id":                signal.ID,
 		"crypto_id":         signal.CryptoID,
@@ -101,6 +124,13 @@ func (s *SupabaseRestClient) CreateSignal(signal *models.TradingSignal) error {
 		"timeframe":         signal.Timeframe,
 		"created_at":        signal.CreatedAt,
 		"status":            signal.Status,
+		"ref_code":          utils.StringPtr(signal.RefCode),
+		"source":            signalSource(signal),
+		"context":           signal.Context,
+		"priority":          utils.StringPtr(signal.Priority),
+		"data_quality":      signal.DataQuality,
+		"entry_low":         signal.EntryLow,
+		"entry_high":        signal.EntryHigh,
 	}
 
 	resp, err := s.makeRequest("POST", "trading_signals", data)
@@ -114,10 +144,108 @@ func (s *SupabaseRestClient) CreateSignal(signal *models.TradingSignal) error {
 		return fmt.Errorf("failed to create signal: %s - %s", resp.Status, string(body))
 	}
 
+	if outbox != nil {
+		if err := s.createOutboxEntry(outbox); err != nil {
+			// A signal without its outbox entry would never have a failed
+			// notification retried, so take it back and fail the whole write
+			if deleteErr := s.deleteSignal(signal.ID); deleteErr != nil {
+				logrus.Error("Failed to remove signal ", signal.ID, " after its outbox write failed: ", deleteErr)
+			}
+			return fmt.Errorf("failed to create signal outbox entry: %w", err)
+		}
+	}
+
 	logrus.Info("✅ Signal created successfully via REST API: ", signal.ID)
 	return nil
 }
 
+// deleteSignal removes a signal that was just created
+func (s *SupabaseRestClient) deleteSignal(id uuid.UUID) error {
+	endpoint := fmt.Sprintf("trading_signals?id=eq.%s", id)
+	resp, err := s.makeRequestWithPrefer("DELETE", endpoint, nil, "return=minimal")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete signal: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+func (s *SupabaseRestClient) createOutboxEntry(entry *models.OutboxEntry) error {
+	data := map[string]interface{}{
+		"signal_id":       entry.SignalID,
+		"payload":         entry.Payload,
+		"status":          entry.Status,
+		"attempts":        entry.Attempts,
+		"next_attempt_at": entry.NextAttemptAt,
+		"created_at":      entry.CreatedAt,
+	}
+
+	resp, err := s.makeRequest("POST", "signal_outbox", data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create outbox entry: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+func (s *SupabaseRestClient) GetPendingOutbox(limit int) ([]*models.OutboxEntry, error) {
+	endpoint := fmt.Sprintf("signal_outbox?status=eq.pending&next_attempt_at=lte.%s&order=created_at&limit=%d",
+		url.QueryEscape(time.Now().UTC().Format(time.RFC3339)), limit)
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get signal outbox: %s - %s", resp.Status, string(body))
+	}
+
+	var entries []*models.OutboxEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *SupabaseRestClient) UpdateOutboxEntry(entry *models.OutboxEntry) error {
+	data := map[string]interface{}{
+		"status":          entry.Status,
+		"attempts":        entry.Attempts,
+		"last_error":      utils.StringPtr(entry.LastError),
+		"next_attempt_at": entry.NextAttemptAt,
+		"sent_at":         entry.SentAt,
+	}
+
+	endpoint := fmt.Sprintf("signal_outbox?signal_id=eq.%s", entry.SignalID.String())
+	resp, err := s.makeRequest("PATCH", endpoint, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update outbox entry: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
 func (s *SupabaseRestClient) GetActiveSignals() ([]*models.TradingSignal, error) {
 	resp, err := s.makeRequest("GET", "trading_signals?status=eq.active&order=created_at.desc", nil)
 	if err != nil {
@@ -139,6 +267,9 @@ func (s *SupabaseRestClient) GetActiveSignals() ([]*models.TradingSignal, error)
 	if err := json.Unmarshal(body, &signals); err != nil {
 		return nil, err
 	}
+	for _, signal := range signals {
+		restoreTakeProfits(signal)
+	}
 
 	return signals, nil
 }
@@ -163,6 +294,46 @@ func (s *SupabaseRestClient) UpdateSignalStatus(signalID uuid.UUID, status strin
 	return nil
 }
 
+func (s *SupabaseRestClient) SetSignalTelegramMessageID(signalID uuid.UUID, messageID int) error {
+	data := map[string]interface{}{
+		"telegram_message_id": messageID,
+	}
+
+	endpoint := fmt.Sprintf("trading_signals?id=eq.%s", signalID.String())
+	resp, err := s.makeRequest("PATCH", endpoint, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to set signal telegram message id: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+func (s *SupabaseRestClient) SetSignalUserAction(signalID uuid.UUID, action string) error {
+	data := map[string]interface{}{
+		"user_action": action,
+	}
+
+	endpoint := fmt.Sprintf("trading_signals?id=eq.%s", signalID.String())
+	resp, err := s.makeRequest("PATCH", endpoint, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to set signal user action: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
 func (s *SupabaseRestClient) GetCryptocurrencies() ([]models.Cryptocurrency, error) {
 	resp, err := s.makeRequest("GET", "cryptocurrencies?order=symbol", nil)
 	if err != nil {
@@ -204,25 +375,317 @@ func (s *SupabaseRestClient) CreateCryptocurrency(crypto *models.Cryptocurrency)
 		"is_active":        crypto.IsActive,
 		"created_at":       crypto.CreatedAt,
 		"updated_at":       crypto.UpdatedAt,
+		"price_precision":  crypto.PricePrecision,
 	}
 
-	resp, err := s.makeRequest("POST", "cryptocurrencies", data)
+	// Skip the insert when the symbol exists; only a newly inserted row comes back
+	resp, err := s.makeRequestWithPrefer("POST", "cryptocurrencies?on_conflict=symbol", data,
+		"resolution=ignore-duplicates,return=representation")
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 201 {
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("failed to create cryptocurrency: %s - %s", resp.Status, string(body))
 	}
 
+	var inserted []models.Cryptocurrency
+	if err := json.NewDecoder(resp.Body).Decode(&inserted); err != nil {
+		return err
+	}
+	if len(inserted) > 0 {
+		return nil
+	}
+
+	return s.loadExistingCryptocurrency(crypto)
+}
+
+// loadExistingCryptocurrency overwrites crypto with the stored row of its symbol
+func (s *SupabaseRestClient) loadExistingCryptocurrency(crypto *models.Cryptocurrency) error {
+	endpoint := fmt.Sprintf("cryptocurrencies?symbol=eq.%s&limit=1", url.QueryEscape(crypto.Symbol))
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to load existing cryptocurrency %s: %s - %s", crypto.Symbol, resp.Status, string(body))
+	}
+
+	var existing []models.Cryptocurrency
+	if err := json.NewDecoder(resp.Body).Decode(&existing); err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return fmt.Errorf("failed to create cryptocurrency %s: insert ignored but no existing row found", crypto.Symbol)
+	}
+
+	logrus.Info("Cryptocurrency ", crypto.Symbol, " already exists, using stored row")
+	*crypto = existing[0]
+	return nil
+}
+
+func (s *SupabaseRestClient) UpdateCryptocurrency(crypto *models.Cryptocurrency) error {
+	now := time.Now()
+	crypto.UpdatedAt = &now
+
+	data := map[string]interface{}{
+		"name":             crypto.Name,
+		"cmc_id":           crypto.CmcID,
+		"contract_address": crypto.ContractAddress,
+		"platform":         crypto.Platform,
+		"slug":             crypto.Slug,
+		"coingecko_id":     crypto.CoingeckoID,
+		"is_active":        crypto.IsActive,
+		"updated_at":       crypto.UpdatedAt,
+		"price_precision":  crypto.PricePrecision,
+	}
+
+	endpoint := fmt.Sprintf("cryptocurrencies?id=eq.%s", crypto.ID.String())
+	resp, err := s.makeRequest("PATCH", endpoint, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update cryptocurrency: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+func (s *SupabaseRestClient) UpdateCryptoFailureCount(id uuid.UUID, failures int) error {
+	data := map[string]interface{}{
+		"consecutive_failures": failures,
+	}
+
+	endpoint := fmt.Sprintf("cryptocurrencies?id=eq.%s", id.String())
+	resp, err := s.makeRequest("PATCH", endpoint, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update failure count: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+// DeleteCryptocurrency deletes a cryptocurrency by symbol. Related rows are
+// detached by the ON DELETE SET NULL foreign keys in the schema.
+func (s *SupabaseRestClient) DeleteCryptocurrency(symbol string) error {
+	endpoint := fmt.Sprintf("cryptocurrencies?symbol=eq.%s", url.QueryEscape(symbol))
+	resp, err := s.makeRequest("DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete cryptocurrency: %s - %s", resp.Status, string(body))
+	}
+
+	var deleted []models.Cryptocurrency
+	if err := json.NewDecoder(resp.Body).Decode(&deleted); err != nil {
+		return fmt.Errorf("failed to decode delete response: %w", err)
+	}
+	if len(deleted) == 0 {
+		return ErrCryptocurrencyNotFound
+	}
+
+	return nil
+}
+
+func (s *SupabaseRestClient) GetStrategyProfiles() ([]*models.StrategyProfile, error) {
+	resp, err := s.makeRequest("GET", "strategy_profiles?order=symbol", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get strategy profiles: %s - %s", resp.Status, string(body))
+	}
+
+	var profiles []*models.StrategyProfile
+	if err := json.NewDecoder(resp.Body).Decode(&profiles); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+func (s *SupabaseRestClient) UpsertStrategyProfile(profile *models.StrategyProfile) error {
+	profile.UpdatedAt = time.Now()
+
+	// Explicit nulls so a PUT clears overrides that were dropped
+	data := map[string]interface{}{
+		"symbol":               profile.Symbol,
+		"min_confidence":       profile.MinConfidence,
+		"rsi_oversold":         profile.RSIOversold,
+		"rsi_overbought":       profile.RSIOverbought,
+		"stop_loss_percentage": profile.StopLossPercentage,
+		"take_profit_levels":   nil,
+		"enabled_indicators":   nil,
+		"updated_at":           profile.UpdatedAt,
+	}
+	if len(profile.TakeProfitLevels) > 0 {
+		data["take_profit_levels"] = profile.TakeProfitLevels
+	}
+	if len(profile.EnabledIndicators) > 0 {
+		data["enabled_indicators"] = profile.EnabledIndicators
+	}
+
+	resp, err := s.makeRequestWithPrefer("POST", "strategy_profiles?on_conflict=symbol", data,
+		"resolution=merge-duplicates,return=minimal")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 201 && resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to save strategy profile: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+func (s *SupabaseRestClient) GetBotSetting(key string) (*models.BotSetting, error) {
+	resp, err := s.makeRequest("GET", fmt.Sprintf("bot_settings?setting_key=eq.%s&limit=1", url.QueryEscape(key)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get bot setting %s: %s - %s", key, resp.Status, string(body))
+	}
+
+	var settings []models.BotSetting
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("failed to decode bot setting: %w", err)
+	}
+	if len(settings) == 0 {
+		return nil, ErrBotSettingNotFound
+	}
+
+	return &settings[0], nil
+}
+
+func (s *SupabaseRestClient) UpsertBotSetting(setting *models.BotSetting) error {
+	setting.UpdatedAt = time.Now()
+
+	data := map[string]interface{}{
+		"setting_key":   setting.SettingKey,
+		"setting_value": setting.SettingValue,
+		"description":   setting.Description,
+		"data_type":     setting.DataType,
+		"updated_at":    setting.UpdatedAt,
+	}
+
+	resp, err := s.makeRequestWithPrefer("POST", "bot_settings?on_conflict=setting_key", data,
+		"resolution=merge-duplicates,return=minimal")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 201 && resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to save bot setting %s: %s - %s", setting.SettingKey, resp.Status, string(body))
+	}
+
+	return nil
+}
+
+func (s *SupabaseRestClient) GetRemovedDefaults() ([]string, error) {
+	resp, err := s.makeRequest("GET", "watchlist_overrides?select=symbol&order=symbol", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get watchlist overrides: %s - %s", resp.Status, string(body))
+	}
+
+	var rows []struct {
+		Symbol string `json:"symbol"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, err
+	}
+
+	symbols := make([]string, 0, len(rows))
+	for _, row := range rows {
+		symbols = append(symbols, row.Symbol)
+	}
+	return symbols, nil
+}
+
+func (s *SupabaseRestClient) SetDefaultRemoved(symbol string, removed bool) error {
+	var resp *http.Response
+	var err error
+	if removed {
+		data := map[string]interface{}{
+			"symbol":     symbol,
+			"removed_at": time.Now(),
+		}
+		resp, err = s.makeRequestWithPrefer("POST", "watchlist_overrides?on_conflict=symbol", data,
+			"resolution=ignore-duplicates,return=minimal")
+	} else {
+		endpoint := fmt.Sprintf("watchlist_overrides?symbol=eq.%s", url.QueryEscape(symbol))
+		resp, err = s.makeRequestWithPrefer("DELETE", endpoint, nil, "return=minimal")
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 201 && resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update watchlist override: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+func (s *SupabaseRestClient) ClearRemovedDefaults() error {
+	// PostgREST refuses a DELETE without a filter
+	resp, err := s.makeRequestWithPrefer("DELETE", "watchlist_overrides?symbol=not.is.null", nil, "return=minimal")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to clear watchlist overrides: %s - %s", resp.Status, string(body))
+	}
+
 	return nil
 }
 
 func (s *SupabaseRestClient) LogSystem(level, component, message string, context map[string]interface{}) error {
+	if context == nil {
+		context = map[string]interface{}{}
+	}
 	data := map[string]interface{}{
-		"level":      level,
+		"log_level":  level,
 		"component":  component,
 		"message":    message,
 		"context":    context,
@@ -243,6 +706,22 @@ func (s *SupabaseRestClient) LogSystem(level, component, message string, context
 	return nil
 }
 
+// LogNotification records the delivery status of a notification
+func (s *SupabaseRestClient) LogNotification(entry *models.NotificationLog) error {
+	resp, err := s.makeRequest("POST", "notification_logs", entry)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to log notification: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
 // Implement other methods as needed...
 func (s *SupabaseRestClient) GetRecentSignals(limit int) ([]models.TradingSignal, error) {
 	endpoint := fmt.Sprintf("trading_signals?order=created_at.desc&limit=%d", limit)
@@ -266,10 +745,310 @@ func (s *SupabaseRestClient) GetRecentSignals(limit int) ([]models.TradingSignal
 	if err := json.Unmarshal(body, &signals); err != nil {
 		return nil, err
 	}
+	for i := range signals {
+		restoreTakeProfits(&signals[i])
+	}
+
+	return signals, nil
+}
+
+func (s *SupabaseRestClient) GetSignalsByPriority(priority string, limit int) ([]models.TradingSignal, error) {
+	endpoint := fmt.Sprintf("trading_signals?priority=eq.%s&order=created_at.desc&limit=%d", url.QueryEscape(priority), limit)
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get signals by priority: %s - %s", resp.Status, string(body))
+	}
+
+	var signals []models.TradingSignal
+	if err := json.NewDecoder(resp.Body).Decode(&signals); err != nil {
+		return nil, err
+	}
+	for i := range signals {
+		restoreTakeProfits(&signals[i])
+	}
 
 	return signals, nil
 }
 
+// GetSignalsFiltered fetches one page of the matching signals with an exact
+// count, which PostgREST reports in the Content-Range header
+func (s *SupabaseRestClient) GetSignalsFiltered(filter models.SignalFilter) ([]models.TradingSignal, int, error) {
+	params := url.Values{}
+	params.Set("order", "created_at.desc")
+	params.Set("limit", strconv.Itoa(filter.Limit))
+	params.Set("offset", strconv.Itoa(filter.Offset))
+	if filter.Symbol != "" {
+		cryptoID, err := s.cryptoIDBySymbol(filter.Symbol)
+		if err != nil {
+			return nil, 0, err
+		}
+		if cryptoID == uuid.Nil {
+			return []models.TradingSignal{}, 0, nil
+		}
+		params.Set("crypto_id", "eq."+cryptoID.String())
+	}
+	if filter.Action != "" {
+		params.Set("action", "eq."+filter.Action)
+	}
+	if filter.Status != "" {
+		params.Set("status", "eq."+filter.Status)
+	}
+	if filter.Priority != "" {
+		params.Set("priority", "eq."+filter.Priority)
+	}
+	if !filter.From.IsZero() {
+		params.Add("created_at", "gte."+filter.From.UTC().Format(time.RFC3339))
+	}
+	if !filter.To.IsZero() {
+		params.Add("created_at", "lt."+filter.To.UTC().Format(time.RFC3339))
+	}
+
+	resp, err := s.makeRequestWithPrefer("GET", "trading_signals?"+params.Encode(), nil, "count=exact")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	// An offset past the last row answers 416 with the total still in Content-Range
+	if resp.StatusCode != 200 && resp.StatusCode != 206 && resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, 0, fmt.Errorf("failed to get filtered signals: %s - %s", resp.Status, string(body))
+	}
+
+	// Content-Range looks like "0-49/123", or "*/0" without rows
+	contentRange := resp.Header.Get("Content-Range")
+	total, err := strconv.Atoi(contentRange[strings.LastIndex(contentRange, "/")+1:])
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read signal count from Content-Range %q", contentRange)
+	}
+
+	signals := []models.TradingSignal{}
+	if resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		if err := json.NewDecoder(resp.Body).Decode(&signals); err != nil {
+			return nil, 0, err
+		}
+	}
+	for i := range signals {
+		restoreTakeProfits(&signals[i])
+	}
+
+	return signals, total, nil
+}
+
+// cryptoIDBySymbol looks up a coin's ID; uuid.Nil when the symbol is unknown
+func (s *SupabaseRestClient) cryptoIDBySymbol(symbol string) (uuid.UUID, error) {
+	endpoint := fmt.Sprintf("cryptocurrencies?select=id&symbol=eq.%s&limit=1", url.QueryEscape(symbol))
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return uuid.Nil, fmt.Errorf("failed to look up cryptocurrency %s: %s - %s", symbol, resp.Status, string(body))
+	}
+
+	var rows []struct {
+		ID uuid.UUID `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return uuid.Nil, err
+	}
+	if len(rows) == 0 {
+		return uuid.Nil, nil
+	}
+	return rows[0].ID, nil
+}
+
+func (s *SupabaseRestClient) GetPerformanceOutcomes(since time.Time) ([]*models.SignalPerformance, error) {
+	endpoint := fmt.Sprintf("signal_performance?select=id,signal_id,entry_price,pnl_percentage,entry_time,exit_time,outcome,signal:trading_signals(id,confidence_score,data_quality)&entry_time=gte.%s&outcome=in.(profit,loss,breakeven)&order=entry_time.asc",
+		url.QueryEscape(since.UTC().Format(time.RFC3339)))
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get performance outcomes: %s - %s", resp.Status, string(body))
+	}
+
+	var outcomes []*models.SignalPerformance
+	if err := json.NewDecoder(resp.Body).Decode(&outcomes); err != nil {
+		return nil, err
+	}
+
+	return outcomes, nil
+}
+
+func (s *SupabaseRestClient) GetSignalByRefCode(code string) (*models.TradingSignal, error) {
+	endpoint := fmt.Sprintf("trading_signals?ref_code=eq.%s&limit=1", url.QueryEscape(code))
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get signal by ref code: %s - %s", resp.Status, string(body))
+	}
+
+	var signals []models.TradingSignal
+	if err := json.NewDecoder(resp.Body).Decode(&signals); err != nil {
+		return nil, err
+	}
+	for i := range signals {
+		restoreTakeProfits(&signals[i])
+	}
+
+	if len(signals) == 0 {
+		return nil, fmt.Errorf("signal with ref code %s not found", code)
+	}
+
+	return &signals[0], nil
+}
+
+func (s *SupabaseRestClient) GetSignalByID(id string) (*models.TradingSignal, error) {
+	endpoint := fmt.Sprintf("trading_signals?id=eq.%s&limit=1", url.QueryEscape(id))
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get signal: %s - %s", resp.Status, string(body))
+	}
+
+	var signals []models.TradingSignal
+	if err := json.NewDecoder(resp.Body).Decode(&signals); err != nil {
+		return nil, err
+	}
+	for i := range signals {
+		restoreTakeProfits(&signals[i])
+	}
+
+	if len(signals) == 0 {
+		return nil, fmt.Errorf("failed to get signal: %s not found", id)
+	}
+
+	return &signals[0], nil
+}
+
+func (s *SupabaseRestClient) SignalRefCodeExists(code string) (bool, error) {
+	endpoint := fmt.Sprintf("trading_signals?ref_code=eq.%s&select=id&limit=1", url.QueryEscape(code))
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("failed to check ref code: %s - %s", resp.Status, string(body))
+	}
+
+	var rows []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return false, err
+	}
+
+	return len(rows) > 0, nil
+}
+
+func (s *SupabaseRestClient) SaveRejectedSignal(rejected *models.RejectedSignal) error {
+	data := map[string]interface{}{
+		"id":                rejected.ID,
+		"crypto_id":         rejected.CryptoID,
+		"symbol":            rejected.Symbol,
+		"reason":            rejected.Reason,
+		"details":           rejected.Details,
+		"confidence_score":  rejected.ConfidenceScore,
+		"entry_price":       rejected.EntryPrice,
+		"stop_loss":         rejected.StopLoss,
+		"take_profit_1":     rejected.TakeProfit1,
+		"take_profit_2":     rejected.TakeProfit2,
+		"reasoning":         rejected.Reasoning,
+		"market_conditions": rejected.MarketConditions,
+		"timeframe":         rejected.Timeframe,
+		"created_at":        rejected.CreatedAt,
+	}
+	if rejected.Action != "" {
+		data["action"] = rejected.Action
+	}
+
+	resp, err := s.makeRequest("POST", "rejected_signals", data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to save rejected signal: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+func (s *SupabaseRestClient) GetSystemLogs(level string, limit int) ([]models.SystemLog, error) {
+	endpoint := fmt.Sprintf("system_logs?order=created_at.desc&limit=%d", limit)
+	if level != "" {
+		endpoint += "&log_level=eq." + url.QueryEscape(level)
+	}
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get system logs: %s - %s", resp.Status, string(body))
+	}
+
+	var logs []models.SystemLog
+	if err := json.NewDecoder(resp.Body).Decode(&logs); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (s *SupabaseRestClient) GetRejectedSignals(reason string, limit int) ([]models.RejectedSignal, error) {
+	endpoint := fmt.Sprintf("rejected_signals?order=created_at.desc&limit=%d", limit)
+	if reason != "" {
+		endpoint += "&reason=eq." + url.QueryEscape(reason)
+	}
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get rejected signals: %s - %s", resp.Status, string(body))
+	}
+
+	var rejected []models.RejectedSignal
+	if err := json.NewDecoder(resp.Body).Decode(&rejected); err != nil {
+		return nil, err
+	}
+
+	return rejected, nil
+}
+
 func (s *SupabaseRestClient) SaveMarketSnapshot(snapshot *models.MarketSnapshot) error {
 	// Create minimal data that should always work
 	// Use only basic fields that definitely exist
@@ -324,6 +1103,75 @@ func (s *SupabaseRestClient) SaveMarketSnapshot(snapshot *models.MarketSnapshot)
 	return nil
 }
 
+func (s *SupabaseRestClient) GetLearningDataBySignal(signalID uuid.UUID) (*models.LearningData, error) {
+	endpoint := fmt.Sprintf("learning_data?signal_id=eq.%s&order=created_at.desc&limit=1", signalID.String())
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get learning data: %s - %s", resp.Status, string(body))
+	}
+
+	var rows []models.LearningData
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("failed to decode learning data: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrLearningDataNotFound
+	}
+
+	return &rows[0], nil
+}
+
+func (s *SupabaseRestClient) GetLearningOutcomes(since time.Time) ([]*models.LearningData, error) {
+	endpoint := fmt.Sprintf("learning_data?select=id,signal_id,features,actual_outcome,actual_pnl_percentage,created_at&created_at=gte.%s&actual_outcome=not.is.null&order=created_at.asc",
+		url.QueryEscape(since.UTC().Format(time.RFC3339)))
+	resp, err := s.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get learning outcomes: %s - %s", resp.Status, string(body))
+	}
+
+	var records []*models.LearningData
+	if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode learning outcomes: %w", err)
+	}
+
+	return records, nil
+}
+
+func (s *SupabaseRestClient) UpdateLearningDataOutcome(signalID uuid.UUID, outcome string, pnl decimal.Decimal, durationMinutes int, accuracy decimal.Decimal) error {
+	data := map[string]interface{}{
+		"actual_outcome":          outcome,
+		"actual_pnl_percentage":   pnl,
+		"actual_duration_minutes": durationMinutes,
+		"prediction_accuracy":     accuracy,
+	}
+
+	// PATCH can't report matches under return=minimal; callers load the record first
+	resp, err := s.makeRequest("PATCH", fmt.Sprintf("learning_data?signal_id=eq.%s", signalID.String()), data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update learning data: %s - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
 func (s *SupabaseRestClient) Close() error {
 	// No connection to close for REST client
 	return nil
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"crypto-signal-bot/internal/config"
+	"crypto-signal-bot/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRestCreateSignalRemovesSignalWhenOutboxFails(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.String(), "/rest/v1/"))
+		mu.Unlock()
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/rest/v1/trading_signals":
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "POST" && r.URL.Path == "/rest/v1/signal_outbox":
+			http.Error(w, `{"message":"permission denied"}`, http.StatusForbidden)
+		case r.Method == "DELETE" && r.URL.Path == "/rest/v1/trading_signals":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "unexpected request", http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewSupabaseRestClient(&config.Config{SupabaseURL: ts.URL, SupabaseServiceKey: "key"})
+	signal := &models.TradingSignal{ID: uuid.New(), CryptoID: uuid.New(), Action: "BUY", CreatedAt: time.Now()}
+	outbox := &models.OutboxEntry{SignalID: signal.ID, Status: "pending", NextAttemptAt: time.Now(), CreatedAt: time.Now()}
+
+	if err := client.CreateSignal(signal, outbox); err == nil {
+		t.Fatal("CreateSignal succeeded although the outbox write failed")
+	}
+
+	want := []string{
+		"POST trading_signals",
+		"POST signal_outbox",
+		"DELETE trading_signals?id=eq." + signal.ID.String(),
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests:\n%s\nwant:\n%s", strings.Join(requests, "\n"), strings.Join(want, "\n"))
+	}
+}
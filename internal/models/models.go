@@ -20,18 +20,28 @@ type Cryptocurrency struct {
 	IsActive        bool       `json:"is_active" db:"is_active"`
 	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt       *time.Time `json:"updated_at" db:"updated_at"`
+
+	ConsecutiveFailures int  `json:"consecutive_failures" db:"consecutive_failures"` // Analysis cycles in a row without market data
+	PricePrecision      *int `json:"price_precision" db:"price_precision"`           // Price decimals from the exchange tick size; nil uses SIGNAL_PRICE_PRECISION
 }
 
 // TradingSignal represents a trading signal
 type TradingSignal struct {
 	ID               uuid.UUID              `json:"id" db:"id"`
 	CryptoID         uuid.UUID              `json:"crypto_id" db:"crypto_id"`
+	RefCode          string                 `json:"ref_code" db:"ref_code"` // Short shareable reference, e.g. BTC-240612-A3F
+	Source           string                 `json:"source" db:"source"` // internal, tradingview
+	Priority         string                 `json:"priority,omitempty" db:"priority"` // low, medium, high
+	DataQuality      *decimal.Decimal       `json:"data_quality,omitempty" db:"data_quality"` // 0-1, from candle count, source, enrichment and freshness
 	Action           string                 `json:"action" db:"action"` // BUY, SELL, HOLD
 	ConfidenceScore  decimal.Decimal        `json:"confidence_score" db:"confidence_score"`
 	EntryPrice       decimal.Decimal        `json:"entry_price" db:"entry_price"`
+	EntryLow         *decimal.Decimal       `json:"entry_low,omitempty" db:"entry_low"`   // Entry zone bounds; nil for a single entry price
+	EntryHigh        *decimal.Decimal       `json:"entry_high,omitempty" db:"entry_high"`
 	StopLoss         *decimal.Decimal       `json:"stop_loss" db:"stop_loss"`
 	TakeProfit1      *decimal.Decimal       `json:"take_profit_1" db:"take_profit_1"`
 	TakeProfit2      *decimal.Decimal       `json:"take_profit_2" db:"take_profit_2"`
+	TakeProfits      []TakeProfitTarget     `json:"take_profits,omitempty" db:"-"` // Scaled targets, persisted in market_conditions
 	Reasoning        string                 `json:"reasoning" db:"reasoning"`
 	
 	// Technical indicators at signal time
@@ -58,8 +68,93 @@ type TradingSignal struct {
 	CreatedAt        time.Time              `json:"created_at" db:"created_at"`
 	Status           string                 `json:"status" db:"status"` // active, expired, triggered, cancelled
 	
+	Context          *SignalContext         `json:"context,omitempty" db:"context"` // Set when STORE_SIGNAL_CONTEXT is on
+	TelegramMessageID *int                  `json:"telegram_message_id,omitempty" db:"telegram_message_id"` // Original notification, replied to by lifecycle updates
+	UserAction       string                 `json:"user_action,omitempty" db:"user_action"` // taken or ignored, from the notification buttons
+	
 	// Related data (not stored in DB)
 	Crypto           *Cryptocurrency        `json:"crypto,omitempty"`
+	PriceHistory     []decimal.Decimal      `json:"-" db:"-"` // Recent closes for display, oldest first
+}
+
+// StrategyProfile holds one coin's overrides of the global strategy settings.
+// Nil or empty fields fall back to the config.
+type StrategyProfile struct {
+	Symbol             string    `json:"symbol" db:"symbol"`
+	MinConfidence      *float64  `json:"min_confidence,omitempty" db:"min_confidence"`
+	RSIOversold        *float64  `json:"rsi_oversold,omitempty" db:"rsi_oversold"`
+	RSIOverbought      *float64  `json:"rsi_overbought,omitempty" db:"rsi_overbought"`
+	StopLossPercentage *float64  `json:"stop_loss_percentage,omitempty" db:"stop_loss_percentage"`
+	TakeProfitLevels   []float64 `json:"take_profit_levels,omitempty" db:"take_profit_levels"` // Overrides TAKE_PROFIT_LEVELS
+	EnabledIndicators  []string  `json:"enabled_indicators,omitempty" db:"enabled_indicators"` // Empty enables all
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// RejectedSignal is a BUY/SELL setup that a filter kept from becoming a signal,
+// stored when LOG_REJECTED_SIGNALS is on to review how strict the filters are
+type RejectedSignal struct {
+	ID               uuid.UUID              `json:"id" db:"id"`
+	CryptoID         uuid.UUID              `json:"crypto_id" db:"crypto_id"`
+	Symbol           string                 `json:"symbol" db:"symbol"`
+	Reason           string                 `json:"reason" db:"reason"` // confidence, confidence_smoothing, daily_limit, invalid_indicators, indicator_conflict, htf_trend, volatility_window, source_divergence, cycle_limit
+	Details          string                 `json:"details,omitempty" db:"details"`
+	Action           string                 `json:"action,omitempty" db:"action"` // Direction before the filter; empty when no decision was made
+	ConfidenceScore  decimal.Decimal        `json:"confidence_score" db:"confidence_score"`
+	EntryPrice       decimal.Decimal        `json:"entry_price" db:"entry_price"`
+	StopLoss         *decimal.Decimal       `json:"stop_loss,omitempty" db:"stop_loss"`
+	TakeProfit1      *decimal.Decimal       `json:"take_profit_1,omitempty" db:"take_profit_1"`
+	TakeProfit2      *decimal.Decimal       `json:"take_profit_2,omitempty" db:"take_profit_2"`
+	Reasoning        string                 `json:"reasoning,omitempty" db:"reasoning"`
+	MarketConditions map[string]interface{} `json:"market_conditions,omitempty" db:"market_conditions"`
+	Timeframe        string                 `json:"timeframe" db:"timeframe"`
+	CreatedAt        time.Time              `json:"created_at" db:"created_at"`
+}
+
+// OutboxEntry is the pending notification of a signal, written together with
+// the signal so a crash or a failed send can't lose it
+type OutboxEntry struct {
+	SignalID      uuid.UUID      `json:"signal_id" db:"signal_id"`
+	Payload       *TradingSignal `json:"payload" db:"payload"` // The signal as it was when created, crypto included
+	Status        string         `json:"status" db:"status"`   // pending, sent, failed
+	Attempts      int            `json:"attempts" db:"attempts"`
+	LastError     string         `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt time.Time      `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	SentAt        *time.Time     `json:"sent_at,omitempty" db:"sent_at"`
+}
+
+// SignalContext is the chart state a signal was generated from, kept so the
+// signal can be audited or replayed later
+type SignalContext struct {
+	Candles    []ContextCandle              `json:"candles"`
+	Indicators map[string][]decimal.Decimal `json:"indicators"` // Series aligned with Candles
+}
+
+// ContextCandle is one OHLCV candle of a SignalContext
+type ContextCandle struct {
+	OpenTime time.Time       `json:"open_time"`
+	Open     decimal.Decimal `json:"open"`
+	High     decimal.Decimal `json:"high"`
+	Low      decimal.Decimal `json:"low"`
+	Close    decimal.Decimal `json:"close"`
+	Volume   decimal.Decimal `json:"volume"`
+}
+
+// Candle is one OHLCV kline as served by the API; prices encode as strings
+type Candle struct {
+	Timestamp int64           `json:"timestamp"` // Open time, Unix milliseconds
+	Open      decimal.Decimal `json:"open"`
+	High      decimal.Decimal `json:"high"`
+	Low       decimal.Decimal `json:"low"`
+	Close     decimal.Decimal `json:"close"`
+	Volume    decimal.Decimal `json:"volume"`
+}
+
+// TakeProfitTarget represents one scaled take-profit level of a signal
+type TakeProfitTarget struct {
+	Level      int             `json:"level"`
+	Price      decimal.Decimal `json:"price"`
+	Allocation decimal.Decimal `json:"allocation"` // Fraction of the position closed at this level (0-1)
 }
 
 // SignalPerformance tracks the performance of a trading signal
@@ -183,6 +278,20 @@ type SignalAnalytics struct {
 	AvgConfidence       decimal.Decimal `json:"avg_confidence" db:"avg_confidence"`
 }
 
+// PerformanceRollup aggregates closed signal outcomes over one period bucket
+type PerformanceRollup struct {
+	Period      string          `json:"period"`       // daily, weekly, monthly, all
+	PeriodStart time.Time       `json:"period_start"` // UTC start of the bucket
+	Signals     int             `json:"signals"`
+	Wins        int             `json:"wins"`
+	Losses      int             `json:"losses"`
+	WinRate     decimal.Decimal `json:"win_rate"`
+	TotalPnL    decimal.Decimal `json:"total_pnl"`
+	AvgPnL      decimal.Decimal `json:"avg_pnl"`
+	BestPnL     decimal.Decimal `json:"best_pnl"`
+	WorstPnL    decimal.Decimal `json:"worst_pnl"`
+}
+
 type LearningInsight struct {
 	Date              time.Time `json:"date" db:"date"`
 	SignalsGenerated  int       `json:"signals_generated" db:"signals_generated"`
@@ -193,10 +302,30 @@ type LearningInsight struct {
 
 // API Response models
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Message string      `json:"message,omitempty"`
+	Success    bool        `json:"success"`
+	Data       interface{} `json:"data,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"` // Set by paged listings
+}
+
+// Pagination describes the page of a listing returned in Data
+type Pagination struct {
+	Total  int `json:"total"` // Matching rows across all pages
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// SignalFilter narrows a signal listing; zero fields don't filter
+type SignalFilter struct {
+	Symbol   string
+	Action   string    // BUY, SELL, HOLD
+	Status   string    // active, triggered, expired, cancelled
+	Priority string    // low, medium, high
+	From     time.Time // created_at at or after
+	To       time.Time // created_at before
+	Limit    int
+	Offset   int
 }
 
 
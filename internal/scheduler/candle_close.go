@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// candleCloseSchedule returns the UTC cron spec (with seconds) that fires
+// delaySeconds after each close of a timeframe's candles, e.g. 4h closes at
+// 00/04/08/... UTC
+func candleCloseSchedule(timeframe string, delaySeconds int) (string, error) {
+	if delaySeconds < 0 || delaySeconds > 59 {
+		return "", fmt.Errorf("candle close delay must be between 0 and 59 seconds, got %d", delaySeconds)
+	}
+
+	var fields string
+	switch timeframe {
+	case "1m":
+		fields = "* * * * *"
+	case "3m", "5m", "15m", "30m":
+		fields = fmt.Sprintf("*/%s * * * *", timeframe[:len(timeframe)-1])
+	case "1h":
+		fields = "0 * * * *"
+	case "2h", "4h", "6h", "8h", "12h":
+		fields = fmt.Sprintf("0 */%s * * *", timeframe[:len(timeframe)-1])
+	case "1d":
+		fields = "0 0 * * *"
+	default:
+		return "", fmt.Errorf("unsupported candle close timeframe: %s", timeframe)
+	}
+
+	return fmt.Sprintf("CRON_TZ=UTC %d %s", delaySeconds, fields), nil
+}
+
+// scheduleCandleCloseAnalysis adds one analysis job per configured timeframe,
+// each running on that timeframe's klines right after its candles close
+func (s *Scheduler) scheduleCandleCloseAnalysis() error {
+	for _, timeframe := range s.cfg.AnalysisCandleCloses {
+		schedule, err := candleCloseSchedule(timeframe, s.cfg.AnalysisCandleCloseDelaySeconds)
+		if err != nil {
+			return err
+		}
+
+		timeframe := timeframe
+		entryID, err := s.cron.AddFunc(schedule, func() { s.runCandleCloseAnalysis(timeframe) })
+		if err != nil {
+			return fmt.Errorf("failed to add %s candle close analysis job: %w", timeframe, err)
+		}
+		s.analysisEntryIDs = append(s.analysisEntryIDs, entryID)
+		logrus.Info("✅ Market analysis scheduled on ", timeframe, " candle closes: ", schedule)
+	}
+
+	return nil
+}
+
+// runCandleCloseAnalysis analyzes one timeframe. Closes that coincide (a 4h
+// close is also a 1h close) run one after another rather than in parallel.
+func (s *Scheduler) runCandleCloseAnalysis(timeframe string) {
+	s.candleCloseMu.Lock()
+	defer s.candleCloseMu.Unlock()
+
+	logrus.Info("🕯️ ", timeframe, " candle closed, starting market analysis...")
+	start := time.Now()
+
+	if err := s.botService.RunCandleCloseAnalysis(timeframe); err != nil {
+		logrus.Error(timeframe, " candle close analysis failed: ", err)
+		s.sendErrorNotification("Market Analysis Failed", err.Error())
+		return
+	}
+
+	logrus.Info("✅ ", timeframe, " candle close analysis completed in ", time.Since(start))
+}
+
+// nextAnalysisRun returns the earliest upcoming run among the analysis jobs
+func (s *Scheduler) nextAnalysisRun() (time.Time, bool) {
+	var next time.Time
+	for _, id := range s.analysisEntryIDs {
+		entry := s.cron.Entry(id)
+		if !entry.Valid() || entry.Next.IsZero() {
+			continue
+		}
+		if next.IsZero() || entry.Next.Before(next) {
+			next = entry.Next
+		}
+	}
+	return next, !next.IsZero()
+}
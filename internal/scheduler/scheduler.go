@@ -4,6 +4,7 @@ import (
 	"crypto-signal-bot/internal/config"
 	"crypto-signal-bot/internal/services"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -15,6 +16,9 @@ type Scheduler struct {
 	cfg        *config.Config
 	botService *services.BotService
 	isRunning  bool
+
+	analysisEntryIDs []cron.EntryID // Regular market analysis jobs, for next-run lookups
+	candleCloseMu    sync.Mutex     // Serializes candle close analyses that fire together
 }
 
 func NewScheduler(cfg *config.Config, botService *services.BotService) *Scheduler {
@@ -32,25 +36,33 @@ func NewScheduler(cfg *config.Config, botService *services.BotService) *Schedule
 func (s *Scheduler) Start() error {
 	logrus.Info("⏰ Starting scheduler...")
 
-	// Market analysis job - every 15 minutes during market hours
-	analysisSchedule := fmt.Sprintf("0 */15 * * * *") // Every 15 minutes
-	if s.cfg.AnalysisIntervalSeconds > 0 {
-		// Custom interval in minutes
-		intervalMinutes := s.cfg.AnalysisIntervalSeconds / 60
-		if intervalMinutes < 1 {
-			intervalMinutes = 1
+	if len(s.cfg.AnalysisCandleCloses) > 0 {
+		// Market analysis jobs - on candle closes of the configured timeframes
+		if err := s.scheduleCandleCloseAnalysis(); err != nil {
+			return err
+		}
+	} else {
+		// Market analysis job - every 15 minutes during market hours
+		analysisSchedule := fmt.Sprintf("0 */15 * * * *") // Every 15 minutes
+		if s.cfg.AnalysisIntervalSeconds > 0 {
+			// Custom interval in minutes
+			intervalMinutes := s.cfg.AnalysisIntervalSeconds / 60
+			if intervalMinutes < 1 {
+				intervalMinutes = 1
+			}
+			analysisSchedule = fmt.Sprintf("0 */%d * * * *", intervalMinutes)
 		}
-		analysisSchedule = fmt.Sprintf("0 */%d * * * *", intervalMinutes)
-	}
 
-	_, err := s.cron.AddFunc(analysisSchedule, s.runMarketAnalysis)
-	if err != nil {
-		return fmt.Errorf("failed to add market analysis job: %w", err)
+		analysisEntryID, err := s.cron.AddFunc(analysisSchedule, s.runMarketAnalysis)
+		if err != nil {
+			return fmt.Errorf("failed to add market analysis job: %w", err)
+		}
+		s.analysisEntryIDs = append(s.analysisEntryIDs, analysisEntryID)
+		logrus.Info("✅ Market analysis scheduled: ", analysisSchedule)
 	}
-	logrus.Info("✅ Market analysis scheduled: ", analysisSchedule)
 
 	// Performance tracking job - every hour
-	_, err = s.cron.AddFunc("0 0 * * * *", s.updatePerformanceTracking)
+	_, err := s.cron.AddFunc("0 0 * * * *", s.updatePerformanceTracking)
 	if err != nil {
 		return fmt.Errorf("failed to add performance tracking job: %w", err)
 	}
@@ -77,6 +89,27 @@ func (s *Scheduler) Start() error {
 	}
 	logrus.Info("✅ Cleanup scheduled: 02:00 daily")
 
+	// Metadata backfill job - at 03:00 every day
+	_, err = s.cron.AddFunc("0 0 3 * * *", s.runMetadataBackfill)
+	if err != nil {
+		return fmt.Errorf("failed to add metadata backfill job: %w", err)
+	}
+	logrus.Info("✅ Metadata backfill scheduled: 03:00 daily")
+
+	// Watchlist pruning job - every hour at :30
+	_, err = s.cron.AddFunc("0 30 * * * *", s.runWatchlistPrune)
+	if err != nil {
+		return fmt.Errorf("failed to add watchlist prune job: %w", err)
+	}
+	logrus.Info("✅ Watchlist pruning scheduled: every hour at :30")
+
+	// Signal outbox job - every minute
+	_, err = s.cron.AddFunc("0 * * * * *", s.runOutboxDelivery)
+	if err != nil {
+		return fmt.Errorf("failed to add outbox delivery job: %w", err)
+	}
+	logrus.Info("✅ Signal outbox delivery scheduled: every minute")
+
 	// No health check needed for personal bot
 
 	// Start the cron scheduler
@@ -90,21 +123,33 @@ func (s *Scheduler) Start() error {
 func (s *Scheduler) Stop() {
 	logrus.Info("🛑 Stopping scheduler...")
 
+	// Mark stopped before waiting so Resume can restart cron while jobs drain
+	s.isRunning = false
 	if s.cron != nil {
 		ctx := s.cron.Stop()
 		<-ctx.Done() // Wait for all jobs to complete
 	}
 
-	s.isRunning = false
 	logrus.Info("✅ Scheduler stopped")
 }
 
+// Resume restarts a stopped scheduler with the jobs registered by Start
+func (s *Scheduler) Resume() {
+	if s.isRunning || s.cron == nil {
+		return
+	}
+
+	s.cron.Start()
+	s.isRunning = true
+	logrus.Info("✅ Scheduler resumed")
+}
+
 func (s *Scheduler) runMarketAnalysis() {
 	logrus.Info("🔍 Scheduled market analysis starting...")
 	
 	start := time.Now()
 	
-	if err := s.botService.RunAnalysis(); err != nil {
+	if err := s.botService.RunAnalysis(false); err != nil {
 		logrus.Error("Scheduled market analysis failed: ", err)
 		// Send error notification
 		s.sendErrorNotification("Market Analysis Failed", err.Error())
@@ -137,22 +182,29 @@ func (s *Scheduler) sendDailySummary() {
 	logrus.Info("✅ Daily summary sent")
 }
 
+func (s *Scheduler) runOutboxDelivery() {
+	sent, err := s.botService.DeliverOutbox()
+	if err != nil {
+		logrus.Error("Signal outbox delivery failed: ", err)
+		return
+	}
+	if sent > 0 {
+		logrus.Info("📬 Delivered ", sent, " pending signal notifications from the outbox")
+	}
+}
+
 func (s *Scheduler) runLearningOptimization() {
 	logrus.Info("🧠 Running learning optimization...")
 	
-	// Get performance metrics
-	metrics, err := s.botService.GetPerformanceMetrics()
+	result, err := s.botService.RunLearningOptimization()
 	if err != nil {
-		logrus.Error("Failed to get performance metrics: ", err)
+		logrus.Error("Failed to run learning optimization: ", err)
 		return
 	}
 	
-	logrus.Info("Current performance - Win Rate: ", metrics.WinRate.StringFixed(2), "%, Total Signals: ", metrics.TotalSignals)
-	
-	// TODO: Implement actual learning optimization
-	// This could include adjusting thresholds, weights, etc.
+	logrus.Info("Current performance - Win Rate: ", result.Metrics.WinRate.StringFixed(2), "%, Total Signals: ", result.Metrics.TotalSignals)
 	
-	logrus.Info("✅ Learning optimization completed")
+	logrus.Info("✅ Learning optimization completed (", len(result.Changes), " changes)")
 }
 
 func (s *Scheduler) runCleanup() {
@@ -167,12 +219,35 @@ func (s *Scheduler) runCleanup() {
 	logrus.Info("✅ Cleanup completed")
 }
 
+func (s *Scheduler) runMetadataBackfill() {
+	logrus.Info("🪪 Backfilling cryptocurrency metadata...")
+
+	if err := s.botService.BackfillCryptoMetadata(); err != nil {
+		logrus.Error("Failed to backfill cryptocurrency metadata: ", err)
+		return
+	}
+
+	logrus.Info("✅ Metadata backfill completed")
+}
+
+func (s *Scheduler) runWatchlistPrune() {
+	logrus.Info("🪓 Checking watchlist for coins without market data...")
+
+	if err := s.botService.PruneWatchlist(); err != nil {
+		logrus.Error("Failed to prune watchlist: ", err)
+		return
+	}
+
+	logrus.Info("✅ Watchlist check completed")
+}
+
 // Health check removed - not needed for personal bot
 
 func (s *Scheduler) sendErrorNotification(title, message string) {
 	// TODO: Implement error notification
 	// This could send alerts to Telegram or other channels
 	logrus.Error(title, ": ", message)
+	s.botService.RecordSystemLog("error", "scheduler", title+": "+message, nil)
 }
 
 func (s *Scheduler) GetStatus() map[string]interface{} {
@@ -194,17 +269,42 @@ func (s *Scheduler) GetStatus() map[string]interface{} {
 	}
 }
 
-func (s *Scheduler) AddCustomJob(schedule string, jobFunc func()) error {
+func (s *Scheduler) AddCustomJob(schedule string, jobFunc func()) (cron.EntryID, error) {
 	if !s.isRunning {
-		return fmt.Errorf("scheduler is not running")
+		return 0, fmt.Errorf("scheduler is not running")
 	}
 	
-	_, err := s.cron.AddFunc(schedule, jobFunc)
+	id, err := s.cron.AddFunc(schedule, jobFunc)
 	if err != nil {
-		return fmt.Errorf("failed to add custom job: %w", err)
+		return 0, fmt.Errorf("failed to add custom job: %w", err)
 	}
 	
 	logrus.Info("✅ Custom job added with schedule: ", schedule)
+	return id, nil
+}
+
+// ScheduleAnalysisAt runs a one-off market analysis at the given time (to the
+// second) and removes the job once it has fired
+func (s *Scheduler) ScheduleAnalysisAt(at time.Time) error {
+	at = at.In(s.cron.Location())
+	if !at.After(time.Now()) {
+		return fmt.Errorf("time %s is in the past", at.Format("15:04:05"))
+	}
+
+	// Cron has no year field; removing the entry on first run keeps it one-off
+	schedule := fmt.Sprintf("%d %d %d %d %d *", at.Second(), at.Minute(), at.Hour(), at.Day(), int(at.Month()))
+
+	entryID := make(chan cron.EntryID, 1)
+	id, err := s.AddCustomJob(schedule, func() {
+		s.cron.Remove(<-entryID)
+		logrus.Info("⏰ One-off market analysis scheduled for ", at.Format("15:04 02/01/2006"), " starting")
+		s.runMarketAnalysis()
+	})
+	if err != nil {
+		return err
+	}
+	entryID <- id
+
 	return nil
 }
 
@@ -222,6 +322,10 @@ func (s *Scheduler) RunJobNow(jobName string) error {
 		go s.runLearningOptimization()
 	case "cleanup":
 		go s.runCleanup()
+	case "metadata_backfill":
+		go s.runMetadataBackfill()
+	case "watchlist_prune":
+		go s.runWatchlistPrune()
 	default:
 		return fmt.Errorf("unknown job name: %s", jobName)
 	}
@@ -244,11 +348,10 @@ func (s *Scheduler) IsMarketHours() bool {
 	return true
 }
 
-// GetNextAnalysisTime returns the next scheduled analysis time
+// GetNextAnalysisTime returns the next run of the regular market analysis jobs
 func (s *Scheduler) GetNextAnalysisTime() time.Time {
-	entries := s.cron.Entries()
-	if len(entries) > 0 {
-		return entries[0].Next // First entry is usually the most frequent (market analysis)
+	if next, ok := s.nextAnalysisRun(); ok {
+		return next
 	}
 	return time.Now()
 }
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+// AnalysisScheduler is the part of the scheduler the Telegram commands drive;
+// the scheduler depends on BotService, so it is wired in from main
+type AnalysisScheduler interface {
+	GetNextAnalysisTime() time.Time
+	ScheduleAnalysisAt(at time.Time) error
+}
+
+// SetAnalysisScheduler registers the scheduler used by /nexttick and /runat
+func (bs *BotService) SetAnalysisScheduler(scheduler AnalysisScheduler) {
+	bs.analysisScheduler = scheduler
+}
+
+// NextAnalysisTime returns when the regular market analysis runs next
+func (bs *BotService) NextAnalysisTime() (time.Time, error) {
+	if bs.analysisScheduler == nil {
+		return time.Time{}, fmt.Errorf("scheduler not available")
+	}
+	return bs.analysisScheduler.GetNextAnalysisTime(), nil
+}
+
+// ScheduleAnalysisAt queues a one-off market analysis at the given time
+func (bs *BotService) ScheduleAnalysisAt(at time.Time) error {
+	if bs.analysisScheduler == nil {
+		return fmt.Errorf("scheduler not available")
+	}
+	return bs.analysisScheduler.ScheduleAnalysisAt(at)
+}
+
+// nextClockTime returns the next occurrence of an "HH:MM" clock time in now's
+// location: later today, or tomorrow if that minute has already started
+func nextClockTime(clock string, now time.Time) (time.Time, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected HH:MM", clock)
+	}
+
+	at := time.Date(now.Year(), now.Month(), now.Day(), parsed.Hour(), parsed.Minute(), 0, 0, now.Location())
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// keyRateLimitCooldown is how long a rate-limited key rests before reuse
+const keyRateLimitCooldown = time.Minute
+
+// cmcRateLimitErrorCode is CMC's per-minute rate limit status.error_code
+const cmcRateLimitErrorCode = 1008
+
+// keyLimitError reports that the key used for a request hit its quota or rate
+// limit, so the request should be retried with the next key
+type keyLimitError struct {
+	quota   bool // Plan credits ran out; otherwise a short-lived rate limit
+	resetAt time.Time
+	Err     error
+}
+
+func (e *keyLimitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *keyLimitError) Unwrap() error {
+	return e.Err
+}
+
+// newKeyLimitError classifies a failed response as a per-key quota or rate
+// limit, returning nil for failures that another key would not fix
+func newKeyLimitError(provider string, statusCode int, body []byte, err error) *keyLimitError {
+	now := time.Now()
+	if isQuotaResponse(provider, statusCode, body) {
+		return &keyLimitError{quota: true, resetAt: quotaResetTime(provider, body, now), Err: err}
+	}
+	if statusCode == http.StatusTooManyRequests || (provider == providerCoinMarketCap && cmcErrorCode(body) == cmcRateLimitErrorCode) {
+		return &keyLimitError{resetAt: now.Add(keyRateLimitCooldown), Err: err}
+	}
+	return nil
+}
+
+// cmcErrorCode extracts status.error_code from a CMC response body
+func cmcErrorCode(body []byte) int {
+	var cmcResp CMCQuoteResponse
+	if err := json.Unmarshal(body, &cmcResp); err != nil {
+		return 0
+	}
+	return cmcResp.Status.ErrorCode
+}
+
+// quotaResetTime estimates when an exhausted key gets credits again: CMC daily
+// and monthly limits reset at the UTC day/month boundary, anything else is
+// rechecked after quotaRecheckInterval
+func quotaResetTime(provider string, body []byte, now time.Time) time.Time {
+	if provider == providerCoinMarketCap {
+		utc := now.UTC()
+		switch cmcErrorCode(body) {
+		case 1009:
+			return time.Date(utc.Year(), utc.Month(), utc.Day()+1, 0, 0, 0, 0, time.UTC)
+		case 1010:
+			return time.Date(utc.Year(), utc.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		}
+	}
+	return now.Add(quotaRecheckInterval)
+}
+
+// apiKeyManager rotates through a provider's API keys, skipping keys that are
+// exhausted or rate limited until their reset time
+type apiKeyManager struct {
+	mu        sync.Mutex
+	provider  string
+	keys      []string
+	current   int
+	limitedAt map[int]*keyLimitError
+}
+
+// newAPIKeyManager builds a key manager; an empty list is treated as a single
+// keyless slot so public endpoints share the same rotation bookkeeping
+func newAPIKeyManager(provider string, keys []string) *apiKeyManager {
+	if len(keys) == 0 {
+		keys = []string{""}
+	}
+	return &apiKeyManager{
+		provider:  provider,
+		keys:      keys,
+		limitedAt: make(map[int]*keyLimitError),
+	}
+}
+
+// next returns the first usable key starting from the current one, clearing
+// keys whose reset time has passed; ok is false when every key is limited
+func (m *apiKeyManager) next() (key string, index int, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for offset := 0; offset < len(m.keys); offset++ {
+		i := (m.current + offset) % len(m.keys)
+		if limit, limited := m.limitedAt[i]; limited {
+			if now.Before(limit.resetAt) {
+				continue
+			}
+			delete(m.limitedAt, i)
+			logrus.Infof("%s API key %d/%d reset, back in rotation", m.provider, i+1, len(m.keys))
+		}
+		if i != m.current {
+			logrus.Infof("Rotating %s API key %d/%d -> %d/%d", m.provider, m.current+1, len(m.keys), i+1, len(m.keys))
+			m.current = i
+		}
+		return m.keys[i], i, true
+	}
+	return "", 0, false
+}
+
+// markLimited takes a key out of rotation until the limit resets
+func (m *apiKeyManager) markLimited(index int, limit *keyLimitError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.limitedAt[index] = limit
+	kind := "rate limited"
+	if limit.quota {
+		kind = "exhausted"
+	}
+	logrus.Warnf("%s API key %d/%d %s until %s: %v", m.provider, index+1, len(m.keys), kind,
+		limit.resetAt.Format(time.RFC3339), limit.Err)
+}
+
+// unavailableError explains why no key is usable. It is a quota exhaustion
+// only when every key is out of credits; rate limits alone are transient.
+func (m *apiKeyManager) unavailableError(op string) (quota bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	quota = true
+	var soonest time.Time
+	for _, limit := range m.limitedAt {
+		quota = quota && limit.quota
+		if soonest.IsZero() || limit.resetAt.Before(soonest) {
+			soonest = limit.resetAt
+		}
+	}
+
+	err = fmt.Errorf("all %d %s API keys limited until %s", len(m.keys), m.provider, soonest.Format(time.RFC3339))
+	if !quota {
+		return false, &TransientError{Op: op, Err: err}
+	}
+	return true, err
+}
+
+// available counts keys not currently limited
+func (m *apiKeyManager) available() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	available := 0
+	for i := range m.keys {
+		if limit, limited := m.limitedAt[i]; !limited || !now.Before(limit.resetAt) {
+			available++
+		}
+	}
+	return available
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/config"
+	"crypto-signal-bot/internal/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidBacktest wraps validation failures of a backtest request
+var ErrInvalidBacktest = errors.New("invalid backtest")
+
+// ErrBacktestDisabled is returned while BACKTEST_ENABLED is off
+var ErrBacktestDisabled = errors.New("backtesting is disabled (BACKTEST_ENABLED=false)")
+
+// Exit reasons of a simulated trade
+const (
+	backtestExitTakeProfit = "take_profit"
+	backtestExitStopLoss   = "stop_loss"
+	backtestExitMaxHold    = "max_hold"
+	backtestExitEndOfData  = "end_of_data"
+)
+
+// BacktestRequest selects the candles to replay. End defaults to now and
+// Interval to the analysis interval.
+type BacktestRequest struct {
+	Symbol         string    `json:"symbol"`
+	Interval       string    `json:"interval"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	MaxHoldCandles int       `json:"max_hold_candles"` // Close a trade after this many candles; 0 holds until SL/TP
+}
+
+// BacktestTrade is one simulated trade
+type BacktestTrade struct {
+	Action        string          `json:"action"`
+	Confidence    decimal.Decimal `json:"confidence"`
+	EntryTime     time.Time       `json:"entry_time"`
+	EntryPrice    decimal.Decimal `json:"entry_price"`
+	ExitTime      time.Time       `json:"exit_time"`
+	ExitPrice     decimal.Decimal `json:"exit_price"` // Price of the final exit
+	ExitReason    string          `json:"exit_reason"`
+	Outcome       string          `json:"outcome"`
+	PnLPercentage decimal.Decimal `json:"pnl_percentage"` // Allocation-weighted over partial exits
+}
+
+// BacktestResult reports a backtest in the shape of PerformanceMetrics, plus
+// the drawdown and every trade
+type BacktestResult struct {
+	Symbol   string    `json:"symbol"`
+	Interval string    `json:"interval"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Candles  int       `json:"candles"` // Candles replayed, warmup excluded
+
+	PerformanceMetrics
+	LosingSignals int             `json:"losing_signals"`
+	MaxDrawdown   decimal.Decimal `json:"max_drawdown"` // Percent, of the compounded equity curve
+
+	Trades []BacktestTrade `json:"trades"`
+}
+
+// Backtester replays historical candles through the signal generator and
+// simulates each signal's entry and exit against its stop loss and take
+// profits. One trade is open at a time.
+type Backtester struct {
+	cfg               *config.Config
+	dataCollector     *DataCollector
+	technicalAnalyzer *TechnicalAnalyzer
+	signalGenerator   *SignalGenerator
+}
+
+func NewBacktester(cfg *config.Config, dataCollector *DataCollector, technicalAnalyzer *TechnicalAnalyzer, signalGenerator *SignalGenerator) *Backtester {
+	return &Backtester{
+		cfg:               cfg,
+		dataCollector:     dataCollector,
+		technicalAnalyzer: technicalAnalyzer,
+		signalGenerator:   signalGenerator,
+	}
+}
+
+// RunBacktest backtests the current strategy settings on Binance history
+func (bs *BotService) RunBacktest(req BacktestRequest) (*BacktestResult, error) {
+	if !bs.cfg.BacktestEnabled {
+		return nil, ErrBacktestDisabled
+	}
+	return NewBacktester(bs.cfg, bs.dataCollector, bs.technicalAnalyzer, bs.signalGenerator).Run(req)
+}
+
+// Run fetches the candles of the request, plus a warmup of one analysis
+// window before Start, and replays them. Each candle is analyzed like the
+// live cycle analyzes the latest one; a BUY or SELL that meets the
+// confidence threshold enters at the candle's close. Filters that need live
+// data (Fear & Greed, source agreement, higher timeframes) see none, so
+// HTF_CONFLICT_MODE=require doesn't hold replayed signals.
+func (b *Backtester) Run(req BacktestRequest) (*BacktestResult, error) {
+	req.Symbol = strings.ToUpper(strings.TrimSpace(req.Symbol))
+	if req.Symbol == "" {
+		return nil, fmt.Errorf("%w: symbol is required", ErrInvalidBacktest)
+	}
+	if req.Interval == "" {
+		req.Interval = b.cfg.KlineInterval
+	}
+	step, ok := candleIntervals[req.Interval]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported interval %q", ErrInvalidBacktest, req.Interval)
+	}
+	if req.End.IsZero() || req.End.After(time.Now()) {
+		req.End = time.Now()
+	}
+	if req.Start.IsZero() || !req.Start.Before(req.End) {
+		return nil, fmt.Errorf("%w: start is required and must be before end", ErrInvalidBacktest)
+	}
+	if req.MaxHoldCandles < 0 {
+		return nil, fmt.Errorf("%w: max_hold_candles must not be negative", ErrInvalidBacktest)
+	}
+
+	fetchStart := req.Start.Add(-time.Duration(klineFetchLimit) * step)
+	expected := int(req.End.Sub(fetchStart)/step) + 1
+	if expected > b.cfg.BacktestMaxCandles {
+		return nil, fmt.Errorf("%w: range needs about %d %s candles with warmup, BACKTEST_MAX_CANDLES is %d",
+			ErrInvalidBacktest, expected, req.Interval, b.cfg.BacktestMaxCandles)
+	}
+
+	klines, err := b.dataCollector.getBinanceKlinesRange(req.Symbol, req.Interval, fetchStart, req.End, expected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch klines for %s: %w", req.Symbol, err)
+	}
+	candles, err := b.technicalAnalyzer.parseKlineData(klines)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("📈 Backtesting %s %s from %s to %s over %d candles", req.Symbol, req.Interval,
+		req.Start.Format(time.RFC3339), req.End.Format(time.RFC3339), len(candles))
+
+	result := &BacktestResult{
+		Symbol:   req.Symbol,
+		Interval: req.Interval,
+		Start:    req.Start,
+		End:      req.End,
+		Trades:   []BacktestTrade{},
+	}
+
+	startMillis := req.Start.UnixMilli()
+	for i := 0; i < len(candles); i++ {
+		if candles[i].Timestamp < startMillis || i < klineFetchLimit-1 {
+			continue
+		}
+		result.Candles++
+
+		trade, exitIndex := b.evaluate(req, candles, i, step)
+		if trade == nil {
+			continue
+		}
+		result.Trades = append(result.Trades, *trade)
+		result.Candles += exitIndex - i
+		i = exitIndex
+	}
+
+	b.summarize(result)
+	logrus.Infof("✅ Backtest of %s finished: %d trades, win rate %s%%", req.Symbol, result.TotalSignals, result.WinRate.StringFixed(2))
+	return result, nil
+}
+
+// evaluate analyzes the window ending at candle i and, on a signal, simulates
+// the trade. It returns the trade and the index of its exit candle.
+func (b *Backtester) evaluate(req BacktestRequest, candles []OHLCV, i int, step time.Duration) (*BacktestTrade, int) {
+	window := candles[i-klineFetchLimit+1 : i+1]
+	current := candles[i]
+	closedAt := time.UnixMilli(current.Timestamp).Add(step)
+
+	marketData := &MarketData{
+		Symbol:         req.Symbol,
+		Price:          current.Close,
+		FearGreedIndex: 50,
+		KlineData:      backtestKlines(window),
+		Interval:       req.Interval,
+		Timestamp:      closedAt,
+		AsOf:           closedAt,
+		PriceSource:    "backtest",
+		KlineSource:    "binance",
+	}
+	marketData.Volume24h, marketData.PriceChange24h = backtestDailyStats(window, step)
+
+	indicators, err := b.technicalAnalyzer.AnalyzeMarketData(marketData)
+	if err != nil || len(invalidIndicators(indicators)) > 0 {
+		return nil, i
+	}
+
+	decision := b.signalGenerator.analyzeMarketConditions(marketData, indicators)
+	if decision.Action != "BUY" && decision.Action != "SELL" {
+		return nil, i
+	}
+	if decision.Confidence.LessThan(decimal.NewFromFloat(b.signalGenerator.settingsFor(req.Symbol).MinConfidence)) {
+		return nil, i
+	}
+
+	return simulateTrade(decision, candles, i, closedAt, step, req.MaxHoldCandles)
+}
+
+// simulateTrade follows a position entered at the close of candle entry.
+// Each take profit closes its allocation when touched; the stop closes what
+// remains. A candle touching both counts as stopped out.
+func simulateTrade(decision *SignalDecision, candles []OHLCV, entry int, enteredAt time.Time, step time.Duration, maxHold int) (*BacktestTrade, int) {
+	targets := decision.TakeProfits
+	if len(targets) == 0 {
+		targets = []models.TakeProfitTarget{{Level: 1, Price: decision.TakeProfit1, Allocation: decimal.NewFromInt(1)}}
+	}
+
+	trade := &BacktestTrade{
+		Action:     decision.Action,
+		Confidence: decision.Confidence,
+		EntryTime:  enteredAt,
+		EntryPrice: decision.EntryPrice,
+	}
+	isBuy := decision.Action == "BUY"
+	pnlAt := func(price decimal.Decimal) decimal.Decimal {
+		change := price.Sub(decision.EntryPrice)
+		if !isBuy {
+			change = change.Neg()
+		}
+		return change.Div(decision.EntryPrice).Mul(decimal.NewFromInt(100))
+	}
+
+	remaining := decimal.NewFromInt(1)
+	realized := decimal.Zero
+	next := 0
+	exit := func(index int, price decimal.Decimal, reason string) (*BacktestTrade, int) {
+		realized = realized.Add(remaining.Mul(pnlAt(price)))
+		trade.ExitTime = time.UnixMilli(candles[index].Timestamp).Add(step)
+		trade.ExitPrice = price
+		trade.ExitReason = reason
+		trade.PnLPercentage = realized
+		switch {
+		case realized.IsPositive():
+			trade.Outcome = "profit"
+		case realized.IsNegative():
+			trade.Outcome = "loss"
+		default:
+			trade.Outcome = "breakeven"
+		}
+		return trade, index
+	}
+
+	for j := entry + 1; j < len(candles); j++ {
+		candle := candles[j]
+		stopped := candle.Low.LessThanOrEqual(decision.StopLoss)
+		if !isBuy {
+			stopped = candle.High.GreaterThanOrEqual(decision.StopLoss)
+		}
+		if stopped {
+			return exit(j, decision.StopLoss, backtestExitStopLoss)
+		}
+
+		for next < len(targets) {
+			target := targets[next]
+			reached := candle.High.GreaterThanOrEqual(target.Price)
+			if !isBuy {
+				reached = candle.Low.LessThanOrEqual(target.Price)
+			}
+			if !reached {
+				break
+			}
+			realized = realized.Add(target.Allocation.Mul(pnlAt(target.Price)))
+			remaining = remaining.Sub(target.Allocation)
+			next++
+		}
+		if next == len(targets) || !remaining.IsPositive() {
+			remaining = decimal.Zero
+			return exit(j, targets[next-1].Price, backtestExitTakeProfit)
+		}
+
+		if maxHold > 0 && j-entry >= maxHold {
+			return exit(j, candle.Close, backtestExitMaxHold)
+		}
+	}
+
+	last := len(candles) - 1
+	return exit(last, candles[last].Close, backtestExitEndOfData)
+}
+
+// summarize fills in the metrics of the simulated trades
+func (b *Backtester) summarize(result *BacktestResult) {
+	metrics := &result.PerformanceMetrics
+	metrics.TotalSignals = len(result.Trades)
+	if metrics.TotalSignals == 0 {
+		return
+	}
+
+	pnls := make([]decimal.Decimal, 0, len(result.Trades))
+	duration := decimal.Zero
+	for i, trade := range result.Trades {
+		switch trade.Outcome {
+		case "profit":
+			metrics.ProfitableSignals++
+		case "loss":
+			result.LosingSignals++
+		}
+		metrics.TotalPnL = metrics.TotalPnL.Add(trade.PnLPercentage)
+		if i == 0 || trade.PnLPercentage.GreaterThan(metrics.BestPnL) {
+			metrics.BestPnL = trade.PnLPercentage
+		}
+		if i == 0 || trade.PnLPercentage.LessThan(metrics.WorstPnL) {
+			metrics.WorstPnL = trade.PnLPercentage
+		}
+		duration = duration.Add(decimal.NewFromFloat(trade.ExitTime.Sub(trade.EntryTime).Minutes()))
+		pnls = append(pnls, trade.PnLPercentage)
+	}
+
+	count := decimal.NewFromInt(int64(metrics.TotalSignals))
+	metrics.WinRate = decimal.NewFromInt(int64(metrics.ProfitableSignals)).Div(count).Mul(decimal.NewFromInt(100))
+	metrics.AvgPnL = metrics.TotalPnL.Div(count)
+	metrics.AvgDuration = duration.Div(count)
+	metrics.Accuracy = metrics.WinRate.Div(decimal.NewFromInt(100))
+	result.MaxDrawdown = maxEquityDrawdown(pnls)
+}
+
+// maxEquityDrawdown compounds each PnL into an equity curve starting at 1 and
+// returns the deepest fall below a running peak, in percent
+func maxEquityDrawdown(pnls []decimal.Decimal) decimal.Decimal {
+	one := decimal.NewFromInt(1)
+	hundred := decimal.NewFromInt(100)
+
+	equity := one
+	peak := one
+	deepest := decimal.Zero
+	for _, pnl := range pnls {
+		equity = equity.Mul(one.Add(pnl.Div(hundred)))
+		if equity.GreaterThan(peak) {
+			peak = equity
+		}
+		if drawdown := peak.Sub(equity).Div(peak).Mul(hundred); drawdown.GreaterThan(deepest) {
+			deepest = drawdown
+		}
+	}
+	return deepest
+}
+
+// backtestKlines turns parsed candles back into the kline rows analysis reads
+func backtestKlines(candles []OHLCV) [][]interface{} {
+	klines := make([][]interface{}, len(candles))
+	for i, candle := range candles {
+		klines[i] = []interface{}{candle.Timestamp, candle.Open.String(), candle.High.String(), candle.Low.String(), candle.Close.String(), candle.Volume.String()}
+	}
+	return klines
+}
+
+// backtestDailyStats derives the 24h quote volume and price change from the
+// candles of the last day in the window
+func backtestDailyStats(window []OHLCV, step time.Duration) (decimal.Decimal, decimal.Decimal) {
+	count := int(24 * time.Hour / step)
+	if count < 1 {
+		count = 1
+	}
+	if count > len(window) {
+		count = len(window)
+	}
+	recent := window[len(window)-count:]
+
+	volume := decimal.Zero
+	for _, candle := range recent {
+		volume = volume.Add(candle.Volume.Mul(candle.Close))
+	}
+
+	change := decimal.Zero
+	if open := recent[0].Open; open.IsPositive() {
+		change = recent[len(recent)-1].Close.Sub(open).Div(open).Mul(decimal.NewFromInt(100))
+	}
+	return volume, change
+}
@@ -4,29 +4,61 @@ import (
 	"crypto-signal-bot/internal/config"
 	"crypto-signal-bot/internal/database"
 	"crypto-signal-bot/internal/models"
+	"crypto-signal-bot/internal/utils"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
 
 type BotService struct {
-	db                  *database.SupabaseClient
+	db                  database.Store
 	cfg                 *config.Config
 	dataCollector       *DataCollector
+	marketDataSource    MarketDataSource // Defaults to dataCollector
 	technicalAnalyzer   *TechnicalAnalyzer
 	signalGenerator     *SignalGenerator
 	notificationService *NotificationService
 	learningEngine      *LearningEngine
+	cmcService          *CoinMarketCapService
+	analysisScheduler   AnalysisScheduler
 	
-	// Runtime state
-	isRunning           bool
-	lastAnalysisTime    time.Time
-	totalSignalsToday   int
+	// Runtime state; the atomics are written by the analysis goroutine and
+	// read by the status endpoint and Telegram commands
+	startedAt           time.Time
+	isRunning           atomic.Bool
+	lastAnalysisTime    atomic.Int64 // UnixNano the last cycle started at, 0 before the first; read through lastAnalysis()
+	totalSignalsToday   atomic.Int64
+	cryptoListMu        sync.RWMutex // Guards cryptoList and cryptoListFallback; read through watchlist()
 	cryptoList          []*models.Cryptocurrency
+	killSwitch          killSwitch
+	drawdownGuard       drawdownGuard
+	confidenceSmoother  *confidenceSmoother // Smoothed confidence per symbol/timeframe; nil when off
+	flaggedCoins        map[string]bool     // Coins already reported for failing market data
+	cryptoListFallback  bool                // Watchlist built from defaults because the database was unreachable
+	cycleTimer          cycleTimer          // Recent analysis cycle durations
+	charts              chartCache          // Recently rendered /chart images
+	marketDetails       marketDetailCache   // Recent GET /market/{symbol} responses
+	snoozes             coinSnoozes         // Coins muted from the snooze button of a signal
+	dryRun              dryRunMode          // Signals are only logged while on
+	outboxMu            sync.Mutex          // Serializes signal deliveries so an outbox entry is never sent twice at once
+	signalHooksMu       sync.RWMutex
+	signalHooks         []func(*models.TradingSignal) // Called with every signal whose notification was sent
+
+	// Readiness state, set by the startup probes and kept current by analysis
+	// runs and deliveries; read by HTTP handlers from other goroutines
+	databaseReady       atomic.Bool
+	telegramReady       atomic.Bool
+	dataSourcesReady    atomic.Bool
+	analysisCompleted   atomic.Bool
 }
 
-func NewBotService(db *database.SupabaseClient, cfg *config.Config) *BotService {
+func NewBotService(db database.Store, cfg *config.Config) *BotService {
 	bs := &BotService{
 		db:                  db,
 		cfg:                 cfg,
@@ -35,17 +67,49 @@ func NewBotService(db *database.SupabaseClient, cfg *config.Config) *BotService
 		signalGenerator:     NewSignalGenerator(db, cfg),
 		notificationService: NewNotificationService(cfg),
 		learningEngine:      NewLearningEngine(db, cfg),
-		isRunning:           false,
+		cmcService:          NewCoinMarketCapService(cfg),
+		startedAt:           time.Now(),
 		cryptoList:          []*models.Cryptocurrency{},
+		flaggedCoins:        make(map[string]bool),
 	}
 
+	bs.marketDataSource = bs.dataCollector
+
+	// Per-symbol confidence history lives here; the generator reads it each cycle
+	bs.confidenceSmoother = newConfidenceSmoother(cfg.ConfidenceSmoothingAlpha)
+	bs.signalGenerator.confidenceSmoother = bs.confidenceSmoother
+
+	// Dry run starts from DRY_RUN and is flipped at runtime; the generator checks it before saving
+	bs.dryRun.set(cfg.DryRun)
+	bs.signalGenerator.dryRun = &bs.dryRun
+
+	// Optimization adjusts thresholds and weights; the generator reads the current ones
+	bs.signalGenerator.learning = bs.learningEngine
+
 	// Set bot service reference for notification service
 	bs.notificationService.SetBotService(bs)
 
+	// Surface API quota exhaustion instead of silently degrading to fallbacks
+	bs.dataCollector.SetQuotaExhaustedHandler(func(provider string, err error) {
+		bs.RecordSystemLog("warning", "data_collector", fmt.Sprintf("%s quota exhausted: %v", provider, err), map[string]interface{}{"provider": provider})
+		bs.notificationService.SendSystemNotification("warning",
+			fmt.Sprintf("Kuota API *%s* habis, beralih ke sumber data fallback.", provider))
+	})
+
 	return bs
 }
 
+// SetMarketDataSource swaps the market data used by analysis, e.g. for a
+// StaticMarketDataSource when exercising the pipeline without live APIs
+func (bs *BotService) SetMarketDataSource(source MarketDataSource) {
+	bs.marketDataSource = source
+}
+
 func (bs *BotService) Start() error {
+	if bs.IsKillSwitchEngaged() {
+		return fmt.Errorf("kill switch is engaged, re-arm it before starting")
+	}
+
 	logrus.Info("🚀 Starting Crypto Signal Bot...")
 
 	// Initialize cryptocurrency list
@@ -53,9 +117,19 @@ func (bs *BotService) Start() error {
 		return err
 	}
 
-	// Test connections
+	// Apply per-coin strategy overrides
+	if err := bs.loadStrategyProfiles(); err != nil {
+		logrus.Warn("Failed to load strategy profiles, using global settings: ", err)
+	}
+
+	// Restore the thresholds and weights of the last learning optimization
+	if err := bs.learningEngine.LoadOptimizedParams(); err != nil {
+		logrus.Warn("Failed to load optimized strategy parameters, using the configured baseline: ", err)
+	}
+
+	// Test connections; only STARTUP_CRITICAL_PROBES failures stop startup
 	if err := bs.testConnections(); err != nil {
-		logrus.Warn("Some connections failed during startup: ", err)
+		return err
 	}
 
 	// Start Telegram bot with interactive menu
@@ -64,18 +138,29 @@ func (bs *BotService) Start() error {
 	}
 
 	// Send startup notification
-	bs.notificationService.SendSystemNotification("info", "🤖 Crypto Signal Bot started successfully!\n\nGunakan /menu untuk mengakses fitur interaktif.")
+	bs.notificationService.SendSystemNotification("info", "🤖 Crypto Signal Bot started successfully!\n\nGunakan /menu untuk mengakses fitur interaktif."+bs.dryRunNotice())
 
-	bs.isRunning = true
+	bs.isRunning.Store(true)
 	logrus.Info("✅ Crypto Signal Bot is now running")
 
 	return nil
 }
 
+// StopAcceptingCommands stops handling Telegram commands and menu buttons.
+// Call it first on shutdown so no command reaches a half-stopped service.
+func (bs *BotService) StopAcceptingCommands() {
+	bs.notificationService.StopTelegramBot()
+}
+
 func (bs *BotService) Stop() error {
 	logrus.Info("🛑 Stopping Crypto Signal Bot...")
 
-	bs.isRunning = false
+	bs.isRunning.Store(false)
+
+	// Don't drop signals still waiting for the digest
+	if err := bs.notificationService.FlushDigest(); err != nil {
+		logrus.Error("Failed to send pending signal digest: ", err)
+	}
 
 	// Send shutdown notification
 	bs.notificationService.SendSystemNotification("info", "🤖 Crypto Signal Bot stopped")
@@ -84,33 +169,160 @@ func (bs *BotService) Stop() error {
 	return nil
 }
 
-func (bs *BotService) RunAnalysis() error {
-	if !bs.isRunning {
+// RunAnalysis runs one analysis cycle on KLINE_INTERVAL klines. force is for deliberate
+// manual runs and bypasses the daily signal cap; the kill switch, drawdown
+// guard and signal filters still apply.
+func (bs *BotService) RunAnalysis(force bool) error {
+	return bs.runAnalysis(force, bs.cfg.KlineInterval)
+}
+
+// RunManualAnalysis runs an analysis cycle requested by the user on freshly
+// fetched market data rather than cached data
+func (bs *BotService) RunManualAnalysis(force bool) error {
+	bs.dataCollector.ClearCache()
+	return bs.RunAnalysis(force)
+}
+
+// RunCandleCloseAnalysis runs one analysis cycle on the klines of a timeframe
+// whose candle just closed
+func (bs *BotService) RunCandleCloseAnalysis(interval string) error {
+	return bs.runAnalysis(false, interval)
+}
+
+func (bs *BotService) runAnalysis(force bool, interval string) error {
+	if !bs.isRunning.Load() {
 		return nil
 	}
 
-	logrus.Info("🔍 Running market analysis...")
-	bs.lastAnalysisTime = time.Now()
+	// Retry the whole cycle on transient failures so a brief provider
+	// hiccup doesn't skip an entire analysis window. Requests already retry
+	// on their own, so retries stop once they would run into the next window.
+	backoff := time.Duration(bs.cfg.AnalysisRetryBackoffSeconds) * time.Second
+	deadline := time.Now().Add(time.Duration(bs.cfg.AnalysisIntervalSeconds) * time.Second)
+	var err error
+	for attempt := 0; attempt <= bs.cfg.AnalysisRetryAttempts; attempt++ {
+		if attempt > 0 {
+			if time.Now().Add(backoff).After(deadline) {
+				logrus.Warnf("Analysis cycle failed with transient error, no time left to retry before the next cycle: %v", err)
+				return err
+			}
+			logrus.Warnf("Analysis cycle failed with transient error, retrying in %s (attempt %d/%d): %v",
+				backoff, attempt, bs.cfg.AnalysisRetryAttempts, err)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		err = bs.runAnalysisCycle(force, interval)
+		if err == nil || !IsTransient(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (bs *BotService) runAnalysisCycle(force bool, interval string) error {
+	logrus.Info("🔍 Running market analysis on ", interval, " klines...")
+	cycleStartedAt := time.Now()
+	bs.lastAnalysisTime.Store(cycleStartedAt.UnixNano())
 
 	// Check daily signal limit
-	if bs.totalSignalsToday >= bs.cfg.MaxSignalsPerDay {
-		logrus.Info("Daily signal limit reached, skipping analysis")
+	if bs.DailyLimitReached() {
+		if !force {
+			logrus.Info("Daily signal limit reached, skipping analysis")
+			return nil
+		}
+		logrus.Warn("Daily signal limit reached, bypassed by forced manual analysis")
+	}
+
+	// Step back after a losing run until resumed
+	if bs.checkDrawdownGuard() {
+		logrus.Warn("Drawdown guard active, skipping signal generation")
 		return nil
 	}
 
+	if window, active := bs.signalGenerator.volatilityWindow(cycleStartedAt); active {
+		effect := "skipped"
+		if bs.cfg.VolatilityWindowMode == "reduce" {
+			effect = "down-weighted"
+		}
+		logrus.Infof("⏱️ Inside volatility window after %s UTC, signals will be %s", window, effect)
+	}
+
 	signalsGenerated := 0
+	analyzed := 0
+	failures := 0
+	allTransient := true
+	var lastErr error
+	var dataSucceeded, dataFailed []*models.Cryptocurrency
+	var candidates []*signalCandidate
+	cycleStart := time.Now()
+	symbolDurations := make(map[string]time.Duration)
 
 	// Analyze each cryptocurrency
-	for _, crypto := range bs.cryptoList {
-		if err := bs.analyzeCryptocurrency(crypto); err != nil {
+	watchlist := bs.watchlist()
+	for _, crypto := range watchlist {
+		if bs.IsKillSwitchEngaged() {
+			logrus.Warn("Kill switch engaged, aborting analysis cycle")
+			return nil
+		}
+
+		// Pruned coins stay listed but are no longer analyzed
+		if !crypto.IsActive {
+			continue
+		}
+		analyzed++
+
+		analysisStart := time.Now()
+		candidate, err := bs.analyzeCryptocurrency(crypto, interval)
+		if err == nil && candidate != nil {
+			if bs.cfg.MaxSignalsPerCycle > 0 {
+				// Ranked against the rest of the cycle before anything is sent
+				candidates = append(candidates, candidate)
+			} else {
+				var stored bool
+				if stored, err = bs.commitSignal(candidate); stored {
+					signalsGenerated++
+				}
+			}
+		}
+		symbolDurations[crypto.Symbol] = time.Since(analysisStart)
+		if err != nil {
 			logrus.Error("Failed to analyze ", crypto.Symbol, ": ", err)
+			bs.RecordSystemLog("error", "analysis", fmt.Sprintf("Failed to analyze %s: %v", crypto.Symbol, err), map[string]interface{}{"symbol": crypto.Symbol})
+			failures++
+			allTransient = allTransient && IsTransient(err)
+			lastErr = err
+
+			var dataErr *marketDataError
+			if errors.As(err, &dataErr) {
+				dataFailed = append(dataFailed, crypto)
+			}
 			continue
 		}
+		dataSucceeded = append(dataSucceeded, crypto)
 		
 		// Rate limiting between analyses
-		time.Sleep(time.Duration(bs.cfg.AnalysisIntervalSeconds) * time.Second / time.Duration(len(bs.cryptoList)))
+		time.Sleep(time.Duration(bs.cfg.AnalysisIntervalSeconds) * time.Second / time.Duration(len(watchlist)))
+	}
+
+	// Only the strongest MAX_SIGNALS_PER_CYCLE signals of the cycle go out
+	signalsGenerated += bs.commitTopSignals(candidates)
+
+	bs.recordCycleDuration(cycleStart, interval, symbolDurations)
+	bs.refreshReadiness(len(dataSucceeded), len(dataFailed))
+
+	// Every coin failed - treat as a cycle-level failure. Failure counts are
+	// left alone: an outage on our side says nothing about individual coins.
+	if failures > 0 && failures == analyzed {
+		if allTransient {
+			return &TransientError{Op: "analysis cycle", Err: lastErr}
+		}
+		return fmt.Errorf("analysis cycle failed for all %d coins: %w", failures, lastErr)
 	}
 
+	bs.trackMarketDataFailures(dataSucceeded, dataFailed)
+
 	// Update performance tracking
 	if err := bs.updatePerformanceTracking(); err != nil {
 		logrus.Error("Failed to update performance tracking: ", err)
@@ -118,28 +330,61 @@ func (bs *BotService) RunAnalysis() error {
 
 	// Run learning optimization (daily)
 	if bs.shouldRunLearningOptimization() {
-		if err := bs.learningEngine.OptimizeStrategy(); err != nil {
+		if _, err := bs.learningEngine.OptimizeStrategy(); err != nil {
 			logrus.Error("Failed to run learning optimization: ", err)
 		}
 	}
 
+	bs.analysisCompleted.Store(true)
 	logrus.Info("✅ Market analysis completed. Signals generated: ", signalsGenerated)
 	return nil
 }
 
-func (bs *BotService) analyzeCryptocurrency(crypto *models.Cryptocurrency) error {
+// staleDataAge reports how old the provider's quote is and whether that exceeds
+// MAX_DATA_AGE_SECONDS. Data without a timestamp is never considered stale.
+func (bs *BotService) staleDataAge(marketData *MarketData) (time.Duration, bool) {
+	if bs.cfg.MaxDataAgeSeconds <= 0 || marketData.Timestamp.IsZero() {
+		return 0, false
+	}
+	age := time.Since(marketData.Timestamp)
+	return age, age > time.Duration(bs.cfg.MaxDataAgeSeconds)*time.Second
+}
+
+// lastAnalysis returns when the last analysis cycle started; zero before the first
+func (bs *BotService) lastAnalysis() time.Time {
+	nanos := bs.lastAnalysisTime.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// DailyLimitReached reports whether MAX_SIGNALS_PER_DAY signals were already sent today
+func (bs *BotService) DailyLimitReached() bool {
+	return bs.totalSignalsToday.Load() >= int64(bs.cfg.MaxSignalsPerDay)
+}
+
+// analyzeCryptocurrency analyzes one coin and returns the signal it produced,
+// not yet stored or sent; nil when no signal passed the filters
+func (bs *BotService) analyzeCryptocurrency(crypto *models.Cryptocurrency, interval string) (*signalCandidate, error) {
 	logrus.Debug("Analyzing cryptocurrency: ", crypto.Symbol)
 
 	// Collect market data
-	marketData, err := bs.dataCollector.GetMarketData(crypto.Symbol)
+	marketData, err := bs.marketDataSource.GetMarketData(crypto.Symbol, interval)
 	if err != nil {
-		return err
+		return nil, &marketDataError{Err: err}
+	}
+
+	// Cached quotes can lag well behind the market; don't trade on them
+	if age, stale := bs.staleDataAge(marketData); stale {
+		logrus.Warnf("Skipping %s: %s quote is %s old (limit %ds)", crypto.Symbol, marketData.PriceSource, age.Round(time.Second), bs.cfg.MaxDataAgeSeconds)
+		return nil, nil
 	}
 
 	// Perform technical analysis
 	indicators, err := bs.technicalAnalyzer.AnalyzeMarketData(marketData)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Save market snapshot
@@ -147,6 +392,11 @@ func (bs *BotService) analyzeCryptocurrency(crypto *models.Cryptocurrency) error
 		logrus.Error("Failed to save market snapshot: ", err)
 	}
 
+	// Skip signal generation for freshly listed coins; the snapshot above is still kept
+	if bs.isRecentlyListed(marketData) {
+		return nil, nil
+	}
+
 	// Extract features for learning
 	features := bs.learningEngine.ExtractFeatures(marketData, indicators)
 
@@ -156,29 +406,76 @@ func (bs *BotService) analyzeCryptocurrency(crypto *models.Cryptocurrency) error
 		logrus.Error("Failed to predict signal outcome: ", err)
 	}
 
-	// Generate trading signal
-	signal, err := bs.signalGenerator.GenerateSignal(marketData, indicators, crypto)
-	if err != nil {
-		return err
+	// Build the trading signal; it is stored and sent by commitSignal
+	signal, decision := bs.signalGenerator.buildSignal(marketData, indicators, crypto)
+	if signal == nil {
+		return nil, nil
 	}
 
-	// If signal was generated
-	if signal != nil {
-		// Save learning data
-		if err := bs.learningEngine.SaveLearningData(signal, features, predictedOutcome, predictedConfidence); err != nil {
-			logrus.Error("Failed to save learning data: ", err)
-		}
+	return &signalCandidate{
+		signal:              signal,
+		decision:            decision,
+		marketData:          marketData,
+		features:            features,
+		predictedOutcome:    predictedOutcome,
+		predictedConfidence: predictedConfidence,
+	}, nil
+}
 
-		// Send notification
-		if err := bs.notificationService.SendSignalNotification(signal); err != nil {
-			logrus.Error("Failed to send signal notification: ", err)
-		}
+// commitSignal stores a signal, saves its learning data and sends it. It
+// reports whether a signal was stored; dry run keeps it back.
+func (bs *BotService) commitSignal(candidate *signalCandidate) (bool, error) {
+	crypto := candidate.signal.Crypto
 
-		bs.totalSignalsToday++
-		logrus.Info("✅ Signal generated and sent for ", crypto.Symbol)
+	signal, err := bs.signalGenerator.saveSignal(candidate.signal)
+	if err != nil || signal == nil {
+		return false, err
 	}
 
-	return nil
+	// Save learning data
+	if err := bs.learningEngine.SaveLearningData(signal, candidate.features, candidate.predictedOutcome, candidate.predictedConfidence); err != nil {
+		logrus.Error("Failed to save learning data: ", err)
+	}
+
+	// Send notification; a failure stays in the outbox and is retried.
+	// Signals below the notify thresholds settle their entry unsent.
+	if err := bs.deliverSignal(signal); err != nil {
+		logrus.Error("Failed to send signal notification, queued for retry: ", err)
+	}
+
+	// Signals below the notify thresholds are only recorded for learning
+	if !bs.notificationService.ShouldNotify(signal) {
+		logrus.Info("Signal recorded for ", crypto.Symbol, " below notify threshold (confidence ", signal.ConfidenceScore, ", data quality ", signal.DataQuality, ")")
+		return true, nil
+	}
+
+	bs.totalSignalsToday.Add(1)
+	logrus.Info("✅ Signal generated and sent for ", crypto.Symbol)
+	return true, nil
+}
+
+// isRecentlyListed reports whether a coin is younger than MIN_LISTING_AGE_DAYS.
+// Unknown listing ages are allowed through.
+func (bs *BotService) isRecentlyListed(marketData *MarketData) bool {
+	if bs.cfg.MinListingAgeDays <= 0 {
+		return false
+	}
+
+	listedAt, err := bs.marketDataSource.GetListingTime(marketData)
+	if err != nil {
+		logrus.Debug("Could not determine listing age for ", marketData.Symbol, ": ", err)
+		return false
+	}
+
+	age := time.Since(listedAt)
+	minAge := time.Duration(bs.cfg.MinListingAgeDays) * 24 * time.Hour
+	if age < minAge {
+		logrus.Infof("Skipping signal for %s: listed %.1f days ago, minimum is %d days",
+			marketData.Symbol, age.Hours()/24, bs.cfg.MinListingAgeDays)
+		return true
+	}
+
+	return false
 }
 
 func (bs *BotService) saveMarketSnapshot(crypto *models.Cryptocurrency, marketData *MarketData, indicators *TechnicalIndicators) error {
@@ -219,14 +516,6 @@ func (bs *BotService) saveMarketSnapshot(crypto *models.Cryptocurrency, marketDa
 	return bs.db.SaveMarketSnapshot(snapshot)
 }
 
-func (bs *BotService) updatePerformanceTracking() error {
-	// TODO: Implement performance tracking update
-	// This would check active signals and update their performance
-	// based on current market prices
-	logrus.Debug("Updating performance tracking...")
-	return nil
-}
-
 func (bs *BotService) shouldRunLearningOptimization() bool {
 	// Run learning optimization once per day
 	now := time.Now()
@@ -236,28 +525,13 @@ func (bs *BotService) shouldRunLearningOptimization() bool {
 func (bs *BotService) initializeCryptoList() error {
 	logrus.Info("Initializing cryptocurrency list...")
 
-	// Default cryptocurrencies to monitor
-	defaultCryptos := []struct {
-		Symbol      string
-		Name        string
-		CoingeckoID string
-	}{
-		{"BTC", "Bitcoin", "bitcoin"},
-		{"ETH", "Ethereum", "ethereum"},
-		{"BNB", "Binance Coin", "binancecoin"},
-		{"ADA", "Cardano", "cardano"},
-		{"SOL", "Solana", "solana"},
-		{"DOT", "Polkadot", "polkadot"},
-		{"MATIC", "Polygon", "matic-network"},
-		{"AVAX", "Avalanche", "avalanche-2"},
-		{"LINK", "Chainlink", "chainlink"},
-		{"ATOM", "Cosmos", "cosmos"},
-	}
-
 	// If database is not available, use default list
 	if bs.db == nil {
 		logrus.Warn("Database not available, using default cryptocurrency list")
-		for _, defaultCrypto := range defaultCryptos {
+		for _, defaultCrypto := range defaultWatchlist {
+			if bs.isExcludedSymbol(defaultCrypto.Symbol) {
+				continue
+			}
 			newCrypto := &models.Cryptocurrency{
 				ID:        uuid.New(),
 				Symbol:    defaultCrypto.Symbol,
@@ -265,9 +539,9 @@ func (bs *BotService) initializeCryptoList() error {
 				IsActive:  true,
 				CreatedAt: time.Now(),
 			}
-			bs.cryptoList = append(bs.cryptoList, newCrypto)
+			bs.watchCrypto(newCrypto)
 		}
-		logrus.Infof("✅ Initialized %d cryptocurrencies (offline mode)", len(bs.cryptoList))
+		logrus.Infof("✅ Initialized %d cryptocurrencies (offline mode)", bs.watchlistSize())
 		return nil
 	}
 
@@ -276,7 +550,10 @@ func (bs *BotService) initializeCryptoList() error {
 	if err != nil {
 		logrus.Warnf("Failed to get cryptocurrencies from database: %v, using defaults", err)
 		// Fallback to default list
-		for _, defaultCrypto := range defaultCryptos {
+		for _, defaultCrypto := range defaultWatchlist {
+			if bs.isExcludedSymbol(defaultCrypto.Symbol) {
+				continue
+			}
 			newCrypto := &models.Cryptocurrency{
 				ID:        uuid.New(),
 				Symbol:    defaultCrypto.Symbol,
@@ -284,9 +561,12 @@ func (bs *BotService) initializeCryptoList() error {
 				IsActive:  true,
 				CreatedAt: time.Now(),
 			}
-			bs.cryptoList = append(bs.cryptoList, newCrypto)
+			bs.watchCrypto(newCrypto)
 		}
-		logrus.Infof("✅ Initialized %d cryptocurrencies (fallback mode)", len(bs.cryptoList))
+		bs.cryptoListMu.Lock()
+		bs.cryptoListFallback = true
+		bs.cryptoListMu.Unlock()
+		logrus.Infof("✅ Initialized %d cryptocurrencies (fallback mode)", bs.watchlistSize())
 		return nil
 	}
 
@@ -296,10 +576,20 @@ func (bs *BotService) initializeCryptoList() error {
 		existingMap[crypto.Symbol] = &crypto
 	}
 
+	removed := bs.removedDefaults()
+
 	// Add missing cryptocurrencies
-	for _, defaultCrypto := range defaultCryptos {
+	for _, defaultCrypto := range defaultWatchlist {
+		if removed[defaultCrypto.Symbol] {
+			logrus.Info("Skipping ", defaultCrypto.Symbol, ": removed from the watchlist by the user")
+			continue
+		}
+		if err := bs.checkWatchable(defaultCrypto.Symbol); err != nil {
+			logrus.Info("Skipping default coin: ", err)
+			continue
+		}
 		if existing, exists := existingMap[defaultCrypto.Symbol]; exists {
-			bs.cryptoList = append(bs.cryptoList, existing)
+			bs.watchCrypto(existing)
 		} else {
 			// Create new cryptocurrency
 			newCrypto := &models.Cryptocurrency{
@@ -310,61 +600,327 @@ func (bs *BotService) initializeCryptoList() error {
 				CreatedAt: time.Now(),
 			}
 
+			// A concurrent add resolves to the stored row instead of failing
 			if err := bs.db.CreateCryptocurrency(newCrypto); err != nil {
 				logrus.Error("Failed to create cryptocurrency ", defaultCrypto.Symbol, ": ", err)
 				continue
 			}
 
-			bs.cryptoList = append(bs.cryptoList, newCrypto)
-			logrus.Info("Added new cryptocurrency: ", defaultCrypto.Symbol)
+			if bs.watchCrypto(newCrypto) {
+				logrus.Info("Added new cryptocurrency: ", defaultCrypto.Symbol)
+			}
 		}
 	}
 
-	logrus.Info("✅ Cryptocurrency list initialized with ", len(bs.cryptoList), " coins")
+	logrus.Info("✅ Cryptocurrency list initialized with ", bs.watchlistSize(), " coins")
 	return nil
 }
 
-func (bs *BotService) testConnections() error {
-	logrus.Info("Testing connections...")
+// enrichCryptocurrency fills in missing CMC ID, slug, CoinGecko ID and price
+// precision. Returns true if any field was changed.
+func (bs *BotService) enrichCryptocurrency(crypto *models.Cryptocurrency) (bool, error) {
+	changed := false
 
-	// Test Telegram connection
-	if err := bs.notificationService.TestConnection(); err != nil {
-		logrus.Error("Telegram connection test failed: ", err)
-		return err
+	if crypto.CoingeckoID == nil {
+		crypto.CoingeckoID = utils.StringPtr(getCoinGeckoID(crypto.Symbol))
+		changed = true
 	}
 
-	// Test database connection (only if available)
-	if bs.db != nil {
-		if err := bs.db.TestConnection(); err != nil {
-			logrus.Warn("Database connection test failed (continuing without database): ", err)
-			// Don't return error, continue without database
+	if crypto.PricePrecision == nil && bs.marketDataSource != nil {
+		if places, err := bs.marketDataSource.GetPricePrecision(crypto.Symbol); err != nil {
+			logrus.Debug("Could not resolve price precision for ", crypto.Symbol, ": ", err)
 		} else {
-			logrus.Info("✅ Database connection test passed")
+			crypto.PricePrecision = &places
+			changed = true
 		}
-	} else {
-		logrus.Warn("⚠️ Database not available, skipping database test")
 	}
 
-	// Test data collector (get BTC data)
-	if _, err := bs.dataCollector.GetMarketData("BTC"); err != nil {
-		logrus.Error("Data collector test failed: ", err)
-		return err
+	if crypto.CmcID != nil && crypto.Slug != nil {
+		return changed, nil
+	}
+
+	if bs.cfg.CoinMarketCapAPIKey == "" {
+		return changed, fmt.Errorf("CoinMarketCap API key not configured")
+	}
+
+	cmcCrypto, err := bs.cmcService.GetCryptocurrencyBySymbol(crypto.Symbol)
+	if err != nil {
+		return changed, err
+	}
+
+	if crypto.CmcID == nil && cmcCrypto.CmcID != nil {
+		crypto.CmcID = cmcCrypto.CmcID
+		changed = true
 	}
+	if crypto.Slug == nil && cmcCrypto.Slug != nil {
+		crypto.Slug = cmcCrypto.Slug
+		changed = true
+	}
+	if (crypto.Name == "" || crypto.Name == crypto.Symbol) && cmcCrypto.Name != "" {
+		crypto.Name = cmcCrypto.Name
+		changed = true
+	}
+
+	return changed, nil
+}
 
-	logrus.Info("✅ Essential connections tested successfully")
+// BackfillCryptoMetadata reconciles watchlist coins that are missing CMC/CoinGecko
+// metadata or price precision
+func (bs *BotService) BackfillCryptoMetadata() error {
+	if bs.db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	logrus.Info("Backfilling cryptocurrency metadata...")
+
+	updated := 0
+	for _, crypto := range bs.watchlist() {
+		changed, err := bs.enrichCryptocurrency(crypto)
+		if err != nil {
+			logrus.Warn("Failed to resolve metadata for ", crypto.Symbol, ": ", err)
+		}
+		if !changed {
+			continue
+		}
+
+		if err := bs.db.UpdateCryptocurrency(crypto); err != nil {
+			logrus.Error("Failed to update cryptocurrency ", crypto.Symbol, ": ", err)
+			continue
+		}
+		updated++
+
+		// Rate limiting - be nice to APIs
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	logrus.Infof("✅ Cryptocurrency metadata backfill completed: %d updated", updated)
 	return nil
 }
 
+// OnDatabaseConnected is called when a database that was unreachable or only
+// available over REST comes up ("rest") or switches to a direct connection
+// ("direct"). A watchlist built from defaults is reloaded from the database.
+func (bs *BotService) OnDatabaseConnected(mode string) {
+	bs.databaseReady.Store(true)
+
+	bs.cryptoListMu.Lock()
+	reload := bs.cryptoListFallback
+	if reload {
+		bs.cryptoList = []*models.Cryptocurrency{}
+		bs.cryptoListFallback = false
+	}
+	bs.cryptoListMu.Unlock()
+
+	if reload {
+		if err := bs.initializeCryptoList(); err != nil {
+			logrus.Error("Failed to reload cryptocurrency list: ", err)
+		}
+		if err := bs.loadStrategyProfiles(); err != nil {
+			logrus.Warn("Failed to load strategy profiles, using global settings: ", err)
+		}
+	}
+
+	message := "Database terhubung kembali via REST API."
+	if mode == "direct" {
+		message = "Database kini terhubung langsung (bukan lagi via REST API)."
+	}
+	bs.notificationService.SendSystemNotification("info", message)
+}
+
 func (bs *BotService) GetStatus() map[string]interface{} {
 	return map[string]interface{}{
-		"is_running":           bs.isRunning,
-		"last_analysis_time":   bs.lastAnalysisTime,
-		"total_signals_today":  bs.totalSignalsToday,
-		"monitored_cryptos":    len(bs.cryptoList),
+		"is_running":           bs.isRunning.Load(),
+		"last_analysis_time":   bs.lastAnalysis(),
+		"total_signals_today":  bs.totalSignalsToday.Load(),
+		"monitored_cryptos":    bs.watchlistSize(),
 		"max_signals_per_day":  bs.cfg.MaxSignalsPerDay,
+		"api_quota":            bs.marketDataSource.GetQuotaStatus(),
+		"kill_switch_engaged":  bs.IsKillSwitchEngaged(),
+		"dry_run":              bs.DryRunStatus(),
+		"drawdown_paused":      bs.IsDrawdownPaused(),
+		"recent_cycles":        bs.cycleTimer.recentCycles(),
 	}
 }
 
+// IsWarmedUp reports whether startup has finished: the bot is running,
+// the watchlist is loaded and Telegram is connected (when configured)
+func (bs *BotService) IsWarmedUp() bool {
+	return bs.isRunning.Load() && bs.watchlistSize() > 0 && bs.notificationService.IsTelegramReady()
+}
+
+// WaitUntilWarmedUp blocks until the bot is warmed up or timeout elapses.
+// Returns false if the timeout was reached first.
+func (bs *BotService) WaitUntilWarmedUp(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for !bs.IsWarmedUp() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return true
+}
+
+// GetReadiness reports which dependencies are currently confirmed working
+func (bs *BotService) GetReadiness() map[string]bool {
+	return map[string]bool{
+		"database":           bs.databaseReady.Load(),
+		"telegram":           bs.telegramReady.Load(),
+		"data_sources":       bs.dataSourcesReady.Load(),
+		"analysis_completed": bs.analysisCompleted.Load(),
+	}
+}
+
+// requiresDatabase reports whether readiness waits for the database. The
+// memory backend has nothing to wait for, and without a store the bot runs
+// degraded by design.
+func (bs *BotService) requiresDatabase() bool {
+	return bs.db != nil && bs.cfg.StorageBackend != "memory"
+}
+
+// IsReady returns true while every required dependency is confirmed working
+// and at least one analysis has completed
+func (bs *BotService) IsReady() bool {
+	for name, ready := range bs.GetReadiness() {
+		if name == probeDatabase && !bs.requiresDatabase() {
+			continue
+		}
+		if !ready {
+			return false
+		}
+	}
+	return true
+}
+
+// watchlist returns a snapshot of the analysis list, safe to range over while
+// commands and reconnects change the list
+func (bs *BotService) watchlist() []*models.Cryptocurrency {
+	bs.cryptoListMu.RLock()
+	defer bs.cryptoListMu.RUnlock()
+
+	return append([]*models.Cryptocurrency(nil), bs.cryptoList...)
+}
+
+// watchlistSize returns the number of coins in the analysis list
+func (bs *BotService) watchlistSize() int {
+	bs.cryptoListMu.RLock()
+	defer bs.cryptoListMu.RUnlock()
+
+	return len(bs.cryptoList)
+}
+
+// watchCrypto adds a coin to the analysis list unless its symbol is already
+// there, reporting whether it was added
+func (bs *BotService) watchCrypto(crypto *models.Cryptocurrency) bool {
+	bs.cryptoListMu.Lock()
+	defer bs.cryptoListMu.Unlock()
+
+	for _, existing := range bs.cryptoList {
+		if existing.Symbol == crypto.Symbol {
+			return false
+		}
+	}
+	bs.cryptoList = append(bs.cryptoList, crypto)
+	return true
+}
+
+// unwatchCrypto removes a coin from the analysis list, reporting whether it was there
+func (bs *BotService) unwatchCrypto(symbol string) bool {
+	bs.cryptoListMu.Lock()
+	defer bs.cryptoListMu.Unlock()
+
+	for i, crypto := range bs.cryptoList {
+		if crypto.Symbol == symbol {
+			// Build a new slice so snapshots taken earlier stay intact
+			bs.cryptoList = append(bs.cryptoList[:i:i], bs.cryptoList[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteCryptocurrency permanently removes a coin from the database and the
+// active watchlist; its historical signals are kept but detached
+func (bs *BotService) DeleteCryptocurrency(symbol string) error {
+	if bs.db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	if err := bs.db.DeleteCryptocurrency(symbol); err != nil {
+		return err
+	}
+
+	bs.unwatchCrypto(symbol)
+	bs.signalGenerator.removeStrategyProfile(symbol)
+	bs.rememberRemovedDefault(symbol)
+
+	logrus.Info("Deleted cryptocurrency: ", symbol)
+	return nil
+}
+
+// Bounds for manually reported PnL; anything outside is almost certainly a typo
+const (
+	minFeedbackPnL = -100.0
+	maxFeedbackPnL = 1000.0
+)
+
+// RecordSignalFeedback stores a manually reported outcome ("win" or "loss")
+// for a signal as ground truth in the learning dataset
+func (bs *BotService) RecordSignalFeedback(refCode, result string, pnl decimal.Decimal) (*models.TradingSignal, error) {
+	if bs.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	var outcome string
+	switch result {
+	case "win":
+		outcome = "profit"
+	case "loss":
+		outcome = "loss"
+	default:
+		return nil, fmt.Errorf("result must be win or loss, got %q", result)
+	}
+
+	if pnl.LessThan(decimal.NewFromFloat(minFeedbackPnL)) || pnl.GreaterThan(decimal.NewFromFloat(maxFeedbackPnL)) {
+		return nil, fmt.Errorf("PnL %s%% is outside %.0f%%..%.0f%%", pnl.String(), minFeedbackPnL, maxFeedbackPnL)
+	}
+	if (outcome == "profit" && pnl.IsNegative()) || (outcome == "loss" && pnl.IsPositive()) {
+		return nil, fmt.Errorf("PnL %s%% contradicts result %s", pnl.String(), result)
+	}
+
+	signal, err := bs.db.GetSignalByRefCode(refCode)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := int(time.Since(signal.CreatedAt).Minutes())
+	if err := bs.learningEngine.UpdateLearningDataWithOutcome(signal.ID, outcome, pnl, duration); err != nil {
+		return nil, err
+	}
+
+	return signal, nil
+}
+
+// GetPerformanceRollup returns signal outcomes aggregated per daily, weekly,
+// monthly or all-time period
+func (bs *BotService) GetPerformanceRollup(period string) ([]*models.PerformanceRollup, error) {
+	if bs.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	return bs.db.GetPerformanceRollup(period)
+}
+
+// GetRecentSignals lists the newest signals, optionally only those of one priority
+func (bs *BotService) GetRecentSignals(priority string, limit int) ([]models.TradingSignal, error) {
+	if bs.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	if priority != "" {
+		return bs.db.GetSignalsByPriority(priority, limit)
+	}
+	return bs.db.GetRecentSignals(limit)
+}
+
 func (bs *BotService) SendDailySummary() error {
 	analytics, err := bs.db.GetSignalAnalytics()
 	if err != nil {
@@ -374,6 +930,91 @@ func (bs *BotService) SendDailySummary() error {
 	return bs.notificationService.SendDailySummary(analytics)
 }
 
+// RunLearningOptimization runs strategy optimization on demand and reports what changed
+func (bs *BotService) RunLearningOptimization() (*OptimizationResult, error) {
+	return bs.learningEngine.OptimizeStrategy()
+}
+
 func (bs *BotService) GetPerformanceMetrics() (*PerformanceMetrics, error) {
-	return bs.learningEngine.AnalyzePatterns()
+	metrics, err := bs.learningEngine.AnalyzePatterns()
+	if err != nil {
+		return nil, err
+	}
+
+	benchmark, err := bs.calculateBenchmark(metrics)
+	if err != nil {
+		logrus.Warn("Failed to calculate buy-and-hold benchmark: ", err)
+	} else {
+		metrics.Benchmark = benchmark
+	}
+
+	return metrics, nil
+}
+
+// calculateBenchmark computes the buy-and-hold return of each signalled coin
+// since its first signal and compares it with the strategy's cumulative PnL
+func (bs *BotService) calculateBenchmark(metrics *PerformanceMetrics) (*BenchmarkComparison, error) {
+	if bs.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	signals, err := bs.db.GetRecentSignals(1000)
+	if err != nil {
+		return nil, err
+	}
+	if len(signals) == 0 {
+		return nil, fmt.Errorf("no signals to benchmark against")
+	}
+
+	symbols := make(map[uuid.UUID]string)
+	for _, crypto := range bs.watchlist() {
+		symbols[crypto.ID] = crypto.Symbol
+	}
+
+	// Earliest signal per coin marks the start of its holding period
+	firstSignal := make(map[string]time.Time)
+	periodStart := time.Now()
+	for _, signal := range signals {
+		symbol, exists := symbols[signal.CryptoID]
+		if !exists {
+			continue
+		}
+		if first, seen := firstSignal[symbol]; !seen || signal.CreatedAt.Before(first) {
+			firstSignal[symbol] = signal.CreatedAt
+		}
+		if signal.CreatedAt.Before(periodStart) {
+			periodStart = signal.CreatedAt
+		}
+	}
+
+	benchmark := &BenchmarkComparison{
+		PeriodStart: periodStart,
+		CoinReturns: make(map[string]decimal.Decimal),
+	}
+
+	total := decimal.Zero
+	for symbol, since := range firstSignal {
+		change, err := bs.marketDataSource.GetPriceChangeSince(symbol, since)
+		if err != nil {
+			logrus.Warn("Failed to get buy-and-hold return for ", symbol, ": ", err)
+			continue
+		}
+		benchmark.CoinReturns[symbol] = change
+		total = total.Add(change)
+	}
+
+	if len(benchmark.CoinReturns) > 0 {
+		benchmark.BuyAndHoldReturn = total.Div(decimal.NewFromInt(int64(len(benchmark.CoinReturns))))
+	}
+
+	if btcChange, exists := benchmark.CoinReturns["BTC"]; exists && firstSignal["BTC"].Equal(periodStart) {
+		benchmark.BTCReturn = btcChange
+	} else if btcChange, err := bs.marketDataSource.GetPriceChangeSince("BTC", periodStart); err == nil {
+		benchmark.BTCReturn = btcChange
+	} else {
+		logrus.Warn("Failed to get BTC buy-and-hold return: ", err)
+	}
+
+	benchmark.ExcessReturn = metrics.TotalPnL.Sub(benchmark.BuyAndHoldReturn)
+	return benchmark, nil
 }
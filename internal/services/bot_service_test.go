@@ -0,0 +1,108 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/database"
+	"crypto-signal-bot/internal/models"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Run with -race: the watchlist changes from commands while a cycle ranges over it
+func TestWatchlistConcurrentAccess(t *testing.T) {
+	cfg := testConfig()
+	cfg.AnalysisIntervalSeconds = 0
+	bs := NewBotService(database.NewMemoryStore(), cfg)
+	source := NewStaticMarketDataSource()
+	source.Set("BTC", staticMarketData("BTC", trendKlines(100, 0.01)))
+	bs.SetMarketDataSource(source)
+	bs.watchCrypto(&models.Cryptocurrency{ID: uuid.New(), Symbol: "BTC", IsActive: true})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			bs.runAnalysisCycle(true, "15m")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			bs.watchCrypto(&models.Cryptocurrency{ID: uuid.New(), Symbol: "ETH", IsActive: true})
+			bs.unwatchCrypto("ETH")
+		}
+	}()
+	wg.Wait()
+
+	if !bs.watchCrypto(&models.Cryptocurrency{ID: uuid.New(), Symbol: "SOL"}) {
+		t.Error("watchCrypto did not add SOL")
+	}
+	if bs.watchCrypto(&models.Cryptocurrency{ID: uuid.New(), Symbol: "SOL"}) {
+		t.Error("watchCrypto added SOL twice")
+	}
+	if !bs.unwatchCrypto("SOL") || bs.unwatchCrypto("SOL") {
+		t.Error("unwatchCrypto should remove SOL exactly once")
+	}
+	if size := bs.watchlistSize(); size != 1 {
+		t.Errorf("watchlist has %d coins, want 1", size)
+	}
+}
+
+// Without a database main.go passes a nil store; startup runs degraded on the
+// default watchlist instead of crashing
+func TestStartWithoutStore(t *testing.T) {
+	cfg := testConfig()
+	cfg.StartupProbeTimeoutSeconds = 1
+	bs := NewBotService(nil, cfg)
+
+	if err := bs.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer bs.Stop()
+
+	if bs.watchlistSize() == 0 {
+		t.Error("watchlist is empty, want the defaults")
+	}
+}
+
+// Run with -race: the status endpoint and /status read the runtime state the
+// analysis goroutine writes
+func TestStatusDuringAnalysis(t *testing.T) {
+	cfg := testConfig()
+	cfg.AnalysisIntervalSeconds = 0
+	cfg.MinConfidenceThreshold = 0.4
+	cfg.NotifyConfidenceThreshold = 0.4
+	bs := NewBotService(database.NewMemoryStore(), cfg)
+	source := NewStaticMarketDataSource()
+	source.Set("BTC", staticMarketData("BTC", trendKlines(100, -0.5)))
+	bs.SetMarketDataSource(source)
+	bs.watchCrypto(&models.Cryptocurrency{ID: uuid.New(), Symbol: "BTC", IsActive: true})
+	bs.isRunning.Store(true)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			bs.runAnalysisCycle(true, "15m")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			bs.GetStatus()
+			bs.GetDiagnostics()
+			bs.IsWarmedUp()
+		}
+	}()
+	wg.Wait()
+
+	if bs.lastAnalysis().IsZero() {
+		t.Error("last analysis time was not recorded")
+	}
+	if got := bs.GetStatus()["total_signals_today"]; got.(int64) == 0 {
+		t.Error("no signal was counted")
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxCandleLimit caps how many candles one request may fetch (Binance's page size)
+const MaxCandleLimit = 1000
+
+// ErrInvalidCandleRequest wraps validation failures of a candle request
+var ErrInvalidCandleRequest = errors.New("invalid candle request")
+
+// candleIntervals are the kline intervals Binance accepts, with the time one
+// candle spans (a month counted as 30 days)
+var candleIntervals = map[string]time.Duration{
+	"1m": time.Minute, "3m": 3 * time.Minute, "5m": 5 * time.Minute, "15m": 15 * time.Minute, "30m": 30 * time.Minute,
+	"1h": time.Hour, "2h": 2 * time.Hour, "4h": 4 * time.Hour, "6h": 6 * time.Hour, "8h": 8 * time.Hour, "12h": 12 * time.Hour,
+	"1d": 24 * time.Hour, "3d": 3 * 24 * time.Hour, "1w": 7 * 24 * time.Hour, "1M": 30 * 24 * time.Hour,
+}
+
+// GetCandles returns parsed OHLCV candles for a symbol from the same kline
+// sources the analysis uses, oldest first
+func (bs *BotService) GetCandles(symbol, interval string, limit int) ([]models.Candle, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return nil, fmt.Errorf("%w: symbol is required", ErrInvalidCandleRequest)
+	}
+	if _, ok := candleIntervals[interval]; !ok {
+		return nil, fmt.Errorf("%w: unsupported interval %q", ErrInvalidCandleRequest, interval)
+	}
+	if limit < 1 || limit > MaxCandleLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidCandleRequest, MaxCandleLimit)
+	}
+
+	klines, err := bs.dataCollector.getKlines(symbol, interval, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ohlcvData, err := bs.technicalAnalyzer.parseKlineData(klines)
+	if err != nil {
+		return nil, err
+	}
+
+	candles := make([]models.Candle, len(ohlcvData))
+	for i, ohlcv := range ohlcvData {
+		candles[i] = models.Candle{
+			Timestamp: ohlcv.Timestamp,
+			Open:      ohlcv.Open,
+			High:      ohlcv.High,
+			Low:       ohlcv.Low,
+			Close:     ohlcv.Close,
+			Volume:    ohlcv.Volume,
+		}
+	}
+	return candles, nil
+}
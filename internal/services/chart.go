@@ -0,0 +1,171 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// chartCandleCount is how many candles a chart shows; enough for SMA50 to
+// cover most of the window
+const chartCandleCount = 120
+
+// chartCacheTTL is how long a rendered chart is reused for the same symbol and
+// interval, so repeated /chart taps don't refetch klines
+const chartCacheTTL = 2 * time.Minute
+
+// Chart is a rendered candlestick chart and the signal whose levels it marks
+type Chart struct {
+	Symbol   string
+	Interval string
+	PNG      []byte
+	Last     models.Candle
+	Signal   *models.TradingSignal // Latest active signal of the coin; nil when none
+}
+
+// chartCache holds recently rendered charts keyed by symbol and interval
+type chartCache struct {
+	mu      sync.Mutex
+	entries map[string]chartCacheEntry
+}
+
+type chartCacheEntry struct {
+	chart   *Chart
+	expires time.Time
+}
+
+func (c *chartCache) get(key string) (*Chart, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[key]
+	if !exists || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.chart, true
+}
+
+func (c *chartCache) put(key string, chart *Chart) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]chartCacheEntry)
+	}
+	// Drop expired charts so the cache stays as small as the set of active requests
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = chartCacheEntry{chart: chart, expires: now.Add(chartCacheTTL)}
+}
+
+// GetChart renders a candlestick chart of a coin with SMA20/SMA50 and Bollinger
+// Bands, marking entry, SL and TP levels of its latest active signal
+func (bs *BotService) GetChart(symbol, interval string) (*Chart, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if interval == "" {
+		interval = bs.cfg.KlineInterval
+	}
+
+	key := symbol + "|" + interval
+	if chart, ok := bs.charts.get(key); ok {
+		return chart, nil
+	}
+
+	candles, err := bs.GetCandles(symbol, interval, chartCandleCount)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no candles for %s %s", symbol, interval)
+	}
+
+	chart := &Chart{
+		Symbol:   symbol,
+		Interval: interval,
+		Last:     candles[len(candles)-1],
+		Signal:   bs.latestActiveSignal(symbol),
+	}
+
+	chart.PNG, err = renderCandlestickChart(candles, signalLevels(chart.Signal))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	bs.charts.put(key, chart)
+	return chart, nil
+}
+
+// latestActiveSignal returns the newest active signal of a watched coin, if any
+func (bs *BotService) latestActiveSignal(symbol string) *models.TradingSignal {
+	if bs.db == nil {
+		return nil
+	}
+
+	var crypto *models.Cryptocurrency
+	for _, watched := range bs.watchlist() {
+		if watched.Symbol == symbol {
+			crypto = watched
+			break
+		}
+	}
+	if crypto == nil {
+		return nil
+	}
+
+	signals, err := bs.db.GetActiveSignals()
+	if err != nil {
+		return nil
+	}
+
+	var latest *models.TradingSignal
+	for _, signal := range signals {
+		if signal.CryptoID != crypto.ID || (signal.Action != "BUY" && signal.Action != "SELL") {
+			continue
+		}
+		if latest == nil || signal.CreatedAt.After(latest.CreatedAt) {
+			latest = signal
+		}
+	}
+	return latest
+}
+
+// signalLevels lists the horizontal lines drawn for a signal
+func signalLevels(signal *models.TradingSignal) []chartLevel {
+	if signal == nil {
+		return nil
+	}
+
+	levels := []chartLevel{{Price: signal.EntryPrice.InexactFloat64(), Color: chartEntryColor}}
+	if signal.EntryLow != nil && signal.EntryHigh != nil {
+		levels = append(levels,
+			chartLevel{Price: signal.EntryLow.InexactFloat64(), Color: chartEntryColor},
+			chartLevel{Price: signal.EntryHigh.InexactFloat64(), Color: chartEntryColor},
+		)
+	}
+	if signal.StopLoss != nil {
+		levels = append(levels, chartLevel{Price: signal.StopLoss.InexactFloat64(), Color: chartStopLossColor})
+	}
+
+	if len(signal.TakeProfits) > 0 {
+		for _, target := range signal.TakeProfits {
+			levels = append(levels, chartLevel{Price: target.Price.InexactFloat64(), Color: chartTakeProfitColor})
+		}
+	} else {
+		// Signals loaded from the database carry only the first two targets
+		for _, tp := range []*decimal.Decimal{signal.TakeProfit1, signal.TakeProfit2} {
+			if tp != nil {
+				levels = append(levels, chartLevel{Price: tp.InexactFloat64(), Color: chartTakeProfitColor})
+			}
+		}
+	}
+
+	return levels
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"bytes"
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	chartWidth     = 1000
+	chartHeight    = 560
+	chartPadding   = 20
+	chartPriceAxis = 96 // Width of the price scale right of the plot
+	chartTimeAxis  = 24 // Height of the time scale below the plot
+	chartTicks     = 6  // Roughly how many price and time labels to draw
+)
+
+var (
+	chartBackground      = color.RGBA{19, 23, 34, 255}
+	chartGridColor       = color.RGBA{42, 46, 57, 255}
+	chartLabelColor      = color.RGBA{178, 181, 190, 255}
+	chartUpColor         = color.RGBA{38, 166, 154, 255}
+	chartDownColor       = color.RGBA{239, 83, 80, 255}
+	chartSMA20Color      = color.RGBA{255, 193, 7, 255}
+	chartSMA50Color      = color.RGBA{171, 71, 188, 255}
+	chartBandColor       = color.RGBA{100, 181, 246, 255}
+	chartEntryColor      = color.RGBA{33, 150, 243, 255}
+	chartStopLossColor   = color.RGBA{255, 82, 82, 255}
+	chartTakeProfitColor = color.RGBA{0, 230, 118, 255}
+)
+
+// chartLevel is a horizontal price line across the chart
+type chartLevel struct {
+	Price float64
+	Color color.RGBA
+}
+
+// chartFrame maps candle indexes and prices to pixels of the plot area
+type chartFrame struct {
+	count      int
+	minPrice   float64
+	maxPrice   float64
+	left, top  int
+	plotWidth  int
+	plotHeight int
+}
+
+func (f chartFrame) x(i int) int {
+	slot := float64(f.plotWidth) / float64(f.count)
+	return f.left + int(slot*(float64(i)+0.5))
+}
+
+func (f chartFrame) y(price float64) int {
+	ratio := (f.maxPrice - price) / (f.maxPrice - f.minPrice)
+	return f.top + int(ratio*float64(f.plotHeight))
+}
+
+// renderCandlestickChart draws candles with SMA20, SMA50 and Bollinger Bands
+// (20, 2) and the given levels as dashed lines, encoded as PNG. A price scale
+// on the right tags the levels and the last close, and UTC open times run
+// along the bottom. The legend is left to the message caption.
+func renderCandlestickChart(candles []models.Candle, levels []chartLevel) ([]byte, error) {
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no candles to draw")
+	}
+
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close.InexactFloat64()
+	}
+	sma20 := chartSMA(closes, 20)
+	sma50 := chartSMA(closes, 50)
+	bbUpper, bbLower := chartBollinger(closes, sma20, 20, 2)
+
+	frame := chartFrame{
+		count:      len(candles),
+		minPrice:   math.Inf(1),
+		maxPrice:   math.Inf(-1),
+		left:       chartPadding,
+		top:        chartPadding,
+		plotWidth:  chartWidth - chartPadding - chartPriceAxis,
+		plotHeight: chartHeight - chartPadding - chartTimeAxis,
+	}
+	include := func(price float64) {
+		if math.IsNaN(price) || price <= 0 {
+			return
+		}
+		frame.minPrice = math.Min(frame.minPrice, price)
+		frame.maxPrice = math.Max(frame.maxPrice, price)
+	}
+	for i, candle := range candles {
+		include(candle.Low.InexactFloat64())
+		include(candle.High.InexactFloat64())
+		include(bbUpper[i])
+		include(bbLower[i])
+	}
+	for _, level := range levels {
+		include(level.Price)
+	}
+	if frame.maxPrice <= frame.minPrice {
+		frame.maxPrice = frame.minPrice * 1.01
+		frame.minPrice *= 0.99
+	}
+	margin := (frame.maxPrice - frame.minPrice) * 0.03
+	frame.minPrice -= margin
+	frame.maxPrice += margin
+
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{chartBackground}, image.Point{}, draw.Src)
+
+	right, bottom := frame.left+frame.plotWidth, frame.top+frame.plotHeight
+	drawLine(img, right, frame.top, right, bottom, chartGridColor)
+	drawLine(img, frame.left, bottom, right, bottom, chartGridColor)
+
+	// Price scale: grid lines on round prices
+	step := niceStep((frame.maxPrice - frame.minPrice) / chartTicks)
+	decimals := stepDecimals(step)
+	for k := math.Ceil(frame.minPrice / step); k*step <= frame.maxPrice; k++ {
+		y := frame.y(k * step)
+		drawLine(img, frame.left, y, right, y, chartGridColor)
+		drawText(img, right+6, y+4, strconv.FormatFloat(k*step, 'f', decimals, 64), chartLabelColor)
+	}
+
+	// Time scale: open times of evenly spaced candles, with the date only for
+	// daily and longer candles
+	layout := "02 Jan 15:04"
+	if len(candles) > 1 && candles[1].Timestamp-candles[0].Timestamp >= (24*time.Hour).Milliseconds() {
+		layout = "02 Jan 2006"
+	}
+	every := (frame.count + chartTicks - 1) / chartTicks
+	for i := every / 2; i < frame.count; i += every {
+		x := frame.x(i)
+		drawLine(img, x, frame.top, x, bottom, chartGridColor)
+
+		label := time.UnixMilli(candles[i].Timestamp).UTC().Format(layout)
+		labelX := x - textWidth(label)/2
+		labelX = max(frame.left, min(labelX, right-textWidth(label)))
+		drawText(img, labelX, bottom+17, label, chartLabelColor)
+	}
+	drawText(img, right+6, bottom+17, "UTC", chartLabelColor)
+
+	drawSeries(img, frame, bbUpper, chartBandColor)
+	drawSeries(img, frame, bbLower, chartBandColor)
+
+	bodyWidth := int(float64(frame.plotWidth) / float64(frame.count) * 0.6)
+	if bodyWidth < 1 {
+		bodyWidth = 1
+	}
+	for i, candle := range candles {
+		open, close := candle.Open.InexactFloat64(), candle.Close.InexactFloat64()
+		candleColor := chartUpColor
+		if close < open {
+			candleColor = chartDownColor
+		}
+
+		x := frame.x(i)
+		drawLine(img, x, frame.y(candle.High.InexactFloat64()), x, frame.y(candle.Low.InexactFloat64()), candleColor)
+
+		top, bottom := frame.y(math.Max(open, close)), frame.y(math.Min(open, close))
+		if bottom == top {
+			bottom++ // Doji still shows a body
+		}
+		body := image.Rect(x-bodyWidth/2, top, x-bodyWidth/2+bodyWidth, bottom)
+		draw.Draw(img, body, &image.Uniform{candleColor}, image.Point{}, draw.Src)
+	}
+
+	drawSeries(img, frame, sma50, chartSMA50Color)
+	drawSeries(img, frame, sma20, chartSMA20Color)
+
+	// Tags carry two more decimals than the scale so nearby levels differ
+	for _, level := range levels {
+		if level.Price <= 0 {
+			continue
+		}
+		y := frame.y(level.Price)
+		for x := frame.left; x < frame.left+frame.plotWidth; x += 12 {
+			dash := image.Rect(x, y, x+7, y+2)
+			draw.Draw(img, dash, &image.Uniform{level.Color}, image.Point{}, draw.Src)
+		}
+		drawPriceTag(img, right, y, strconv.FormatFloat(level.Price, 'f', decimals+2, 64), level.Color)
+	}
+
+	last := candles[len(candles)-1]
+	lastColor := chartUpColor
+	if last.Close.LessThan(last.Open) {
+		lastColor = chartDownColor
+	}
+	drawPriceTag(img, right, frame.y(last.Close.InexactFloat64()), strconv.FormatFloat(last.Close.InexactFloat64(), 'f', decimals+2, 64), lastColor)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// niceStep rounds a raw tick interval up to 1, 2 or 5 times a power of ten
+func niceStep(raw float64) float64 {
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, multiple := range []float64{1, 2, 5} {
+		if step := multiple * magnitude; step >= raw {
+			return step
+		}
+	}
+	return 10 * magnitude
+}
+
+// stepDecimals is the number of decimals that shows every multiple of step
+func stepDecimals(step float64) int {
+	return max(0, int(-math.Floor(math.Log10(step))))
+}
+
+// chartSMA returns the simple moving average at each index; NaN until the
+// window is full
+func chartSMA(values []float64, period int) []float64 {
+	series := make([]float64, len(values))
+	sum := 0.0
+	for i, value := range values {
+		sum += value
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i < period-1 {
+			series[i] = math.NaN()
+			continue
+		}
+		series[i] = sum / float64(period)
+	}
+	return series
+}
+
+// chartBollinger returns the upper and lower bands around a precomputed SMA
+func chartBollinger(values, sma []float64, period int, multiplier float64) (upper, lower []float64) {
+	upper = make([]float64, len(values))
+	lower = make([]float64, len(values))
+	for i := range values {
+		if math.IsNaN(sma[i]) {
+			upper[i], lower[i] = math.NaN(), math.NaN()
+			continue
+		}
+		variance := 0.0
+		for _, value := range values[i-period+1 : i+1] {
+			variance += (value - sma[i]) * (value - sma[i])
+		}
+		deviation := math.Sqrt(variance / float64(period))
+		upper[i] = sma[i] + multiplier*deviation
+		lower[i] = sma[i] - multiplier*deviation
+	}
+	return upper, lower
+}
+
+// drawSeries connects consecutive defined points of an indicator series
+func drawSeries(img *image.RGBA, frame chartFrame, series []float64, c color.RGBA) {
+	for i := 1; i < len(series); i++ {
+		if math.IsNaN(series[i-1]) || math.IsNaN(series[i]) {
+			continue
+		}
+		drawLine(img, frame.x(i-1), frame.y(series[i-1]), frame.x(i), frame.y(series[i]), c)
+	}
+}
+
+// drawLine plots a one-pixel line with Bresenham's algorithm
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx := int(math.Abs(float64(x1 - x0)))
+	dy := -int(math.Abs(float64(y1 - y0)))
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for {
+		img.SetRGBA(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// drawText writes a label in the 7x13 bitmap font with its baseline at y
+func drawText(img *image.RGBA, x, y int, text string, c color.RGBA) {
+	drawer := font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{c},
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
+
+func textWidth(text string) int {
+	return font.MeasureString(basicfont.Face7x13, text).Ceil()
+}
+
+// drawPriceTag labels a price on the scale right of the plot
+func drawPriceTag(img *image.RGBA, right, y int, label string, c color.RGBA) {
+	tag := image.Rect(right+1, y-7, right+textWidth(label)+11, y+8)
+	draw.Draw(img, tag, &image.Uniform{c}, image.Point{}, draw.Src)
+	drawText(img, right+6, y+4, label, chartBackground)
+}
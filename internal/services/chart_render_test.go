@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bytes"
+	"crypto-signal-bot/internal/models"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestChartPriceTicks(t *testing.T) {
+	tests := []struct {
+		raw      float64
+		step     float64
+		decimals int
+	}{
+		{raw: 130, step: 200, decimals: 0},
+		{raw: 0.42, step: 0.5, decimals: 1},
+		{raw: 1, step: 1, decimals: 0},
+		{raw: 0.0000012, step: 0.000002, decimals: 6},
+		{raw: 6, step: 10, decimals: 0},
+	}
+
+	for _, tt := range tests {
+		step := niceStep(tt.raw)
+		if diff := step - tt.step; diff > tt.step*1e-9 || diff < -tt.step*1e-9 {
+			t.Errorf("niceStep(%v) = %v, want %v", tt.raw, step, tt.step)
+		}
+		if decimals := stepDecimals(step); decimals != tt.decimals {
+			t.Errorf("stepDecimals(%v) = %d, want %d", step, decimals, tt.decimals)
+		}
+	}
+}
+
+func TestRenderCandlestickChart(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]models.Candle, 120)
+	for i := range candles {
+		open := 0.00001200 + float64(i%7)*0.00000003
+		candles[i] = models.Candle{
+			Timestamp: start.Add(time.Duration(i) * 15 * time.Minute).UnixMilli(),
+			Open:      decimal.NewFromFloat(open),
+			High:      decimal.NewFromFloat(open * 1.01),
+			Low:       decimal.NewFromFloat(open * 0.99),
+			Close:     decimal.NewFromFloat(open * 1.005),
+		}
+	}
+	levels := []chartLevel{
+		{Price: 0.0000121, Color: chartEntryColor},
+		{Price: 0.0000118, Color: chartStopLossColor},
+	}
+
+	data, err := renderCandlestickChart(candles, levels)
+	if err != nil {
+		t.Fatalf("renderCandlestickChart: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != chartWidth || size.Y != chartHeight {
+		t.Errorf("size = %v, want %dx%d", size, chartWidth, chartHeight)
+	}
+
+	// The price scale must hold some label pixels
+	labelled := false
+	for y := chartPadding; y < chartHeight-chartTimeAxis && !labelled; y++ {
+		for x := chartWidth - chartPriceAxis + 6; x < chartWidth; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			if r>>8 == uint32(chartLabelColor.R) && g>>8 == uint32(chartLabelColor.G) && b>>8 == uint32(chartLabelColor.B) {
+				labelled = true
+				break
+			}
+		}
+	}
+	if !labelled {
+		t.Error("no price labels drawn on the scale")
+	}
+}
@@ -27,7 +27,7 @@ func NewCoinMarketCapService(cfg *config.Config) *CoinMarketCapService {
 	return &CoinMarketCapService{
 		apiKey:  cfg.CoinMarketCapAPIKey,
 		baseURL: "https://pro-api.coinmarketcap.com/v1",
-		client:  &http.Client{Timeout: 30 * time.Second},
+		client:  utils.NewHTTPClient(30*time.Second, cfg.DebugHTTP),
 	}
 }
 
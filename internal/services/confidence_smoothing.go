@@ -0,0 +1,65 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// confidenceSmoother keeps an exponentially smoothed, directional confidence
+// per symbol across analysis cycles. BUY counts as positive and SELL as
+// negative confidence, so opposing cycles pull the average back toward zero.
+type confidenceSmoother struct {
+	mu    sync.Mutex
+	alpha decimal.Decimal
+	state map[string]*smoothedConfidence
+}
+
+type smoothedConfidence struct {
+	value decimal.Decimal // Signed: positive leans BUY, negative leans SELL
+	above bool            // Magnitude was at or above the threshold last cycle
+}
+
+// newConfidenceSmoother returns nil when alpha is outside (0, 1), which leaves
+// raw per-cycle confidence in charge
+func newConfidenceSmoother(alpha float64) *confidenceSmoother {
+	if alpha <= 0 || alpha >= 1 {
+		return nil
+	}
+	return &confidenceSmoother{
+		alpha: decimal.NewFromFloat(alpha),
+		state: make(map[string]*smoothedConfidence),
+	}
+}
+
+// update folds this cycle's decision into the key's average. fire is true only
+// on the cycle the smoothed confidence crosses the threshold in the direction
+// of action; it re-arms once the average falls back below.
+func (cs *confidenceSmoother) update(key, action string, confidence, threshold decimal.Decimal) (smoothed decimal.Decimal, fire bool) {
+	observed := decimal.Zero
+	switch action {
+	case "BUY":
+		observed = confidence
+	case "SELL":
+		observed = confidence.Neg()
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	entry, exists := cs.state[key]
+	if !exists {
+		entry = &smoothedConfidence{value: observed}
+		cs.state[key] = entry
+	} else {
+		entry.value = cs.alpha.Mul(observed).Add(decimal.NewFromInt(1).Sub(cs.alpha).Mul(entry.value))
+	}
+
+	smoothed = entry.value.Abs()
+	above := !smoothed.LessThan(threshold)
+	sameDirection := observed.Sign() != 0 && observed.Sign() == entry.value.Sign()
+	fire = above && !entry.above && sameDirection
+	entry.above = above
+
+	return smoothed, fire
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// cycleHistorySize is how many recent cycles the rolling baseline averages
+const cycleHistorySize = 20
+
+// cycleBaselineMinSamples is how many cycles are needed before a baseline is trusted
+const cycleBaselineMinSamples = 3
+
+// cycleSlowestShown is how many of the slowest symbols a cycle keeps
+const cycleSlowestShown = 3
+
+// SymbolDuration is how long analyzing one coin took, excluding rate-limit pauses
+type SymbolDuration struct {
+	Symbol  string  `json:"symbol"`
+	Seconds float64 `json:"seconds"`
+}
+
+// CycleDuration records one analysis cycle
+type CycleDuration struct {
+	StartedAt time.Time        `json:"started_at"`
+	Interval  string           `json:"interval"`
+	Seconds   float64          `json:"seconds"`
+	Coins     int              `json:"coins"`
+	Slowest   []SymbolDuration `json:"slowest"`
+}
+
+// cycleTimer keeps recent cycle durations for the status endpoint and the
+// slow-cycle alert
+type cycleTimer struct {
+	mu     sync.Mutex
+	recent []CycleDuration // Oldest first
+}
+
+// record stores a cycle and returns the baseline (mean of the cycles before
+// it); ok is false until enough cycles have been seen
+func (ct *cycleTimer) record(cycle CycleDuration) (baseline float64, ok bool) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if len(ct.recent) >= cycleBaselineMinSamples {
+		total := 0.0
+		for _, previous := range ct.recent {
+			total += previous.Seconds
+		}
+		baseline, ok = total/float64(len(ct.recent)), true
+	}
+
+	ct.recent = append(ct.recent, cycle)
+	if len(ct.recent) > cycleHistorySize {
+		ct.recent = ct.recent[len(ct.recent)-cycleHistorySize:]
+	}
+	return baseline, ok
+}
+
+// recentCycles returns the recorded cycles, newest first
+func (ct *cycleTimer) recentCycles() []CycleDuration {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	cycles := make([]CycleDuration, len(ct.recent))
+	for i, cycle := range ct.recent {
+		cycles[len(ct.recent)-1-i] = cycle
+	}
+	return cycles
+}
+
+// slowestSymbols returns the coins that took longest, slowest first
+func slowestSymbols(durations map[string]time.Duration) []SymbolDuration {
+	slowest := make([]SymbolDuration, 0, len(durations))
+	for symbol, duration := range durations {
+		slowest = append(slowest, SymbolDuration{Symbol: symbol, Seconds: duration.Seconds()})
+	}
+	sort.Slice(slowest, func(i, j int) bool { return slowest[i].Seconds > slowest[j].Seconds })
+	if len(slowest) > cycleSlowestShown {
+		slowest = slowest[:cycleSlowestShown]
+	}
+	return slowest
+}
+
+// recordCycleDuration stores a finished cycle and alerts when it ran longer
+// than CYCLE_SLOW_MULTIPLIER times the rolling baseline or CYCLE_MAX_SECONDS
+func (bs *BotService) recordCycleDuration(startedAt time.Time, interval string, durations map[string]time.Duration) {
+	cycle := CycleDuration{
+		StartedAt: startedAt,
+		Interval:  interval,
+		Seconds:   time.Since(startedAt).Seconds(),
+		Coins:     len(durations),
+		Slowest:   slowestSymbols(durations),
+	}
+	baseline, hasBaseline := bs.cycleTimer.record(cycle)
+
+	var reason string
+	switch {
+	case bs.cfg.CycleMaxSeconds > 0 && cycle.Seconds > float64(bs.cfg.CycleMaxSeconds):
+		reason = fmt.Sprintf("melebihi batas %ds", bs.cfg.CycleMaxSeconds)
+	case bs.cfg.CycleSlowMultiplier > 0 && hasBaseline && cycle.Seconds > baseline*bs.cfg.CycleSlowMultiplier:
+		reason = fmt.Sprintf("%.1fx dari rata-rata %.0fs", cycle.Seconds/baseline, baseline)
+	default:
+		return
+	}
+
+	logrus.Warnf("Analysis cycle took %.0fs (%s)", cycle.Seconds, reason)
+
+	var slowest []string
+	for _, symbol := range cycle.Slowest {
+		slowest = append(slowest, fmt.Sprintf("• *%s* - %.1fs", symbol.Symbol, symbol.Seconds))
+	}
+	message := fmt.Sprintf("🐢 Siklus analisis %s berjalan lambat: *%.0fs* (%s) untuk %d coin.",
+		interval, cycle.Seconds, reason, cycle.Coins)
+	if len(slowest) > 0 {
+		message += "\n\nCoin paling lambat:\n" + strings.Join(slowest, "\n")
+	}
+	bs.notificationService.SendSystemNotification("warning", message)
+}
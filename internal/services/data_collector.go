@@ -2,11 +2,15 @@ package services
 
 import (
 	"crypto-signal-bot/internal/config"
+	"crypto-signal-bot/internal/utils"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -16,6 +20,20 @@ import (
 type DataCollector struct {
 	cfg        *config.Config
 	httpClient *http.Client
+	quota      *quotaGuard
+	cmcKeys       *apiKeyManager
+	coinGeckoKeys *apiKeyManager
+
+	// First-candle times per symbol; listing dates never change so they are cached
+	listingMu    sync.Mutex
+	listingTimes map[string]time.Time
+
+	latencies *sourceLatencies // Request times per provider, for /diagnostics
+
+	// Recent market data per symbol/interval and the Fear & Greed reading
+	cacheMu        sync.RWMutex
+	marketCache    map[string]marketDataCacheEntry
+	fearGreedCache *fearGreedCacheEntry
 }
 
 type BinanceKlineData struct {
@@ -101,6 +119,7 @@ type CoinGeckoPrice struct {
 	PriceChangePercent24h float64 `json:"price_change_percentage_24h"`
 	PriceChangePercent1h  float64 `json:"price_change_percentage_1h_in_currency"`
 	PriceChangePercent7d  float64 `json:"price_change_percentage_7d_in_currency"`
+	LastUpdated           string  `json:"last_updated"`
 }
 
 type FearGreedIndex struct {
@@ -123,19 +142,86 @@ type MarketData struct {
 	PriceChange7d    decimal.Decimal
 	FearGreedIndex   int
 	KlineData        [][]interface{} // OHLCV data for technical analysis
-	Timestamp        time.Time
+	ListedAt         *time.Time      // When the coin was listed, if the source reports it
+	HTFKlineData     [][]interface{} // Higher-timeframe klines for trend confirmation, if enabled
+	DailyKlineData   [][]interface{} // 1d klines for the daily trend bonus, if enabled
+	Interval         string          // Kline interval of KlineData, e.g. 15m
+	Timestamp        time.Time       // When the provider last updated the quote, not when it was fetched
+	AsOf             time.Time       // Time the analysis is for when replaying history; zero means now
+
+	// Provenance, used to score data quality
+	PriceSource        string // Provider of the quote: coinmarketcap, or binance as fallback
+	KlineSource        string // Kline provider that answered, from KLINE_SOURCES
+	CoinGeckoEnriched  bool   // CoinGecko market data was merged in
+	FearGreedAvailable bool   // Fear & Greed came from the API rather than the neutral default
+	FearGreedSource    string // Source of FearGreedIndex; empty for the neutral default
+	SourceAgreement    *SourceAgreement // Price/24h change spread across providers; nil with a single source
 }
 
+// klineFetchLimit is how many candles GetMarketData requests
+const klineFetchLimit = 100
+
+
 func NewDataCollector(cfg *config.Config) *DataCollector {
-	return &DataCollector{
+	dc := &DataCollector{
 		cfg: cfg,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient: utils.NewHTTPClient(30*time.Second, cfg.DebugHTTP),
+		quota: newQuotaGuard(cfg.CMCMonthlyCreditLimit),
+		cmcKeys:       newAPIKeyManager(providerCoinMarketCap, cfg.CoinMarketCapAPIKeys),
+		coinGeckoKeys: newAPIKeyManager(providerCoinGecko, coinGeckoKeys(cfg)),
+		listingTimes: make(map[string]time.Time),
+		latencies:    &sourceLatencies{},
+		marketCache:  make(map[string]marketDataCacheEntry),
+	}
+
+	next := dc.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	dc.httpClient.Transport = &latencyTransport{next: next, latencies: dc.latencies}
+
+	return dc
+}
+
+// coinGeckoKeys returns the rotated CoinGecko keys; pro keys take precedence over demo keys
+func coinGeckoKeys(cfg *config.Config) []string {
+	if len(cfg.CoinGeckoProAPIKeys) > 0 {
+		return cfg.CoinGeckoProAPIKeys
+	}
+	return cfg.CoinGeckoAPIKeys
+}
+
+// keysUnavailable reports that every key of a provider is limited; only
+// all-keys quota exhaustion marks the provider exhausted and falls back
+func (dc *DataCollector) keysUnavailable(keys *apiKeyManager, op string) error {
+	quota, err := keys.unavailableError(op)
+	if quota {
+		return dc.quota.markExhausted(keys.provider, err)
 	}
+	return err
+}
+
+// SetQuotaExhaustedHandler registers a callback fired once when a provider's quota runs out
+func (dc *DataCollector) SetQuotaExhaustedHandler(handler func(provider string, err error)) {
+	dc.quota.setHandler(handler)
 }
 
-func (dc *DataCollector) GetMarketData(symbol string) (*MarketData, error) {
+// GetQuotaStatus reports quota state, tracked credit usage and usable API keys per provider
+func (dc *DataCollector) GetQuotaStatus() []ProviderQuota {
+	statuses := dc.quota.status()
+	for i := range statuses {
+		keys := dc.cmcKeys
+		if statuses[i].Provider == providerCoinGecko {
+			keys = dc.coinGeckoKeys
+		}
+		statuses[i].KeysTotal = len(keys.keys)
+		statuses[i].KeysAvailable = keys.available()
+	}
+	return statuses
+}
+
+// fetchMarketData collects quotes, enrichment and klines of the given interval
+func (dc *DataCollector) fetchMarketData(symbol, interval string) (*MarketData, error) {
 	logrus.Debug("Fetching market data for: ", symbol)
 
 	// Primary: Get price data from CoinMarketCap (free tier)
@@ -146,10 +232,11 @@ func (dc *DataCollector) GetMarketData(symbol string) (*MarketData, error) {
 		binanceData, binanceErr := dc.getBinanceData(symbol)
 		if binanceErr != nil {
 			logrus.Error("Failed to get both CMC and Binance data: ", binanceErr)
-			return nil, fmt.Errorf("no market data available: CMC error: %v, Binance error: %v", err, binanceErr)
+			// Wrap the Binance error so callers can tell whether the fallback failure is transient
+			return nil, fmt.Errorf("no market data available: CMC error: %v, Binance error: %w", err, binanceErr)
 		}
 		logrus.Info("Using Binance data as fallback")
-		return dc.processMarketDataFromBinance(symbol, binanceData)
+		return dc.processMarketDataFromBinance(symbol, interval, binanceData)
 	}
 
 	// Get additional market data from CoinGecko (optional)
@@ -160,16 +247,15 @@ func (dc *DataCollector) GetMarketData(symbol string) (*MarketData, error) {
 	}
 
 	// Get Fear & Greed Index
-	fearGreedIndex, err := dc.getFearGreedIndex()
-	if err != nil {
-		logrus.Warn("Failed to get Fear & Greed Index: ", err)
-		fearGreedIndex = 50 // Default neutral value
+	fearGreedIndex, fearGreedSource, fearGreedErr := dc.getFearGreed()
+	if fearGreedErr != nil {
+		logrus.Warn("Failed to get Fear & Greed Index, using neutral default: ", fearGreedErr)
 	}
 
 	// Try to get kline data for technical analysis (fallback to Binance if CMC doesn't provide)
-	klineData, err := dc.getBinanceKlines(symbol, "15m", 100)
+	klineData, klineSource, err := dc.getKlinesWithSource(symbol, interval, klineFetchLimit)
 	if err != nil {
-		logrus.Warn("Failed to get kline data from Binance: ", err)
+		logrus.Warn("Failed to get kline data: ", err)
 		// For now, we'll continue without kline data
 		// In production, you might want to use alternative sources
 		klineData = [][]interface{}{}
@@ -177,20 +263,36 @@ func (dc *DataCollector) GetMarketData(symbol string) (*MarketData, error) {
 
 	// Create market data from CMC
 	marketData := &MarketData{
-		Symbol:         symbol,
-		FearGreedIndex: fearGreedIndex,
-		KlineData:      klineData,
-		Timestamp:      time.Now(),
+		Symbol:             symbol,
+		FearGreedIndex:     fearGreedIndex,
+		KlineData:          klineData,
+		Interval:           interval,
+		PriceSource:        providerCoinMarketCap,
+		KlineSource:        klineSource,
+		CoinGeckoEnriched:  coinGeckoData != nil,
+		FearGreedAvailable: fearGreedErr == nil,
+		FearGreedSource:    fearGreedSource,
 	}
 
+	var quotes []sourceQuote
+
 	// Parse CMC data
 	if usdQuote, exists := cmcData.Quote["USD"]; exists {
+		change24h := usdQuote.PercentChange24h
+		quotes = append(quotes, sourceQuote{source: providerCoinMarketCap, price: decimal.NewFromFloat(usdQuote.Price), change24h: &change24h})
 		marketData.Price = decimal.NewFromFloat(usdQuote.Price)
 		marketData.Volume24h = decimal.NewFromFloat(usdQuote.Volume24h)
 		marketData.MarketCap = decimal.NewFromFloat(usdQuote.MarketCap)
 		marketData.PriceChange1h = decimal.NewFromFloat(usdQuote.PercentChange1h)
 		marketData.PriceChange24h = decimal.NewFromFloat(usdQuote.PercentChange24h)
 		marketData.PriceChange7d = decimal.NewFromFloat(usdQuote.PercentChange7d)
+		marketData.Timestamp = quoteTimestamp(usdQuote.LastUpdated, cmcData.LastUpdated)
+	} else {
+		marketData.Timestamp = quoteTimestamp(cmcData.LastUpdated)
+	}
+
+	if dateAdded, err := time.Parse(time.RFC3339, cmcData.DateAdded); err == nil {
+		marketData.ListedAt = &dateAdded
 	}
 
 	// Parse CoinGecko data (if available)
@@ -202,24 +304,87 @@ func (dc *DataCollector) GetMarketData(symbol string) (*MarketData, error) {
 		// Use CoinGecko price if more accurate
 		if coinGeckoData.CurrentPrice > 0 {
 			marketData.Price = decimal.NewFromFloat(coinGeckoData.CurrentPrice)
+			marketData.Timestamp = quoteTimestamp(coinGeckoData.LastUpdated)
 		}
+
+		change24h := coinGeckoData.PriceChangePercent24h
+		quotes = append(quotes, sourceQuote{source: providerCoinGecko, price: decimal.NewFromFloat(coinGeckoData.CurrentPrice), change24h: &change24h})
 	}
 
+	// The latest kline close is the kline provider's live price, at no extra request
+	if klineSource != "" && klineSource != providerCoinGecko {
+		quotes = append(quotes, sourceQuote{source: klineSource, price: lastKlineClose(klineData)})
+	}
+	marketData.SourceAgreement = compareSources(quotes)
+
+	dc.attachHigherTimeframe(marketData)
+	dc.attachDailyTrend(marketData)
+
 	logrus.Debug("Market data collected successfully for: ", symbol)
 	return marketData, nil
 }
 
+// quoteTimestamp parses the first usable provider "last updated" value
+// (RFC 3339). When none parses the fetch time is used, so a provider that omits
+// the field is never mistaken for stale.
+func quoteTimestamp(values ...string) time.Time {
+	for _, value := range values {
+		if updated, err := time.Parse(time.RFC3339, value); err == nil {
+			return updated
+		}
+	}
+	return time.Now()
+}
+
+// htfKlineLimit is enough higher-timeframe candles to seed the 26-period trend EMA
+const htfKlineLimit = 60
+
+// attachHigherTimeframe fetches higher-timeframe klines used only for trend confirmation
+func (dc *DataCollector) attachHigherTimeframe(marketData *MarketData) {
+	if !dc.cfg.HTFConfirmationEnabled {
+		return
+	}
+
+	klines, err := dc.getKlines(marketData.Symbol, dc.cfg.HTFInterval, htfKlineLimit)
+	if err != nil {
+		logrus.Warn("Failed to get ", dc.cfg.HTFInterval, " klines for trend confirmation: ", err)
+		return
+	}
+	marketData.HTFKlineData = klines
+}
+
+// attachDailyTrend fetches just enough 1d klines to seed the daily trend EMA,
+// reusing the higher-timeframe klines when those are already daily
+func (dc *DataCollector) attachDailyTrend(marketData *MarketData) {
+	if !dc.cfg.DailyTrendEnabled {
+		return
+	}
+
+	limit := dc.cfg.DailyTrendEMAPeriod * 2
+	if dc.cfg.HTFInterval == "1d" && len(marketData.HTFKlineData) >= limit {
+		marketData.DailyKlineData = marketData.HTFKlineData
+		return
+	}
+
+	klines, err := dc.getKlines(marketData.Symbol, "1d", limit)
+	if err != nil {
+		logrus.Warn("Failed to get 1d klines for the daily trend: ", err)
+		return
+	}
+	marketData.DailyKlineData = klines
+}
+
 func (dc *DataCollector) getBinanceData(symbol string) (*BinanceTicker, error) {
 	url := fmt.Sprintf("https://api.binance.com/api/v3/ticker/24hr?symbol=%sUSDT", symbol)
 	
-	resp, err := dc.httpClient.Get(url)
+	resp, err := dc.getWithRetry("binance ticker", url)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("binance API error: %d", resp.StatusCode)
+		return nil, statusError("binance ticker", resp.StatusCode, fmt.Errorf("binance API error: %d", resp.StatusCode))
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -235,46 +400,41 @@ func (dc *DataCollector) getBinanceData(symbol string) (*BinanceTicker, error) {
 	return &ticker, nil
 }
 
-func (dc *DataCollector) getCoinGeckoData(symbol string) (*CoinGeckoPrice, error) {
-	// Map common symbols to CoinGecko IDs
-	coinGeckoIDs := map[string]string{
-		"BTC":   "bitcoin",
-		"ETH":   "ethereum",
-		"BNB":   "binancecoin",
-		"ADA":   "cardano",
-		"SOL":   "solana",
-		"DOT":   "polkadot",
-		"MATIC": "matic-network",
-		"AVAX":  "avalanche-2",
-		"LINK":  "chainlink",
-		"ATOM":  "cosmos",
-	}
+// coinGeckoIDs maps common symbols to CoinGecko IDs
+var coinGeckoIDs = map[string]string{
+	"BTC":   "bitcoin",
+	"ETH":   "ethereum",
+	"BNB":   "binancecoin",
+	"ADA":   "cardano",
+	"SOL":   "solana",
+	"DOT":   "polkadot",
+	"MATIC": "matic-network",
+	"AVAX":  "avalanche-2",
+	"LINK":  "chainlink",
+	"ATOM":  "cosmos",
+}
 
+func (dc *DataCollector) getCoinGeckoData(symbol string) (*CoinGeckoPrice, error) {
 	coinID, exists := coinGeckoIDs[symbol]
 	if !exists {
 		return nil, fmt.Errorf("unsupported symbol for CoinGecko: %s", symbol)
 	}
 
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&ids=%s&order=market_cap_desc&per_page=1&page=1&sparkline=false&price_change_percentage=1h,24h,7d", coinID)
-	
-	if dc.cfg.CoinGeckoAPIKey != "" {
-		url += "&x_cg_demo_api_key=" + dc.cfg.CoinGeckoAPIKey
+	if !dc.quota.available(providerCoinGecko) {
+		return nil, &QuotaExhaustedError{Provider: providerCoinGecko, Err: fmt.Errorf("skipping until quota recheck")}
 	}
 
-	resp, err := dc.httpClient.Get(url)
+	resp, err := dc.doCoinGeckoRequest("coingecko markets", fmt.Sprintf("/coins/markets?vs_currency=usd&ids=%s&order=market_cap_desc&per_page=1&page=1&sparkline=false&price_change_percentage=1h,24h,7d", coinID))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("coingecko API error: %d", resp.StatusCode)
-	}
-
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
+	dc.quota.markHealthy(providerCoinGecko)
 
 	var prices []CoinGeckoPrice
 	if err := json.Unmarshal(body, &prices); err != nil {
@@ -288,10 +448,172 @@ func (dc *DataCollector) getCoinGeckoData(symbol string) (*CoinGeckoPrice, error
 	return &prices[0], nil
 }
 
+// newCoinGeckoRequest builds a GET request against the CoinGecko API with the given key
+func (dc *DataCollector) newCoinGeckoRequest(path, key string) (*http.Request, error) {
+	req, err := http.NewRequest("GET", dc.coinGeckoBaseURL()+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pro keys authenticate via header, demo keys via query param
+	if dc.cfg.CoinGeckoProAPIKey != "" {
+		req.Header.Set("x-cg-pro-api-key", key)
+	} else if key != "" {
+		q := req.URL.Query()
+		q.Set("x_cg_demo_api_key", key)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	return req, nil
+}
+
+// doCoinGeckoRequest GETs a CoinGecko path, rotating to the next API key on
+// quota and rate-limit responses. The caller closes the returned 200 response.
+func (dc *DataCollector) doCoinGeckoRequest(op, path string) (*http.Response, error) {
+	for {
+		key, index, ok := dc.coinGeckoKeys.next()
+		if !ok {
+			return nil, dc.keysUnavailable(dc.coinGeckoKeys, op)
+		}
+
+		req, err := dc.newCoinGeckoRequest(path, key)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := dc.doWithRetry(op, req, true)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == 200 {
+			return resp, nil
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		apiErr := fmt.Errorf("coingecko API error: %d", resp.StatusCode)
+		if limit := newKeyLimitError(providerCoinGecko, resp.StatusCode, body, apiErr); limit != nil {
+			dc.coinGeckoKeys.markLimited(index, limit)
+			continue
+		}
+		return nil, statusError(op, resp.StatusCode, apiErr)
+	}
+}
+
+// getCoinGeckoOHLC fetches OHLC candles from CoinGecko and normalizes them into
+// the Binance kline shape. CoinGecko picks the candle size from the day range
+// (30m for 1-2 days, 4h for 3-30 days, 4d beyond) and provides no volume.
+func (dc *DataCollector) getCoinGeckoOHLC(coinID string, days int) ([][]interface{}, error) {
+	if !dc.quota.available(providerCoinGecko) {
+		return nil, &QuotaExhaustedError{Provider: providerCoinGecko, Err: fmt.Errorf("skipping until quota recheck")}
+	}
+
+	resp, err := dc.doCoinGeckoRequest("coingecko ohlc", fmt.Sprintf("/coins/%s/ohlc?vs_currency=usd&days=%d", coinID, days))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var candles [][]float64
+	if err := json.NewDecoder(resp.Body).Decode(&candles); err != nil {
+		return nil, err
+	}
+	dc.quota.markHealthy(providerCoinGecko)
+
+	klines := make([][]interface{}, 0, len(candles))
+	for _, candle := range candles {
+		if len(candle) < 5 {
+			continue
+		}
+		klines = append(klines, []interface{}{
+			candle[0],
+			strconv.FormatFloat(candle[1], 'f', -1, 64),
+			strconv.FormatFloat(candle[2], 'f', -1, 64),
+			strconv.FormatFloat(candle[3], 'f', -1, 64),
+			strconv.FormatFloat(candle[4], 'f', -1, 64),
+			"0",
+		})
+	}
+
+	return klines, nil
+}
+
+// coinGeckoOHLCDays picks the CoinGecko day range whose candle size best matches interval
+func coinGeckoOHLCDays(interval string) int {
+	switch interval {
+	case "1m", "3m", "5m", "15m", "30m", "1h":
+		return 1
+	case "2h", "4h", "6h", "8h", "12h":
+		return 30
+	default:
+		return 365
+	}
+}
+
+// getKlines walks the configured kline sources in order and returns the first success
+func (dc *DataCollector) getKlines(symbol, interval string, limit int) ([][]interface{}, error) {
+	klines, _, err := dc.getKlinesWithSource(symbol, interval, limit)
+	return klines, err
+}
+
+// getKlinesWithSource tries KLINE_SOURCES in order and also reports which source answered
+func (dc *DataCollector) getKlinesWithSource(symbol, interval string, limit int) ([][]interface{}, string, error) {
+	var lastErr error
+
+	for _, source := range dc.cfg.KlineSources {
+		var klines [][]interface{}
+		var err error
+
+		switch strings.TrimSpace(strings.ToLower(source)) {
+		case "binance":
+			klines, err = dc.getBinanceKlines(symbol, interval, limit)
+		case "coingecko":
+			coinID, exists := coinGeckoIDs[symbol]
+			if !exists {
+				err = fmt.Errorf("unsupported symbol for CoinGecko: %s", symbol)
+				break
+			}
+			klines, err = dc.getCoinGeckoOHLC(coinID, coinGeckoOHLCDays(interval))
+			if err == nil && len(klines) > limit {
+				klines = klines[len(klines)-limit:]
+			}
+		default:
+			err = fmt.Errorf("unknown kline source: %s", source)
+		}
+
+		if err == nil && len(klines) > 0 {
+			return klines, source, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("%s returned no klines for %s", source, symbol)
+		}
+		logrus.Warn("Kline source ", source, " failed for ", symbol, ": ", err)
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no kline sources configured")
+	}
+	return nil, "", lastErr
+}
+
+// coinGeckoBaseURL returns the configured CoinGecko API root, defaulting to the
+// pro endpoint when a pro key is set and the public endpoint otherwise
+func (dc *DataCollector) coinGeckoBaseURL() string {
+	if dc.cfg.CoinGeckoBaseURL != "" {
+		return strings.TrimRight(dc.cfg.CoinGeckoBaseURL, "/")
+	}
+	if dc.cfg.CoinGeckoProAPIKey != "" {
+		return "https://pro-api.coingecko.com/api/v3"
+	}
+	return "https://api.coingecko.com/api/v3"
+}
+
+// getFearGreedIndex reads the alternative.me Fear & Greed index
 func (dc *DataCollector) getFearGreedIndex() (int, error) {
 	url := "https://api.alternative.me/fng/"
 	
-	resp, err := dc.httpClient.Get(url)
+	resp, err := dc.getWithRetry("fear & greed", url)
 	if err != nil {
 		return 50, err
 	}
@@ -326,14 +648,14 @@ func (dc *DataCollector) getFearGreedIndex() (int, error) {
 func (dc *DataCollector) getBinanceKlines(symbol, interval string, limit int) ([][]interface{}, error) {
 	url := fmt.Sprintf("https://api.binance.com/api/v3/klines?symbol=%sUSDT&interval=%s&limit=%d", symbol, interval, limit)
 	
-	resp, err := dc.httpClient.Get(url)
+	resp, err := dc.getWithRetry("binance klines", url)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("binance klines API error: %d", resp.StatusCode)
+		return nil, statusError("binance klines", resp.StatusCode, fmt.Errorf("binance klines API error: %d", resp.StatusCode))
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -349,13 +671,184 @@ func (dc *DataCollector) getBinanceKlines(symbol, interval string, limit int) ([
 	return klines, nil
 }
 
+// binanceKlinePageSize is the most candles one Binance klines request returns
+const binanceKlinePageSize = 1000
+
+// GetKlinesSince returns up to MaxCandleLimit Binance klines opened since the
+// given time, oldest first
+func (dc *DataCollector) GetKlinesSince(symbol, interval string, since time.Time) ([][]interface{}, error) {
+	return dc.getBinanceKlinesRange(symbol, interval, since, time.Now(), MaxCandleLimit)
+}
+
+// getBinanceKlinesRange pages through the Binance klines opened between start
+// and end, oldest first, stopping after limit candles
+func (dc *DataCollector) getBinanceKlinesRange(symbol, interval string, start, end time.Time, limit int) ([][]interface{}, error) {
+	var klines [][]interface{}
+	from := start.UnixMilli()
+	for len(klines) < limit && from <= end.UnixMilli() {
+		pageSize := binanceKlinePageSize
+		if remaining := limit - len(klines); remaining < pageSize {
+			pageSize = remaining
+		}
+		url := fmt.Sprintf("https://api.binance.com/api/v3/klines?symbol=%sUSDT&interval=%s&startTime=%d&endTime=%d&limit=%d",
+			symbol, interval, from, end.UnixMilli(), pageSize)
+
+		resp, err := dc.getWithRetry("binance klines", url)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			resp.Body.Close()
+			return nil, statusError("binance klines", resp.StatusCode, fmt.Errorf("binance klines API error: %d", resp.StatusCode))
+		}
+		var page [][]interface{}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		klines = append(klines, page...)
+		lastOpen, err := klineInt(page[len(page)-1][0])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected kline open time for %s: %w", symbol, err)
+		}
+		if len(page) < pageSize {
+			break
+		}
+		from = lastOpen + 1
+	}
+
+	return klines, nil
+}
+
+// GetListingTime returns when a symbol started trading, taken from the market
+// data when the source reports it, otherwise from Binance's first daily candle
+func (dc *DataCollector) GetListingTime(marketData *MarketData) (time.Time, error) {
+	if marketData.ListedAt != nil {
+		return *marketData.ListedAt, nil
+	}
+
+	dc.listingMu.Lock()
+	listedAt, cached := dc.listingTimes[marketData.Symbol]
+	dc.listingMu.Unlock()
+	if cached {
+		return listedAt, nil
+	}
+
+	url := fmt.Sprintf("https://api.binance.com/api/v3/klines?symbol=%sUSDT&interval=1d&startTime=0&limit=1", marketData.Symbol)
+
+	resp, err := dc.getWithRetry("binance klines", url)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return time.Time{}, statusError("binance klines", resp.StatusCode, fmt.Errorf("binance klines API error: %d", resp.StatusCode))
+	}
+
+	var klines [][]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&klines); err != nil {
+		return time.Time{}, err
+	}
+	if len(klines) == 0 || len(klines[0]) == 0 {
+		return time.Time{}, fmt.Errorf("no klines available for %s", marketData.Symbol)
+	}
+
+	openTime, err := klineInt(klines[0][0])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected kline open time for %s: %w", marketData.Symbol, err)
+	}
+	listedAt = time.UnixMilli(openTime)
+
+	dc.listingMu.Lock()
+	dc.listingTimes[marketData.Symbol] = listedAt
+	dc.listingMu.Unlock()
+
+	return listedAt, nil
+}
+
+// GetCurrentPrice fetches just the latest Binance price, much cheaper than a full GetMarketData
+func (dc *DataCollector) GetCurrentPrice(symbol string) (decimal.Decimal, error) {
+	url := fmt.Sprintf("https://api.binance.com/api/v3/ticker/price?symbol=%sUSDT", symbol)
+
+	resp, err := dc.getWithRetry("binance price", url)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return decimal.Zero, statusError("binance price", resp.StatusCode, fmt.Errorf("binance price API error: %d", resp.StatusCode))
+	}
+
+	var ticker struct {
+		Price string `json:"price"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&ticker); err != nil {
+		return decimal.Zero, err
+	}
+
+	price, err := decimal.NewFromString(ticker.Price)
+	if err != nil || !price.IsPositive() {
+		return decimal.Zero, fmt.Errorf("invalid price for %s: %q", symbol, ticker.Price)
+	}
+	return price, nil
+}
+
+// GetPriceChangeSince returns the percentage price change of a symbol from the
+// open of the daily candle containing since to the latest close
+func (dc *DataCollector) GetPriceChangeSince(symbol string, since time.Time) (decimal.Decimal, error) {
+	url := fmt.Sprintf("https://api.binance.com/api/v3/klines?symbol=%sUSDT&interval=1d&startTime=%d&limit=1000", symbol, since.UnixMilli())
+
+	resp, err := dc.getWithRetry("binance klines", url)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return decimal.Zero, statusError("binance klines", resp.StatusCode, fmt.Errorf("binance klines API error: %d", resp.StatusCode))
+	}
+
+	var klines [][]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&klines); err != nil {
+		return decimal.Zero, err
+	}
+
+	return klinesPriceChange(symbol, since, klines)
+}
+
+// klinesPriceChange returns the percentage change from the open of the first
+// kline to the close of the last one
+func klinesPriceChange(symbol string, since time.Time, klines [][]interface{}) (decimal.Decimal, error) {
+	if len(klines) == 0 || len(klines[0]) < 5 || len(klines[len(klines)-1]) < 5 {
+		return decimal.Zero, fmt.Errorf("no price history for %s since %s", symbol, since.Format("2006-01-02"))
+	}
+
+	open, err := klineDecimal(klines[0][1])
+	if err != nil || open.IsZero() {
+		return decimal.Zero, fmt.Errorf("invalid opening price for %s", symbol)
+	}
+	close, err := klineDecimal(klines[len(klines)-1][4])
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid closing price for %s", symbol)
+	}
+
+	return close.Sub(open).Div(open).Mul(decimal.NewFromInt(100)), nil
+}
+
 func (dc *DataCollector) GetMultipleMarketData(symbols []string) (map[string]*MarketData, error) {
 	logrus.Info("Fetching market data for multiple symbols: ", symbols)
 	
 	results := make(map[string]*MarketData)
 	
 	for _, symbol := range symbols {
-		data, err := dc.GetMarketData(symbol)
+		data, err := dc.GetMarketData(symbol, dc.cfg.KlineInterval)
 		if err != nil {
 			logrus.Error("Failed to get market data for ", symbol, ": ", err)
 			continue
@@ -375,7 +868,30 @@ func (dc *DataCollector) getCMCData(symbol string) (*CMCCurrency, error) {
 	if dc.cfg.CoinMarketCapAPIKey == "" {
 		return nil, fmt.Errorf("CoinMarketCap API key not configured")
 	}
+	if !dc.quota.available(providerCoinMarketCap) {
+		return nil, &QuotaExhaustedError{Provider: providerCoinMarketCap, Err: fmt.Errorf("skipping until quota recheck")}
+	}
 
+	// Try each key in rotation until one is not limited
+	for {
+		key, index, ok := dc.cmcKeys.next()
+		if !ok {
+			return nil, dc.keysUnavailable(dc.cmcKeys, "cmc quotes")
+		}
+
+		currency, err := dc.fetchCMCQuote(symbol, key)
+		var limit *keyLimitError
+		if errors.As(err, &limit) {
+			dc.cmcKeys.markLimited(index, limit)
+			continue
+		}
+		return currency, err
+	}
+}
+
+// fetchCMCQuote requests a CMC quote with one API key; per-key quota and rate
+// limits come back as *keyLimitError
+func (dc *DataCollector) fetchCMCQuote(symbol, key string) (*CMCCurrency, error) {
 	// CMC API endpoint for quotes
 	url := fmt.Sprintf("https://pro-api.coinmarketcap.com/v1/cryptocurrency/quotes/latest?symbol=%s&convert=USD", symbol)
 
@@ -385,10 +901,10 @@ func (dc *DataCollector) getCMCData(symbol string) (*CMCCurrency, error) {
 	}
 
 	// Add required headers
-	req.Header.Set("X-CMC_PRO_API_KEY", dc.cfg.CoinMarketCapAPIKey)
+	req.Header.Set("X-CMC_PRO_API_KEY", key)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := dc.httpClient.Do(req)
+	resp, err := dc.doWithRetry("cmc quotes", req, true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -396,7 +912,11 @@ func (dc *DataCollector) getCMCData(symbol string) (*CMCCurrency, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("CMC API error: status %d, body: %s", resp.StatusCode, string(body))
+		apiErr := fmt.Errorf("CMC API error: status %d, body: %s", resp.StatusCode, string(body))
+		if limit := newKeyLimitError(providerCoinMarketCap, resp.StatusCode, body, apiErr); limit != nil {
+			return nil, limit
+		}
+		return nil, statusError("cmc quotes", resp.StatusCode, apiErr)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -411,9 +931,16 @@ func (dc *DataCollector) getCMCData(symbol string) (*CMCCurrency, error) {
 
 	// Check for API errors
 	if cmcResponse.Status.ErrorCode != 0 {
-		return nil, fmt.Errorf("CMC API error: %s", cmcResponse.Status.ErrorMessage)
+		apiErr := fmt.Errorf("CMC API error: %s", cmcResponse.Status.ErrorMessage)
+		if limit := newKeyLimitError(providerCoinMarketCap, resp.StatusCode, body, apiErr); limit != nil {
+			return nil, limit
+		}
+		return nil, apiErr
 	}
 
+	dc.quota.addCMCCredits(cmcResponse.Status.CreditCount)
+	dc.quota.markHealthy(providerCoinMarketCap)
+
 	// Get currency data
 	currency, exists := cmcResponse.Data[symbol]
 	if !exists {
@@ -424,16 +951,15 @@ func (dc *DataCollector) getCMCData(symbol string) (*CMCCurrency, error) {
 }
 
 // processMarketDataFromBinance processes market data when using Binance as fallback
-func (dc *DataCollector) processMarketDataFromBinance(symbol string, binanceData *BinanceTicker) (*MarketData, error) {
+func (dc *DataCollector) processMarketDataFromBinance(symbol, interval string, binanceData *BinanceTicker) (*MarketData, error) {
 	// Get Fear & Greed Index
-	fearGreedIndex, err := dc.getFearGreedIndex()
-	if err != nil {
-		logrus.Warn("Failed to get Fear & Greed Index: ", err)
-		fearGreedIndex = 50 // Default neutral value
+	fearGreedIndex, fearGreedSource, fearGreedErr := dc.getFearGreed()
+	if fearGreedErr != nil {
+		logrus.Warn("Failed to get Fear & Greed Index, using neutral default: ", fearGreedErr)
 	}
 
 	// Get kline data for technical analysis
-	klineData, err := dc.getBinanceKlines(symbol, "15m", 100)
+	klineData, klineSource, err := dc.getKlinesWithSource(symbol, interval, klineFetchLimit)
 	if err != nil {
 		logrus.Error("Failed to get kline data: ", err)
 		return nil, err
@@ -441,10 +967,15 @@ func (dc *DataCollector) processMarketDataFromBinance(symbol string, binanceData
 
 	// Create market data
 	marketData := &MarketData{
-		Symbol:         symbol,
-		FearGreedIndex: fearGreedIndex,
-		KlineData:      klineData,
-		Timestamp:      time.Now(),
+		Symbol:             symbol,
+		FearGreedIndex:     fearGreedIndex,
+		KlineData:          klineData,
+		Interval:           interval,
+		Timestamp:          time.Now(),
+		PriceSource:        "binance",
+		KlineSource:        klineSource,
+		FearGreedAvailable: fearGreedErr == nil,
+		FearGreedSource:    fearGreedSource,
 	}
 
 	// Parse Binance data
@@ -457,6 +988,13 @@ func (dc *DataCollector) processMarketDataFromBinance(symbol string, binanceData
 	if change, err := decimal.NewFromString(binanceData.PriceChangePercent); err == nil {
 		marketData.PriceChange24h = change
 	}
+	// closeTime is the end of the rolling 24h window, i.e. the last ticker update
+	if binanceData.CloseTime > 0 {
+		marketData.Timestamp = time.UnixMilli(binanceData.CloseTime)
+	}
+
+	dc.attachHigherTimeframe(marketData)
+	dc.attachDailyTrend(marketData)
 
 	return marketData, nil
 }
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Data-quality component weights; they sum to 1
+const (
+	qualityWeightCandles     = 0.35
+	qualityWeightKlineSource = 0.20
+	qualityWeightPriceSource = 0.15
+	qualityWeightEnrichment  = 0.15
+	qualityWeightFreshness   = 0.15
+)
+
+// Candle freshness: full marks up to two 15m candles old, nothing past eight
+const (
+	qualityFreshCandleAge = 30 * time.Minute
+	qualityStaleCandleAge = 2 * time.Hour
+)
+
+// dataQualityScore rates how much the market data behind a signal can be
+// trusted, from 0 to 1, and lists what pulled the score down. klineSources is
+// the configured KLINE_SOURCES order; the first entry is the primary source.
+func dataQualityScore(marketData *MarketData, klineSources []string, now time.Time) (decimal.Decimal, []string) {
+	var issues []string
+	score := 0.0
+
+	// Candle depth
+	candles := len(marketData.KlineData)
+	candleScore := float64(candles) / klineFetchLimit
+	if candleScore > 1 {
+		candleScore = 1
+	}
+	if candleScore < 1 {
+		issues = append(issues, fmt.Sprintf("%d/%d candles", candles, klineFetchLimit))
+	}
+	score += qualityWeightCandles * candleScore
+
+	// Kline provider: primary, fallback, or unknown (e.g. a static source)
+	klineScore := 0.5
+	switch {
+	case candles == 0:
+		klineScore = 0
+	case marketData.KlineSource == "":
+	case len(klineSources) > 0 && strings.EqualFold(strings.TrimSpace(klineSources[0]), marketData.KlineSource):
+		klineScore = 1
+	default:
+		klineScore = 0.6
+		issues = append(issues, fmt.Sprintf("fallback candles (%s)", marketData.KlineSource))
+	}
+	score += qualityWeightKlineSource * klineScore
+
+	// Quote provider
+	priceScore := 0.5
+	switch marketData.PriceSource {
+	case providerCoinMarketCap:
+		priceScore = 1
+	case "binance":
+		priceScore = 0.7
+		issues = append(issues, "fallback price (binance)")
+	}
+	score += qualityWeightPriceSource * priceScore
+
+	// Enrichment
+	enrichmentScore := 0.0
+	if marketData.CoinGeckoEnriched {
+		enrichmentScore += 0.5
+	} else {
+		issues = append(issues, "no CoinGecko data")
+	}
+	if marketData.FearGreedAvailable {
+		enrichmentScore += 0.5
+	} else {
+		issues = append(issues, "default Fear & Greed")
+	}
+	score += qualityWeightEnrichment * enrichmentScore
+
+	// Freshness of the latest candle
+	if candles > 0 {
+		if openTime, err := klineInt(marketData.KlineData[candles-1][0]); err == nil {
+			age := now.Sub(time.UnixMilli(openTime))
+			freshness := 1.0
+			if age > qualityFreshCandleAge {
+				freshness = 1 - float64(age-qualityFreshCandleAge)/float64(qualityStaleCandleAge-qualityFreshCandleAge)
+				if freshness < 0 {
+					freshness = 0
+				}
+				issues = append(issues, fmt.Sprintf("latest candle %s old", age.Round(time.Minute)))
+			}
+			score += qualityWeightFreshness * freshness
+		}
+	}
+
+	return decimal.NewFromFloat(score).Round(2), issues
+}
+
+// FilterByDataQuality keeps signals whose data-quality score is at least
+// minQuality. Signals without a score (e.g. external alerts) are kept.
+func FilterByDataQuality(signals []models.TradingSignal, minQuality decimal.Decimal) []models.TradingSignal {
+	filtered := make([]models.TradingSignal, 0, len(signals))
+	for _, signal := range signals {
+		if signal.DataQuality == nil || !signal.DataQuality.LessThan(minQuality) {
+			filtered = append(filtered, signal)
+		}
+	}
+	return filtered
+}
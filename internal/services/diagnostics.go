@@ -0,0 +1,79 @@
+package services
+
+import (
+	"time"
+)
+
+// Version is the bot release reported by the API and /diagnostics
+const Version = "1.0.0"
+
+// Diagnostics is a snapshot of the bot's internal state for support
+type Diagnostics struct {
+	Version            string               `json:"version"`
+	Uptime             time.Duration        `json:"uptime"`
+	Running            bool                 `json:"running"`
+	DatabaseMode       string               `json:"database_mode"` // direct, rest, memory or none
+	DatabaseReady      bool                 `json:"database_ready"`
+	Channels           map[string]bool      `json:"channels"`
+	WatchlistActive    int                  `json:"watchlist_active"`
+	WatchlistTotal     int                  `json:"watchlist_total"`
+	LastAnalysis       time.Time            `json:"last_analysis"`
+	LastCycle          *CycleDuration       `json:"last_cycle,omitempty"`
+	SourceLatencies    []SourceLatency      `json:"source_latencies"`
+	SignalsToday       int                  `json:"signals_today"`
+	MaxSignalsPerDay   int                  `json:"max_signals_per_day"`
+	KillSwitchEngaged  bool                 `json:"kill_switch_engaged"`
+	DrawdownPaused     bool                 `json:"drawdown_paused"`
+	DryRun             bool                 `json:"dry_run"`
+	ExhaustedProviders []string             `json:"exhausted_providers"` // Skipped until the quota recheck
+	SnoozedCoins       map[string]time.Time `json:"snoozed_coins"`
+}
+
+// GetDiagnostics assembles the state that is otherwise spread over /status,
+// the health endpoint and the logs
+func (bs *BotService) GetDiagnostics() *Diagnostics {
+	diagnostics := &Diagnostics{
+		Version:           Version,
+		Uptime:            time.Since(bs.startedAt),
+		Running:           bs.isRunning.Load(),
+		DatabaseMode:      "none",
+		DatabaseReady:     bs.databaseReady.Load(),
+		LastAnalysis:      bs.lastAnalysis(),
+		SourceLatencies:   bs.dataCollector.GetSourceLatencies(),
+		SignalsToday:      int(bs.totalSignalsToday.Load()),
+		MaxSignalsPerDay:  bs.cfg.MaxSignalsPerDay,
+		KillSwitchEngaged: bs.IsKillSwitchEngaged(),
+		DrawdownPaused:    bs.IsDrawdownPaused(),
+		DryRun:            bs.IsDryRun(),
+		SnoozedCoins:      bs.snoozedCoins(),
+		Channels: map[string]bool{
+			"telegram":         bs.notificationService.telegramBot != nil && bs.cfg.TelegramChatID != "",
+			"whatsapp":         bs.cfg.WhatsAppEnabled,
+			"discord":          bs.cfg.DiscordEnabled,
+			"priority_webhook": bs.cfg.PriorityWebhookURL != "",
+		},
+	}
+
+	if bs.db != nil {
+		diagnostics.DatabaseMode = bs.db.Mode()
+	}
+
+	for _, crypto := range bs.watchlist() {
+		diagnostics.WatchlistTotal++
+		if crypto.IsActive {
+			diagnostics.WatchlistActive++
+		}
+	}
+
+	if cycles := bs.cycleTimer.recentCycles(); len(cycles) > 0 {
+		diagnostics.LastCycle = &cycles[0]
+	}
+
+	for _, quota := range bs.marketDataSource.GetQuotaStatus() {
+		if quota.Exhausted {
+			diagnostics.ExhaustedProviders = append(diagnostics.ExhaustedProviders, quota.Provider)
+		}
+	}
+
+	return diagnostics
+}
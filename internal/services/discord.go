@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bytes"
+	"crypto-signal-bot/internal/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	discordTimeout          = 10 * time.Second
+	discordMaxEmbeds        = 10   // Embeds Discord accepts per webhook message
+	discordDescriptionLimit = 4096 // Maximum embed description length
+)
+
+// Embed colors by action
+const (
+	discordColorBuy  = 0x2ECC71
+	discordColorSell = 0xE74C3C
+	discordColorHold = 0xF1C40F
+)
+
+// discordWebhookMessage is the body of a Discord webhook post
+type discordWebhookMessage struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Footer      *discordEmbedFooter `json:"footer,omitempty"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// formatDiscordEmbed renders a signal as a Discord embed: colored by action,
+// with entry, stop loss, take profits and confidence as fields. Telegram's
+// message format doesn't carry over, so embeds are built from the signal.
+func (ns *NotificationService) formatDiscordEmbed(signal *models.TradingSignal) discordEmbed {
+	embed := discordEmbed{
+		Title:       fmt.Sprintf("%s %s/USDT", signal.Action, signal.Crypto.Symbol),
+		Description: truncateRunes(signal.Reasoning, discordDescriptionLimit),
+		Timestamp:   signal.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	switch signal.Action {
+	case "BUY":
+		embed.Color = discordColorBuy
+		embed.Title = "🟢 " + embed.Title
+	case "SELL":
+		embed.Color = discordColorSell
+		embed.Title = "🔴 " + embed.Title
+	default:
+		embed.Color = discordColorHold
+		embed.Title = "🟡 " + embed.Title
+	}
+
+	addField := func(name, value string) {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: name, Value: value, Inline: true})
+	}
+
+	if signal.EntryLow != nil && signal.EntryHigh != nil {
+		addField("Entry zone", fmt.Sprintf("$%s – $%s", ns.formatPrice(signal.Crypto, *signal.EntryLow), ns.formatPrice(signal.Crypto, *signal.EntryHigh)))
+	} else {
+		addField("Entry", "$"+ns.formatPrice(signal.Crypto, signal.EntryPrice))
+	}
+	addField("Confidence", fmt.Sprintf("%.1f%%", signal.ConfidenceScore.Mul(decimal.NewFromInt(100)).InexactFloat64()))
+	if label := signalPriorityLabel(signal.Priority); label != "" {
+		addField("Priority", label)
+	}
+
+	if signal.Action != "HOLD" {
+		if signal.StopLoss != nil {
+			addField("Stop Loss", "$"+ns.formatPrice(signal.Crypto, *signal.StopLoss))
+		}
+		if len(signal.TakeProfits) > 0 {
+			for _, target := range signal.TakeProfits {
+				addField(fmt.Sprintf("Take Profit %d", target.Level), fmt.Sprintf("$%s (%.0f%%)",
+					ns.formatPrice(signal.Crypto, target.Price), target.Allocation.Mul(decimal.NewFromInt(100)).InexactFloat64()))
+			}
+		} else {
+			if signal.TakeProfit1 != nil {
+				addField("Take Profit 1", "$"+ns.formatPrice(signal.Crypto, *signal.TakeProfit1))
+			}
+			if signal.TakeProfit2 != nil {
+				addField("Take Profit 2", "$"+ns.formatPrice(signal.Crypto, *signal.TakeProfit2))
+			}
+		}
+	}
+
+	if signal.DataQuality != nil {
+		addField("Data Quality", fmt.Sprintf("%.0f%%", signal.DataQuality.Mul(decimal.NewFromInt(100)).InexactFloat64()))
+	}
+	if signal.Source != "" && signal.Source != SignalSourceInternal {
+		addField("Source", signalSourceLabel(signal.Source))
+	}
+
+	footer := "DYOR - Not Financial Advice"
+	if signal.RefCode != "" {
+		footer = "Ref " + signal.RefCode + " · " + footer
+	}
+	embed.Footer = &discordEmbedFooter{Text: footer}
+	return embed
+}
+
+// sendDiscordMessage posts signals to DISCORD_WEBHOOK_URL as embeds, up to
+// discordMaxEmbeds per message. Each signal's delivery is recorded in
+// notification_logs; the first failure is returned.
+func (ns *NotificationService) sendDiscordMessage(signals ...*models.TradingSignal) error {
+	var firstErr error
+	for start := 0; start < len(signals); start += discordMaxEmbeds {
+		end := start + discordMaxEmbeds
+		if end > len(signals) {
+			end = len(signals)
+		}
+		batch := signals[start:end]
+
+		message := discordWebhookMessage{}
+		for _, signal := range batch {
+			message.Embeds = append(message.Embeds, ns.formatDiscordEmbed(signal))
+		}
+		err := ns.postDiscordWebhook(message)
+		for i, signal := range batch {
+			ns.logNotification("signal", "discord", message.Embeds[i].Title, signal, err)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if firstErr == nil {
+		logrus.Info("✅ Discord message sent with ", len(signals), " signals")
+	}
+	return firstErr
+}
+
+// postDiscordWebhook makes one webhook post
+func (ns *NotificationService) postDiscordWebhook(message discordWebhookMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: discordTimeout}
+	resp, err := client.Post(ns.cfg.DiscordWebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post Discord webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("discord webhook returned %s - %s", resp.Status, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// drawdownGuard pauses signal generation after the strategy's equity falls
+// MaxDrawdownPercent below its peak, until resumed manually or by timeout
+type drawdownGuard struct {
+	mu       sync.RWMutex
+	paused   bool
+	pausedAt time.Time
+	drawdown decimal.Decimal
+	resetAt  time.Time // Outcomes entered before this are ignored after a resume
+}
+
+// DrawdownStatus describes the equity curve and the guard state
+type DrawdownStatus struct {
+	Paused      bool            `json:"paused"`
+	PausedAt    *time.Time      `json:"paused_at,omitempty"`
+	DrawdownPct decimal.Decimal `json:"drawdown_pct"`
+	LimitPct    float64         `json:"limit_pct"`
+}
+
+// equityDrawdown compounds each outcome's PnL into an equity curve starting at
+// 1 and returns how far, in percent, the final equity sits below the peak
+func equityDrawdown(pnls []decimal.Decimal) decimal.Decimal {
+	one := decimal.NewFromInt(1)
+	hundred := decimal.NewFromInt(100)
+
+	equity := one
+	peak := one
+	for _, pnl := range pnls {
+		equity = equity.Mul(one.Add(pnl.Div(hundred)))
+		if equity.GreaterThan(peak) {
+			peak = equity
+		}
+	}
+
+	return peak.Sub(equity).Div(peak).Mul(hundred)
+}
+
+// currentDrawdown loads closed outcomes since the last resume and returns the drawdown
+func (bs *BotService) currentDrawdown() (decimal.Decimal, error) {
+	bs.drawdownGuard.mu.RLock()
+	since := bs.drawdownGuard.resetAt
+	bs.drawdownGuard.mu.RUnlock()
+
+	outcomes, err := bs.db.GetPerformanceOutcomes(since)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	pnls := make([]decimal.Decimal, 0, len(outcomes))
+	for _, perf := range outcomes {
+		if perf.PnLPercentage != nil {
+			pnls = append(pnls, *perf.PnLPercentage)
+		}
+	}
+	return equityDrawdown(pnls), nil
+}
+
+// checkDrawdownGuard reports whether signal generation should be skipped,
+// tripping the guard when the drawdown limit is exceeded and lifting it once
+// the auto-resume timeout has passed
+func (bs *BotService) checkDrawdownGuard() bool {
+	if bs.cfg.MaxDrawdownPercent <= 0 || bs.db == nil {
+		return false
+	}
+
+	bs.drawdownGuard.mu.RLock()
+	paused := bs.drawdownGuard.paused
+	pausedAt := bs.drawdownGuard.pausedAt
+	bs.drawdownGuard.mu.RUnlock()
+
+	if paused {
+		autoResume := time.Duration(bs.cfg.DrawdownAutoResumeHours) * time.Hour
+		if autoResume <= 0 || time.Since(pausedAt) < autoResume {
+			return true
+		}
+		if err := bs.ResumeFromDrawdown(); err != nil {
+			logrus.Warn("Failed to auto-resume drawdown pause: ", err)
+			return true
+		}
+		return false
+	}
+
+	drawdown, err := bs.currentDrawdown()
+	if err != nil {
+		// Don't halt trading on a read failure; the next cycle retries
+		logrus.Warn("Failed to compute drawdown: ", err)
+		return false
+	}
+	if drawdown.LessThan(decimal.NewFromFloat(bs.cfg.MaxDrawdownPercent)) {
+		return false
+	}
+
+	bs.drawdownGuard.mu.Lock()
+	bs.drawdownGuard.paused = true
+	bs.drawdownGuard.pausedAt = time.Now()
+	bs.drawdownGuard.drawdown = drawdown
+	bs.drawdownGuard.mu.Unlock()
+
+	logrus.Warnf("📉 Drawdown %.2f%% exceeds %.2f%%, pausing signal generation", drawdown.InexactFloat64(), bs.cfg.MaxDrawdownPercent)
+
+	message := fmt.Sprintf("📉 *DRAWDOWN LIMIT TERCAPAI*\n\nDrawdown dari puncak equity: *%.2f%%* (batas %.2f%%).\nPembuatan sinyal baru dijeda.",
+		drawdown.InexactFloat64(), bs.cfg.MaxDrawdownPercent)
+	if bs.cfg.DrawdownAutoResumeHours > 0 {
+		message += fmt.Sprintf("\n\nOtomatis lanjut dalam %d jam, atau gunakan /resume.", bs.cfg.DrawdownAutoResumeHours)
+	} else {
+		message += "\n\nGunakan /resume untuk melanjutkan."
+	}
+	bs.notificationService.SendSystemNotification("warning", message)
+
+	return true
+}
+
+// IsDrawdownPaused reports whether the drawdown guard has paused signal generation
+func (bs *BotService) IsDrawdownPaused() bool {
+	bs.drawdownGuard.mu.RLock()
+	defer bs.drawdownGuard.mu.RUnlock()
+
+	return bs.drawdownGuard.paused
+}
+
+// GetDrawdownStatus reports the current drawdown and whether the guard is tripped
+func (bs *BotService) GetDrawdownStatus() (*DrawdownStatus, error) {
+	if bs.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	drawdown, err := bs.currentDrawdown()
+	if err != nil {
+		return nil, err
+	}
+
+	bs.drawdownGuard.mu.RLock()
+	defer bs.drawdownGuard.mu.RUnlock()
+
+	status := &DrawdownStatus{
+		Paused:      bs.drawdownGuard.paused,
+		DrawdownPct: drawdown,
+		LimitPct:    bs.cfg.MaxDrawdownPercent,
+	}
+	if bs.drawdownGuard.paused {
+		pausedAt := bs.drawdownGuard.pausedAt
+		status.PausedAt = &pausedAt
+	}
+	return status, nil
+}
+
+// ResumeFromDrawdown lifts a drawdown pause. The equity peak restarts from
+// now so the losses that tripped the guard don't immediately trip it again.
+func (bs *BotService) ResumeFromDrawdown() error {
+	bs.drawdownGuard.mu.Lock()
+	if !bs.drawdownGuard.paused {
+		bs.drawdownGuard.mu.Unlock()
+		return fmt.Errorf("signal generation is not paused by the drawdown guard")
+	}
+	drawdown := bs.drawdownGuard.drawdown
+	bs.drawdownGuard.paused = false
+	bs.drawdownGuard.resetAt = time.Now()
+	bs.drawdownGuard.mu.Unlock()
+
+	logrus.Info("✅ Drawdown pause lifted")
+	bs.notificationService.SendSystemNotification("info",
+		fmt.Sprintf("✅ *Pembuatan sinyal dilanjutkan*\n\nDrawdown saat jeda: %.2f%%. Puncak equity dihitung ulang mulai sekarang.", drawdown.InexactFloat64()))
+	return nil
+}
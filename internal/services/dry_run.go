@@ -0,0 +1,98 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// dryRunMode holds whether signals are only logged. While on, generated
+// signals are neither stored nor sent, so strategy changes can be tried live.
+type dryRunMode struct {
+	mu       sync.RWMutex
+	enabled  bool
+	since    time.Time
+	withheld int // Signals withheld since dry run was turned on
+}
+
+// DryRunStatus reports the dry-run state
+type DryRunStatus struct {
+	Enabled         bool       `json:"enabled"`
+	Since           *time.Time `json:"since,omitempty"`
+	WithheldSignals int        `json:"withheld_signals"`
+}
+
+// set switches dry run and reports whether the state changed
+func (d *dryRunMode) set(enabled bool) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.enabled == enabled {
+		return false
+	}
+	d.enabled = enabled
+	d.since = time.Now()
+	d.withheld = 0
+	return true
+}
+
+// withhold reports whether a signal must be kept back, counting it if so
+func (d *dryRunMode) withhold() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.enabled {
+		d.withheld++
+	}
+	return d.enabled
+}
+
+func (d *dryRunMode) status() DryRunStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	status := DryRunStatus{Enabled: d.enabled, WithheldSignals: d.withheld}
+	if d.enabled {
+		since := d.since
+		status.Since = &since
+	}
+	return status
+}
+
+// IsDryRun reports whether signals are currently only logged
+func (bs *BotService) IsDryRun() bool {
+	return bs.dryRun.status().Enabled
+}
+
+// DryRunStatus returns the dry-run state
+func (bs *BotService) DryRunStatus() DryRunStatus {
+	return bs.dryRun.status()
+}
+
+// SetDryRun turns dry run on or off at runtime and announces the change
+func (bs *BotService) SetDryRun(enabled bool) DryRunStatus {
+	if !bs.dryRun.set(enabled) {
+		return bs.dryRun.status()
+	}
+
+	if enabled {
+		logrus.Warn("🧪 Dry run enabled: signals are logged but not stored or sent")
+		bs.notificationService.SendSystemNotification("warning", "🧪 *DRY RUN AKTIF*\n\nSinyal hanya dicatat di log, tidak disimpan dan tidak dikirim.\nGunakan /dryrun off untuk kembali normal.")
+	} else {
+		logrus.Info("✅ Dry run disabled")
+		bs.notificationService.SendSystemNotification("info", "✅ *Dry run dinonaktifkan*\n\nSinyal kembali disimpan dan dikirim.")
+	}
+	return bs.dryRun.status()
+}
+
+// dryRunNotice is appended to status and startup messages while dry run is on
+func (bs *BotService) dryRunNotice() string {
+	status := bs.dryRun.status()
+	if !status.Enabled {
+		return ""
+	}
+	return fmt.Sprintf("\n\n🧪 *DRY RUN AKTIF* sejak %s: sinyal tidak disimpan atau dikirim (%d ditahan). /dryrun off untuk menonaktifkan.",
+		status.Since.Format("15:04 02/01/2006"), status.WithheldSignals)
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+// entryZone returns the price band around the signal price in which the
+// position may be entered, per ENTRY_ZONE_MODE. ok is false when zones are off,
+// the action isn't tradable or the width can't be computed (e.g. no ATR yet).
+func (sg *SignalGenerator) entryZone(action string, price, atr decimal.Decimal) (low, high decimal.Decimal, ok bool) {
+	if action != "BUY" && action != "SELL" {
+		return decimal.Zero, decimal.Zero, false
+	}
+
+	var halfWidth decimal.Decimal
+	switch sg.cfg.EntryZoneMode {
+	case "percent":
+		halfWidth = price.Mul(decimal.NewFromFloat(sg.cfg.EntryZonePercent / 100))
+	case "atr":
+		halfWidth = atr.Mul(decimal.NewFromFloat(sg.cfg.EntryZoneATRMultiplier))
+	default:
+		return decimal.Zero, decimal.Zero, false
+	}
+
+	if !halfWidth.IsPositive() || !halfWidth.LessThan(price) {
+		return decimal.Zero, decimal.Zero, false
+	}
+	return price.Sub(halfWidth), price.Add(halfWidth), true
+}
+
+// EntryZoneTouched reports whether a signal's position counts as entered given
+// the price range traded since it was issued. Signals without a zone enter at
+// market; zoned ones need price to trade anywhere inside the zone.
+func EntryZoneTouched(signal *models.TradingSignal, highestPrice, lowestPrice decimal.Decimal) bool {
+	if signal.EntryLow == nil || signal.EntryHigh == nil {
+		return true
+	}
+	return !lowestPrice.GreaterThan(*signal.EntryHigh) && !highestPrice.LessThan(*signal.EntryLow)
+}
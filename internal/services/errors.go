@@ -0,0 +1,51 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// TransientError marks a failure that is expected to succeed on retry,
+// such as a network hiccup or a provider rate limit
+type TransientError struct {
+	Op  string
+	Err error
+}
+
+func (e *TransientError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient reports whether err (or anything it wraps) is worth retrying
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var transientErr *TransientError
+	if errors.As(err, &transientErr) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// isTransientStatus reports whether an HTTP status indicates a retryable failure
+func isTransientStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// statusError builds an API status error, marking it transient when retryable
+func statusError(op string, statusCode int, err error) error {
+	if isTransientStatus(statusCode) {
+		return &TransientError{Op: op, Err: err}
+	}
+	return err
+}
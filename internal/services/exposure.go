@@ -0,0 +1,105 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// CoinExposure counts the active signals on one coin per direction
+type CoinExposure struct {
+	Symbol string `json:"symbol"`
+	Buy    int    `json:"buy"`
+	Sell   int    `json:"sell"`
+	Net    int    `json:"net"` // Buy minus sell; positive leans long
+	Tilt   string `json:"tilt"`
+}
+
+// Exposure is the bot's open directional exposure across active signals
+type Exposure struct {
+	Coins       []CoinExposure `json:"coins"`
+	TotalActive int            `json:"total_active"`
+	TotalBuy    int            `json:"total_buy"`
+	TotalSell   int            `json:"total_sell"`
+	Net         int            `json:"net"`
+	Tilt        string         `json:"tilt"`
+}
+
+// exposureTilt names the direction a net count leans
+func exposureTilt(net int) string {
+	switch {
+	case net > 0:
+		return "long"
+	case net < 0:
+		return "short"
+	default:
+		return "neutral"
+	}
+}
+
+// GetExposure groups active signals by coin and action, with the net long/short
+// tilt per coin and overall. Coins with the most active signals come first.
+func (bs *BotService) GetExposure() (*Exposure, error) {
+	if bs.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	signals, err := bs.db.GetActiveSignals()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load active signals: %w", err)
+	}
+
+	symbols := make(map[uuid.UUID]string)
+	for _, crypto := range bs.watchlist() {
+		symbols[crypto.ID] = crypto.Symbol
+	}
+
+	exposure := &Exposure{}
+	byCoin := make(map[string]*CoinExposure)
+	for _, signal := range signals {
+		symbol, exists := symbols[signal.CryptoID]
+		if !exists {
+			symbol = "UNKNOWN" // Coin deleted or no longer watched
+		}
+
+		coin, exists := byCoin[symbol]
+		if !exists {
+			coin = &CoinExposure{Symbol: symbol}
+			byCoin[symbol] = coin
+		}
+
+		switch signal.Action {
+		case "BUY":
+			coin.Buy++
+			exposure.TotalBuy++
+		case "SELL":
+			coin.Sell++
+			exposure.TotalSell++
+		default:
+			continue
+		}
+		exposure.TotalActive++
+	}
+
+	for _, coin := range byCoin {
+		if coin.Buy+coin.Sell == 0 {
+			continue
+		}
+		coin.Net = coin.Buy - coin.Sell
+		coin.Tilt = exposureTilt(coin.Net)
+		exposure.Coins = append(exposure.Coins, *coin)
+	}
+	sort.Slice(exposure.Coins, func(i, j int) bool {
+		a, b := exposure.Coins[i], exposure.Coins[j]
+		if a.Buy+a.Sell != b.Buy+b.Sell {
+			return a.Buy+a.Sell > b.Buy+b.Sell
+		}
+		return a.Symbol < b.Symbol
+	})
+
+	exposure.Net = exposure.TotalBuy - exposure.TotalSell
+	exposure.Tilt = exposureTilt(exposure.Net)
+
+	return exposure, nil
+}
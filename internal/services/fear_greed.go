@@ -0,0 +1,137 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Fear & Greed sources, as listed in FEAR_GREED_SOURCES
+const (
+	fearGreedAlternativeMe = "alternative_me"
+	fearGreedCoinMarketCap = providerCoinMarketCap
+)
+
+// fearGreedNeutral is used when no source answers
+const fearGreedNeutral = 50
+
+// cmcFearGreedResponse is CMC's /v3/fear-and-greed/latest response
+type cmcFearGreedResponse struct {
+	Status struct {
+		ErrorCode    int    `json:"error_code"`
+		ErrorMessage string `json:"error_message"`
+		CreditCount  int    `json:"credit_count"`
+	} `json:"status"`
+	Data struct {
+		Value               int    `json:"value"`
+		ValueClassification string `json:"value_classification"`
+	} `json:"data"`
+}
+
+// fetchFearGreed tries the FEAR_GREED_SOURCES in order and returns the first
+// reading with its source. When all fail it returns the neutral default, an
+// empty source and the collected errors.
+func (dc *DataCollector) fetchFearGreed() (int, string, error) {
+	var failures []string
+	for _, source := range dc.cfg.FearGreedSources {
+		var value int
+		var err error
+		switch source {
+		case fearGreedAlternativeMe:
+			value, err = dc.getFearGreedIndex()
+		case fearGreedCoinMarketCap:
+			value, err = dc.getCMCFearGreed()
+		default:
+			err = fmt.Errorf("unknown source")
+		}
+		if err == nil {
+			return value, source, nil
+		}
+		logrus.Debug("Fear & Greed source ", source, " failed: ", err)
+		failures = append(failures, fmt.Sprintf("%s: %v", source, err))
+	}
+
+	if len(failures) == 0 {
+		return fearGreedNeutral, "", fmt.Errorf("no Fear & Greed sources configured")
+	}
+	return fearGreedNeutral, "", fmt.Errorf("all Fear & Greed sources failed: %s", strings.Join(failures, "; "))
+}
+
+// getCMCFearGreed reads CMC's Fear & Greed index, rotating keys like the quotes
+func (dc *DataCollector) getCMCFearGreed() (int, error) {
+	if dc.cfg.CoinMarketCapAPIKey == "" {
+		return 0, fmt.Errorf("CoinMarketCap API key not configured")
+	}
+	if !dc.quota.available(providerCoinMarketCap) {
+		return 0, &QuotaExhaustedError{Provider: providerCoinMarketCap, Err: fmt.Errorf("skipping until quota recheck")}
+	}
+
+	for {
+		key, index, ok := dc.cmcKeys.next()
+		if !ok {
+			return 0, dc.keysUnavailable(dc.cmcKeys, "cmc fear & greed")
+		}
+
+		value, err := dc.fetchCMCFearGreed(key)
+		var limit *keyLimitError
+		if errors.As(err, &limit) {
+			dc.cmcKeys.markLimited(index, limit)
+			continue
+		}
+		return value, err
+	}
+}
+
+// fetchCMCFearGreed requests the latest CMC Fear & Greed value with one API key
+func (dc *DataCollector) fetchCMCFearGreed(key string) (int, error) {
+	req, err := http.NewRequest("GET", "https://pro-api.coinmarketcap.com/v3/fear-and-greed/latest", nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-CMC_PRO_API_KEY", key)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := dc.doWithRetry("cmc fear & greed", req, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := fmt.Errorf("CMC fear & greed API error: status %d, body: %s", resp.StatusCode, string(body))
+		if limit := newKeyLimitError(providerCoinMarketCap, resp.StatusCode, body, apiErr); limit != nil {
+			return 0, limit
+		}
+		return 0, statusError("cmc fear & greed", resp.StatusCode, apiErr)
+	}
+
+	var fgi cmcFearGreedResponse
+	if err := json.Unmarshal(body, &fgi); err != nil {
+		return 0, fmt.Errorf("failed to parse CMC fear & greed response: %w", err)
+	}
+	if fgi.Status.ErrorCode != 0 {
+		apiErr := fmt.Errorf("CMC fear & greed API error: %s", fgi.Status.ErrorMessage)
+		if limit := newKeyLimitError(providerCoinMarketCap, resp.StatusCode, body, apiErr); limit != nil {
+			return 0, limit
+		}
+		return 0, apiErr
+	}
+
+	dc.quota.addCMCCredits(fgi.Status.CreditCount)
+	dc.quota.markHealthy(providerCoinMarketCap)
+
+	if fgi.Data.Value < 0 || fgi.Data.Value > 100 {
+		return 0, fmt.Errorf("CMC fear & greed value %d out of range", fgi.Data.Value)
+	}
+	return fgi.Data.Value, nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// FibSwing is the recent swing range Fibonacci levels are drawn on: the
+// highest pivot high and lowest pivot low within SWING_LOOKBACK candles
+type FibSwing struct {
+	High    decimal.Decimal
+	Low     decimal.Decimal
+	Uptrend bool // The low formed before the high
+}
+
+// calculateFibSwing finds the swing range from the pivots of the swing
+// detector; nil when there is no pivot high above a pivot low
+func calculateFibSwing(data []OHLCV, lookback, strength int) *FibSwing {
+	highs, lows := findSwingPivots(data, lookback, strength)
+	if len(highs) == 0 || len(lows) == 0 {
+		return nil
+	}
+
+	high, low := highs[0], lows[0]
+	for _, pivot := range highs[1:] {
+		if pivot.price.GreaterThan(high.price) {
+			high = pivot
+		}
+	}
+	for _, pivot := range lows[1:] {
+		if pivot.price.LessThan(low.price) {
+			low = pivot
+		}
+	}
+	if !high.price.GreaterThan(low.price) {
+		return nil
+	}
+	return &FibSwing{High: high.price, Low: low.price, Uptrend: low.index < high.index}
+}
+
+// extension projects ratio times the swing range from the far end of the
+// swing in the trade's direction: up from the low for BUY, down from the high for SELL
+func (s *FibSwing) extension(action string, ratio float64) decimal.Decimal {
+	distance := s.High.Sub(s.Low).Mul(decimal.NewFromFloat(ratio))
+	if action == "SELL" {
+		return s.High.Sub(distance)
+	}
+	return s.Low.Add(distance)
+}
+
+// retracement gives back ratio of the swing range against the trade: down
+// from the high for BUY, up from the low for SELL
+func (s *FibSwing) retracement(action string, ratio float64) decimal.Decimal {
+	distance := s.High.Sub(s.Low).Mul(decimal.NewFromFloat(ratio))
+	if action == "SELL" {
+		return s.Low.Add(distance)
+	}
+	return s.High.Sub(distance)
+}
+
+// describe renders the swing for reasoning text
+func (s *FibSwing) describe() string {
+	direction := "down"
+	if s.Uptrend {
+		direction = "up"
+	}
+	return fmt.Sprintf("low %s, high %s (%s)", s.Low.StringFixed(4), s.High.StringFixed(4), direction)
+}
+
+// fibTakeProfits moves the targets to the FIB_EXTENSION_LEVELS extensions
+// that lie beyond entry, in order. Targets past the last extension stay when
+// they are still further out; otherwise their allocation goes to the last
+// Fibonacci target so the ladder stays ordered. It also returns the levels
+// used, e.g. "1.272 = 125.4400".
+func (sg *SignalGenerator) fibTakeProfits(action string, price decimal.Decimal, swing *FibSwing, targets []models.TakeProfitTarget) ([]models.TakeProfitTarget, []string, bool) {
+	if swing == nil || len(targets) == 0 {
+		return nil, nil, false
+	}
+
+	beyond := func(level, reference decimal.Decimal) bool {
+		if action == "SELL" {
+			return level.LessThan(reference)
+		}
+		return level.GreaterThan(reference)
+	}
+
+	var prices []decimal.Decimal
+	var used []string
+	for _, ratio := range sg.cfg.FibExtensionLevels {
+		level := swing.extension(action, ratio)
+		if !beyond(level, price) {
+			continue
+		}
+		prices = append(prices, level)
+		used = append(used, fmt.Sprintf("%s = %s", strconv.FormatFloat(ratio, 'f', -1, 64), level.StringFixed(4)))
+		if len(prices) == len(targets) {
+			break
+		}
+	}
+	if len(prices) == 0 {
+		return nil, nil, false
+	}
+
+	placed := make([]models.TakeProfitTarget, 0, len(targets))
+	for i, target := range targets {
+		if i < len(prices) {
+			target.Price = prices[i]
+		} else if !beyond(target.Price, placed[len(placed)-1].Price) {
+			placed[len(placed)-1].Allocation = placed[len(placed)-1].Allocation.Add(target.Allocation)
+			continue
+		}
+		target.Level = len(placed) + 1
+		placed = append(placed, target)
+	}
+	return placed, used, true
+}
+
+// fibStopLoss places the stop SWING_BUFFER_PERCENT beyond the
+// FIB_STOP_RETRACEMENT retracement; skipped when that isn't on the losing side of entry
+func (sg *SignalGenerator) fibStopLoss(action string, price decimal.Decimal, swing *FibSwing) (stop, level decimal.Decimal, ok bool) {
+	if swing == nil {
+		return decimal.Zero, decimal.Zero, false
+	}
+	buffer := decimal.NewFromFloat(sg.cfg.SwingBufferPercent / 100)
+
+	level = swing.retracement(action, sg.cfg.FibStopRetracement)
+	if action == "SELL" {
+		stop = level.Mul(decimal.NewFromInt(1).Add(buffer))
+		return stop, level, stop.GreaterThan(price)
+	}
+	stop = level.Mul(decimal.NewFromInt(1).Sub(buffer))
+	return stop, level, stop.LessThan(price)
+}
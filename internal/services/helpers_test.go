@@ -0,0 +1,24 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/config"
+
+	"github.com/shopspring/decimal"
+)
+
+// testConfig returns the default configuration, unaffected by a local .env
+// since tests don't load it
+func testConfig() *config.Config {
+	cfg := config.Load()
+	cfg.StorageBackend = "memory"
+	return cfg
+}
+
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func decPtr(value string) *decimal.Decimal {
+	d := dec(value)
+	return &d
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// httpRetryMaxDelay caps one wait between attempts, Retry-After hints included
+const httpRetryMaxDelay = 30 * time.Second
+
+// doWithRetry sends a provider request, retrying network errors, 5xx and 429
+// responses up to HTTP_MAX_RETRIES times with jittered exponential backoff
+// from HTTP_RETRY_BASE_DELAY_MS. A 429's Retry-After hint replaces the
+// backoff. Other responses, such as 400 or 401, come back on the first try.
+// With rotatesKeys a 429 is returned at once, so the caller can mark the key
+// limited and move on to the next one. The last response or error is
+// returned for the caller to handle as before.
+func (dc *DataCollector) doWithRetry(op string, req *http.Request, rotatesKeys bool) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := dc.httpClient.Do(req)
+
+		retry := err != nil
+		if err == nil {
+			retry = resp.StatusCode >= http.StatusInternalServerError ||
+				(resp.StatusCode == http.StatusTooManyRequests && !rotatesKeys)
+		}
+		if !retry || attempt >= dc.cfg.HTTPMaxRetries {
+			return resp, err
+		}
+
+		wait := dc.retryBackoff(attempt)
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			if hint := retryAfter(resp); hint > 0 {
+				wait = hint
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		logrus.Warnf("%s failed (%s), retrying in %s (attempt %d/%d)", op, reason, wait.Round(time.Millisecond), attempt+1, dc.cfg.HTTPMaxRetries)
+		time.Sleep(wait)
+	}
+}
+
+// getWithRetry makes a GET request through doWithRetry
+func (dc *DataCollector) getWithRetry(op, url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return dc.doWithRetry(op, req, false)
+}
+
+// retryBackoff is the wait before retry number attempt+1: the base delay
+// doubled per attempt, randomized to between half and all of it so clients
+// don't retry in lockstep
+func (dc *DataCollector) retryBackoff(attempt int) time.Duration {
+	delay := time.Duration(dc.cfg.HTTPRetryBaseDelayMS) * time.Millisecond << attempt
+	if delay <= 0 || delay > httpRetryMaxDelay {
+		delay = httpRetryMaxDelay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryAfter reads a 429's Retry-After header, in seconds or as an HTTP date;
+// zero when absent
+func retryAfter(resp *http.Response) time.Duration {
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return 0
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		wait = time.Until(at)
+	}
+	if wait <= 0 {
+		return 0
+	}
+	if wait > httpRetryMaxDelay {
+		wait = httpRetryMaxDelay
+	}
+	return wait
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// killSwitchExitReason marks performance records closed by the kill switch
+const killSwitchExitReason = "killswitch"
+
+// killSwitch holds the emergency-stop state. While engaged, analysis is
+// halted and signal notifications are suppressed until it is re-armed.
+type killSwitch struct {
+	mu         sync.RWMutex
+	engaged    bool
+	engagedAt  time.Time
+	stopJobs   func()
+	resumeJobs func()
+}
+
+// KillSwitchResult summarizes what an engaged kill switch did
+type KillSwitchResult struct {
+	EngagedAt        time.Time `json:"engaged_at"`
+	CancelledSignals int       `json:"cancelled_signals"`
+	FailedSignals    int       `json:"failed_signals,omitempty"`
+}
+
+// SetSchedulerControls lets the kill switch stop and later resume scheduled
+// jobs; the scheduler depends on BotService, so it is wired in from main
+func (bs *BotService) SetSchedulerControls(stop, resume func()) {
+	bs.killSwitch.mu.Lock()
+	defer bs.killSwitch.mu.Unlock()
+
+	bs.killSwitch.stopJobs = stop
+	bs.killSwitch.resumeJobs = resume
+}
+
+// IsKillSwitchEngaged reports whether the emergency stop is active
+func (bs *BotService) IsKillSwitchEngaged() bool {
+	bs.killSwitch.mu.RLock()
+	defer bs.killSwitch.mu.RUnlock()
+
+	return bs.killSwitch.engaged
+}
+
+// EngageKillSwitch halts analysis, stops scheduled jobs, cancels every active
+// signal and suppresses signal notifications until RearmKillSwitch is called
+func (bs *BotService) EngageKillSwitch() (*KillSwitchResult, error) {
+	bs.killSwitch.mu.Lock()
+	if bs.killSwitch.engaged {
+		engagedAt := bs.killSwitch.engagedAt
+		bs.killSwitch.mu.Unlock()
+		return nil, fmt.Errorf("kill switch already engaged since %s", engagedAt.Format("15:04 02/01/2006"))
+	}
+	bs.killSwitch.engaged = true
+	bs.killSwitch.engagedAt = time.Now()
+	stopJobs := bs.killSwitch.stopJobs
+	result := &KillSwitchResult{EngagedAt: bs.killSwitch.engagedAt}
+	bs.killSwitch.mu.Unlock()
+
+	logrus.Warn("🛑 Kill switch engaged")
+	bs.RecordSystemLog("warning", "kill_switch", "Kill switch engaged", nil)
+	bs.isRunning.Store(false)
+
+	// Stopping cron waits for running jobs; the flag above already makes them bail out
+	if stopJobs != nil {
+		go stopJobs()
+	}
+
+	if bs.db != nil {
+		signals, err := bs.db.GetActiveSignals()
+		if err != nil {
+			logrus.Error("Kill switch could not load active signals: ", err)
+		}
+		for _, signal := range signals {
+			if err := bs.cancelSignal(signal, result.EngagedAt); err != nil {
+				logrus.Error("Kill switch failed to cancel signal ", signal.ID, ": ", err)
+				result.FailedSignals++
+				continue
+			}
+			result.CancelledSignals++
+		}
+	}
+
+	message := fmt.Sprintf("🛑 *KILL SWITCH AKTIF*\n\nAnalisis dan jadwal dihentikan.\n%d sinyal aktif dibatalkan.", result.CancelledSignals)
+	if result.FailedSignals > 0 {
+		message += fmt.Sprintf("\n⚠️ %d sinyal gagal dibatalkan.", result.FailedSignals)
+	}
+	message += "\n\nNotifikasi sinyal ditahan sampai di-re-arm dengan /rearm."
+	bs.notificationService.SendSystemNotification("warning", message)
+
+	return result, nil
+}
+
+// cancelSignal marks a signal cancelled and closes its performance record
+func (bs *BotService) cancelSignal(signal *models.TradingSignal, at time.Time) error {
+	if err := bs.db.UpdateSignalStatus(signal.ID, "cancelled"); err != nil {
+		return err
+	}
+
+	duration := int(at.Sub(signal.CreatedAt).Minutes())
+	perf := &models.SignalPerformance{
+		ID:              uuid.New(),
+		SignalID:        signal.ID,
+		EntryPrice:      signal.EntryPrice,
+		EntryTime:       signal.CreatedAt,
+		ExitTime:        &at,
+		Outcome:         "cancelled",
+		DurationMinutes: &duration,
+		ExitReason:      killSwitchExitReason,
+	}
+	if err := bs.db.CreatePerformanceRecord(perf); err != nil {
+		// The signal is already cancelled; a missing record only affects analytics
+		logrus.Warn("Failed to record kill switch exit for signal ", signal.ID, ": ", err)
+	}
+
+	return nil
+}
+
+// RearmKillSwitch clears the emergency stop and resumes the bot and its jobs
+func (bs *BotService) RearmKillSwitch() error {
+	bs.killSwitch.mu.Lock()
+	if !bs.killSwitch.engaged {
+		bs.killSwitch.mu.Unlock()
+		return fmt.Errorf("kill switch is not engaged")
+	}
+	bs.killSwitch.engaged = false
+	resumeJobs := bs.killSwitch.resumeJobs
+	bs.killSwitch.mu.Unlock()
+
+	if resumeJobs != nil {
+		resumeJobs()
+	}
+	bs.isRunning.Store(true)
+
+	logrus.Info("✅ Kill switch re-armed")
+	bs.notificationService.SendSystemNotification("info", "✅ *Kill switch di-re-arm*\n\nAnalisis dan notifikasi sinyal berjalan kembali.")
+	return nil
+}
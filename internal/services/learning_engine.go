@@ -4,6 +4,10 @@ import (
 	"crypto-signal-bot/internal/config"
 	"crypto-signal-bot/internal/database"
 	"crypto-signal-bot/internal/models"
+	"errors"
+	"math"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,8 +16,11 @@ import (
 )
 
 type LearningEngine struct {
-	db  *database.SupabaseClient
+	db  database.Store
 	cfg *config.Config
+
+	paramsMu sync.RWMutex
+	params   *LearnedParams // Result of the last optimization; nil uses the baseline
 }
 
 type FeatureVector struct {
@@ -39,13 +46,36 @@ type PerformanceMetrics struct {
 	ProfitableSignals int             `json:"profitable_signals"`
 	WinRate           decimal.Decimal `json:"win_rate"`
 	AvgPnL            decimal.Decimal `json:"avg_pnl"`
+	TotalPnL          decimal.Decimal `json:"total_pnl"`
 	BestPnL           decimal.Decimal `json:"best_pnl"`
 	WorstPnL          decimal.Decimal `json:"worst_pnl"`
 	AvgDuration       decimal.Decimal `json:"avg_duration"`
 	Accuracy          decimal.Decimal `json:"accuracy"`
+
+	Benchmark         *BenchmarkComparison `json:"benchmark,omitempty"`
+	TimeWeighted      *TimeWeightedMetrics `json:"time_weighted,omitempty"`
+}
+
+// TimeWeightedMetrics are performance metrics where each outcome is weighted
+// by 0.5^(age / half-life), so recent signals dominate
+type TimeWeightedMetrics struct {
+	HalfLifeDays     float64         `json:"half_life_days"`
+	Samples          int             `json:"samples"`
+	EffectiveSamples decimal.Decimal `json:"effective_samples"` // Sum of weights
+	WinRate          decimal.Decimal `json:"win_rate"`
+	AvgPnL           decimal.Decimal `json:"avg_pnl"`
+}
+
+// BenchmarkComparison compares strategy PnL to simply holding the signalled coins
+type BenchmarkComparison struct {
+	PeriodStart      time.Time                  `json:"period_start"`
+	BuyAndHoldReturn decimal.Decimal            `json:"buy_and_hold_return"` // Average across signalled coins
+	BTCReturn        decimal.Decimal            `json:"btc_return"`
+	CoinReturns      map[string]decimal.Decimal `json:"coin_returns"`
+	ExcessReturn     decimal.Decimal            `json:"excess_return"` // Strategy total PnL minus buy-and-hold
 }
 
-func NewLearningEngine(db *database.SupabaseClient, cfg *config.Config) *LearningEngine {
+func NewLearningEngine(db database.Store, cfg *config.Config) *LearningEngine {
 	return &LearningEngine{
 		db:  db,
 		cfg: cfg,
@@ -80,14 +110,20 @@ func (le *LearningEngine) ExtractFeatures(marketData *MarketData, indicators *Te
 	priceAboveSMA20 := marketData.Price.GreaterThan(indicators.SMA20)
 	emaCrossover := indicators.EMA12.GreaterThan(indicators.EMA26)
 	
-	rsiOversold := indicators.RSI.LessThan(decimal.NewFromFloat(le.cfg.RSIOversoldThreshold))
-	rsiOverbought := indicators.RSI.GreaterThan(decimal.NewFromFloat(le.cfg.RSIOverboughtThreshold))
-	macdBullish := indicators.MACDHistogram.GreaterThan(decimal.Zero)
+	params := le.Params()
+
+	// An unavailable indicator (too few candles) is zero and must not set a flag
+	rsiOversold := indicators.available(indicatorRSI) && indicators.RSI.LessThan(decimal.NewFromFloat(params.RSIOversold))
+	rsiOverbought := indicators.available(indicatorRSI) && indicators.RSI.GreaterThan(decimal.NewFromFloat(params.RSIOverbought))
+	macdBullish := indicators.available(indicatorMACD) && indicators.MACDHistogram.GreaterThan(decimal.Zero)
 	
 	// BB Squeeze detection (simplified)
-	bbRange := indicators.BBUpper.Sub(indicators.BBLower)
-	avgPrice := indicators.SMA20
-	bbSqueeze := bbRange.Div(avgPrice).LessThan(decimal.NewFromFloat(0.02)) // 2% range
+	bbSqueeze := false
+	if indicators.available(indicatorBollinger) && !indicators.SMA20.IsZero() {
+		bbRange := indicators.BBUpper.Sub(indicators.BBLower)
+		avgPrice := indicators.SMA20
+		bbSqueeze = bbRange.Div(avgPrice).LessThan(decimal.NewFromFloat(0.02)) // 2% range
+	}
 	
 	// High volume detection (simplified)
 	highVolume := marketData.Volume24h.GreaterThan(decimal.Zero) // TODO: Compare with average volume
@@ -150,6 +186,10 @@ func (le *LearningEngine) SaveLearningData(signal *models.TradingSignal, feature
 		"trend_direction":    features.TrendDirection,
 		"market_sentiment":   features.MarketSentiment,
 	}
+	// Indicators that agreed with the signal, for tuning their weights
+	if indicators, ok := signal.MarketConditions["agreeing_indicators"].([]string); ok {
+		featuresMap["indicators"] = indicators
+	}
 
 	learningData := &models.LearningData{
 		ID:                  uuid.New(),
@@ -163,10 +203,56 @@ func (le *LearningEngine) SaveLearningData(signal *models.TradingSignal, feature
 	return le.db.SaveLearningData(learningData)
 }
 
+// predictionAccuracy scores a stored prediction against the actual outcome as
+// 1 - (p - y)^2, where p is the predicted confidence and y is 1 when the
+// predicted outcome happened: confident hits score near 1, confident misses
+// near 0 and hedged predictions land in between
+func predictionAccuracy(predictedOutcome string, predictedConfidence decimal.Decimal, actualOutcome string) decimal.Decimal {
+	p := decimal.Min(decimal.Max(predictedConfidence, decimal.Zero), decimal.NewFromInt(1))
+	y := decimal.Zero
+	if predictedOutcome == actualOutcome {
+		y = decimal.NewFromInt(1)
+	}
+	diff := p.Sub(y)
+	return decimal.NewFromInt(1).Sub(diff.Mul(diff))
+}
+
+// UpdateLearningDataWithOutcome records a signal's real outcome and scores the
+// prediction made when it was generated. Signals without a learning record
+// (e.g. sent while learning was disabled) get a new outcome-only record so the
+// ground truth isn't lost.
 func (le *LearningEngine) UpdateLearningDataWithOutcome(signalID uuid.UUID, actualOutcome string, actualPnL decimal.Decimal, duration int) error {
-	// TODO: Implement update learning data with actual outcomes
-	// This would require additional database methods
-	logrus.Info("Learning data updated for signal: ", signalID, " outcome: ", actualOutcome)
+	record, err := le.db.GetLearningDataBySignal(signalID)
+	if errors.Is(err, database.ErrLearningDataNotFound) {
+		err = le.db.SaveLearningData(&models.LearningData{
+			ID:                    uuid.New(),
+			SignalID:              &signalID,
+			Features:              map[string]interface{}{},
+			ActualOutcome:         actualOutcome,
+			ActualPnLPercentage:   actualPnL,
+			ActualDurationMinutes: duration,
+			CreatedAt:             time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		logrus.Info("Outcome-only learning record saved for signal: ", signalID, " outcome: ", actualOutcome)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	accuracy := decimal.Zero
+	if record.PredictedOutcome != "" {
+		accuracy = predictionAccuracy(record.PredictedOutcome, record.PredictedConfidence, actualOutcome)
+	}
+
+	if err := le.db.UpdateLearningDataOutcome(signalID, actualOutcome, actualPnL, duration, accuracy); err != nil {
+		return err
+	}
+
+	logrus.Info("Learning data updated for signal: ", signalID, " outcome: ", actualOutcome, " prediction accuracy: ", accuracy.StringFixed(2))
 	return nil
 }
 
@@ -187,20 +273,31 @@ func (le *LearningEngine) AnalyzePatterns() (*PerformanceMetrics, error) {
 	totalSignals := 0
 	totalProfitable := 0
 	totalPnL := decimal.Zero
-	bestPnL := decimal.NewFromInt(-1000)
-	worstPnL := decimal.NewFromInt(1000)
+	cumulativePnL := decimal.Zero
+	bestPnL := decimal.Zero
+	worstPnL := decimal.Zero
+	hasPnL := false
 
 	for _, analytic := range analytics {
+		if analytic == nil {
+			continue
+		}
 		totalSignals += analytic.TotalSignals
 		totalProfitable += analytic.ProfitableSignals
 		totalPnL = totalPnL.Add(analytic.AvgPnLPercentage)
-		
-		if analytic.BestSignalPnL.GreaterThan(bestPnL) {
+		cumulativePnL = cumulativePnL.Add(analytic.AvgPnLPercentage.Mul(decimal.NewFromInt(int64(analytic.TotalSignals))))
+
+		// Only symbols with signals carry meaningful best/worst PnL
+		if analytic.TotalSignals == 0 {
+			continue
+		}
+		if !hasPnL || analytic.BestSignalPnL.GreaterThan(bestPnL) {
 			bestPnL = analytic.BestSignalPnL
 		}
-		if analytic.WorstSignalPnL.LessThan(worstPnL) {
+		if !hasPnL || analytic.WorstSignalPnL.LessThan(worstPnL) {
 			worstPnL = analytic.WorstSignalPnL
 		}
+		hasPnL = true
 	}
 
 	winRate := decimal.Zero
@@ -218,36 +315,169 @@ func (le *LearningEngine) AnalyzePatterns() (*PerformanceMetrics, error) {
 		ProfitableSignals: totalProfitable,
 		WinRate:           winRate,
 		AvgPnL:            avgPnL,
+		TotalPnL:          cumulativePnL,
 		BestPnL:           bestPnL,
 		WorstPnL:          worstPnL,
 		AvgDuration:       decimal.NewFromInt(60), // TODO: Calculate from actual data
 		Accuracy:          accuracy,
 	}
 
+	if le.cfg.LearningHalfLifeDays > 0 {
+		weighted, err := le.calculateTimeWeightedMetrics(time.Now())
+		if err != nil {
+			logrus.Warn("Failed to calculate time-weighted metrics: ", err)
+		} else {
+			metrics.TimeWeighted = weighted
+		}
+	}
+
 	logrus.Info("Pattern analysis completed - Win Rate: ", winRate.StringFixed(2), "%")
 	return metrics, nil
 }
 
-func (le *LearningEngine) OptimizeStrategy() error {
+// decayLookbackHalfLives bounds the outcome query; older records weigh under 0.1%
+const decayLookbackHalfLives = 10
+
+// calculateTimeWeightedMetrics aggregates closed outcomes with exponential time decay
+func (le *LearningEngine) calculateTimeWeightedMetrics(now time.Time) (*TimeWeightedMetrics, error) {
+	halfLife := le.cfg.LearningHalfLifeDays * 24 * float64(time.Hour)
+	since := now.Add(-time.Duration(halfLife * decayLookbackHalfLives))
+
+	outcomes, err := le.db.GetPerformanceOutcomes(since)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := &TimeWeightedMetrics{HalfLifeDays: le.cfg.LearningHalfLifeDays}
+
+	totalWeight := 0.0
+	winWeight := 0.0
+	pnlWeighted := 0.0
+	for _, outcome := range outcomes {
+		if outcome == nil {
+			continue
+		}
+
+		age := now.Sub(outcome.EntryTime)
+		if outcome.ExitTime != nil {
+			age = now.Sub(*outcome.ExitTime)
+		}
+		weight := math.Pow(0.5, float64(age)/halfLife)
+
+		totalWeight += weight
+		if outcome.Outcome == "profit" {
+			winWeight += weight
+		}
+		if outcome.PnLPercentage != nil {
+			pnlWeighted += weight * outcome.PnLPercentage.InexactFloat64()
+		}
+		metrics.Samples++
+	}
+
+	metrics.EffectiveSamples = decimal.NewFromFloat(totalWeight)
+	if totalWeight > 0 {
+		metrics.WinRate = decimal.NewFromFloat(winWeight / totalWeight * 100)
+		metrics.AvgPnL = decimal.NewFromFloat(pnlWeighted / totalWeight)
+	}
+
+	return metrics, nil
+}
+
+// ParameterChange records a tunable parameter adjusted by optimization
+type ParameterChange struct {
+	Parameter string  `json:"parameter"`
+	OldValue  float64 `json:"old_value"`
+	NewValue  float64 `json:"new_value"`
+}
+
+// OptimizationResult reports the outcome of a strategy optimization run
+type OptimizationResult struct {
+	Metrics *PerformanceMetrics `json:"metrics"`
+	Changes []ParameterChange   `json:"changes"`
+	RanAt   time.Time           `json:"ran_at"`
+}
+
+// tunableParameters snapshots the parameters optimization is allowed to adjust
+func (le *LearningEngine) tunableParameters() map[string]float64 {
+	params := le.Params()
+	parameters := map[string]float64{
+		"min_confidence_threshold": params.MinConfidence,
+		"rsi_oversold_threshold":   params.RSIOversold,
+		"rsi_overbought_threshold": params.RSIOverbought,
+		"stop_loss_percentage":     le.cfg.StopLossPercentage,
+		"take_profit_1_percentage": le.cfg.TakeProfit1Percentage,
+		"take_profit_2_percentage": le.cfg.TakeProfit2Percentage,
+	}
+	for name, weight := range params.Weights {
+		parameters["weight_"+name] = weight
+	}
+	return parameters
+}
+
+// OptimizeStrategy adjusts the confidence cutoff, RSI bounds and indicator
+// weights from recent performance and saves them to bot_settings, so they
+// survive a restart
+func (le *LearningEngine) OptimizeStrategy() (*OptimizationResult, error) {
 	logrus.Info("Optimizing trading strategy based on learning data...")
 
+	before := le.tunableParameters()
+
 	// Analyze current performance
 	metrics, err := le.AnalyzePatterns()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	outcomes, err := le.db.GetLearningOutcomes(time.Now().Add(-learningOutcomeWindow))
+	if err != nil {
+		logrus.Warn("Failed to load learning outcomes, indicator weights unchanged: ", err)
+	}
+
+	// Recent performance tracks the current market regime better when available
+	winRate, samples := metrics.WinRate.InexactFloat64(), metrics.TotalSignals
+	if weighted := metrics.TimeWeighted; weighted != nil && weighted.Samples >= learningMinSamples {
+		winRate, samples = weighted.WinRate.InexactFloat64(), weighted.Samples
 	}
 
-	// TODO: Implement strategy optimization logic
-	// This could include:
-	// 1. Adjusting confidence thresholds based on historical accuracy
-	// 2. Modifying technical indicator weights
-	// 3. Updating stop loss and take profit levels
-	// 4. Filtering out low-performing patterns
+	// TODO: stop loss and take profit levels are not tuned yet
+	result := &OptimizationResult{
+		Metrics: metrics,
+		Changes: []ParameterChange{},
+		RanAt:   time.Now(),
+	}
 
-	logrus.Info("Strategy optimization completed")
+	params := le.adjustParams(le.Params(), winRate, samples, outcomes)
+	params.UpdatedAt = result.RanAt
+	le.setParams(params)
+
+	after := le.tunableParameters()
+	for parameter, oldValue := range before {
+		if newValue := after[parameter]; newValue != oldValue {
+			result.Changes = append(result.Changes, ParameterChange{
+				Parameter: parameter,
+				OldValue:  oldValue,
+				NewValue:  newValue,
+			})
+		}
+	}
+	sort.Slice(result.Changes, func(i, j int) bool {
+		return result.Changes[i].Parameter < result.Changes[j].Parameter
+	})
+
+	if len(result.Changes) > 0 {
+		if err := le.saveParams(params); err != nil {
+			logrus.Error("Failed to save optimized strategy parameters: ", err)
+		}
+	}
+
+	logrus.Info("Strategy optimization completed with ", len(result.Changes), " parameter changes")
 	logrus.Info("Current performance - Win Rate: ", metrics.WinRate.StringFixed(2), "%, Avg PnL: ", metrics.AvgPnL.StringFixed(2), "%")
+	if metrics.TimeWeighted != nil {
+		logrus.Info("Recent performance (half-life ", metrics.TimeWeighted.HalfLifeDays, "d) - Win Rate: ",
+			metrics.TimeWeighted.WinRate.StringFixed(2), "%, Avg PnL: ", metrics.TimeWeighted.AvgPnL.StringFixed(2), "%")
+	}
 
-	return nil
+	return result, nil
 }
 
 func (le *LearningEngine) GetBestPerformingIndicators() (map[string]decimal.Decimal, error) {
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/database"
+	"crypto-signal-bot/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAnalyzePatternsBestWorstPnL(t *testing.T) {
+	tests := []struct {
+		name        string
+		pnls        []string // One closed BTC signal per PnL
+		wantSignals int
+		wantBest    string
+		wantWorst   string
+	}{
+		{name: "no analytics", wantBest: "0", wantWorst: "0"},
+		{name: "single loss is both best and worst", pnls: []string{"-3"}, wantSignals: 1, wantBest: "-3", wantWorst: "-3"},
+		{name: "single win is both best and worst", pnls: []string{"4"}, wantSignals: 1, wantBest: "4", wantWorst: "4"},
+		{name: "all losses keep the best below zero", pnls: []string{"-3", "-1.5"}, wantSignals: 2, wantBest: "-1.5", wantWorst: "-3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.LearningHalfLifeDays = 0
+			store := database.NewMemoryStore()
+			crypto := &models.Cryptocurrency{ID: uuid.New(), Symbol: "BTC"}
+			for _, pnl := range tt.pnls {
+				signal := &models.TradingSignal{ID: uuid.New(), CryptoID: crypto.ID, Crypto: crypto, Action: "BUY"}
+				if err := store.CreateSignal(signal, nil); err != nil {
+					t.Fatalf("CreateSignal: %v", err)
+				}
+				outcome := "profit"
+				if dec(pnl).IsNegative() {
+					outcome = "loss"
+				}
+				if err := store.CreatePerformanceRecord(&models.SignalPerformance{ID: uuid.New(), SignalID: signal.ID, Outcome: outcome, PnLPercentage: decPtr(pnl)}); err != nil {
+					t.Fatalf("CreatePerformanceRecord: %v", err)
+				}
+			}
+
+			metrics, err := NewLearningEngine(store, cfg).AnalyzePatterns()
+			if err != nil {
+				t.Fatalf("AnalyzePatterns: %v", err)
+			}
+			if metrics.TotalSignals != tt.wantSignals {
+				t.Errorf("total signals = %d, want %d", metrics.TotalSignals, tt.wantSignals)
+			}
+			if !metrics.BestPnL.Equal(dec(tt.wantBest)) {
+				t.Errorf("best PnL = %s, want %s", metrics.BestPnL, tt.wantBest)
+			}
+			if !metrics.WorstPnL.Equal(dec(tt.wantWorst)) {
+				t.Errorf("worst PnL = %s, want %s", metrics.WorstPnL, tt.wantWorst)
+			}
+		})
+	}
+}
+
+func TestOptimizedParamsWithoutStore(t *testing.T) {
+	cfg := testConfig()
+	le := NewLearningEngine(nil, cfg)
+
+	if err := le.LoadOptimizedParams(); err != nil {
+		t.Fatalf("LoadOptimizedParams: %v", err)
+	}
+	if got, want := le.Params().MinConfidence, baselineParams(cfg).MinConfidence; got != want {
+		t.Errorf("min confidence = %g, want the baseline %g", got, want)
+	}
+
+	params := le.Params()
+	params.MinConfidence += 0.05
+	if err := le.saveParams(params); err != nil {
+		t.Errorf("saveParams: %v", err)
+	}
+}
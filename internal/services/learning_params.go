@@ -0,0 +1,267 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/config"
+	"crypto-signal-bot/internal/database"
+	"crypto-signal-bot/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// learningParamsKey is the bot_settings row holding the optimized parameters
+const learningParamsKey = "learning_optimized_params"
+
+// Limits of what one optimization run may adjust
+const (
+	learningMinSamples          = 20   // Closed outcomes needed before the thresholds move
+	learningMinIndicatorSamples = 10   // Outcomes an indicator needs before its weight moves
+	learningLowWinRate          = 45.0 // Win rate (%) below which thresholds tighten
+	learningHighWinRate         = 60.0 // Win rate (%) above which they relax
+	learningConfidenceStep      = 0.02
+	learningMaxConfidenceShift  = 0.1 // Furthest the cutoff moves from MIN_CONFIDENCE_THRESHOLD
+	learningRSIStep             = 1.0
+	learningMaxRSIShift         = 10.0 // Furthest the RSI bounds move from the configured ones
+	learningMinWeightFactor     = 0.5  // A tuned weight is within these multiples of its baseline before rescaling
+	learningMaxWeightFactor     = 1.5
+	learningOutcomeWindow       = 30 * 24 * time.Hour // Outcomes considered for the weights
+)
+
+// baselineIndicatorWeights are the confidence weights of the strategy
+// indicator blocks before any optimization
+var baselineIndicatorWeights = map[string]float64{
+	indicatorRSI:       0.3,
+	indicatorMACD:      0.25,
+	indicatorBollinger: 0.2,
+	"fear_greed":       0.15,
+	indicatorTrend:     0.1,
+}
+
+// LearnedParams are the strategy thresholds and indicator weights adjusted by
+// optimization. Without saved ones the configured thresholds and the baseline
+// weights apply.
+type LearnedParams struct {
+	MinConfidence float64            `json:"min_confidence"`
+	RSIOversold   float64            `json:"rsi_oversold"`
+	RSIOverbought float64            `json:"rsi_overbought"`
+	Weights       map[string]float64 `json:"weights"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+func baselineParams(cfg *config.Config) LearnedParams {
+	params := LearnedParams{
+		MinConfidence: cfg.MinConfidenceThreshold,
+		RSIOversold:   cfg.RSIOversoldThreshold,
+		RSIOverbought: cfg.RSIOverboughtThreshold,
+	}
+	params.Weights = make(map[string]float64, len(baselineIndicatorWeights))
+	for name, weight := range baselineIndicatorWeights {
+		params.Weights[name] = weight
+	}
+	return params
+}
+
+func (p LearnedParams) clone() LearnedParams {
+	weights := make(map[string]float64, len(p.Weights))
+	for name, weight := range p.Weights {
+		weights[name] = weight
+	}
+	p.Weights = weights
+	return p
+}
+
+// bound keeps the thresholds within the optimization limits around the
+// baseline, so saved values follow a later change of the configured ones.
+// Missing weights take their baseline and all are scaled to sum to
+// maxConfluenceWeight.
+func (p *LearnedParams) bound(baseline LearnedParams) {
+	clamp := func(value, low, high float64) float64 {
+		return math.Max(low, math.Min(high, value))
+	}
+	round := func(value float64) float64 {
+		return math.Round(value*10000) / 10000
+	}
+
+	p.MinConfidence = round(clamp(p.MinConfidence, baseline.MinConfidence-learningMaxConfidenceShift, baseline.MinConfidence+learningMaxConfidenceShift))
+	p.MinConfidence = clamp(p.MinConfidence, 0, 1)
+	p.RSIOversold = round(clamp(p.RSIOversold, baseline.RSIOversold-learningMaxRSIShift, baseline.RSIOversold+learningMaxRSIShift))
+	p.RSIOverbought = round(clamp(p.RSIOverbought, baseline.RSIOverbought-learningMaxRSIShift, baseline.RSIOverbought+learningMaxRSIShift))
+	if p.RSIOversold >= p.RSIOverbought {
+		p.RSIOversold, p.RSIOverbought = baseline.RSIOversold, baseline.RSIOverbought
+	}
+
+	weights := make(map[string]float64, len(baseline.Weights))
+	total := 0.0
+	for name, base := range baseline.Weights {
+		weight, exists := p.Weights[name]
+		if !exists || !(weight > 0) {
+			weight = base
+		}
+		weights[name] = weight
+		total += weight
+	}
+	if math.Abs(total-maxConfluenceWeight) > 0.001 {
+		for name := range weights {
+			weights[name] = round(weights[name] / total * maxConfluenceWeight)
+		}
+	}
+	p.Weights = weights
+}
+
+// Params returns the parameters signal generation currently uses
+func (le *LearningEngine) Params() LearnedParams {
+	le.paramsMu.RLock()
+	defer le.paramsMu.RUnlock()
+
+	if le.params == nil {
+		return baselineParams(le.cfg)
+	}
+	return le.params.clone()
+}
+
+func (le *LearningEngine) setParams(params LearnedParams) {
+	le.paramsMu.Lock()
+	defer le.paramsMu.Unlock()
+
+	stored := params.clone()
+	le.params = &stored
+}
+
+// LoadOptimizedParams restores the parameters saved by the last optimization.
+// Without a store or a saved row the configured baseline stays in effect.
+func (le *LearningEngine) LoadOptimizedParams() error {
+	if le.db == nil {
+		logrus.Info("Database not available, using the configured strategy parameters")
+		return nil
+	}
+
+	setting, err := le.db.GetBotSetting(learningParamsKey)
+	if errors.Is(err, database.ErrBotSettingNotFound) {
+		logrus.Info("No optimized strategy parameters saved, using the configured baseline")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var params LearnedParams
+	if err := json.Unmarshal([]byte(setting.SettingValue), &params); err != nil {
+		return fmt.Errorf("invalid %s setting: %w", learningParamsKey, err)
+	}
+	params.bound(baselineParams(le.cfg))
+	le.setParams(params)
+
+	logrus.Infof("✅ Loaded optimized strategy parameters from %s: min confidence %.2f, RSI %.0f/%.0f",
+		params.UpdatedAt.Format("2006-01-02 15:04"), params.MinConfidence, params.RSIOversold, params.RSIOverbought)
+	return nil
+}
+
+// saveParams stores the optimized parameters in bot_settings. Without a store
+// they only last until the next restart.
+func (le *LearningEngine) saveParams(params LearnedParams) error {
+	if le.db == nil {
+		return nil
+	}
+
+	value, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	description := "Strategy thresholds and indicator weights adjusted by learning optimization"
+	return le.db.UpsertBotSetting(&models.BotSetting{
+		SettingKey:   learningParamsKey,
+		SettingValue: string(value),
+		Description:  &description,
+		DataType:     "json",
+	})
+}
+
+// adjustParams moves the thresholds one step from the current ones: stricter
+// after a poor win rate, looser after a good one. Indicator weights follow
+// each indicator's win rate relative to the overall one and stay as they are
+// without outcomes to compare.
+func (le *LearningEngine) adjustParams(current LearnedParams, winRate float64, samples int, outcomes []*models.LearningData) LearnedParams {
+	next := current.clone()
+	baseline := baselineParams(le.cfg)
+
+	if samples >= learningMinSamples {
+		switch {
+		case winRate < learningLowWinRate:
+			next.MinConfidence += learningConfidenceStep
+			next.RSIOversold -= learningRSIStep
+			next.RSIOverbought += learningRSIStep
+		case winRate > learningHighWinRate:
+			next.MinConfidence -= learningConfidenceStep
+			next.RSIOversold += learningRSIStep
+			next.RSIOverbought -= learningRSIStep
+		}
+	}
+
+	wins, totals := make(map[string]int), make(map[string]int)
+	overallWins, overallTotal := 0, 0
+	for _, record := range outcomes {
+		indicators := featureIndicators(record.Features)
+		if len(indicators) == 0 {
+			continue
+		}
+		won := record.ActualOutcome == "profit"
+		overallTotal++
+		if won {
+			overallWins++
+		}
+		for _, name := range indicators {
+			totals[name]++
+			if won {
+				wins[name]++
+			}
+		}
+	}
+
+	// Weights are rebuilt from the baseline on each run; indicators with too
+	// few outcomes keep their baseline share
+	if overallWins > 0 {
+		overallRate := float64(overallWins) / float64(overallTotal)
+		for name, base := range baseline.Weights {
+			factor := 1.0
+			if totals[name] >= learningMinIndicatorSamples {
+				factor = float64(wins[name]) / float64(totals[name]) / overallRate
+			}
+			next.Weights[name] = base * math.Max(learningMinWeightFactor, math.Min(learningMaxWeightFactor, factor))
+		}
+	}
+
+	next.bound(baseline)
+	return next
+}
+
+// featureIndicators reads the indicators that agreed with a signal from its
+// learning features; stored JSON decodes them as []interface{}
+func featureIndicators(features map[string]interface{}) []string {
+	switch value := features["indicators"].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		names := make([]string, 0, len(value))
+		for _, item := range value {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// learnedParams returns the parameters the generator works with: the
+// learning engine's current ones, or the configured baseline without it
+func (sg *SignalGenerator) learnedParams() LearnedParams {
+	if sg.learning == nil {
+		return baselineParams(sg.cfg)
+	}
+	return sg.learning.Params()
+}
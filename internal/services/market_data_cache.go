@@ -0,0 +1,121 @@
+package services
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fearGreedCacheTTL is how long a Fear & Greed reading is reused; the index
+// is only published once a day
+const fearGreedCacheTTL = time.Hour
+
+type marketDataCacheEntry struct {
+	data    *MarketData
+	expires time.Time
+}
+
+type fearGreedCacheEntry struct {
+	value   int
+	source  string
+	expires time.Time
+}
+
+// GetMarketData returns a coin's market data for the kline interval, reusing
+// a fetch from the last MARKET_DATA_CACHE_SECONDS
+func (dc *DataCollector) GetMarketData(symbol, interval string) (*MarketData, error) {
+	key := symbol + "/" + interval
+	if data, ok := dc.cachedMarketData(key); ok {
+		logrus.Debug("Using cached market data for: ", symbol)
+		return data, nil
+	}
+
+	data, err := dc.fetchMarketData(symbol, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(dc.cfg.MarketDataCacheSeconds) * time.Second
+	if ttl > 0 {
+		dc.cacheMu.Lock()
+		dc.marketCache[key] = marketDataCacheEntry{data: copyMarketData(data), expires: time.Now().Add(ttl)}
+		dc.cacheMu.Unlock()
+	}
+	return data, nil
+}
+
+// cachedMarketData returns a deep copy of a fresh cache entry, so callers may
+// adjust fields, klines included, without touching the cache
+func (dc *DataCollector) cachedMarketData(key string) (*MarketData, bool) {
+	dc.cacheMu.RLock()
+	defer dc.cacheMu.RUnlock()
+
+	entry, exists := dc.marketCache[key]
+	if !exists || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return copyMarketData(entry.data), true
+}
+
+// copyMarketData copies market data along with its klines, listing time and
+// source agreement, which would otherwise be shared with the original
+func copyMarketData(data *MarketData) *MarketData {
+	copied := *data
+	copied.KlineData = copyKlines(data.KlineData)
+	copied.HTFKlineData = copyKlines(data.HTFKlineData)
+	copied.DailyKlineData = copyKlines(data.DailyKlineData)
+	if data.ListedAt != nil {
+		listedAt := *data.ListedAt
+		copied.ListedAt = &listedAt
+	}
+	if data.SourceAgreement != nil {
+		agreement := *data.SourceAgreement
+		agreement.Sources = append([]string(nil), data.SourceAgreement.Sources...)
+		copied.SourceAgreement = &agreement
+	}
+	return &copied
+}
+
+// copyKlines copies each kline row; the row values themselves are immutable
+// strings and numbers
+func copyKlines(klines [][]interface{}) [][]interface{} {
+	if klines == nil {
+		return nil
+	}
+	copied := make([][]interface{}, len(klines))
+	for i, kline := range klines {
+		copied[i] = append([]interface{}(nil), kline...)
+	}
+	return copied
+}
+
+// getFearGreed returns the Fear & Greed reading of the last hour, or asks
+// the sources for a new one. Failures aren't cached.
+func (dc *DataCollector) getFearGreed() (int, string, error) {
+	dc.cacheMu.RLock()
+	cached := dc.fearGreedCache
+	dc.cacheMu.RUnlock()
+	if cached != nil && time.Now().Before(cached.expires) {
+		return cached.value, cached.source, nil
+	}
+
+	value, source, err := dc.fetchFearGreed()
+	if err != nil {
+		return value, source, err
+	}
+
+	dc.cacheMu.Lock()
+	dc.fearGreedCache = &fearGreedCacheEntry{value: value, source: source, expires: time.Now().Add(fearGreedCacheTTL)}
+	dc.cacheMu.Unlock()
+	return value, source, nil
+}
+
+// ClearCache drops cached market data and the Fear & Greed reading so the
+// next fetches go to the providers
+func (dc *DataCollector) ClearCache() {
+	dc.cacheMu.Lock()
+	defer dc.cacheMu.Unlock()
+
+	dc.marketCache = make(map[string]marketDataCacheEntry)
+	dc.fearGreedCache = nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MarketDataSource is the seam between the analysis pipeline and market data
+// providers. DataCollector is the live implementation; StaticMarketDataSource
+// serves fixed data so signal generation can run deterministically.
+type MarketDataSource interface {
+	GetMarketData(symbol, interval string) (*MarketData, error)
+	GetListingTime(marketData *MarketData) (time.Time, error)
+	GetCurrentPrice(symbol string) (decimal.Decimal, error)
+	GetKlinesSince(symbol, interval string, since time.Time) ([][]interface{}, error)
+	GetPriceChangeSince(symbol string, since time.Time) (decimal.Decimal, error)
+	GetPricePrecision(symbol string) (int, error)
+	GetQuotaStatus() []ProviderQuota
+}
+
+// Compile-time check that DataCollector satisfies MarketDataSource
+var _ MarketDataSource = (*DataCollector)(nil)
+
+// StaticMarketDataSource returns preset market data per symbol without any network calls
+type StaticMarketDataSource struct {
+	mu   sync.RWMutex
+	data map[string]*MarketData
+}
+
+func NewStaticMarketDataSource() *StaticMarketDataSource {
+	return &StaticMarketDataSource{
+		data: make(map[string]*MarketData),
+	}
+}
+
+// Set replaces the market data returned for a symbol
+func (s *StaticMarketDataSource) Set(symbol string, marketData *MarketData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[symbol] = marketData
+}
+
+// GetMarketData returns the preset data whatever the interval, tagged with it
+func (s *StaticMarketDataSource) GetMarketData(symbol, interval string) (*MarketData, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	marketData, exists := s.data[symbol]
+	if !exists {
+		return nil, fmt.Errorf("no market data set for %s", symbol)
+	}
+
+	copied := *marketData
+	copied.Interval = interval
+	return &copied, nil
+}
+
+// GetListingTime only knows listing dates carried on the market data itself
+func (s *StaticMarketDataSource) GetListingTime(marketData *MarketData) (time.Time, error) {
+	if marketData.ListedAt == nil {
+		return time.Time{}, fmt.Errorf("listing time unknown for %s", marketData.Symbol)
+	}
+	return *marketData.ListedAt, nil
+}
+
+func (s *StaticMarketDataSource) GetCurrentPrice(symbol string) (decimal.Decimal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	marketData, exists := s.data[symbol]
+	if !exists {
+		return decimal.Zero, fmt.Errorf("no market data set for %s", symbol)
+	}
+	return marketData.Price, nil
+}
+
+// GetKlinesSince returns the preset klines opened at or after since
+func (s *StaticMarketDataSource) GetKlinesSince(symbol, interval string, since time.Time) ([][]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	marketData, exists := s.data[symbol]
+	if !exists {
+		return nil, fmt.Errorf("no market data set for %s", symbol)
+	}
+
+	var klines [][]interface{}
+	for _, kline := range marketData.KlineData {
+		if len(kline) == 0 {
+			continue
+		}
+		if openedAt, err := klineInt(kline[0]); err == nil && openedAt >= since.UnixMilli() {
+			klines = append(klines, kline)
+		}
+	}
+	return klines, nil
+}
+
+// GetPriceChangeSince measures the change over the preset klines opened at or
+// after since
+func (s *StaticMarketDataSource) GetPriceChangeSince(symbol string, since time.Time) (decimal.Decimal, error) {
+	klines, err := s.GetKlinesSince(symbol, "", since)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return klinesPriceChange(symbol, since, klines)
+}
+
+// GetPricePrecision is unknown for static data, so prices keep the default formatting
+func (s *StaticMarketDataSource) GetPricePrecision(symbol string) (int, error) {
+	return 0, fmt.Errorf("price precision unknown for %s", symbol)
+}
+
+// GetQuotaStatus is empty since static data never calls a provider
+func (s *StaticMarketDataSource) GetQuotaStatus() []ProviderQuota {
+	return nil
+}
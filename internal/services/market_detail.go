@@ -0,0 +1,161 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/database"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// marketDetailCacheTTL is how long a coin's market detail is reused, so
+// repeated dashboard refreshes don't hit the providers again
+const marketDetailCacheTTL = 60 * time.Second
+
+// MarketDetail is a watched coin's current quote, sentiment and indicators
+type MarketDetail struct {
+	Symbol          string            `json:"symbol"`
+	Price           decimal.Decimal   `json:"price"`
+	Volume24h       decimal.Decimal   `json:"volume_24h"`
+	MarketCap       decimal.Decimal   `json:"market_cap"`
+	PriceChange1h   decimal.Decimal   `json:"price_change_1h"`
+	PriceChange24h  decimal.Decimal   `json:"price_change_24h"`
+	PriceChange7d   decimal.Decimal   `json:"price_change_7d"`
+	FearGreedIndex  *int              `json:"fear_greed_index"` // nil when no source answered
+	FearGreedSource string            `json:"fear_greed_source,omitempty"`
+	PriceSource     string            `json:"price_source"`
+	Interval        string            `json:"interval"`
+	Timestamp       time.Time         `json:"timestamp"` // When the provider last updated the quote
+	FetchedAt       time.Time         `json:"fetched_at"`
+	Indicators      *MarketIndicators `json:"indicators,omitempty"`
+	IndicatorsError string            `json:"indicators_error,omitempty"` // Why Indicators is missing
+}
+
+// MarketIndicators are the technical indicators of a coin's latest candles.
+// Values left uncomputed for lack of candles are nil and listed in Unavailable.
+type MarketIndicators struct {
+	RSI           *decimal.Decimal `json:"rsi"`
+	MACDLine      *decimal.Decimal `json:"macd_line"`
+	MACDSignal    *decimal.Decimal `json:"macd_signal"`
+	MACDHistogram *decimal.Decimal `json:"macd_histogram"`
+	BBUpper       *decimal.Decimal `json:"bb_upper"`
+	BBMiddle      *decimal.Decimal `json:"bb_middle"`
+	BBLower       *decimal.Decimal `json:"bb_lower"`
+	SMA20         *decimal.Decimal `json:"sma20"`
+	EMA12         *decimal.Decimal `json:"ema12"`
+	EMA26         *decimal.Decimal `json:"ema26"`
+	StochK        *decimal.Decimal `json:"stoch_k"`
+	StochD        *decimal.Decimal `json:"stoch_d"`
+	WilliamsR     *decimal.Decimal `json:"williams_r"`
+	ATR           *decimal.Decimal `json:"atr"`
+	BaseTrend     string           `json:"base_trend,omitempty"`
+	HTFTrend      string           `json:"htf_trend,omitempty"`
+	DailyTrend    string           `json:"daily_trend,omitempty"`
+	Unavailable   map[string]int   `json:"unavailable,omitempty"` // Indicator name to the candles it needs
+}
+
+// marketDetailCache holds recently fetched market details keyed by symbol
+type marketDetailCache struct {
+	mu      sync.Mutex
+	entries map[string]marketDetailCacheEntry
+}
+
+type marketDetailCacheEntry struct {
+	detail  *MarketDetail
+	expires time.Time
+}
+
+func (c *marketDetailCache) get(symbol string) (*MarketDetail, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[symbol]
+	if !exists || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.detail, true
+}
+
+func (c *marketDetailCache) put(symbol string, detail *MarketDetail) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]marketDetailCacheEntry)
+	}
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[symbol] = marketDetailCacheEntry{detail: detail, expires: now.Add(marketDetailCacheTTL)}
+}
+
+// GetMarketDetail returns the market data and indicators of a watched coin,
+// reusing a fetch from the last minute. database.ErrCryptocurrencyNotFound
+// for coins that aren't watched.
+func (bs *BotService) GetMarketDetail(symbol string) (*MarketDetail, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if !bs.isWatched(symbol) {
+		return nil, database.ErrCryptocurrencyNotFound
+	}
+	if detail, ok := bs.marketDetails.get(symbol); ok {
+		return detail, nil
+	}
+
+	marketData, err := bs.marketDataSource.GetMarketData(symbol, bs.cfg.KlineInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &MarketDetail{
+		Symbol:          symbol,
+		Price:           marketData.Price,
+		Volume24h:       marketData.Volume24h,
+		MarketCap:       marketData.MarketCap,
+		PriceChange1h:   marketData.PriceChange1h,
+		PriceChange24h:  marketData.PriceChange24h,
+		PriceChange7d:   marketData.PriceChange7d,
+		FearGreedSource: marketData.FearGreedSource,
+		PriceSource:     marketData.PriceSource,
+		Interval:        marketData.Interval,
+		Timestamp:       marketData.Timestamp,
+		FetchedAt:       time.Now(),
+	}
+	if marketData.FearGreedAvailable {
+		fearGreed := marketData.FearGreedIndex
+		detail.FearGreedIndex = &fearGreed
+	}
+
+	// The quote is still useful when candles are too short for indicators
+	indicators, err := bs.technicalAnalyzer.AnalyzeMarketData(marketData)
+	if err != nil {
+		detail.IndicatorsError = err.Error()
+	} else {
+		detail.Indicators = &MarketIndicators{
+			RSI:           indicators.availableValue(indicatorRSI, &indicators.RSI),
+			MACDLine:      indicators.availableValue(indicatorMACD, &indicators.MACDLine),
+			MACDSignal:    indicators.availableValue(indicatorMACD, &indicators.MACDSignal),
+			MACDHistogram: indicators.availableValue(indicatorMACD, &indicators.MACDHistogram),
+			BBUpper:       indicators.availableValue(indicatorBollinger, &indicators.BBUpper),
+			BBMiddle:      indicators.availableValue(indicatorBollinger, &indicators.BBMiddle),
+			BBLower:       indicators.availableValue(indicatorBollinger, &indicators.BBLower),
+			SMA20:         indicators.availableValue(indicatorTrend, &indicators.SMA20),
+			EMA12:         indicators.availableValue(indicatorTrend, &indicators.EMA12),
+			EMA26:         indicators.availableValue(indicatorTrend, &indicators.EMA26),
+			StochK:        indicators.availableValue(indicatorStochastic, &indicators.StochK),
+			StochD:        indicators.availableValue(indicatorStochastic, &indicators.StochD),
+			WilliamsR:     indicators.availableValue(indicatorWilliams, &indicators.Williams),
+			ATR:           indicators.availableValue(indicatorATR, &indicators.ATR),
+			BaseTrend:     indicators.BaseTrend,
+			HTFTrend:      indicators.HTFTrend,
+			DailyTrend:    indicators.DailyTrend,
+			Unavailable:   indicators.Unavailable,
+		}
+	}
+
+	bs.marketDetails.put(symbol, detail)
+	return detail, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxMarketOverviewSymbols caps how many coins one overview request may fetch
+const MaxMarketOverviewSymbols = 20
+
+// marketOverviewWorkers bounds parallel fetches so a full grid doesn't trip
+// provider rate limits
+const marketOverviewWorkers = 5
+
+// ErrInvalidMarketRequest wraps validation failures of a market overview request
+var ErrInvalidMarketRequest = errors.New("invalid market request")
+
+var validMarketSymbol = regexp.MustCompile(`^[A-Z0-9]{1,10}$`)
+
+// MarketOverview is one coin's current price, change and key indicators.
+// Error is set instead of the figures when the coin could not be fetched.
+type MarketOverview struct {
+	Symbol         string           `json:"symbol"`
+	Price          *decimal.Decimal `json:"price,omitempty"`
+	PriceChange1h  *decimal.Decimal `json:"price_change_1h,omitempty"`
+	PriceChange24h *decimal.Decimal `json:"price_change_24h,omitempty"`
+	Volume24h      *decimal.Decimal `json:"volume_24h,omitempty"`
+	RSI            *decimal.Decimal `json:"rsi,omitempty"`
+	MACDHistogram  *decimal.Decimal `json:"macd_histogram,omitempty"`
+	BBUpper        *decimal.Decimal `json:"bb_upper,omitempty"`
+	BBLower        *decimal.Decimal `json:"bb_lower,omitempty"`
+	SMA20          *decimal.Decimal `json:"sma20,omitempty"`
+	Interval       string           `json:"interval,omitempty"`
+	Timestamp      *time.Time       `json:"timestamp,omitempty"`
+	Error          string           `json:"error,omitempty"`
+}
+
+// ParseMarketSymbols splits a comma-separated symbol list, upper-casing,
+// de-duplicating and validating each entry against the cap
+func ParseMarketSymbols(raw string) ([]string, error) {
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		symbol := strings.ToUpper(strings.TrimSpace(part))
+		if symbol == "" {
+			continue
+		}
+		if !validMarketSymbol.MatchString(symbol) {
+			return nil, fmt.Errorf("%w: invalid symbol %q", ErrInvalidMarketRequest, symbol)
+		}
+		if !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("%w: symbols is required", ErrInvalidMarketRequest)
+	}
+	if len(symbols) > MaxMarketOverviewSymbols {
+		return nil, fmt.Errorf("%w: at most %d symbols per request, got %d", ErrInvalidMarketRequest, MaxMarketOverviewSymbols, len(symbols))
+	}
+	return symbols, nil
+}
+
+// GetMarketOverview fetches market data and indicators for several coins in
+// parallel, in the order requested. A coin that fails carries its error
+// rather than failing the whole overview.
+func (bs *BotService) GetMarketOverview(symbols []string) []MarketOverview {
+	overviews := make([]MarketOverview, len(symbols))
+	slots := make(chan struct{}, marketOverviewWorkers)
+
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		go func(i int, symbol string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			overviews[i] = bs.marketOverview(symbol)
+		}(i, symbol)
+	}
+	wg.Wait()
+
+	return overviews
+}
+
+func (bs *BotService) marketOverview(symbol string) MarketOverview {
+	overview := MarketOverview{Symbol: symbol}
+
+	marketData, err := bs.marketDataSource.GetMarketData(symbol, bs.cfg.KlineInterval)
+	if err != nil {
+		overview.Error = err.Error()
+		return overview
+	}
+
+	overview.Price = &marketData.Price
+	overview.PriceChange1h = &marketData.PriceChange1h
+	overview.PriceChange24h = &marketData.PriceChange24h
+	overview.Volume24h = &marketData.Volume24h
+	overview.Interval = marketData.Interval
+	overview.Timestamp = &marketData.Timestamp
+
+	// The quote is still useful when candles are too short for indicators
+	indicators, err := bs.technicalAnalyzer.AnalyzeMarketData(marketData)
+	if err != nil {
+		overview.Error = "indicators unavailable: " + err.Error()
+		return overview
+	}
+
+	overview.RSI = indicators.availableValue(indicatorRSI, &indicators.RSI)
+	overview.MACDHistogram = indicators.availableValue(indicatorMACD, &indicators.MACDHistogram)
+	overview.BBUpper = indicators.availableValue(indicatorBollinger, &indicators.BBUpper)
+	overview.BBLower = indicators.availableValue(indicatorBollinger, &indicators.BBLower)
+	overview.SMA20 = indicators.availableValue(indicatorTrend, &indicators.SMA20)
+
+	return overview
+}
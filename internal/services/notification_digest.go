@@ -0,0 +1,149 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// signalDigest holds signals queued in digest mode until the next flush
+type signalDigest struct {
+	mu      sync.Mutex
+	signals []*models.TradingSignal
+	since   time.Time // When the oldest queued signal arrived
+}
+
+// startDigest flushes the digest every DIGEST_INTERVAL_MINUTES for the life of
+// the process
+func (ns *NotificationService) startDigest() {
+	interval := time.Duration(ns.cfg.DigestIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := ns.FlushDigest(); err != nil {
+				logrus.Error("Failed to send signal digest: ", err)
+			}
+		}
+	}()
+
+	logrus.Info("✅ Signal digest mode enabled: every ", interval)
+}
+
+// queueForDigest reports whether the signal was queued for the next digest
+// instead of being sent now. Signals at or above DIGEST_INSTANT_PRIORITY
+// break through immediately.
+func (ns *NotificationService) queueForDigest(signal *models.TradingSignal) bool {
+	if ns.cfg.NotificationMode != "digest" {
+		return false
+	}
+	if ns.cfg.DigestInstantPriority != "" && priorityAtLeast(signal.Priority, ns.cfg.DigestInstantPriority) {
+		return false
+	}
+
+	ns.digest.mu.Lock()
+	defer ns.digest.mu.Unlock()
+
+	if len(ns.digest.signals) == 0 {
+		ns.digest.since = time.Now()
+	}
+	ns.digest.signals = append(ns.digest.signals, signal)
+	logrus.Info("Queued ", signal.Crypto.Symbol, " signal for the next digest (", len(ns.digest.signals), " queued)")
+	return true
+}
+
+// FlushDigest sends all queued signals as one consolidated message. Nothing is
+// sent while the queue is empty; when no Telegram chat received it the signals stay queued.
+func (ns *NotificationService) FlushDigest() error {
+	ns.digest.mu.Lock()
+	signals, since := ns.digest.signals, ns.digest.since
+	ns.digest.signals = nil
+	ns.digest.mu.Unlock()
+
+	if len(signals) == 0 {
+		return nil
+	}
+
+	message := formatDigestMessage(signals, since)
+
+	if ns.telegramBot != nil && len(ns.telegramChatIDs()) > 0 {
+		messageIDs, err := ns.broadcastTelegram(message, nil)
+		if err != nil {
+			ns.digest.mu.Lock()
+			ns.digest.signals = append(signals, ns.digest.signals...)
+			ns.digest.since = since
+			ns.digest.mu.Unlock()
+			return err
+		}
+		// Lifecycle updates of each signal reply to the digest it was listed in
+		if messageID, sent := messageIDs[ns.cfg.TelegramChatID]; sent {
+			for _, signal := range signals {
+				ns.recordSignalMessage(signal, messageID)
+			}
+		}
+	}
+
+	if ns.cfg.WhatsAppEnabled {
+		if err := ns.sendWhatsAppMessage("signal", nil, message); err != nil {
+			logrus.Error("Failed to send WhatsApp message: ", err)
+		}
+	}
+
+	if ns.cfg.DiscordEnabled {
+		if err := ns.sendDiscordMessage(signals...); err != nil {
+			logrus.Error("Failed to send Discord message: ", err)
+		}
+	}
+
+	logrus.Info("✅ Signal digest sent with ", len(signals), " signals")
+	return nil
+}
+
+// formatDigestMessage lists queued signals one per line, oldest first
+func formatDigestMessage(signals []*models.TradingSignal, since time.Time) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📋 *SIGNAL DIGEST* - %d sinyal sejak %s\n", len(signals), since.Format("15:04")))
+
+	for _, signal := range signals {
+		emoji := "🟡"
+		switch signal.Action {
+		case "BUY":
+			emoji = "🟢"
+		case "SELL":
+			emoji = "🔴"
+		}
+
+		confidence := signal.ConfidenceScore.Mul(decimal.NewFromInt(100))
+		b.WriteString(fmt.Sprintf("\n%s *%s* %s @ $%s · %.0f%%", emoji, signal.Crypto.Symbol, signal.Action,
+			signal.EntryPrice.String(), confidence.InexactFloat64()))
+		if label := signalPriorityLabel(signal.Priority); label != "" {
+			b.WriteString(" · " + label)
+		}
+
+		var targets []string
+		if signal.StopLoss != nil {
+			targets = append(targets, "SL $"+signal.StopLoss.String())
+		}
+		if signal.TakeProfit1 != nil {
+			targets = append(targets, "TP1 $"+signal.TakeProfit1.String())
+		}
+		if len(targets) > 0 {
+			b.WriteString("\n    " + strings.Join(targets, " · "))
+		}
+		if signal.RefCode != "" {
+			b.WriteString(fmt.Sprintf(" · `%s`", signal.RefCode))
+		}
+	}
+
+	b.WriteString(fmt.Sprintf("\n\n⏰ %s", time.Now().Format("15:04 02/01/2006")))
+	return b.String()
+}
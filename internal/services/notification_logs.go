@@ -0,0 +1,53 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Statuses of a notification_logs entry
+const (
+	notificationSent   = "sent"
+	notificationFailed = "failed"
+)
+
+// logNotification records the outcome of one delivery in notification_logs.
+// The write runs in the background and failures are only logged, so a
+// database problem never holds up or fails a notification.
+func (ns *NotificationService) logNotification(notificationType, recipient, message string, signal *models.TradingSignal, sendErr error) {
+	botService := ns.getBotService()
+	if botService == nil || botService.db == nil {
+		return
+	}
+
+	now := time.Now()
+	entry := &models.NotificationLog{
+		ID:               uuid.New(),
+		NotificationType: notificationType,
+		Recipient:        recipient,
+		Message:          message,
+		Status:           notificationSent,
+		SentAt:           now,
+		CreatedAt:        now,
+	}
+	// Burst test signals are never stored, so there is no row to reference
+	if signal != nil && signal.Source != SignalSourceBurstTest {
+		signalID, cryptoID := signal.ID, signal.CryptoID
+		entry.SignalID = &signalID
+		entry.CryptocurrencyID = &cryptoID
+	}
+	if sendErr != nil {
+		errorMessage := sendErr.Error()
+		entry.Status = notificationFailed
+		entry.ErrorMessage = &errorMessage
+	}
+
+	go func() {
+		if err := botService.db.LogNotification(entry); err != nil {
+			logrus.Debug("Failed to write notification log: ", err)
+		}
+	}()
+}
@@ -1,10 +1,14 @@
 package services
 
 import (
+	"context"
 	"crypto-signal-bot/internal/config"
 	"crypto-signal-bot/internal/models"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -15,12 +19,24 @@ import (
 type NotificationService struct {
 	cfg         *config.Config
 	telegramBot *tgbotapi.BotAPI
-	botService  *BotService // Add reference to bot service for menu actions
+
+	// Runtime-mutable state, shared between the Telegram update loop and
+	// analysis goroutines; access only through the guarded accessors
+	mu             sync.RWMutex
+	botService     *BotService        // Add reference to bot service for menu actions
+	updatesStarted bool               // Set once the update loop is consuming commands
+	stopUpdates    context.CancelFunc // Ends the update loop on shutdown
+	updatesDone    chan struct{}      // Closed when the update loop has returned
+	muted          bool               // Signal notifications are held back while set
+	chatIDs        []string           // Chats notifications go to; starts as TELEGRAM_CHAT_IDS
+
+	digest signalDigest // Signals waiting for the next digest in NOTIFICATION_MODE=digest
 }
 
 func NewNotificationService(cfg *config.Config) *NotificationService {
 	ns := &NotificationService{
-		cfg: cfg,
+		cfg:     cfg,
+		chatIDs: append([]string(nil), cfg.TelegramChatIDs...),
 	}
 
 	// Initialize Telegram bot if token is provided
@@ -31,17 +47,70 @@ func NewNotificationService(cfg *config.Config) *NotificationService {
 		} else {
 			ns.telegramBot = bot
 			logrus.Info("✅ Telegram bot initialized successfully")
+
+			if len(cfg.TelegramChatIDs) == 0 {
+				logrus.Warnf("⚠️ TELEGRAM_CHAT_ID is not set - notifications will not be delivered. Send /start to @%s to get your chat ID.", bot.Self.UserName)
+			} else if len(cfg.TelegramChatIDs) > 1 {
+				logrus.Infof("Telegram notifications go to %d chats", len(cfg.TelegramChatIDs))
+			}
 		}
 	}
 
+	if cfg.NotificationMode == "digest" {
+		ns.startDigest()
+	}
+
 	return ns
 }
 
 // SetBotService sets the bot service reference for menu actions
 func (ns *NotificationService) SetBotService(botService *BotService) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
 	ns.botService = botService
 }
 
+// getBotService returns the bot service reference under the read lock
+func (ns *NotificationService) getBotService() *BotService {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+
+	return ns.botService
+}
+
+// SetMuted turns signal notifications off or back on at runtime
+func (ns *NotificationService) SetMuted(muted bool) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	ns.muted = muted
+}
+
+// IsMuted reports whether signal notifications are held back
+func (ns *NotificationService) IsMuted() bool {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+
+	return ns.muted
+}
+
+// SetChatIDs replaces the Telegram chats notifications are broadcast to
+func (ns *NotificationService) SetChatIDs(chatIDs []string) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	ns.chatIDs = append([]string(nil), chatIDs...)
+}
+
+// telegramChatIDs returns a snapshot of the chats notifications go to
+func (ns *NotificationService) telegramChatIDs() []string {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+
+	return append([]string(nil), ns.chatIDs...)
+}
+
 // StartTelegramBot starts the Telegram bot with command handlers
 func (ns *NotificationService) StartTelegramBot() error {
 	if ns.telegramBot == nil {
@@ -55,27 +124,76 @@ func (ns *NotificationService) StartTelegramBot() error {
 	u.Timeout = 60
 
 	updates := ns.telegramBot.GetUpdatesChan(u)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
 
-	// Handle updates in a goroutine
+	// Handle updates in a goroutine until StopTelegramBot
 	go func() {
-		for update := range updates {
-			if update.Message != nil {
-				ns.handleMessage(update.Message)
-			} else if update.CallbackQuery != nil {
-				ns.handleCallbackQuery(update.CallbackQuery)
+		defer close(done)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+				if ctx.Err() != nil {
+					// Shutdown began while this update was waiting
+					return
+				}
+				if update.Message != nil {
+					ns.handleMessage(update.Message)
+				} else if update.CallbackQuery != nil {
+					ns.handleCallbackQuery(update.CallbackQuery)
+				}
 			}
 		}
 	}()
 
+	ns.mu.Lock()
+	ns.updatesStarted = true
+	ns.stopUpdates = cancel
+	ns.updatesDone = done
+	ns.mu.Unlock()
+
 	logrus.Info("✅ Telegram bot started with interactive menu")
 	return nil
 }
 
+// telegramStopTimeout bounds how long shutdown waits for a command still being handled
+const telegramStopTimeout = 10 * time.Second
+
+// StopTelegramBot stops consuming Telegram updates so no command runs against
+// services that are shutting down. It waits up to telegramStopTimeout for the
+// command in progress to finish. Outgoing notifications keep working.
+func (ns *NotificationService) StopTelegramBot() {
+	ns.mu.Lock()
+	stop, done := ns.stopUpdates, ns.updatesDone
+	ns.stopUpdates, ns.updatesDone = nil, nil
+	ns.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	logrus.Info("Stopping Telegram command handling...")
+	stop()
+	ns.telegramBot.StopReceivingUpdates()
+
+	select {
+	case <-done:
+		logrus.Info("✅ Telegram update loop stopped")
+	case <-time.After(telegramStopTimeout):
+		logrus.Warnf("Telegram update loop still busy after %s, continuing shutdown", telegramStopTimeout)
+	}
+}
+
 // handleMessage handles incoming text messages and commands
 func (ns *NotificationService) handleMessage(message *tgbotapi.Message) {
 	if message.IsCommand() {
 		ns.handleCommand(message)
-	} else {
+	} else if ns.authorizeChat(message.Chat.ID, message.From, "help", "message") {
 		// Handle regular text messages if needed
 		ns.sendHelpMessage(message.Chat.ID)
 	}
@@ -88,8 +206,15 @@ func (ns *NotificationService) handleCommand(message *tgbotapi.Message) {
 
 	logrus.Infof("Received command: /%s from chat %d", command, chatID)
 
+	if !ns.authorizeChat(chatID, message.From, command, "command /"+command) {
+		return
+	}
+
 	switch command {
 	case "start":
+		if ns.cfg.TelegramChatID == "" {
+			ns.sendChatIDSetupMessage(chatID)
+		}
 		ns.sendWelcomeMessage(chatID)
 	case "menu":
 		ns.sendMainMenu(chatID)
@@ -99,6 +224,44 @@ func (ns *NotificationService) handleCommand(message *tgbotapi.Message) {
 		ns.sendCoinsList(chatID)
 	case "performance":
 		ns.sendPerformanceReport(chatID)
+	case "analyze":
+		ns.runManualAnalysis(chatID, strings.EqualFold(strings.TrimSpace(message.CommandArguments()), "force"))
+	case "optimize":
+		ns.runManualOptimization(chatID)
+	case "signal":
+		ns.sendSignalByRef(chatID, strings.TrimSpace(message.CommandArguments()))
+	case "signals":
+		ns.sendRecentSignals(chatID, strings.TrimSpace(message.CommandArguments()))
+	case "perf":
+		ns.sendPerformanceRollup(chatID, strings.ToLower(strings.TrimSpace(message.CommandArguments())))
+	case "simulate":
+		ns.sendThresholdSimulation(chatID, strings.Fields(message.CommandArguments()))
+	case "exposure":
+		ns.sendExposure(chatID)
+	case "diagnostics":
+		ns.sendDiagnostics(chatID)
+	case "logs":
+		ns.sendSystemLogs(chatID, message.CommandArguments())
+	case "chart":
+		ns.sendChart(chatID, strings.Fields(message.CommandArguments()))
+	case "feedback":
+		ns.recordFeedback(chatID, strings.Fields(message.CommandArguments()))
+	case "killswitch":
+		ns.confirmKillSwitch(chatID)
+	case "rearm":
+		ns.rearmKillSwitch(chatID)
+	case "resume":
+		ns.resumeFromDrawdown(chatID)
+	case "dryrun":
+		ns.toggleDryRun(chatID, strings.ToLower(strings.TrimSpace(message.CommandArguments())))
+	case "nexttick":
+		ns.sendNextTick(chatID)
+	case "runat":
+		ns.scheduleAnalysisAt(chatID, strings.TrimSpace(message.CommandArguments()))
+	case "delcoin":
+		ns.deleteCoin(chatID, strings.ToUpper(strings.TrimSpace(message.CommandArguments())))
+	case "resetwatchlist":
+		ns.resetWatchlist(chatID)
 	case "help":
 		ns.sendHelpMessage(chatID)
 	default:
@@ -117,13 +280,17 @@ func (ns *NotificationService) handleCallbackQuery(callbackQuery *tgbotapi.Callb
 	callback := tgbotapi.NewCallback(callbackQuery.ID, "")
 	ns.telegramBot.Request(callback)
 
+	if !ns.authorizeChat(chatID, callbackQuery.From, callbackCommand(data), "button "+data) {
+		return
+	}
+
 	switch data {
 	case "main_menu":
 		ns.sendMainMenu(chatID)
 	case "bot_status":
 		ns.sendBotStatus(chatID)
 	case "manual_analysis":
-		ns.runManualAnalysis(chatID)
+		ns.runManualAnalysis(chatID, false)
 	case "coins_list":
 		ns.sendCoinsList(chatID)
 	case "add_coin":
@@ -136,6 +303,8 @@ func (ns *NotificationService) handleCallbackQuery(callbackQuery *tgbotapi.Callb
 		ns.sendDailySummaryNow(chatID)
 	case "learning_stats":
 		ns.sendLearningStats(chatID)
+	case "killswitch_confirm":
+		ns.engageKillSwitch(chatID)
 	default:
 		if len(data) > 9 && data[:9] == "add_coin_" {
 			symbol := data[9:]
@@ -143,38 +312,177 @@ func (ns *NotificationService) handleCallbackQuery(callbackQuery *tgbotapi.Callb
 		} else if len(data) > 12 && data[:12] == "remove_coin_" {
 			symbol := data[12:]
 			ns.removeCoinFromWatch(chatID, symbol)
+		} else if len(data) > 11 && data[:11] == "coins_page_" {
+			page, _ := strconv.Atoi(data[11:])
+			ns.sendCoinsPage(chatID, page)
+		} else if strings.HasPrefix(data, signalTakenPrefix) {
+			ns.markSignal(callbackQuery.Message, strings.TrimPrefix(data, signalTakenPrefix), signalActionTaken)
+		} else if strings.HasPrefix(data, signalIgnoredPrefix) {
+			ns.markSignal(callbackQuery.Message, strings.TrimPrefix(data, signalIgnoredPrefix), signalActionIgnored)
+		} else if strings.HasPrefix(data, snoozeCoinPrefix) {
+			ns.snoozeCoin(chatID, strings.TrimPrefix(data, snoozeCoinPrefix))
 		} else {
 			ns.sendMainMenu(chatID)
 		}
 	}
 }
 
+// sendChatIDSetupMessage tells a first-run user which chat ID to configure
+func (ns *NotificationService) sendChatIDSetupMessage(chatID int64) {
+	message := fmt.Sprintf("🔧 *Setup Diperlukan*\n\n"+
+		"`TELEGRAM_CHAT_ID` belum dikonfigurasi, sehingga notifikasi sinyal belum bisa dikirim.\n\n"+
+		"🆔 *Chat ID Anda:* `%d`\n\n"+
+		"Tambahkan ke file `.env` lalu restart bot:\n"+
+		"`TELEGRAM_CHAT_ID=%d`", chatID, chatID)
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+
+	logrus.Infof("Sent chat ID setup instructions to chat %d", chatID)
+}
+
+// IsTelegramReady reports whether Telegram is either unavailable or fully started
+func (ns *NotificationService) IsTelegramReady() bool {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+
+	return ns.telegramBot == nil || ns.updatesStarted
+}
+
+// ShouldNotify reports whether a signal's confidence clears the notify threshold
+// and its data quality clears MIN_DATA_QUALITY. Unscored signals pass the latter.
+func (ns *NotificationService) ShouldNotify(signal *models.TradingSignal) bool {
+	if signal.ConfidenceScore.LessThan(decimal.NewFromFloat(ns.cfg.NotifyConfidenceThreshold)) {
+		return false
+	}
+	return signal.DataQuality == nil || !signal.DataQuality.LessThan(decimal.NewFromFloat(ns.cfg.MinDataQuality))
+}
+
+// staleSignalReason explains why a signal is no longer actionable, or returns
+// "" if it is fresh. A live price that can't be fetched doesn't block sending.
+func (ns *NotificationService) staleSignalReason(signal *models.TradingSignal) string {
+	if ns.cfg.MaxSignalAgeMinutes > 0 {
+		maxAge := time.Duration(ns.cfg.MaxSignalAgeMinutes) * time.Minute
+		if age := time.Since(signal.CreatedAt); age > maxAge {
+			return fmt.Sprintf("signal is %s old, max is %s", age.Round(time.Second), maxAge)
+		}
+	}
+
+	if ns.cfg.MaxEntryDeviationPercent <= 0 || signal.EntryPrice.IsZero() {
+		return ""
+	}
+
+	botService := ns.getBotService()
+	if botService == nil {
+		return ""
+	}
+
+	livePrice, err := botService.marketDataSource.GetCurrentPrice(signal.Crypto.Symbol)
+	if err != nil {
+		logrus.Debug("Could not re-validate entry price for ", signal.Crypto.Symbol, ": ", err)
+		return ""
+	}
+
+	deviation := livePrice.Sub(signal.EntryPrice).Div(signal.EntryPrice).Abs().Mul(decimal.NewFromInt(100))
+	if deviation.GreaterThan(decimal.NewFromFloat(ns.cfg.MaxEntryDeviationPercent)) {
+		return fmt.Sprintf("live price %s is %.2f%% away from entry %s, max is %.2f%%",
+			livePrice.String(), deviation.InexactFloat64(), signal.EntryPrice.String(), ns.cfg.MaxEntryDeviationPercent)
+	}
+
+	return ""
+}
+
 func (ns *NotificationService) SendSignalNotification(signal *models.TradingSignal) error {
+	if !ns.ShouldNotify(signal) {
+		logrus.Debug("Signal below notify threshold for ", signal.Crypto.Symbol, ": confidence ", signal.ConfidenceScore, ", data quality ", signal.DataQuality)
+		return nil
+	}
+
+	if ns.IsMuted() {
+		logrus.Info("Notifications muted, suppressing signal notification for ", signal.Crypto.Symbol)
+		return nil
+	}
+
+	if botService := ns.getBotService(); botService != nil && botService.IsKillSwitchEngaged() {
+		logrus.Warn("Kill switch engaged, suppressing signal notification for ", signal.Crypto.Symbol)
+		return nil
+	}
+
+	if botService := ns.getBotService(); botService != nil && botService.IsCoinSnoozed(signal.Crypto.Symbol) {
+		logrus.Info("Coin snoozed, suppressing signal notification for ", signal.Crypto.Symbol)
+		return nil
+	}
+
+	if reason := ns.staleSignalReason(signal); reason != "" {
+		logrus.Warn("Suppressing stale signal notification for ", signal.Crypto.Symbol, ": ", reason)
+		return nil
+	}
+
+	if ns.queueForDigest(signal) {
+		return nil
+	}
+
 	logrus.Info("Sending signal notification for: ", signal.Crypto.Symbol)
 
 	// Format message
 	message := ns.formatSignalMessage(signal)
 
-	// Send to Telegram
-	if ns.telegramBot != nil && ns.cfg.TelegramChatID != "" {
-		if err := ns.sendTelegramMessage(message); err != nil {
+	// Send to every Telegram chat, with the triage buttons in the owner chat,
+	// remembering the owner's message so lifecycle updates can reply to it
+	if ns.telegramBot != nil && len(ns.telegramChatIDs()) > 0 {
+		var markup interface{}
+		if keyboard := signalTriageKeyboard(signal); keyboard != nil {
+			markup = keyboard
+		}
+		messageIDs, err := ns.broadcastTelegram(message, markup)
+		if err != nil {
 			logrus.Error("Failed to send Telegram message: ", err)
 			return err
 		}
+		if messageID, sent := messageIDs[ns.cfg.TelegramChatID]; sent {
+			ns.recordSignalMessage(signal, messageID)
+		}
+	} else if ns.telegramBot != nil {
+		logrus.Warn("TELEGRAM_CHAT_ID is not set, skipping Telegram signal notification")
 	}
 
 	// Send to WhatsApp (if enabled)
 	if ns.cfg.WhatsAppEnabled {
-		if err := ns.sendWhatsAppMessage(message); err != nil {
+		if err := ns.sendWhatsAppMessage("signal", signal, message); err != nil {
 			logrus.Error("Failed to send WhatsApp message: ", err)
 			// Don't return error for WhatsApp failure, continue with other notifications
 		}
 	}
 
+	// Send to Discord as a rich embed (if enabled)
+	if ns.cfg.DiscordEnabled {
+		if err := ns.sendDiscordMessage(signal); err != nil {
+			logrus.Error("Failed to send Discord message: ", err)
+		}
+	}
+
+	// Route priority signals to the additional webhook channel
+	if ns.cfg.PriorityWebhookURL != "" && priorityAtLeast(signal.Priority, ns.cfg.PriorityWebhookMin) {
+		if err := ns.sendPriorityWebhook(message); err != nil {
+			logrus.Error("Failed to send priority webhook message: ", err)
+		}
+	}
+
+	// Live listeners such as the WebSocket stream
+	if botService := ns.getBotService(); botService != nil {
+		botService.publishSignal(signal)
+	}
+
 	logrus.Info("✅ Signal notification sent successfully")
 	return nil
 }
 
+// formatPrice renders a price at the coin's precision
+func (ns *NotificationService) formatPrice(crypto *models.Cryptocurrency, price decimal.Decimal) string {
+	return formatCoinPrice(crypto, price, ns.cfg.SignalPricePrecision)
+}
+
 func (ns *NotificationService) formatSignalMessage(signal *models.TradingSignal) string {
 	// Get action emoji
 	var actionEmoji string
@@ -191,37 +499,61 @@ func (ns *NotificationService) formatSignalMessage(signal *models.TradingSignal)
 	confidence := signal.ConfidenceScore.Mul(decimal.NewFromInt(100))
 
 	// Format prices
-	entryPrice := signal.EntryPrice.StringFixed(8)
+	entryPrice := ns.formatPrice(signal.Crypto, signal.EntryPrice)
 	stopLoss := ""
 	takeProfit1 := ""
 	takeProfit2 := ""
 
 	if signal.StopLoss != nil {
-		stopLoss = signal.StopLoss.StringFixed(8)
+		stopLoss = ns.formatPrice(signal.Crypto, *signal.StopLoss)
 	}
 	if signal.TakeProfit1 != nil {
-		takeProfit1 = signal.TakeProfit1.StringFixed(8)
+		takeProfit1 = ns.formatPrice(signal.Crypto, *signal.TakeProfit1)
 	}
 	if signal.TakeProfit2 != nil {
-		takeProfit2 = signal.TakeProfit2.StringFixed(8)
+		takeProfit2 = ns.formatPrice(signal.Crypto, *signal.TakeProfit2)
+	}
+
+	// Zoned signals show the band to ladder into rather than one price
+	entryLine := fmt.Sprintf("💵 *Entry Price:* $%s", entryPrice)
+	if signal.EntryLow != nil && signal.EntryHigh != nil {
+		entryLine = fmt.Sprintf("💵 *Entry zone:* $%s – $%s", ns.formatPrice(signal.Crypto, *signal.EntryLow), ns.formatPrice(signal.Crypto, *signal.EntryHigh))
+	}
+
+	// Flag alerts that didn't come from the bot's own analysis
+	sourceLine := ""
+	if signal.Source != "" && signal.Source != SignalSourceInternal {
+		sourceLine = fmt.Sprintf("\n📡 *Source:* %s", signalSourceLabel(signal.Source))
+	}
+
+	priorityBadge := ""
+	if label := signalPriorityLabel(signal.Priority); label != "" {
+		priorityBadge = fmt.Sprintf(" · *%s*", label)
 	}
 
 	// Build message
-	message := fmt.Sprintf(`🚨 *CRYPTO SIGNAL* 🚨
+	message := fmt.Sprintf(`🚨 *CRYPTO SIGNAL* 🚨%s
 
 %s *%s/USDT*
 📈 *Action:* %s
-💵 *Entry Price:* $%s
-🎯 *Confidence:* %.1f%%
+%s
+🎯 *Confidence:* %.1f%%%s
 
 📊 *Analysis:*`,
+		priorityBadge,
 		actionEmoji,
 		signal.Crypto.Symbol,
 		signal.Action,
-		entryPrice,
+		entryLine,
 		confidence.InexactFloat64(),
+		sourceLine,
 	)
 
+	// Add sparkline of recent price action
+	if ns.cfg.SparklineEnabled && len(signal.PriceHistory) > 1 {
+		message += fmt.Sprintf("\n`%s`", renderSparkline(signal.PriceHistory))
+	}
+
 	// Add technical indicators
 	if signal.RSI != nil {
 		message += fmt.Sprintf("\n• RSI: %.2f", signal.RSI.InexactFloat64())
@@ -240,17 +572,34 @@ func (ns *NotificationService) formatSignalMessage(signal *models.TradingSignal)
 		message += fmt.Sprintf("\n• Fear & Greed: %d (%s)", *signal.FearGreedIndex, fgiText)
 	}
 
+	if signal.DataQuality != nil {
+		message += fmt.Sprintf("\n• Data Quality: %.0f%%", signal.DataQuality.Mul(decimal.NewFromInt(100)).InexactFloat64())
+		if issues := dataQualityIssues(signal); len(issues) > 0 {
+			message += fmt.Sprintf(" (%s)", strings.Join(issues, ", "))
+		}
+	}
+
 	// Add price targets
 	if signal.Action != "HOLD" {
 		message += "\n\n🎯 *Targets:*"
 		if stopLoss != "" {
 			message += fmt.Sprintf("\n• Stop Loss: $%s", stopLoss)
 		}
-		if takeProfit1 != "" {
-			message += fmt.Sprintf("\n• Take Profit 1: $%s", takeProfit1)
-		}
-		if takeProfit2 != "" {
-			message += fmt.Sprintf("\n• Take Profit 2: $%s", takeProfit2)
+		if len(signal.TakeProfits) > 0 {
+			for _, target := range signal.TakeProfits {
+				message += fmt.Sprintf("\n• Take Profit %d: $%s (%.0f%%)",
+					target.Level,
+					ns.formatPrice(signal.Crypto, target.Price),
+					target.Allocation.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+				)
+			}
+		} else {
+			if takeProfit1 != "" {
+				message += fmt.Sprintf("\n• Take Profit 1: $%s", takeProfit1)
+			}
+			if takeProfit2 != "" {
+				message += fmt.Sprintf("\n• Take Profit 2: $%s", takeProfit2)
+			}
 		}
 	}
 
@@ -259,6 +608,11 @@ func (ns *NotificationService) formatSignalMessage(signal *models.TradingSignal)
 		message += fmt.Sprintf("\n\n💡 *Reasoning:*\n%s", signal.Reasoning)
 	}
 
+	// Add reference code
+	if signal.RefCode != "" {
+		message += fmt.Sprintf("\n\n🔖 *Ref:* `%s`", signal.RefCode)
+	}
+
 	// Add timestamp
 	message += fmt.Sprintf("\n\n⏰ %s WIB", signal.CreatedAt.Format("15:04 02/01/2006"))
 
@@ -268,11 +622,100 @@ func (ns *NotificationService) formatSignalMessage(signal *models.TradingSignal)
 	return message
 }
 
+// dataQualityIssues reads the data-quality notes stored in market conditions;
+// they come back as []interface{} once the signal round-trips through JSON
+func dataQualityIssues(signal *models.TradingSignal) []string {
+	switch issues := signal.MarketConditions["data_quality_issues"].(type) {
+	case []string:
+		return issues
+	case []interface{}:
+		notes := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			if note, ok := issue.(string); ok {
+				notes = append(notes, note)
+			}
+		}
+		return notes
+	}
+	return nil
+}
+
+// renderSparkline draws values as a row of Unicode block characters
+func renderSparkline(values []decimal.Decimal) string {
+	blocks := []rune("▁▂▃▄▅▆▇█")
+
+	low, high := values[0], values[0]
+	for _, value := range values {
+		if value.LessThan(low) {
+			low = value
+		}
+		if value.GreaterThan(high) {
+			high = value
+		}
+	}
+
+	spread := high.Sub(low)
+	var sparkline strings.Builder
+	for _, value := range values {
+		index := 0
+		if !spread.IsZero() {
+			index = int(value.Sub(low).Div(spread).Mul(decimal.NewFromInt(int64(len(blocks) - 1))).Round(0).IntPart())
+		}
+		sparkline.WriteRune(blocks[index])
+	}
+
+	return sparkline.String()
+}
+
 func (ns *NotificationService) sendTelegramMessage(message string) error {
 	return ns.sendTelegramMessageToChat(ns.cfg.TelegramChatID, message)
 }
 
+// broadcastTelegram sends a message to every chat in TELEGRAM_CHAT_IDS and
+// returns the sent message ID per chat. The markup is attached only in the
+// owner chat, the one allowed to use the buttons. A failing chat is logged and
+// skipped; an error is returned only when no chat received the message.
+func (ns *NotificationService) broadcastTelegram(message string, ownerMarkup interface{}) (map[string]int, error) {
+	chatIDs := ns.telegramChatIDs()
+	messageIDs := make(map[string]int, len(chatIDs))
+	if ns.telegramBot == nil || len(chatIDs) == 0 {
+		return messageIDs, nil
+	}
+
+	var errs []error
+	for _, chatID := range chatIDs {
+		var markup interface{}
+		if chatID == ns.cfg.TelegramChatID {
+			markup = ownerMarkup
+		}
+		messageID, err := ns.sendTelegramMarkup(chatID, message, 0, markup)
+		if err != nil {
+			logrus.Warn("Telegram delivery failed, continuing with the other chats: ", err)
+			errs = append(errs, err)
+			continue
+		}
+		messageIDs[chatID] = messageID
+	}
+
+	if len(messageIDs) == 0 {
+		return messageIDs, fmt.Errorf("telegram delivery failed for all %d chats: %w", len(errs), errors.Join(errs...))
+	}
+	return messageIDs, nil
+}
+
 func (ns *NotificationService) sendTelegramMessageToChat(chatIDStr string, message string) error {
+	_, err := ns.sendTelegramReply(chatIDStr, message, 0)
+	return err
+}
+
+// sendTelegramReply sends a message, threaded under replyTo when it is non-zero,
+// and returns the ID of the sent message
+func (ns *NotificationService) sendTelegramReply(chatIDStr string, message string, replyTo int) (int, error) {
+	return ns.sendTelegramMarkup(chatIDStr, message, replyTo, nil)
+}
+
+// sendTelegramMarkup is sendTelegramReply with an optional reply markup
+func (ns *NotificationService) sendTelegramMarkup(chatIDStr string, message string, replyTo int, markup interface{}) (int, error) {
 	var msg tgbotapi.MessageConfig
 
 	// Try to parse as numeric chat ID first
@@ -285,21 +728,39 @@ func (ns *NotificationService) sendTelegramMessageToChat(chatIDStr string, messa
 
 	msg.ParseMode = "Markdown"
 	msg.DisableWebPagePreview = true
+	if replyTo != 0 {
+		msg.ReplyToMessageID = replyTo
+		// Still deliver the update if the original message was deleted
+		msg.AllowSendingWithoutReply = true
+	}
+	if markup != nil {
+		msg.ReplyMarkup = markup
+	}
 
-	_, err := ns.telegramBot.Send(msg)
+	sent, err := ns.telegramBot.Send(msg)
+	if botService := ns.getBotService(); botService != nil {
+		botService.recordTelegramDelivery(err)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to send Telegram message to %s: %w", chatIDStr, err)
+		return 0, fmt.Errorf("failed to send Telegram message to %s: %w", chatIDStr, err)
 	}
 
 	logrus.Info("✅ Telegram message sent successfully to ", chatIDStr)
-	return nil
+	return sent.MessageID, nil
 }
 
-func (ns *NotificationService) sendWhatsAppMessage(message string) error {
-	// TODO: Implement WhatsApp Business API integration
-	// For now, just log that WhatsApp is not implemented
-	logrus.Info("WhatsApp notification would be sent: ", message[:50], "...")
-	return nil
+// recordSignalMessage stores the Telegram message a signal was announced in,
+// so replies keep threading after a restart
+func (ns *NotificationService) recordSignalMessage(signal *models.TradingSignal, messageID int) {
+	signal.TelegramMessageID = &messageID
+
+	botService := ns.getBotService()
+	if botService == nil {
+		return
+	}
+	if err := botService.db.SetSignalTelegramMessageID(signal.ID, messageID); err != nil {
+		logrus.Warn("Failed to save Telegram message ID for signal ", signal.ID, ": ", err)
+	}
 }
 
 func (ns *NotificationService) getFearGreedText(index int) string {
@@ -318,7 +779,7 @@ func (ns *NotificationService) getFearGreedText(index int) string {
 }
 
 func (ns *NotificationService) SendSystemNotification(level, message string) error {
-	if ns.telegramBot == nil || ns.cfg.TelegramChatID == "" {
+	if ns.telegramBot == nil || len(ns.telegramChatIDs()) == 0 {
 		return nil
 	}
 
@@ -341,7 +802,8 @@ func (ns *NotificationService) SendSystemNotification(level, message string) err
 		time.Now().Format("15:04 02/01/2006"),
 	)
 
-	return ns.sendTelegramMessage(systemMessage)
+	_, err := ns.broadcastTelegram(systemMessage, nil)
+	return err
 }
 
 func (ns *NotificationService) SendDailySummary(analytics []*models.SignalAnalytics) error {
@@ -382,7 +844,8 @@ func (ns *NotificationService) SendDailySummary(analytics []*models.SignalAnalyt
 
 	message += fmt.Sprintf("\n\n⏰ %s", time.Now().Format("15:04 02/01/2006"))
 
-	return ns.sendTelegramMessage(message)
+	_, err := ns.broadcastTelegram(message, nil)
+	return err
 }
 
 func (ns *NotificationService) SendPerformanceUpdate(signal *models.TradingSignal, performance *models.SignalPerformance) error {
@@ -419,12 +882,22 @@ func (ns *NotificationService) SendPerformanceUpdate(signal *models.TradingSigna
 		signal.Action,
 		pnlPercent.InexactFloat64(),
 		*performance.DurationMinutes,
-		signal.EntryPrice.StringFixed(8),
-		performance.ExitPrice.StringFixed(8),
+		ns.formatPrice(signal.Crypto, signal.EntryPrice),
+		ns.formatPrice(signal.Crypto, *performance.ExitPrice),
 		time.Now().Format("15:04 02/01/2006"),
 	)
 
-	return ns.sendTelegramMessage(message)
+	if ns.telegramBot == nil || ns.cfg.TelegramChatID == "" {
+		return nil
+	}
+
+	// Thread the update under the original signal notification
+	replyTo := 0
+	if signal.TelegramMessageID != nil {
+		replyTo = *signal.TelegramMessageID
+	}
+	_, err := ns.sendTelegramReply(ns.cfg.TelegramChatID, message, replyTo)
+	return err
 }
 
 func (ns *NotificationService) TestConnection() error {
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/database"
+	"crypto-signal-bot/internal/models"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Run with -race: mute and chat changes from commands must not race with
+// notifications sent from analysis goroutines
+func TestNotificationStateConcurrentAccess(t *testing.T) {
+	cfg := testConfig()
+	cfg.TelegramChatIDs = []string{"1"}
+	ns := NewNotificationService(cfg)
+	bs := NewBotService(database.NewMemoryStore(), cfg)
+	source := NewStaticMarketDataSource()
+	source.Set("BTC", &MarketData{Symbol: "BTC", Price: dec("100")})
+	bs.SetMarketDataSource(source)
+
+	signal := &models.TradingSignal{
+		ID:              uuid.New(),
+		Crypto:          &models.Cryptocurrency{Symbol: "BTC"},
+		Action:          "BUY",
+		ConfidenceScore: dec("0.99"),
+		EntryPrice:      dec("100"),
+		CreatedAt:       time.Now(),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ns.SendSignalNotification(signal)
+				ns.broadcastTelegram("test", nil)
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ns.SetMuted(j%2 == 0)
+				ns.SetChatIDs([]string{"1", "2"}[:1+(i+j)%2])
+				ns.SetBotService(bs)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	ns.SetMuted(true)
+	if !ns.IsMuted() {
+		t.Error("IsMuted = false after SetMuted(true)")
+	}
+	ns.SetChatIDs([]string{"1", "2"})
+	if chatIDs := ns.telegramChatIDs(); len(chatIDs) != 2 {
+		t.Errorf("got %d chat IDs, want 2", len(chatIDs))
+	}
+}
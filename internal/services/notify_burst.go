@@ -0,0 +1,78 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// MaxNotifyBurst caps the synthetic signals of one notification burst test
+const MaxNotifyBurst = 100
+
+// burstTestSymbol names the synthetic coin; no provider quotes it, so the
+// entry re-validation is skipped instead of flagging the signals stale
+const burstTestSymbol = "BURSTTEST"
+
+// NotifyBurstResult reports a notification burst test
+type NotifyBurstResult struct {
+	Requested int      `json:"requested"`
+	Succeeded int      `json:"succeeded"`
+	Failed    int      `json:"failed"`
+	Errors    []string `json:"errors,omitempty"` // First few failures
+	ElapsedMs int64    `json:"elapsed_ms"`
+}
+
+// TestNotifyBurst sends count synthetic signals back to back through the
+// regular signal notification path, to see how the channels cope with a
+// burst. The signals are never stored. Like any signal they are subject to
+// the kill switch and digest mode, which count as succeeded.
+func (bs *BotService) TestNotifyBurst(count int) NotifyBurstResult {
+	result := NotifyBurstResult{Requested: count}
+	crypto := &models.Cryptocurrency{ID: uuid.New(), Symbol: burstTestSymbol, Name: "Burst test"}
+
+	logrus.Warn("🧪 Sending a burst of ", count, " synthetic signal notifications")
+	started := time.Now()
+	for i := 1; i <= count; i++ {
+		if err := bs.notificationService.SendSignalNotification(burstTestSignal(crypto, i, count)); err != nil {
+			result.Failed++
+			if len(result.Errors) < 5 {
+				result.Errors = append(result.Errors, fmt.Sprintf("#%d: %v", i, err))
+			}
+			continue
+		}
+		result.Succeeded++
+	}
+	result.ElapsedMs = time.Since(started).Milliseconds()
+
+	logrus.Infof("Notification burst finished: %d/%d sent, %d failed in %dms", result.Succeeded, count, result.Failed, result.ElapsedMs)
+	return result
+}
+
+// burstTestSignal builds the n-th synthetic signal of a burst, alternating
+// direction and clearing every notify threshold
+func burstTestSignal(crypto *models.Cryptocurrency, n, total int) *models.TradingSignal {
+	action, stopLoss, takeProfit := "BUY", decimal.NewFromInt(95), decimal.NewFromInt(110)
+	if n%2 == 0 {
+		action, stopLoss, takeProfit = "SELL", decimal.NewFromInt(105), decimal.NewFromInt(90)
+	}
+
+	return &models.TradingSignal{
+		ID:              uuid.New(),
+		CryptoID:        crypto.ID,
+		RefCode:         fmt.Sprintf("TEST-%03d", n),
+		Action:          action,
+		ConfidenceScore: decimal.NewFromInt(1),
+		EntryPrice:      decimal.NewFromInt(100),
+		StopLoss:        &stopLoss,
+		TakeProfit1:     &takeProfit,
+		Reasoning:       fmt.Sprintf("Synthetic notification %d of %d, not a trade signal", n, total),
+		CreatedAt:       time.Now(),
+		Status:          "active",
+		Source:          SignalSourceBurstTest,
+		Crypto:          crypto,
+	}
+}
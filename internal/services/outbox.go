@@ -0,0 +1,132 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Outbox entry statuses
+const (
+	outboxPending = "pending"
+	outboxSent    = "sent"
+	outboxFailed  = "failed" // Gave up after OUTBOX_MAX_ATTEMPTS
+)
+
+// Retry backoff of failed deliveries: outboxRetryBase doubled per attempt, capped
+const (
+	outboxRetryBase = time.Minute
+	outboxRetryMax  = time.Hour
+)
+
+// outboxBatchSize is how many due entries one worker pass delivers
+const outboxBatchSize = 20
+
+// newOutboxEntry builds the pending notification stored with a new signal.
+// The first attempt is left to the caller that created the signal; the worker
+// only picks the entry up after outboxRetryBase, when that attempt was lost.
+func newOutboxEntry(signal *models.TradingSignal) *models.OutboxEntry {
+	now := time.Now()
+	return &models.OutboxEntry{
+		SignalID:      signal.ID,
+		Payload:       signal,
+		Status:        outboxPending,
+		NextAttemptAt: now.Add(outboxRetryBase),
+		CreatedAt:     now,
+	}
+}
+
+// outboxBackoff returns the wait before the next attempt after attempts failures
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxRetryBase
+	for i := 1; i < attempts && delay < outboxRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > outboxRetryMax {
+		delay = outboxRetryMax
+	}
+	return delay
+}
+
+// deliverSignal makes the first delivery attempt of a freshly created signal.
+// A failure leaves its outbox entry pending for DeliverOutbox to retry.
+func (bs *BotService) deliverSignal(signal *models.TradingSignal) error {
+	bs.outboxMu.Lock()
+	defer bs.outboxMu.Unlock()
+
+	return bs.attemptDelivery(newOutboxEntry(signal), signal)
+}
+
+// DeliverOutbox retries due outbox entries: notifications that failed or
+// were interrupted by a restart. It returns how many were sent.
+//
+// Entries are marked sent right after the send returns, so delivery is
+// exactly once unless the process dies between the two.
+func (bs *BotService) DeliverOutbox() (int, error) {
+	if bs.db == nil {
+		return 0, nil
+	}
+
+	bs.outboxMu.Lock()
+	defer bs.outboxMu.Unlock()
+
+	entries, err := bs.db.GetPendingOutbox(outboxBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load signal outbox: %w", err)
+	}
+
+	sent := 0
+	for _, entry := range entries {
+		signal := entry.Payload
+		if signal == nil || signal.Crypto == nil {
+			entry.Status = outboxFailed
+			entry.LastError = "outbox payload has no signal"
+			if err := bs.db.UpdateOutboxEntry(entry); err != nil {
+				logrus.Error("Failed to update outbox entry of signal ", entry.SignalID, ": ", err)
+			}
+			continue
+		}
+
+		if err := bs.attemptDelivery(entry, signal); err != nil {
+			logrus.Warn("Outbox retry ", entry.Attempts, " failed for ", signal.Crypto.Symbol, " signal ", signal.RefCode, ": ", err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// attemptDelivery sends a signal notification and records the result on its
+// outbox entry. Signals the notifier deliberately suppresses (thresholds,
+// kill switch, snooze, stale price, digest queue) count as sent.
+func (bs *BotService) attemptDelivery(entry *models.OutboxEntry, signal *models.TradingSignal) error {
+	sendErr := bs.notificationService.SendSignalNotification(signal)
+
+	now := time.Now()
+	entry.Attempts++
+	if sendErr == nil {
+		entry.Status = outboxSent
+		entry.LastError = ""
+		entry.SentAt = &now
+	} else {
+		entry.LastError = sendErr.Error()
+		if entry.Attempts >= bs.cfg.OutboxMaxAttempts {
+			entry.Status = outboxFailed
+			logrus.Error("Giving up on notification of signal ", signal.RefCode, " after ", entry.Attempts, " attempts: ", sendErr)
+			bs.RecordSystemLog("error", "notifications", fmt.Sprintf("Gave up on notification of signal %s after %d attempts: %v", signal.RefCode, entry.Attempts, sendErr),
+				map[string]interface{}{"signal_id": signal.ID.String()})
+		} else {
+			entry.NextAttemptAt = now.Add(outboxBackoff(entry.Attempts))
+		}
+	}
+
+	if bs.db != nil {
+		if err := bs.db.UpdateOutboxEntry(entry); err != nil {
+			logrus.Error("Failed to update outbox entry of signal ", entry.SignalID, ": ", err)
+		}
+	}
+	return sendErr
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// Exit reasons of tracked signals, stored in signal_performance.exit_reason
+const (
+	exitReasonTakeProfit = "take_profit"
+	exitReasonStopLoss   = "stop_loss"
+	exitReasonMaxHold    = "max_hold"
+	exitReasonNotEntered = "entry_not_reached"
+)
+
+// signalExit is where and why a tracked position closed
+type signalExit struct {
+	Price      decimal.Decimal
+	At         time.Time
+	Reason     string
+	Entered    bool            // False when price never traded into the entry zone
+	Highest    decimal.Decimal // Extremes traded while the position was open
+	Lowest     decimal.Decimal
+	TargetsHit int
+}
+
+// resolveSignalExit replays the candles opened since a signal was issued.
+// The position opens on the first candle trading inside its entry zone (at
+// once without a zone); each take profit then closes its allocation and the
+// stop loss the rest, a candle touching both counting as stopped out like in
+// backtests. After maxHold (0 disables) the remainder exits at the close.
+// ok is false while the position is still open.
+func resolveSignalExit(signal *models.TradingSignal, candles []OHLCV, step, maxHold time.Duration) (exit signalExit, ok bool) {
+	targets := signalTakeProfits(signal)
+	isBuy := signal.Action == "BUY"
+	expiresAt := signal.CreatedAt.Add(maxHold)
+
+	for _, candle := range candles {
+		closedAt := time.UnixMilli(candle.Timestamp).Add(step)
+
+		if !exit.Entered {
+			exit.Entered = EntryZoneTouched(signal, candle.High, candle.Low)
+			if exit.Entered {
+				exit.Highest, exit.Lowest = signal.EntryPrice, signal.EntryPrice
+			}
+		}
+
+		if exit.Entered {
+			if signal.StopLoss != nil {
+				stopped := candle.Low.LessThanOrEqual(*signal.StopLoss)
+				if !isBuy {
+					stopped = candle.High.GreaterThanOrEqual(*signal.StopLoss)
+				}
+				if stopped {
+					exit.Price, exit.At, exit.Reason = *signal.StopLoss, closedAt, exitReasonStopLoss
+					return exit, true
+				}
+			}
+
+			exit.Highest = decimal.Max(exit.Highest, candle.High)
+			exit.Lowest = decimal.Min(exit.Lowest, candle.Low)
+			for exit.TargetsHit < len(targets) {
+				target := targets[exit.TargetsHit].Price
+				reached := isBuy && exit.Highest.GreaterThanOrEqual(target) || !isBuy && exit.Lowest.LessThanOrEqual(target)
+				if !reached {
+					break
+				}
+				exit.TargetsHit++
+			}
+			if len(targets) > 0 && exit.TargetsHit == len(targets) {
+				exit.Price, exit.At, exit.Reason = targets[len(targets)-1].Price, closedAt, exitReasonTakeProfit
+				return exit, true
+			}
+		}
+
+		if maxHold > 0 && !closedAt.Before(expiresAt) {
+			exit.Price, exit.At, exit.Reason = candle.Close, closedAt, exitReasonMaxHold
+			if !exit.Entered {
+				exit.Reason = exitReasonNotEntered
+			}
+			return exit, true
+		}
+	}
+
+	return exit, false
+}
+
+// updatePerformanceTracking follows the active BUY/SELL signals on
+// KLINE_INTERVAL candles and closes those whose stop loss or last take profit
+// was reached or that outlived SIGNAL_MAX_HOLD_HOURS. The PnL blends the
+// partial exits at each take profit reached, and is stored as the signal's
+// performance record and learning outcome.
+func (bs *BotService) updatePerformanceTracking() error {
+	if bs.db == nil {
+		return nil
+	}
+	logrus.Debug("Updating performance tracking...")
+
+	signals, err := bs.db.GetActiveSignals()
+	if err != nil {
+		return fmt.Errorf("failed to load active signals: %w", err)
+	}
+
+	symbols := make(map[uuid.UUID]string)
+	for _, crypto := range bs.watchlist() {
+		symbols[crypto.ID] = crypto.Symbol
+	}
+
+	interval := bs.cfg.KlineInterval
+	step := candleIntervals[interval]
+	maxHold := time.Duration(bs.cfg.SignalMaxHoldHours) * time.Hour
+
+	closed := 0
+	for _, signal := range signals {
+		symbol, exists := symbols[signal.CryptoID]
+		if !exists || (signal.Action != "BUY" && signal.Action != "SELL") {
+			continue
+		}
+
+		klines, err := bs.marketDataSource.GetKlinesSince(symbol, interval, signal.CreatedAt)
+		if err != nil {
+			logrus.Warn("Failed to load candles to track signal ", signal.ID, ": ", err)
+			continue
+		}
+		candles, err := bs.technicalAnalyzer.parseKlineData(klines)
+		if err != nil {
+			logrus.Warn("Failed to parse candles to track signal ", signal.ID, ": ", err)
+			continue
+		}
+
+		exit, ok := resolveSignalExit(signal, candles, step, maxHold)
+		if !ok {
+			continue
+		}
+		if err := bs.closeTrackedSignal(signal, exit); err != nil {
+			logrus.Error("Failed to close tracked signal ", signal.ID, ": ", err)
+			continue
+		}
+		closed++
+	}
+
+	if closed > 0 {
+		logrus.Infof("📈 Performance tracking closed %d signals", closed)
+	}
+	return nil
+}
+
+// closeTrackedSignal marks a signal triggered, or expired when it timed out,
+// and records its performance and learning outcome
+func (bs *BotService) closeTrackedSignal(signal *models.TradingSignal, exit signalExit) error {
+	status := "triggered"
+	if exit.Reason == exitReasonMaxHold || exit.Reason == exitReasonNotEntered {
+		status = "expired"
+	}
+	if err := bs.db.UpdateSignalStatus(signal.ID, status); err != nil {
+		return err
+	}
+
+	duration := int(exit.At.Sub(signal.CreatedAt).Minutes())
+	perf := &models.SignalPerformance{
+		ID:              uuid.New(),
+		SignalID:        signal.ID,
+		EntryPrice:      signal.EntryPrice,
+		ExitPrice:       &exit.Price,
+		EntryTime:       signal.CreatedAt,
+		ExitTime:        &exit.At,
+		DurationMinutes: &duration,
+		HitStopLoss:     exit.Reason == exitReasonStopLoss,
+		HitTakeProfit1:  exit.TargetsHit >= 1,
+		HitTakeProfit2:  exit.TargetsHit >= 2,
+		ExitReason:      exit.Reason,
+	}
+
+	if !exit.Entered {
+		// Never opened, so there is nothing to learn from
+		perf.Outcome = "cancelled"
+		if err := bs.db.CreatePerformanceRecord(perf); err != nil {
+			logrus.Warn("Failed to record expiry of signal ", signal.ID, ": ", err)
+		}
+		return nil
+	}
+
+	pnl := CalculateScaledPnL(signal, exit.Highest, exit.Lowest, exit.Price)
+	perf.PnLPercentage = &pnl
+	perf.HighestPrice = &exit.Highest
+	perf.LowestPrice = &exit.Lowest
+	switch {
+	case pnl.IsPositive():
+		perf.Outcome = "profit"
+	case pnl.IsNegative():
+		perf.Outcome = "loss"
+	default:
+		perf.Outcome = "breakeven"
+	}
+
+	// Most favorable and adverse excursions from entry
+	best, worst := exit.Highest, exit.Lowest
+	if signal.Action == "SELL" {
+		best, worst = exit.Lowest, exit.Highest
+	}
+	maxProfit := excursionPercent(signal, best)
+	maxLoss := excursionPercent(signal, worst)
+	perf.MaxProfitPercentage = &maxProfit
+	perf.MaxLossPercentage = &maxLoss
+
+	if err := bs.db.CreatePerformanceRecord(perf); err != nil {
+		// The signal is already closed; a missing record only affects analytics
+		logrus.Warn("Failed to record performance of signal ", signal.ID, ": ", err)
+	}
+	if err := bs.learningEngine.UpdateLearningDataWithOutcome(signal.ID, perf.Outcome, pnl, duration); err != nil {
+		logrus.Warn("Failed to record learning outcome of signal ", signal.ID, ": ", err)
+	}
+
+	logrus.Infof("Signal %s closed by %s: %s%% (%s)", signal.ID, exit.Reason, pnl.StringFixed(2), perf.Outcome)
+	return nil
+}
+
+// excursionPercent is the PnL percentage of a signal's position at price
+func excursionPercent(signal *models.TradingSignal, price decimal.Decimal) decimal.Decimal {
+	if signal.EntryPrice.IsZero() {
+		return decimal.Zero
+	}
+	change := price.Sub(signal.EntryPrice).Div(signal.EntryPrice).Mul(decimal.NewFromInt(100))
+	if signal.Action == "SELL" {
+		return change.Neg()
+	}
+	return change
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// trackedCandles builds 15m candles from [high, low, close] triples starting at start
+func trackedCandles(start time.Time, hlc ...[3]string) []OHLCV {
+	candles := make([]OHLCV, len(hlc))
+	for i, values := range hlc {
+		candles[i] = OHLCV{
+			Timestamp: start.Add(time.Duration(i) * 15 * time.Minute).UnixMilli(),
+			High:      dec(values[0]),
+			Low:       dec(values[1]),
+			Close:     dec(values[2]),
+		}
+	}
+	return candles
+}
+
+func TestResolveSignalExitScaledPnL(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	buy := func() *models.TradingSignal {
+		return &models.TradingSignal{
+			Action:     "BUY",
+			EntryPrice: dec("100"),
+			StopLoss:   decPtr("95"),
+			CreatedAt:  created,
+			TakeProfits: []models.TakeProfitTarget{
+				{Level: 1, Price: dec("103"), Allocation: dec("0.5")},
+				{Level: 2, Price: dec("106"), Allocation: dec("0.3")},
+				{Level: 3, Price: dec("109"), Allocation: dec("0.2")},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		signal     *models.TradingSignal
+		candles    []OHLCV
+		maxHold    time.Duration
+		wantOpen   bool
+		wantReason string
+		wantHits   int
+		wantPnL    string
+	}{
+		{
+			name:     "still open",
+			signal:   buy(),
+			candles:  trackedCandles(created, [3]string{"102", "99", "101"}),
+			wantOpen: true,
+		},
+		{
+			name:       "all targets",
+			signal:     buy(),
+			candles:    trackedCandles(created, [3]string{"104", "99", "103"}, [3]string{"110", "103", "109"}),
+			wantReason: exitReasonTakeProfit,
+			wantHits:   3,
+			wantPnL:    "5.1", // 0.5*3 + 0.3*6 + 0.2*9
+		},
+		{
+			name:       "first target then stop",
+			signal:     buy(),
+			candles:    trackedCandles(created, [3]string{"104", "99", "103"}, [3]string{"103", "94", "95"}),
+			wantReason: exitReasonStopLoss,
+			wantHits:   1,
+			wantPnL:    "-1", // 0.5*3 - 0.5*5
+		},
+		{
+			name:       "stop and target in one candle counts as stopped",
+			signal:     buy(),
+			candles:    trackedCandles(created, [3]string{"104", "94", "100"}),
+			wantReason: exitReasonStopLoss,
+			wantPnL:    "-5",
+		},
+		{
+			name:       "max hold exits at the close",
+			signal:     buy(),
+			candles:    trackedCandles(created, [3]string{"104", "99", "103"}, [3]string{"104", "101", "102"}),
+			maxHold:    30 * time.Minute,
+			wantReason: exitReasonMaxHold,
+			wantHits:   1,
+			wantPnL:    "2.5", // 0.5*3 + 0.5*2
+		},
+		{
+			name: "sell targets below entry",
+			signal: &models.TradingSignal{
+				Action:      "SELL",
+				EntryPrice:  dec("100"),
+				StopLoss:    decPtr("105"),
+				TakeProfit1: decPtr("97"),
+				CreatedAt:   created,
+			},
+			candles:    trackedCandles(created, [3]string{"101", "96", "97"}),
+			wantReason: exitReasonTakeProfit,
+			wantHits:   1,
+			wantPnL:    "3",
+		},
+		{
+			name: "entry zone never reached",
+			signal: func() *models.TradingSignal {
+				signal := buy()
+				signal.EntryLow, signal.EntryHigh = decPtr("98"), decPtr("99")
+				return signal
+			}(),
+			candles:    trackedCandles(created, [3]string{"104", "100", "103"}),
+			maxHold:    15 * time.Minute,
+			wantReason: exitReasonNotEntered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exit, closed := resolveSignalExit(tt.signal, tt.candles, 15*time.Minute, tt.maxHold)
+			if closed == tt.wantOpen {
+				t.Fatalf("closed = %v, want %v", closed, !tt.wantOpen)
+			}
+			if tt.wantOpen {
+				return
+			}
+			if exit.Reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", exit.Reason, tt.wantReason)
+			}
+			if exit.TargetsHit != tt.wantHits {
+				t.Errorf("targets hit = %d, want %d", exit.TargetsHit, tt.wantHits)
+			}
+			if tt.wantPnL == "" {
+				return
+			}
+			pnl := CalculateScaledPnL(tt.signal, exit.Highest, exit.Lowest, exit.Price)
+			if !pnl.Equal(dec(tt.wantPnL)) {
+				t.Errorf("PnL = %s, want %s", pnl, tt.wantPnL)
+			}
+		})
+	}
+}
+
+func TestCalculateTakeProfitTargetsDropsNonPositiveSellTargets(t *testing.T) {
+	cfg := testConfig()
+	cfg.TakeProfitCount = 3
+	cfg.TakeProfitMode = "absolute"
+	cfg.TakeProfitAllocations = []float64{50, 30, 20}
+	sg := NewSignalGenerator(nil, cfg)
+
+	targets := sg.calculateTakeProfitTargets("SELL", dec("10"), []float64{4, 8, 12})
+	if len(targets) != 2 {
+		t.Fatalf("got %d targets, want 2", len(targets))
+	}
+	if !targets[1].Price.Equal(dec("2")) {
+		t.Errorf("TP2 = %s, want 2", targets[1].Price)
+	}
+	total := targets[0].Allocation.Add(targets[1].Allocation)
+	if !total.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("allocations sum to %s, want 1", total)
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// coinPricePlaces is how many decimals a coin's prices are rounded and shown
+// with: its stored exchange precision, else SIGNAL_PRICE_PRECISION
+func coinPricePlaces(crypto *models.Cryptocurrency, fallback int) int32 {
+	if crypto != nil && crypto.PricePrecision != nil {
+		return int32(*crypto.PricePrecision)
+	}
+	return int32(fallback)
+}
+
+// formatCoinPrice renders a price of a coin at its precision
+func formatCoinPrice(crypto *models.Cryptocurrency, price decimal.Decimal, fallback int) string {
+	return price.StringFixed(coinPricePlaces(crypto, fallback))
+}
+
+// tickSizePlaces returns the decimals of an exchange tick size, e.g. 2 for "0.01000000"
+func tickSizePlaces(tickSize string) (int, error) {
+	tick, err := decimal.NewFromString(tickSize)
+	if err != nil || !tick.IsPositive() {
+		return 0, fmt.Errorf("invalid tick size %q", tickSize)
+	}
+
+	trimmed := strings.TrimRight(tick.String(), "0")
+	dot := strings.IndexByte(trimmed, '.')
+	if dot < 0 {
+		return 0, nil
+	}
+	return len(trimmed) - dot - 1, nil
+}
+
+// GetPricePrecision returns the price decimals of the symbol's USDT pair from
+// the tick size in Binance exchange info
+func (dc *DataCollector) GetPricePrecision(symbol string) (int, error) {
+	url := fmt.Sprintf("https://api.binance.com/api/v3/exchangeInfo?symbol=%sUSDT", symbol)
+
+	resp, err := dc.getWithRetry("binance exchange info", url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return 0, statusError("binance exchange info", resp.StatusCode, fmt.Errorf("binance exchange info API error: %d", resp.StatusCode))
+	}
+
+	var info struct {
+		Symbols []struct {
+			Filters []struct {
+				FilterType string `json:"filterType"`
+				TickSize   string `json:"tickSize"`
+			} `json:"filters"`
+		} `json:"symbols"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return 0, err
+	}
+
+	for _, pair := range info.Symbols {
+		for _, filter := range pair.Filters {
+			if filter.FilterType == "PRICE_FILTER" {
+				return tickSizePlaces(filter.TickSize)
+			}
+		}
+	}
+	return 0, fmt.Errorf("no price filter for %sUSDT", symbol)
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	providerCoinMarketCap = "coinmarketcap"
+	providerCoinGecko     = "coingecko"
+)
+
+// quotaRecheckInterval is how long an exhausted provider is skipped before it is tried again
+const quotaRecheckInterval = time.Hour
+
+// cmcQuotaErrorCodes are CMC status.error_code values for exhausted plan credits
+// (1009 daily, 1010 monthly). 1008 is the per-minute rate limit and stays transient.
+var cmcQuotaErrorCodes = map[int]bool{
+	1009: true,
+	1010: true,
+}
+
+// QuotaExhaustedError marks a provider whose API key ran out of plan credits.
+// Retrying won't help until the quota resets, so it is not transient.
+type QuotaExhaustedError struct {
+	Provider string
+	Err      error
+}
+
+func (e *QuotaExhaustedError) Error() string {
+	return fmt.Sprintf("%s quota exhausted: %v", e.Provider, e.Err)
+}
+
+func (e *QuotaExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// IsQuotaExhausted reports whether err (or anything it wraps) is a quota exhaustion
+func IsQuotaExhausted(err error) bool {
+	var quotaErr *QuotaExhaustedError
+	return errors.As(err, &quotaErr)
+}
+
+// isQuotaResponse recognizes quota exhaustion from a failed provider response
+func isQuotaResponse(provider string, statusCode int, body []byte) bool {
+	if provider == providerCoinMarketCap {
+		var cmcResp CMCQuoteResponse
+		if err := json.Unmarshal(body, &cmcResp); err == nil && cmcQuotaErrorCodes[cmcResp.Status.ErrorCode] {
+			return true
+		}
+	}
+
+	switch statusCode {
+	case http.StatusPaymentRequired:
+		return true
+	case http.StatusTooManyRequests:
+		// Plain rate limits are also 429; only quota wording means the plan ran out
+		message := strings.ToLower(string(body))
+		return strings.Contains(message, "quota") || strings.Contains(message, "credit") || strings.Contains(message, "monthly")
+	default:
+		return false
+	}
+}
+
+// ProviderQuota reports the quota state of a data provider
+type ProviderQuota struct {
+	Provider         string     `json:"provider"`
+	Exhausted        bool       `json:"exhausted"`
+	ExhaustedAt      *time.Time `json:"exhausted_at,omitempty"`
+	CreditsUsed      int        `json:"credits_used,omitempty"`      // Tracked for the current month
+	CreditsRemaining *int       `json:"credits_remaining,omitempty"` // Estimated from CMC_MONTHLY_CREDIT_LIMIT
+	KeysTotal        int        `json:"keys_total"`
+	KeysAvailable    int        `json:"keys_available"` // Keys not currently exhausted or rate limited
+}
+
+// quotaGuard tracks provider quota exhaustion and CMC credit usage, and fires
+// a one-time handler when a provider first runs out
+type quotaGuard struct {
+	mu          sync.Mutex
+	exhausted   map[string]time.Time
+	creditsUsed int
+	creditMonth string
+	creditLimit int
+	onExhausted func(provider string, err error)
+}
+
+func newQuotaGuard(creditLimit int) *quotaGuard {
+	return &quotaGuard{
+		exhausted:   make(map[string]time.Time),
+		creditLimit: creditLimit,
+	}
+}
+
+func (q *quotaGuard) setHandler(handler func(provider string, err error)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.onExhausted = handler
+}
+
+// available reports whether a provider may be called; exhausted providers are
+// skipped until quotaRecheckInterval has passed
+func (q *quotaGuard) available(provider string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	exhaustedAt, exhausted := q.exhausted[provider]
+	return !exhausted || time.Since(exhaustedAt) >= quotaRecheckInterval
+}
+
+// markExhausted records an exhausted provider, notifying only on the first occurrence
+func (q *quotaGuard) markExhausted(provider string, err error) *QuotaExhaustedError {
+	q.mu.Lock()
+	_, alreadyExhausted := q.exhausted[provider]
+	q.exhausted[provider] = time.Now()
+	handler := q.onExhausted
+	q.mu.Unlock()
+
+	quotaErr := &QuotaExhaustedError{Provider: provider, Err: err}
+	if !alreadyExhausted {
+		logrus.Error("API quota exhausted for ", provider, ", switching to fallback: ", err)
+		if handler != nil {
+			handler(provider, err)
+		}
+	}
+
+	return quotaErr
+}
+
+// markHealthy clears the exhausted state after a successful call
+func (q *quotaGuard) markHealthy(provider string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, exhausted := q.exhausted[provider]; exhausted {
+		delete(q.exhausted, provider)
+		logrus.Info("API quota restored for ", provider)
+	}
+}
+
+// addCMCCredits accumulates CMC credit usage, resetting at each calendar month
+func (q *quotaGuard) addCMCCredits(credits int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	month := time.Now().UTC().Format("2006-01")
+	if month != q.creditMonth {
+		q.creditMonth = month
+		q.creditsUsed = 0
+	}
+	q.creditsUsed += credits
+}
+
+func (q *quotaGuard) status() []ProviderQuota {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	statuses := make([]ProviderQuota, 0, 2)
+	for _, provider := range []string{providerCoinMarketCap, providerCoinGecko} {
+		status := ProviderQuota{Provider: provider}
+		if exhaustedAt, exhausted := q.exhausted[provider]; exhausted {
+			at := exhaustedAt
+			status.Exhausted = true
+			status.ExhaustedAt = &at
+		}
+
+		if provider == providerCoinMarketCap {
+			status.CreditsUsed = q.creditsUsed
+			if q.creditLimit > 0 {
+				remaining := q.creditLimit - q.creditsUsed
+				if remaining < 0 {
+					remaining = 0
+				}
+				status.CreditsRemaining = &remaining
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Reasons a BUY/SELL setup is dropped before it becomes a signal
+const (
+	RejectConfidence          = "confidence"
+	RejectConfidenceSmoothing = "confidence_smoothing"
+	RejectDailyLimit          = "daily_limit"
+	RejectInvalidIndicators   = "invalid_indicators"
+	RejectIndicatorConflict   = "indicator_conflict"
+	RejectHTFTrend            = "htf_trend"
+	RejectVolatilityWindow    = "volatility_window"
+	RejectSourceDivergence    = "source_divergence"
+	RejectCycleLimit          = "cycle_limit"
+)
+
+// RejectionReasons lists every reason a rejected signal can carry
+var RejectionReasons = []string{
+	RejectConfidence,
+	RejectConfidenceSmoothing,
+	RejectDailyLimit,
+	RejectInvalidIndicators,
+	RejectIndicatorConflict,
+	RejectHTFTrend,
+	RejectVolatilityWindow,
+	RejectSourceDivergence,
+	RejectCycleLimit,
+}
+
+// ParseRejectionReason validates a reason filter of the rejected signals endpoint
+func ParseRejectionReason(reason string) (string, error) {
+	for _, known := range RejectionReasons {
+		if reason == known {
+			return reason, nil
+		}
+	}
+	return "", fmt.Errorf("reason must be one of %v, got %q", RejectionReasons, reason)
+}
+
+// recordRejection stores a dropped setup when LOG_REJECTED_SIGNALS is on.
+// action is the direction before the filter; decision may be nil when the
+// filter ran before a decision was made. Failures are only logged.
+func (sg *SignalGenerator) recordRejection(marketData *MarketData, crypto *models.Cryptocurrency, decision *SignalDecision, action, reason, details string) {
+	if !sg.cfg.LogRejectedSignals || sg.db == nil {
+		return
+	}
+
+	rejected := &models.RejectedSignal{
+		ID:         uuid.New(),
+		CryptoID:   crypto.ID,
+		Symbol:     crypto.Symbol,
+		Reason:     reason,
+		Details:    details,
+		Action:     action,
+		EntryPrice: marketData.Price,
+		Timeframe:  sg.signalTimeframe(marketData),
+		CreatedAt:  time.Now(),
+	}
+
+	if decision != nil {
+		rejected.ConfidenceScore = decision.Confidence
+		rejected.EntryPrice = decision.EntryPrice
+		rejected.Reasoning = decision.Reasoning
+		rejected.MarketConditions = decision.MarketConditions
+
+		// Withheld setups are HOLD by now and carry no targets
+		if decision.Action == "BUY" || decision.Action == "SELL" {
+			rejected.StopLoss = &decision.StopLoss
+			rejected.TakeProfit1 = &decision.TakeProfit1
+			rejected.TakeProfit2 = &decision.TakeProfit2
+		}
+	}
+
+	if err := sg.db.SaveRejectedSignal(rejected); err != nil {
+		logrus.Warn("Failed to save rejected signal for ", crypto.Symbol, ": ", err)
+	}
+}
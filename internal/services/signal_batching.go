@@ -0,0 +1,86 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// signalCandidate is a built signal of one coin, not yet stored or sent, with
+// what committing it needs
+type signalCandidate struct {
+	signal              *models.TradingSignal
+	decision            *SignalDecision
+	marketData          *MarketData
+	features            *FeatureVector
+	predictedOutcome    string
+	predictedConfidence decimal.Decimal
+}
+
+// rankScore is the confidence weighted by data quality; signals without a
+// quality score count as fully trusted
+func (c *signalCandidate) rankScore() decimal.Decimal {
+	if c.signal.DataQuality == nil {
+		return c.signal.ConfidenceScore
+	}
+	return c.signal.ConfidenceScore.Mul(*c.signal.DataQuality)
+}
+
+// rankCandidates orders candidates strongest first: by rank score, then by
+// confidence, then by symbol so the order is stable between runs
+func rankCandidates(candidates []*signalCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if cmp := a.rankScore().Cmp(b.rankScore()); cmp != 0 {
+			return cmp > 0
+		}
+		if cmp := a.signal.ConfidenceScore.Cmp(b.signal.ConfidenceScore); cmp != 0 {
+			return cmp > 0
+		}
+		return a.signal.Crypto.Symbol < b.signal.Crypto.Symbol
+	})
+}
+
+// commitTopSignals ranks the signals collected in one cycle and commits the
+// best MAX_SIGNALS_PER_CYCLE of them. The rest are logged and recorded as
+// rejected. It returns how many signals were stored.
+func (bs *BotService) commitTopSignals(candidates []*signalCandidate) int {
+	if len(candidates) == 0 {
+		return 0
+	}
+
+	rankCandidates(candidates)
+	limit := bs.cfg.MaxSignalsPerCycle
+	if limit > len(candidates) {
+		limit = len(candidates)
+	}
+	if dropped := len(candidates) - limit; dropped > 0 {
+		logrus.Infof("📊 %d signals this cycle, keeping the top %d (MAX_SIGNALS_PER_CYCLE)", len(candidates), limit)
+	}
+
+	stored := 0
+	for rank, candidate := range candidates {
+		symbol := candidate.signal.Crypto.Symbol
+		if rank >= limit {
+			logrus.Infof("Dropping %s %s signal ranked %d of %d (score %s, confidence %s)",
+				symbol, candidate.signal.Action, rank+1, len(candidates),
+				candidate.rankScore().StringFixed(4), candidate.signal.ConfidenceScore.StringFixed(4))
+			bs.signalGenerator.recordRejection(candidate.marketData, candidate.signal.Crypto, candidate.decision, candidate.signal.Action, RejectCycleLimit,
+				fmt.Sprintf("ranked %d of %d, limit %d (score %s)", rank+1, len(candidates), bs.cfg.MaxSignalsPerCycle, candidate.rankScore().StringFixed(4)))
+			continue
+		}
+
+		committed, err := bs.commitSignal(candidate)
+		if err != nil {
+			logrus.Error("Failed to store ", symbol, " signal: ", err)
+			continue
+		}
+		if committed {
+			stored++
+		}
+	}
+	return stored
+}
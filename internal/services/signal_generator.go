@@ -4,7 +4,11 @@ import (
 	"crypto-signal-bot/internal/config"
 	"crypto-signal-bot/internal/database"
 	"crypto-signal-bot/internal/models"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,8 +17,15 @@ import (
 )
 
 type SignalGenerator struct {
-	db  *database.SupabaseClient
+	db  database.Store
 	cfg *config.Config
+
+	confidenceSmoother *confidenceSmoother // Owned by BotService; nil when smoothing is off
+	dryRun             *dryRunMode         // Owned by BotService; nil never withholds
+	learning           *LearningEngine     // Owned by BotService; nil uses the configured baseline
+
+	profilesMu sync.RWMutex
+	profiles   map[string]*models.StrategyProfile // Per-coin overrides keyed by symbol
 }
 
 type SignalDecision struct {
@@ -22,35 +33,84 @@ type SignalDecision struct {
 	Confidence      decimal.Decimal
 	Reasoning       string
 	EntryPrice      decimal.Decimal
+	EntryLow        *decimal.Decimal // Entry zone bounds; nil without a zone
+	EntryHigh       *decimal.Decimal
 	StopLoss        decimal.Decimal
 	TakeProfit1     decimal.Decimal
 	TakeProfit2     decimal.Decimal
+	TakeProfits     []models.TakeProfitTarget
 	MarketConditions map[string]interface{}
+	Priority        string
+	WithheldAction  string // BUY or SELL turned into HOLD by a filter
+	WithheldReason  string // Rejection reason of the withheld action
 }
 
-func NewSignalGenerator(db *database.SupabaseClient, cfg *config.Config) *SignalGenerator {
+func NewSignalGenerator(db database.Store, cfg *config.Config) *SignalGenerator {
 	return &SignalGenerator{
-		db:  db,
-		cfg: cfg,
+		db:       db,
+		cfg:      cfg,
+		profiles: make(map[string]*models.StrategyProfile),
 	}
 }
 
 func (sg *SignalGenerator) GenerateSignal(marketData *MarketData, indicators *TechnicalIndicators, crypto *models.Cryptocurrency) (*models.TradingSignal, error) {
+	signal, _ := sg.buildSignal(marketData, indicators, crypto)
+	if signal == nil {
+		return nil, nil
+	}
+	return sg.saveSignal(signal)
+}
+
+// buildSignal runs the filters and builds the signal of a coin without
+// storing it; nil when no signal passes. The decision is returned alongside
+// so a signal dropped later can still be recorded as rejected.
+func (sg *SignalGenerator) buildSignal(marketData *MarketData, indicators *TechnicalIndicators, crypto *models.Cryptocurrency) (*models.TradingSignal, *SignalDecision) {
 	logrus.Debug("Generating signal for: ", marketData.Symbol)
 
+	// Zero indicators mean they were never computed; RSI=0 would otherwise read as extreme oversold
+	if issues := invalidIndicators(indicators); len(issues) > 0 {
+		logrus.Warnf("Data issue for %s, skipping signal: %s", marketData.Symbol, strings.Join(issues, ", "))
+		sg.recordRejection(marketData, crypto, nil, "", RejectInvalidIndicators, strings.Join(issues, ", "))
+		return nil, nil
+	}
+
 	// Analyze market conditions and generate decision
 	decision := sg.analyzeMarketConditions(marketData, indicators)
+	if decision.WithheldAction != "" {
+		sg.recordRejection(marketData, crypto, decision, decision.WithheldAction, decision.WithheldReason, "")
+	}
 
 	// Check if confidence meets minimum threshold
-	minConfidence := decimal.NewFromFloat(sg.cfg.MinConfidenceThreshold)
-	if decision.Confidence.LessThan(minConfidence) {
+	minConfidence := decimal.NewFromFloat(sg.settingsFor(marketData.Symbol).MinConfidence)
+	if sg.confidenceSmoother != nil {
+		// Act on the smoothed trend, and only when it first crosses the threshold
+		rawConfidence := decision.Confidence
+		smoothed, fire := sg.confidenceSmoother.update(marketData.Symbol+"/"+sg.signalTimeframe(marketData), decision.Action, rawConfidence, minConfidence)
+		if !fire {
+			logrus.Debug("Smoothed confidence for ", marketData.Symbol, " did not cross threshold: ", smoothed, " (raw ", rawConfidence, ")")
+			if decision.Action == "BUY" || decision.Action == "SELL" {
+				sg.recordRejection(marketData, crypto, decision, decision.Action, RejectConfidenceSmoothing,
+					fmt.Sprintf("smoothed %s (raw %s) did not cross %s", smoothed.StringFixed(4), rawConfidence.StringFixed(4), minConfidence.String()))
+			}
+			return nil, nil
+		}
+		decision.Confidence = smoothed
+		decision.MarketConditions["raw_confidence"] = rawConfidence
+	} else if decision.Confidence.LessThan(minConfidence) {
 		logrus.Debug("Signal confidence below threshold for ", marketData.Symbol, ": ", decision.Confidence)
+		if decision.Action == "BUY" || decision.Action == "SELL" {
+			sg.recordRejection(marketData, crypto, decision, decision.Action, RejectConfidence,
+				fmt.Sprintf("%s below %s", decision.Confidence.StringFixed(4), minConfidence.String()))
+		}
 		return nil, nil // No signal generated
 	}
 
 	// Check daily signal limit
 	if sg.hasReachedDailyLimit() {
 		logrus.Info("Daily signal limit reached, skipping signal generation")
+		if decision.Action == "BUY" || decision.Action == "SELL" {
+			sg.recordRejection(marketData, crypto, decision, decision.Action, RejectDailyLimit, fmt.Sprintf("limit %d", sg.cfg.MaxSignalsPerDay))
+		}
 		return nil, nil
 	}
 
@@ -61,52 +121,134 @@ func (sg *SignalGenerator) GenerateSignal(marketData *MarketData, indicators *Te
 		Action:           decision.Action,
 		ConfidenceScore:  decision.Confidence,
 		EntryPrice:       decision.EntryPrice,
+		EntryLow:         decision.EntryLow,
+		EntryHigh:        decision.EntryHigh,
 		StopLoss:         &decision.StopLoss,
 		TakeProfit1:      &decision.TakeProfit1,
 		TakeProfit2:      &decision.TakeProfit2,
+		TakeProfits:      decision.TakeProfits,
 		Reasoning:        decision.Reasoning,
 		
 		// Technical indicators
-		RSI:              &indicators.RSI,
-		MACDLine:         &indicators.MACDLine,
-		MACDSignal:       &indicators.MACDSignal,
-		MACDHistogram:    &indicators.MACDHistogram,
-		BBUpper:          &indicators.BBUpper,
-		BBMiddle:         &indicators.BBMiddle,
-		BBLower:          &indicators.BBLower,
-		SMA20:            &indicators.SMA20,
-		EMA12:            &indicators.EMA12,
-		EMA26:            &indicators.EMA26,
+		// Unavailable indicators are stored as NULL rather than zero
+		RSI:              indicators.availableValue(indicatorRSI, &indicators.RSI),
+		MACDLine:         indicators.availableValue(indicatorMACD, &indicators.MACDLine),
+		MACDSignal:       indicators.availableValue(indicatorMACD, &indicators.MACDSignal),
+		MACDHistogram:    indicators.availableValue(indicatorMACD, &indicators.MACDHistogram),
+		BBUpper:          indicators.availableValue(indicatorBollinger, &indicators.BBUpper),
+		BBMiddle:         indicators.availableValue(indicatorBollinger, &indicators.BBMiddle),
+		BBLower:          indicators.availableValue(indicatorBollinger, &indicators.BBLower),
+		SMA20:            indicators.availableValue(indicatorTrend, &indicators.SMA20),
+		EMA12:            indicators.availableValue(indicatorTrend, &indicators.EMA12),
+		EMA26:            indicators.availableValue(indicatorTrend, &indicators.EMA26),
 		Volume24h:        &marketData.Volume24h,
 		PriceChange24h:   &marketData.PriceChange24h,
 		
 		// Market sentiment
-		FearGreedIndex:   &marketData.FearGreedIndex,
+		FearGreedIndex:   fearGreedReading(marketData),
 		MarketCap:        &marketData.MarketCap,
 		
 		// Additional context
 		MarketConditions: decision.MarketConditions,
-		Timeframe:        "15m",
+		Timeframe:        sg.signalTimeframe(marketData),
 		CreatedAt:        time.Now(),
 		Status:           "active",
+		Source:           SignalSourceInternal,
+		Priority:         decision.Priority,
+		Context:          indicators.Context,
 		
 		// Related data
 		Crypto:           crypto,
 	}
 
-	// Save signal to database
-	if err := sg.db.CreateSignal(signal); err != nil {
+	signal.RefCode = sg.generateRefCode(crypto.Symbol, signal.CreatedAt)
+
+	// Score how far the inputs can be trusted
+	quality, qualityIssues := dataQualityScore(marketData, sg.cfg.KlineSources, signal.CreatedAt)
+	signal.DataQuality = &quality
+	if len(qualityIssues) > 0 && signal.MarketConditions != nil {
+		signal.MarketConditions["data_quality_issues"] = qualityIssues
+	}
+
+	// Keep recent closes for the message sparkline
+	if sg.cfg.SparklineEnabled && sg.cfg.SparklinePoints > 0 {
+		history := indicators.CloseHistory
+		if len(history) > sg.cfg.SparklinePoints {
+			history = history[len(history)-sg.cfg.SparklinePoints:]
+		}
+		signal.PriceHistory = history
+	}
+
+	// Round stored values; decision math above keeps full precision
+	sg.roundSignalValues(signal)
+
+	return signal, decision
+}
+
+// saveSignal stores a built signal together with its pending notification.
+// In dry run the signal is only logged and nil is returned.
+func (sg *SignalGenerator) saveSignal(signal *models.TradingSignal) (*models.TradingSignal, error) {
+	// Dry run: the signal is only logged, never stored or sent
+	if sg.dryRun != nil && sg.dryRun.withhold() {
+		logrus.Info("🧪 [DRY RUN] ", signal.Action, " signal for ", signal.Crypto.Symbol, " with confidence ", signal.ConfidenceScore,
+			" (entry ", signal.EntryPrice, ") not stored or sent")
+		return nil, nil
+	}
+
+	// Save signal to database, together with its pending notification
+	if err := sg.db.CreateSignal(signal, newOutboxEntry(signal)); err != nil {
 		logrus.Error("Failed to save signal to database: ", err)
 		return nil, err
 	}
 
-	logrus.Info("✅ Generated ", decision.Action, " signal for ", marketData.Symbol, " with confidence: ", decision.Confidence)
+	logrus.Info("✅ Generated ", signal.Action, " signal for ", signal.Crypto.Symbol, " with confidence: ", signal.ConfidenceScore, " (", signal.Priority, " priority)")
 	return signal, nil
 }
 
+// fearGreedReading returns the Fear & Greed index of a signal, or nil when it
+// is only the neutral default used after every source failed
+func fearGreedReading(marketData *MarketData) *int {
+	if !marketData.FearGreedAvailable {
+		return nil
+	}
+	value := marketData.FearGreedIndex
+	return &value
+}
+
+// invalidIndicators lists computed key indicators that still came out zero,
+// plus Bollinger Bands collapsed to a single flat line. Indicators marked
+// unavailable are skipped by the decision instead. MACD is excluded since a
+// zero histogram is a genuine reading.
+func invalidIndicators(indicators *TechnicalIndicators) []string {
+	var issues []string
+	keyIndicators := []struct {
+		indicator string
+		name      string
+		value     decimal.Decimal
+	}{
+		{indicatorRSI, "RSI", indicators.RSI},
+		{indicatorTrend, "SMA20", indicators.SMA20},
+		{indicatorTrend, "EMA12", indicators.EMA12},
+		{indicatorTrend, "EMA26", indicators.EMA26},
+		{indicatorBollinger, "BB middle", indicators.BBMiddle},
+	}
+	for _, key := range keyIndicators {
+		if indicators.available(key.indicator) && key.value.IsZero() {
+			issues = append(issues, key.name+" is zero")
+		}
+	}
+
+	if indicators.available(indicatorBollinger) && !indicators.BBMiddle.IsZero() && indicators.BBUpper.Equal(indicators.BBLower) {
+		issues = append(issues, "Bollinger Bands are flat")
+	}
+
+	return issues
+}
+
 func (sg *SignalGenerator) analyzeMarketConditions(marketData *MarketData, indicators *TechnicalIndicators) *SignalDecision {
 	var signals []string
 	var confidenceFactors []decimal.Decimal
+	var voters []string // Indicator behind each entry of signals
 	var reasoning []string
 
 	currentPrice := marketData.Price
@@ -118,40 +260,68 @@ func (sg *SignalGenerator) analyzeMarketConditions(marketData *MarketData, indic
 	bbLower := indicators.BBLower
 	_ = indicators.BBMiddle // Bollinger Bands middle line (not used in current logic)
 	fearGreed := decimal.NewFromInt(int64(marketData.FearGreedIndex))
+	settings := sg.settingsFor(marketData.Symbol)
+	weights := sg.learnedParams().Weights
+
+	// Indicators without enough candles are skipped rather than read as zero
+	var skipped []string
+	for _, name := range strategyIndicators {
+		if required, missing := indicators.Unavailable[name]; missing && settings.indicatorEnabled(name) {
+			skipped = append(skipped, fmt.Sprintf("%s (needs %d candles)", name, required))
+		}
+	}
+	if len(skipped) > 0 {
+		reasoning = append(reasoning, "Skipped for lack of data: "+strings.Join(skipped, ", "))
+	}
+	useIndicator := func(name string) bool {
+		return settings.indicatorEnabled(name) && indicators.available(name)
+	}
+
+	// The neutral default after every source failed is not a reading
+	useFearGreed := useIndicator("fear_greed") && (marketData.FearGreedAvailable || !sg.cfg.FearGreedRequireLive)
+	if useIndicator("fear_greed") && !useFearGreed {
+		reasoning = append(reasoning, "Fear & Greed unavailable, factor skipped")
+	}
 
 	// RSI Analysis
-	rsiOversold := decimal.NewFromFloat(sg.cfg.RSIOversoldThreshold)
-	rsiOverbought := decimal.NewFromFloat(sg.cfg.RSIOverboughtThreshold)
+	rsiOversold := decimal.NewFromFloat(settings.RSIOversold)
+	rsiOverbought := decimal.NewFromFloat(settings.RSIOverbought)
 
-	if rsi.LessThan(rsiOversold) {
+	if useIndicator("rsi") && rsi.LessThan(rsiOversold) {
 		signals = append(signals, "BUY")
-		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(0.3))
+		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(weights[indicatorRSI]))
+		voters = append(voters, indicatorRSI)
 		reasoning = append(reasoning, fmt.Sprintf("RSI oversold (%.2f)", rsi.InexactFloat64()))
-	} else if rsi.GreaterThan(rsiOverbought) {
+	} else if useIndicator("rsi") && rsi.GreaterThan(rsiOverbought) {
 		signals = append(signals, "SELL")
-		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(0.3))
+		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(weights[indicatorRSI]))
+		voters = append(voters, indicatorRSI)
 		reasoning = append(reasoning, fmt.Sprintf("RSI overbought (%.2f)", rsi.InexactFloat64()))
 	}
 
 	// MACD Analysis
-	if macdLine.GreaterThan(macdSignal) && macdHistogram.GreaterThan(decimal.Zero) {
+	if useIndicator("macd") && macdLine.GreaterThan(macdSignal) && macdHistogram.GreaterThan(decimal.Zero) {
 		signals = append(signals, "BUY")
-		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(0.25))
+		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(weights[indicatorMACD]))
+		voters = append(voters, indicatorMACD)
 		reasoning = append(reasoning, "MACD bullish crossover")
-	} else if macdLine.LessThan(macdSignal) && macdHistogram.LessThan(decimal.Zero) {
+	} else if useIndicator("macd") && macdLine.LessThan(macdSignal) && macdHistogram.LessThan(decimal.Zero) {
 		signals = append(signals, "SELL")
-		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(0.25))
+		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(weights[indicatorMACD]))
+		voters = append(voters, indicatorMACD)
 		reasoning = append(reasoning, "MACD bearish crossover")
 	}
 
 	// Bollinger Bands Analysis
-	if currentPrice.LessThan(bbLower) {
+	if useIndicator("bollinger") && currentPrice.LessThan(bbLower) {
 		signals = append(signals, "BUY")
-		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(0.2))
+		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(weights[indicatorBollinger]))
+		voters = append(voters, indicatorBollinger)
 		reasoning = append(reasoning, "Price below lower Bollinger Band")
-	} else if currentPrice.GreaterThan(bbUpper) {
+	} else if useIndicator("bollinger") && currentPrice.GreaterThan(bbUpper) {
 		signals = append(signals, "SELL")
-		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(0.2))
+		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(weights[indicatorBollinger]))
+		voters = append(voters, indicatorBollinger)
 		reasoning = append(reasoning, "Price above upper Bollinger Band")
 	}
 
@@ -159,37 +329,56 @@ func (sg *SignalGenerator) analyzeMarketConditions(marketData *MarketData, indic
 	fearGreedMin := decimal.NewFromInt(int64(sg.cfg.FearGreedMinThreshold))
 	fearGreedMax := decimal.NewFromInt(int64(sg.cfg.FearGreedMaxThreshold))
 
-	if fearGreed.LessThan(fearGreedMin) {
+	if useFearGreed && fearGreed.LessThan(fearGreedMin) {
 		signals = append(signals, "BUY")
-		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(0.15))
+		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(weights["fear_greed"]))
+		voters = append(voters, "fear_greed")
 		reasoning = append(reasoning, fmt.Sprintf("Extreme fear in market (%d)", marketData.FearGreedIndex))
-	} else if fearGreed.GreaterThan(fearGreedMax) {
+	} else if useFearGreed && fearGreed.GreaterThan(fearGreedMax) {
 		signals = append(signals, "SELL")
-		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(0.15))
+		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(weights["fear_greed"]))
+		voters = append(voters, "fear_greed")
 		reasoning = append(reasoning, fmt.Sprintf("Extreme greed in market (%d)", marketData.FearGreedIndex))
 	}
 
 	// Price Action Analysis
-	if currentPrice.GreaterThan(indicators.SMA20) && indicators.EMA12.GreaterThan(indicators.EMA26) {
+	if useIndicator("trend") && currentPrice.GreaterThan(indicators.SMA20) && indicators.EMA12.GreaterThan(indicators.EMA26) {
 		signals = append(signals, "BUY")
-		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(0.1))
+		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(weights[indicatorTrend]))
+		voters = append(voters, indicatorTrend)
 		reasoning = append(reasoning, "Price above SMA20 with bullish EMA crossover")
-	} else if currentPrice.LessThan(indicators.SMA20) && indicators.EMA12.LessThan(indicators.EMA26) {
+	} else if useIndicator("trend") && currentPrice.LessThan(indicators.SMA20) && indicators.EMA12.LessThan(indicators.EMA26) {
 		signals = append(signals, "SELL")
-		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(0.1))
+		confidenceFactors = append(confidenceFactors, decimal.NewFromFloat(weights[indicatorTrend]))
+		voters = append(voters, indicatorTrend)
 		reasoning = append(reasoning, "Price below SMA20 with bearish EMA crossover")
 	}
 
+	// Weight of the indicators able to vote this cycle, the most a direction can gather
+	activeWeight := decimal.Zero
+	for _, name := range []string{indicatorRSI, indicatorMACD, indicatorBollinger, indicatorTrend} {
+		if useIndicator(name) {
+			activeWeight = activeWeight.Add(decimal.NewFromFloat(weights[name]))
+		}
+	}
+	if useFearGreed {
+		activeWeight = activeWeight.Add(decimal.NewFromFloat(weights["fear_greed"]))
+	}
+
 	// Determine final signal
 	buySignals := 0
 	sellSignals := 0
 	totalConfidence := decimal.Zero
+	buyConfidence := decimal.Zero
+	sellConfidence := decimal.Zero
 
 	for i, signal := range signals {
 		if signal == "BUY" {
 			buySignals++
+			buyConfidence = buyConfidence.Add(confidenceFactors[i])
 		} else if signal == "SELL" {
 			sellSignals++
+			sellConfidence = sellConfidence.Add(confidenceFactors[i])
 		}
 		totalConfidence = totalConfidence.Add(confidenceFactors[i])
 	}
@@ -197,40 +386,250 @@ func (sg *SignalGenerator) analyzeMarketConditions(marketData *MarketData, indic
 	// Decision logic
 	var action string
 	var confidence decimal.Decimal
+	var withheldAction, withheldReason string
 
 	if buySignals > sellSignals {
 		action = "BUY"
-		confidence = totalConfidence.Mul(decimal.NewFromFloat(float64(buySignals) / float64(len(signals))))
+		confidence = sg.normalizeConfidence(totalConfidence, buyConfidence, activeWeight, buySignals, len(signals))
 	} else if sellSignals > buySignals {
 		action = "SELL"
-		confidence = totalConfidence.Mul(decimal.NewFromFloat(float64(sellSignals) / float64(len(signals))))
+		confidence = sg.normalizeConfidence(totalConfidence, sellConfidence, activeWeight, sellSignals, len(signals))
 	} else {
 		action = "HOLD"
 		confidence = decimal.NewFromFloat(0.1) // Low confidence for hold
 	}
 
+	// Mixed pictures (e.g. RSI oversold but price above the upper band) call for caution
+	conflict, conflictRatio := sg.indicatorConflict(buyConfidence, sellConfidence, buySignals, sellSignals)
+	if conflict && (action == "BUY" || action == "SELL") {
+		reasoning = append(reasoning, fmt.Sprintf("Conflicting indicators: %d buy vs %d sell (weight ratio %.2f)", buySignals, sellSignals, conflictRatio))
+		if sg.cfg.ConflictMode == "hold" {
+			reasoning = append(reasoning, fmt.Sprintf("%s withheld due to conflicting indicators", action))
+			withheldAction, withheldReason = action, RejectIndicatorConflict
+			action = "HOLD"
+			confidence = decimal.Zero
+		} else {
+			confidence = confidence.Mul(decimal.NewFromFloat(sg.cfg.ConflictPenalty))
+			reasoning = append(reasoning, "Confidence reduced: conflicting indicators")
+		}
+	}
+
+	// Confirm direction against the higher-timeframe trend. "require" mode also
+	// needs the analyzed timeframe's own trend to point the same way, and
+	// withholds live signals whose higher-timeframe klines couldn't be fetched.
+	// Backtest replays have no higher-timeframe data and skip the check.
+	var confirmedTimeframes []string
+	if sg.cfg.HTFConfirmationEnabled && sg.cfg.HTFConflictMode == "require" && indicators.HTFTrend == "" &&
+		marketData.AsOf.IsZero() && (action == "BUY" || action == "SELL") {
+		logrus.Warnf("%s %s withheld: %s trend unavailable for confirmation", marketData.Symbol, action, sg.cfg.HTFInterval)
+		reasoning = append(reasoning, fmt.Sprintf("%s withheld: %s trend unavailable", action, sg.cfg.HTFInterval))
+		withheldAction, withheldReason = action, RejectHTFTrend
+		action = "HOLD"
+		confidence = decimal.Zero
+	}
+	if sg.cfg.HTFConfirmationEnabled && indicators.HTFTrend != "" && (action == "BUY" || action == "SELL") {
+		baseInterval := sg.signalTimeframe(marketData)
+		wanted := "bullish"
+		if action == "SELL" {
+			wanted = "bearish"
+		}
+		if indicators.BaseTrend == wanted {
+			confirmedTimeframes = append(confirmedTimeframes, baseInterval)
+		}
+		if indicators.HTFTrend == wanted {
+			confirmedTimeframes = append(confirmedTimeframes, sg.cfg.HTFInterval)
+		}
+
+		reasoning = append(reasoning, fmt.Sprintf("%s trend %s", sg.cfg.HTFInterval, indicators.HTFTrend))
+		if sg.cfg.HTFConflictMode == "require" && indicators.BaseTrend != "" {
+			reasoning = append(reasoning, fmt.Sprintf("%s trend %s", baseInterval, indicators.BaseTrend))
+		}
+
+		conflicting := (action == "BUY" && indicators.HTFTrend == "bearish") ||
+			(action == "SELL" && indicators.HTFTrend == "bullish")
+		switch {
+		case sg.cfg.HTFConflictMode == "require" && len(confirmedTimeframes) < 2:
+			reasoning = append(reasoning, fmt.Sprintf("%s withheld: %s and %s trends not both %s", action, baseInterval, sg.cfg.HTFInterval, wanted))
+			withheldAction, withheldReason = action, RejectHTFTrend
+			action = "HOLD"
+			confidence = decimal.Zero
+		case conflicting && sg.cfg.HTFConflictMode == "suppress":
+			reasoning = append(reasoning, fmt.Sprintf("%s suppressed by %s trend", action, sg.cfg.HTFInterval))
+			withheldAction, withheldReason = action, RejectHTFTrend
+			action = "HOLD"
+			confidence = decimal.Zero
+		case conflicting:
+			confidence = confidence.Mul(decimal.NewFromFloat(sg.cfg.HTFConfidencePenalty))
+			reasoning = append(reasoning, fmt.Sprintf("Confidence reduced: counter to %s trend", sg.cfg.HTFInterval))
+		case len(confirmedTimeframes) == 2 && sg.cfg.HTFAlignmentBonus > 0:
+			confidence = decimal.Min(confidence.Add(decimal.NewFromFloat(sg.cfg.HTFAlignmentBonus)), decimal.NewFromInt(1))
+			reasoning = append(reasoning, fmt.Sprintf("%s and %s trends aligned: confidence +%.0f%%", baseInterval, sg.cfg.HTFInterval, sg.cfg.HTFAlignmentBonus*100))
+		}
+	}
+
+	// Favor trades with the daily trend and discount counter-trend ones
+	if sg.cfg.DailyTrendEnabled && indicators.DailyTrend != "" && (action == "BUY" || action == "SELL") {
+		aligned := (action == "BUY" && indicators.DailyTrend == "bullish") ||
+			(action == "SELL" && indicators.DailyTrend == "bearish")
+		countertrend := (action == "BUY" && indicators.DailyTrend == "bearish") ||
+			(action == "SELL" && indicators.DailyTrend == "bullish")
+
+		switch {
+		case aligned:
+			confidence = decimal.Min(confidence.Add(decimal.NewFromFloat(sg.cfg.DailyTrendBonus)), decimal.NewFromInt(1))
+			reasoning = append(reasoning, fmt.Sprintf("Daily trend %s: confidence +%.0f%%", indicators.DailyTrend, sg.cfg.DailyTrendBonus*100))
+		case countertrend:
+			confidence = decimal.Max(confidence.Sub(decimal.NewFromFloat(sg.cfg.DailyTrendPenalty)), decimal.Zero)
+			reasoning = append(reasoning, fmt.Sprintf("Daily trend %s: confidence -%.0f%% (counter-trend)", indicators.DailyTrend, sg.cfg.DailyTrendPenalty*100))
+		default:
+			reasoning = append(reasoning, "Daily trend neutral")
+		}
+	}
+
+	// Trust signals more when the providers agree on the price, less when they diverge
+	if sg.cfg.SourceAgreementEnabled && marketData.SourceAgreement != nil && (action == "BUY" || action == "SELL") {
+		agreement := marketData.SourceAgreement
+		switch sg.sourceAgreementLevel(agreement) {
+		case "agree":
+			confidence = decimal.Min(confidence.Add(decimal.NewFromFloat(sg.cfg.SourceAgreementBonus)), decimal.NewFromInt(1))
+			reasoning = append(reasoning, fmt.Sprintf("Sources agree (%s, spread %.2f%%): confidence +%.0f%%",
+				describeSources(agreement), agreement.PriceSpreadPercent, sg.cfg.SourceAgreementBonus*100))
+		case "diverge":
+			if sg.cfg.SourceDivergenceMode == "suppress" {
+				reasoning = append(reasoning, fmt.Sprintf("%s suppressed: sources diverge (%s, spread %.2f%%)",
+					action, describeSources(agreement), agreement.PriceSpreadPercent))
+				withheldAction, withheldReason = action, RejectSourceDivergence
+				action = "HOLD"
+				confidence = decimal.Zero
+			} else {
+				confidence = decimal.Max(confidence.Sub(decimal.NewFromFloat(sg.cfg.SourceDivergencePenalty)), decimal.Zero)
+				reasoning = append(reasoning, fmt.Sprintf("Sources diverge (%s, spread %.2f%%): confidence -%.0f%%",
+					describeSources(agreement), agreement.PriceSpreadPercent, sg.cfg.SourceDivergencePenalty*100))
+			}
+		default:
+			reasoning = append(reasoning, fmt.Sprintf("Sources %s within %.2f%%", describeSources(agreement), agreement.PriceSpreadPercent))
+		}
+	}
+
+	// Avoid the whipsaw right after the daily reset and session opens
+	analyzedAt := marketData.AsOf
+	if analyzedAt.IsZero() {
+		analyzedAt = time.Now()
+	}
+	volatilityWindow, inVolatilityWindow := sg.volatilityWindow(analyzedAt)
+	if inVolatilityWindow && (action == "BUY" || action == "SELL") {
+		if sg.cfg.VolatilityWindowMode == "reduce" {
+			confidence = confidence.Mul(decimal.NewFromFloat(sg.cfg.VolatilityWindowPenalty))
+			reasoning = append(reasoning, fmt.Sprintf("Confidence reduced: within %d min of %s UTC open", sg.cfg.VolatilityWindowMinutes, volatilityWindow))
+		} else {
+			logrus.Infof("Skipping %s %s signal: within %d min of %s UTC open", action, marketData.Symbol, sg.cfg.VolatilityWindowMinutes, volatilityWindow)
+			reasoning = append(reasoning, fmt.Sprintf("%s skipped: within %d min of %s UTC open", action, sg.cfg.VolatilityWindowMinutes, volatilityWindow))
+			withheldAction, withheldReason = action, RejectVolatilityWindow
+			action = "HOLD"
+			confidence = decimal.Zero
+		}
+	}
+
 	// Calculate price targets
-	stopLossPercent := decimal.NewFromFloat(sg.cfg.StopLossPercentage / 100)
-	takeProfit1Percent := decimal.NewFromFloat(sg.cfg.TakeProfit1Percentage / 100)
-	takeProfit2Percent := decimal.NewFromFloat(sg.cfg.TakeProfit2Percentage / 100)
+	stopLossPercent := decimal.NewFromFloat(settings.stopLossFor(action) / 100)
 
 	var stopLoss, takeProfit1, takeProfit2 decimal.Decimal
 
 	if action == "BUY" {
 		stopLoss = currentPrice.Mul(decimal.NewFromInt(1).Sub(stopLossPercent))
-		takeProfit1 = currentPrice.Mul(decimal.NewFromInt(1).Add(takeProfit1Percent))
-		takeProfit2 = currentPrice.Mul(decimal.NewFromInt(1).Add(takeProfit2Percent))
 	} else if action == "SELL" {
 		stopLoss = currentPrice.Mul(decimal.NewFromInt(1).Add(stopLossPercent))
-		takeProfit1 = currentPrice.Mul(decimal.NewFromInt(1).Sub(takeProfit1Percent))
-		takeProfit2 = currentPrice.Mul(decimal.NewFromInt(1).Sub(takeProfit2Percent))
+	}
+
+	takeProfits := sg.calculateTakeProfitTargets(action, currentPrice, settings.takeProfitLevelsFor(action))
+
+	stopLossSource := "percent"
+	takeProfitSource := "percent"
+
+	// Volatility-scaled SL/TP; percentages stay when there are too few candles for ATR
+	if sg.cfg.UseATRStops && (action == "BUY" || action == "SELL") {
+		if atrStop, ok := sg.atrStopLoss(action, currentPrice, indicators.ATR); ok {
+			stopLoss = atrStop
+			stopLossSource = "atr"
+		}
+		if sg.atrTakeProfits(action, currentPrice, indicators.ATR, takeProfits) {
+			takeProfitSource = "atr"
+		}
+		if stopLossSource == "atr" || takeProfitSource == "atr" {
+			reasoning = append(reasoning, fmt.Sprintf("SL/TP from ATR(14) %s", indicators.ATR.StringFixed(4)))
+		} else {
+			reasoning = append(reasoning, "ATR unavailable, using percentage SL/TP")
+		}
+	}
+
+	// Place SL/TP around high-volume nodes when the volume profile is enabled
+	if sg.cfg.UseVolumeProfile && len(indicators.VolumeNodes) > 0 && (action == "BUY" || action == "SELL") {
+		if snapped, ok := sg.snapStopLossToVolumeNode(action, currentPrice, indicators.VolumeNodes); ok {
+			stopLoss = snapped
+			stopLossSource = "volume_profile"
+		}
+		sg.snapTakeProfitsToVolumeNodes(action, currentPrice, indicators.VolumeNodes, takeProfits)
+		takeProfitSource = "volume_profile"
+		reasoning = append(reasoning, fmt.Sprintf("Volume profile S/R levels: %s", formatPriceLevels(indicators.VolumeNodes)))
+	}
+
+	// Structure-aware SL/TP from recent swing pivots; earlier levels remain the fallback
+	if sg.cfg.UseSwingLevels && (action == "BUY" || action == "SELL") {
+		if snapped, level, ok := sg.swingStopLoss(action, currentPrice, indicators.SwingHighs, indicators.SwingLows); ok {
+			stopLoss = snapped
+			stopLossSource = "swing"
+			reasoning = append(reasoning, fmt.Sprintf("Stop loss %s beyond swing level %s", snapped.StringFixed(4), level.StringFixed(4)))
+		} else {
+			reasoning = append(reasoning, fmt.Sprintf("No clear swing level for stop loss, using %s", stopLossSource))
+		}
+
+		if target, level, ok := sg.swingTakeProfit(action, currentPrice, indicators.SwingHighs, indicators.SwingLows, takeProfits); ok {
+			takeProfits[0].Price = target
+			takeProfitSource = "swing"
+			reasoning = append(reasoning, fmt.Sprintf("TP1 %s at swing level %s", target.StringFixed(4), level.StringFixed(4)))
+		} else {
+			reasoning = append(reasoning, fmt.Sprintf("No clear swing level for TP1, using %s", takeProfitSource))
+		}
+	}
+
+	// Fibonacci extension targets and retracement stop of the recent swing range
+	if sg.cfg.UseFibTargets && (action == "BUY" || action == "SELL") {
+		if swing := indicators.FibSwing; swing == nil {
+			reasoning = append(reasoning, fmt.Sprintf("No clear swing for Fibonacci levels, using %s SL and %s TP", stopLossSource, takeProfitSource))
+		} else {
+			reasoning = append(reasoning, "Fibonacci swing "+swing.describe())
+
+			if placed, used, ok := sg.fibTakeProfits(action, currentPrice, swing, takeProfits); ok {
+				takeProfits = placed
+				takeProfitSource = "fibonacci"
+				reasoning = append(reasoning, "TP at Fib extensions "+strings.Join(used, ", "))
+			} else {
+				reasoning = append(reasoning, fmt.Sprintf("No Fib extension beyond entry, using %s TP", takeProfitSource))
+			}
+
+			if stop, level, ok := sg.fibStopLoss(action, currentPrice, swing); ok {
+				stopLoss = stop
+				stopLossSource = "fibonacci"
+				reasoning = append(reasoning, fmt.Sprintf("Stop loss %s beyond %g retracement %s", stop.StringFixed(4), sg.cfg.FibStopRetracement, level.StringFixed(4)))
+			} else {
+				side := "below"
+				if action == "SELL" {
+					side = "above"
+				}
+				reasoning = append(reasoning, fmt.Sprintf("%g retracement %s not %s entry, using %s SL", sg.cfg.FibStopRetracement, level.StringFixed(4), side, stopLossSource))
+			}
+		}
+	}
+
+	if len(takeProfits) > 0 {
+		takeProfit1 = takeProfits[0].Price
+	}
+	if len(takeProfits) > 1 {
+		takeProfit2 = takeProfits[1].Price
 	}
 
 	// Market conditions context
 	marketConditions := map[string]interface{}{
-		"rsi":                rsi.InexactFloat64(),
-		"macd_histogram":     macdHistogram.InexactFloat64(),
-		"bb_position":        sg.calculateBBPosition(currentPrice, bbUpper, bbLower),
 		"fear_greed_index":   marketData.FearGreedIndex,
 		"price_change_24h":   marketData.PriceChange24h.InexactFloat64(),
 		"volume_24h":         marketData.Volume24h.InexactFloat64(),
@@ -238,17 +637,537 @@ func (sg *SignalGenerator) analyzeMarketConditions(marketData *MarketData, indic
 		"sell_signals":       sellSignals,
 		"total_signals":      len(signals),
 	}
+	if indicators.available(indicatorRSI) {
+		marketConditions["rsi"] = rsi.InexactFloat64()
+	}
+	if indicators.available(indicatorMACD) {
+		marketConditions["macd_histogram"] = macdHistogram.InexactFloat64()
+	}
+	if indicators.available(indicatorBollinger) {
+		marketConditions["bb_position"] = sg.calculateBBPosition(currentPrice, bbUpper, bbLower)
+	}
+	if len(indicators.Unavailable) > 0 {
+		marketConditions["unavailable_indicators"] = indicators.Unavailable
+	}
+	if marketData.FearGreedSource != "" {
+		marketConditions["fear_greed_source"] = marketData.FearGreedSource
+	} else {
+		marketConditions["fear_greed_default"] = true
+	}
+	if indicators.BaseTrend != "" {
+		marketConditions["base_trend"] = indicators.BaseTrend
+	}
+	if indicators.HTFTrend != "" {
+		marketConditions["htf_trend"] = indicators.HTFTrend
+	}
+	if len(confirmedTimeframes) > 0 {
+		marketConditions["confirmed_timeframes"] = confirmedTimeframes
+	}
+	if indicators.DailyTrend != "" {
+		marketConditions["daily_trend"] = indicators.DailyTrend
+	}
+	if inVolatilityWindow {
+		marketConditions["volatility_window"] = volatilityWindow
+	}
+	if agreement := marketData.SourceAgreement; agreement != nil {
+		marketConditions["source_price_spread_percent"] = agreement.PriceSpreadPercent
+		marketConditions["price_sources"] = agreement.Sources
+	}
+	if conflict {
+		marketConditions["indicator_conflict_ratio"] = conflictRatio
+	}
+	if action == "BUY" || action == "SELL" {
+		marketConditions["stop_loss_source"] = stopLossSource
+		marketConditions["take_profit_source"] = takeProfitSource
+
+		var agreeing []string
+		for i, signal := range signals {
+			if signal == action {
+				agreeing = append(agreeing, voters[i])
+			}
+		}
+		marketConditions["agreeing_indicators"] = agreeing
+	}
+	if len(takeProfits) > 0 {
+		marketConditions["take_profits"] = takeProfits
+	}
+	if len(indicators.VolumeNodes) > 0 {
+		marketConditions["volume_nodes"] = indicators.VolumeNodes
+	}
+	if swing := indicators.FibSwing; swing != nil && (takeProfitSource == "fibonacci" || stopLossSource == "fibonacci") {
+		marketConditions["fib_swing_high"] = swing.High
+		marketConditions["fib_swing_low"] = swing.Low
+	}
+
+	var entryLow, entryHigh *decimal.Decimal
+	if low, high, ok := sg.entryZone(action, currentPrice, indicators.ATR); ok {
+		entryLow, entryHigh = &low, &high
+		marketConditions["entry_zone_mode"] = sg.cfg.EntryZoneMode
+	}
+
+	agreeing := buySignals
+	if action == "SELL" {
+		agreeing = sellSignals
+	}
+	priority := sg.classifyPriority(confidence, agreeing, len(signals))
 
 	return &SignalDecision{
 		Action:           action,
 		Confidence:       confidence,
 		Reasoning:        fmt.Sprintf("%s", reasoning),
 		EntryPrice:       currentPrice,
+		EntryLow:         entryLow,
+		EntryHigh:        entryHigh,
 		StopLoss:         stopLoss,
 		TakeProfit1:      takeProfit1,
 		TakeProfit2:      takeProfit2,
+		TakeProfits:      takeProfits,
 		MarketConditions: marketConditions,
+		Priority:         priority,
+		WithheldAction:   withheldAction,
+		WithheldReason:   withheldReason,
+	}
+}
+
+// indicatorConflict reports whether buy and sell indicators are both present
+// and the minority side's weight is at least ConflictRatio of the majority's
+func (sg *SignalGenerator) indicatorConflict(buyWeight, sellWeight decimal.Decimal, buySignals, sellSignals int) (bool, float64) {
+	if sg.cfg.ConflictMode == "off" || buySignals == 0 || sellSignals == 0 {
+		return false, 0
+	}
+
+	majority, minority := buyWeight, sellWeight
+	if sellWeight.GreaterThan(buyWeight) {
+		majority, minority = sellWeight, buyWeight
+	}
+	if !majority.IsPositive() {
+		return false, 0
+	}
+
+	ratio := minority.Div(majority).InexactFloat64()
+	return ratio >= sg.cfg.ConflictRatio, ratio
+}
+
+// maxConfluenceWeight is the sum of all indicator weights (RSI, MACD, BB, F&G,
+// trend); optimization rescales adjusted weights to keep it
+const maxConfluenceWeight = 1.0
+
+// normalizeConfidence maps the indicator weights onto a [0,1] confidence score.
+// "ratio" scales the total weight by the share of agreeing signals; "directional"
+// uses the weight of the agreeing signals against activeWeight, the summed
+// weight of the indicators enabled and computed this cycle, so a signal every
+// active indicator agrees with maps to 1.0.
+func (sg *SignalGenerator) normalizeConfidence(totalWeight, directionWeight, activeWeight decimal.Decimal, directionSignals, totalSignals int) decimal.Decimal {
+	if totalSignals == 0 {
+		return decimal.Zero
+	}
+
+	var confidence decimal.Decimal
+	switch sg.cfg.ConfidenceNormalization {
+	case "directional":
+		if !activeWeight.IsPositive() {
+			return decimal.Zero
+		}
+		confidence = directionWeight.Div(activeWeight)
+	default:
+		confidence = totalWeight.Mul(decimal.NewFromFloat(float64(directionSignals) / float64(totalSignals)))
+	}
+
+	return clampConfidence(confidence)
+}
+
+// clampConfidence keeps a confidence score within [0,1]
+func clampConfidence(confidence decimal.Decimal) decimal.Decimal {
+	if confidence.GreaterThan(decimal.NewFromInt(1)) {
+		return decimal.NewFromInt(1)
+	}
+	if confidence.LessThan(decimal.Zero) {
+		return decimal.Zero
+	}
+	return confidence
+}
+
+// calculateTakeProfitTargets builds the scaled take-profit ladder for an action
+// from the given TP distances. SELL targets that would fall to zero or below
+// are dropped and the allocations spread over the remaining ones.
+func (sg *SignalGenerator) calculateTakeProfitTargets(action string, price decimal.Decimal, levels []float64) []models.TakeProfitTarget {
+	if action != "BUY" && action != "SELL" {
+		return nil
+	}
+
+	count := sg.cfg.TakeProfitCount
+	if count > len(levels) {
+		count = len(levels)
+	}
+	if count <= 0 {
+		return nil
+	}
+
+	prices := make([]decimal.Decimal, 0, count)
+	for i := 0; i < count; i++ {
+		distance := decimal.NewFromFloat(levels[i])
+		if sg.cfg.TakeProfitMode != "absolute" {
+			distance = price.Mul(distance.Div(decimal.NewFromInt(100)))
+		}
+
+		target := price.Add(distance)
+		if action == "SELL" {
+			target = price.Sub(distance)
+		}
+		if !target.IsPositive() {
+			logrus.Warnf("Dropping take profit %d and beyond: %s target %s is not above zero", i+1, action, target.String())
+			break
+		}
+		prices = append(prices, target)
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+
+	allocations := normalizeAllocations(sg.cfg.TakeProfitAllocations, len(prices))
+	targets := make([]models.TakeProfitTarget, len(prices))
+	for i, target := range prices {
+		targets[i] = models.TakeProfitTarget{
+			Level:      i + 1,
+			Price:      target,
+			Allocation: allocations[i],
+		}
+	}
+
+	return targets
+}
+
+// atrStopLoss places the stop ATR_STOP_MULTIPLIER ATRs against the action.
+// ok is false when ATR couldn't be computed or the stop would not be positive.
+func (sg *SignalGenerator) atrStopLoss(action string, price, atr decimal.Decimal) (decimal.Decimal, bool) {
+	if !atr.IsPositive() || sg.cfg.ATRStopMultiplier <= 0 {
+		return decimal.Zero, false
+	}
+
+	distance := atr.Mul(decimal.NewFromFloat(sg.cfg.ATRStopMultiplier))
+	if action == "SELL" {
+		return price.Add(distance), true
+	}
+	stop := price.Sub(distance)
+	return stop, stop.IsPositive()
+}
+
+// atrTakeProfits moves target n to n*ATR_TP_MULTIPLIER ATRs in the direction
+// of the action, keeping the allocations. Returns false when ATR is missing
+// or a SELL target would not stay positive, leaving the targets unchanged.
+func (sg *SignalGenerator) atrTakeProfits(action string, price, atr decimal.Decimal, targets []models.TakeProfitTarget) bool {
+	if !atr.IsPositive() || sg.cfg.ATRTakeProfitMultiplier <= 0 || len(targets) == 0 {
+		return false
+	}
+
+	step := atr.Mul(decimal.NewFromFloat(sg.cfg.ATRTakeProfitMultiplier))
+	prices := make([]decimal.Decimal, len(targets))
+	for i := range targets {
+		distance := step.Mul(decimal.NewFromInt(int64(i + 1)))
+		prices[i] = price.Add(distance)
+		if action == "SELL" {
+			prices[i] = price.Sub(distance)
+			if !prices[i].IsPositive() {
+				return false
+			}
+		}
+	}
+
+	for i := range targets {
+		targets[i].Price = prices[i]
+	}
+	return true
+}
+
+// volumeNodeBuffer is how far beyond a high-volume node the stop loss is placed
+const volumeNodeBuffer = 0.002
+
+// snapStopLossToVolumeNode places the stop just beyond the nearest node on the
+// losing side of entry (below for BUY, above for SELL)
+func (sg *SignalGenerator) snapStopLossToVolumeNode(action string, price decimal.Decimal, nodes []decimal.Decimal) (decimal.Decimal, bool) {
+	buffer := decimal.NewFromFloat(volumeNodeBuffer)
+
+	if action == "BUY" {
+		for i := len(nodes) - 1; i >= 0; i-- {
+			if nodes[i].LessThan(price) {
+				return nodes[i].Mul(decimal.NewFromInt(1).Sub(buffer)), true
+			}
+		}
+		return decimal.Zero, false
+	}
+
+	for _, node := range nodes {
+		if node.GreaterThan(price) {
+			return node.Mul(decimal.NewFromInt(1).Add(buffer)), true
+		}
+	}
+	return decimal.Zero, false
+}
+
+// snapTakeProfitsToVolumeNodes moves each TP onto the next node in the trade's
+// direction; levels without a matching node keep their fixed distance
+func (sg *SignalGenerator) snapTakeProfitsToVolumeNodes(action string, price decimal.Decimal, nodes []decimal.Decimal, targets []models.TakeProfitTarget) {
+	var ahead []decimal.Decimal
+	if action == "BUY" {
+		for _, node := range nodes {
+			if node.GreaterThan(price) {
+				ahead = append(ahead, node)
+			}
+		}
+	} else {
+		for i := len(nodes) - 1; i >= 0; i-- {
+			if nodes[i].LessThan(price) {
+				ahead = append(ahead, nodes[i])
+			}
+		}
+	}
+
+	for i := range targets {
+		if i >= len(ahead) {
+			break
+		}
+		targets[i].Price = ahead[i]
+	}
+}
+
+// swingStopLoss places the stop beyond the nearest swing on the losing side of
+// entry: under the closest swing low for BUY, over the closest swing high for SELL
+func (sg *SignalGenerator) swingStopLoss(action string, price decimal.Decimal, highs, lows []decimal.Decimal) (stop, level decimal.Decimal, ok bool) {
+	buffer := decimal.NewFromFloat(sg.cfg.SwingBufferPercent / 100)
+
+	if action == "BUY" {
+		for i := len(lows) - 1; i >= 0; i-- {
+			if lows[i].LessThan(price) {
+				return lows[i].Mul(decimal.NewFromInt(1).Sub(buffer)), lows[i], true
+			}
+		}
+		return decimal.Zero, decimal.Zero, false
+	}
+
+	for _, high := range highs {
+		if high.GreaterThan(price) {
+			return high.Mul(decimal.NewFromInt(1).Add(buffer)), high, true
+		}
+	}
+	return decimal.Zero, decimal.Zero, false
+}
+
+// swingTakeProfit sets TP1 just short of the nearest swing in the trade's
+// direction. It is skipped when the buffered level isn't past entry or would
+// overtake TP2, so the targets stay ordered.
+func (sg *SignalGenerator) swingTakeProfit(action string, price decimal.Decimal, highs, lows []decimal.Decimal, targets []models.TakeProfitTarget) (target, level decimal.Decimal, ok bool) {
+	if len(targets) == 0 {
+		return decimal.Zero, decimal.Zero, false
+	}
+	buffer := decimal.NewFromFloat(sg.cfg.SwingBufferPercent / 100)
+
+	found := false
+	if action == "BUY" {
+		for _, high := range highs {
+			if high.GreaterThan(price) {
+				level, found = high, true
+				break
+			}
+		}
+		target = level.Mul(decimal.NewFromInt(1).Sub(buffer))
+		ok = found && target.GreaterThan(price) && (len(targets) < 2 || target.LessThan(targets[1].Price))
+		return target, level, ok
+	}
+
+	for i := len(lows) - 1; i >= 0; i-- {
+		if lows[i].LessThan(price) {
+			level, found = lows[i], true
+			break
+		}
+	}
+	target = level.Mul(decimal.NewFromInt(1).Add(buffer))
+	ok = found && target.LessThan(price) && (len(targets) < 2 || target.GreaterThan(targets[1].Price))
+	return target, level, ok
+}
+
+// formatPriceLevels renders price levels for reasoning text
+func formatPriceLevels(levels []decimal.Decimal) string {
+	formatted := make([]string, len(levels))
+	for i, level := range levels {
+		formatted[i] = level.StringFixed(4)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// normalizeAllocations scales the configured weights to fractions summing to 1,
+// falling back to an even split when they are missing or invalid
+func normalizeAllocations(weights []float64, count int) []decimal.Decimal {
+	allocations := make([]decimal.Decimal, count)
+
+	total := 0.0
+	valid := len(weights) >= count
+	for i := 0; valid && i < count; i++ {
+		if weights[i] < 0 {
+			valid = false
+		}
+		total += weights[i]
+	}
+
+	for i := 0; i < count; i++ {
+		if valid && total > 0 {
+			allocations[i] = decimal.NewFromFloat(weights[i] / total)
+		} else {
+			allocations[i] = decimal.NewFromInt(1).Div(decimal.NewFromInt(int64(count)))
+		}
+	}
+
+	return allocations
+}
+
+// CalculateScaledPnL returns the blended PnL percentage of a signal whose position
+// is closed partially at each take-profit reached, with the remainder exiting at exitPrice.
+// It is zero when the signal has an entry zone that price never traded into.
+func CalculateScaledPnL(signal *models.TradingSignal, highestPrice, lowestPrice, exitPrice decimal.Decimal) decimal.Decimal {
+	if signal.EntryPrice.IsZero() {
+		return decimal.Zero
+	}
+
+	// A zone that price never traded into means the position was never opened
+	if !EntryZoneTouched(signal, highestPrice, lowestPrice) {
+		return decimal.Zero
+	}
+
+	pnlAt := func(price decimal.Decimal) decimal.Decimal {
+		change := price.Sub(signal.EntryPrice).Div(signal.EntryPrice).Mul(decimal.NewFromInt(100))
+		if signal.Action == "SELL" {
+			return change.Neg()
+		}
+		return change
+	}
+
+	remaining := decimal.NewFromInt(1)
+	pnl := decimal.Zero
+
+	for _, target := range signalTakeProfits(signal) {
+		reached := signal.Action == "BUY" && highestPrice.GreaterThanOrEqual(target.Price) ||
+			signal.Action == "SELL" && lowestPrice.LessThanOrEqual(target.Price)
+		if !reached {
+			break
+		}
+		pnl = pnl.Add(pnlAt(target.Price).Mul(target.Allocation))
+		remaining = remaining.Sub(target.Allocation)
+	}
+
+	if remaining.GreaterThan(decimal.Zero) {
+		pnl = pnl.Add(pnlAt(exitPrice).Mul(remaining))
+	}
+
+	return pnl
+}
+
+// signalTakeProfits returns a signal's scaled targets, or TP1 for the whole
+// position on signals stored before targets were scaled
+func signalTakeProfits(signal *models.TradingSignal) []models.TakeProfitTarget {
+	if len(signal.TakeProfits) > 0 || signal.TakeProfit1 == nil {
+		return signal.TakeProfits
+	}
+	return []models.TakeProfitTarget{{Level: 1, Price: *signal.TakeProfit1, Allocation: decimal.NewFromInt(1)}}
+}
+
+// roundSignalValues rounds persisted/displayed values to the configured precision.
+// Entry and targets use the coin's exchange precision when it is known.
+// Pointer fields are replaced rather than mutated since they may alias the indicators.
+func (sg *SignalGenerator) roundSignalValues(signal *models.TradingSignal) {
+	targetPlaces := coinPricePlaces(signal.Crypto, sg.cfg.SignalPricePrecision)
+	pricePlaces := int32(sg.cfg.SignalPricePrecision)
+	indicatorPlaces := int32(sg.cfg.SignalIndicatorPrecision)
+
+	signal.ConfidenceScore = signal.ConfidenceScore.Round(int32(sg.cfg.SignalConfidencePrecision))
+	signal.EntryPrice = signal.EntryPrice.Round(targetPlaces)
+
+	for _, field := range []**decimal.Decimal{
+		&signal.StopLoss, &signal.TakeProfit1, &signal.TakeProfit2,
+		&signal.EntryLow, &signal.EntryHigh,
+	} {
+		*field = roundDecimalPtr(*field, targetPlaces)
+	}
+
+	for _, field := range []**decimal.Decimal{
+		&signal.BBUpper, &signal.BBMiddle, &signal.BBLower,
+		&signal.SMA20, &signal.EMA12, &signal.EMA26,
+		&signal.MACDLine, &signal.MACDSignal, &signal.MACDHistogram,
+	} {
+		*field = roundDecimalPtr(*field, pricePlaces)
+	}
+
+	for _, field := range []**decimal.Decimal{
+		&signal.RSI, &signal.Volume24h, &signal.PriceChange24h, &signal.MarketCap,
+	} {
+		*field = roundDecimalPtr(*field, indicatorPlaces)
+	}
+
+	if len(signal.TakeProfits) > 0 {
+		rounded := make([]models.TakeProfitTarget, len(signal.TakeProfits))
+		for i, target := range signal.TakeProfits {
+			target.Price = target.Price.Round(targetPlaces)
+			target.Allocation = target.Allocation.Round(4)
+			rounded[i] = target
+		}
+		signal.TakeProfits = rounded
+		if signal.MarketConditions != nil {
+			signal.MarketConditions["take_profits"] = rounded
+		}
+	}
+}
+
+// roundDecimalPtr returns a new pointer holding the rounded value, or nil
+func roundDecimalPtr(value *decimal.Decimal, places int32) *decimal.Decimal {
+	if value == nil {
+		return nil
+	}
+	rounded := value.Round(places)
+	return &rounded
+}
+
+// signalTimeframe is the kline interval a signal was analyzed on
+func (sg *SignalGenerator) signalTimeframe(marketData *MarketData) string {
+	if marketData.Interval == "" {
+		return sg.cfg.KlineInterval
+	}
+	return marketData.Interval
+}
+
+// generateRefCode builds a short shareable reference such as BTC-240612-A3F,
+// checking the database for collisions and widening the suffix if needed
+func (sg *SignalGenerator) generateRefCode(symbol string, createdAt time.Time) string {
+	prefix := fmt.Sprintf("%s-%s-", symbol, createdAt.Format("060102"))
+
+	suffixLength := 3
+	for attempt := 0; attempt < 10; attempt++ {
+		if attempt == 5 {
+			suffixLength = 6
+		}
+
+		code := prefix + randomRefSuffix(suffixLength)
+		if sg.db == nil {
+			return code
+		}
+
+		exists, err := sg.db.SignalRefCodeExists(code)
+		if err != nil {
+			logrus.Warn("Failed to check signal ref code collision: ", err)
+			return code
+		}
+		if !exists {
+			return code
+		}
+	}
+
+	return prefix + strings.ToUpper(uuid.New().String()[:8])
+}
+
+// randomRefSuffix returns n random uppercase hex characters
+func randomRefSuffix(n int) string {
+	buf := make([]byte, (n+1)/2)
+	if _, err := rand.Read(buf); err != nil {
+		return strings.ToUpper(uuid.New().String()[:n])
 	}
+	return strings.ToUpper(hex.EncodeToString(buf))[:n]
 }
 
 func (sg *SignalGenerator) calculateBBPosition(price, upper, lower decimal.Decimal) float64 {
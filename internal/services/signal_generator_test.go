@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+)
+
+// confluenceIndicators sets every strategy indicator at a price of 100. With
+// mixed, Bollinger votes SELL against the RSI and MACD BUY votes and the trend
+// stays neutral; otherwise all of them vote BUY.
+func confluenceIndicators(mixed bool) *TechnicalIndicators {
+	indicators := &TechnicalIndicators{
+		RSI:           dec("20"),
+		MACDLine:      dec("2"),
+		MACDSignal:    dec("1"),
+		MACDHistogram: dec("1"),
+		BBUpper:       dec("120"),
+		BBMiddle:      dec("110"),
+		BBLower:       dec("101"),
+		SMA20:         dec("99"),
+		EMA12:         dec("2"),
+		EMA26:         dec("1"),
+		ATR:           dec("1"),
+		CurrentPrice:  dec("100"),
+	}
+	if mixed {
+		indicators.BBUpper, indicators.BBMiddle, indicators.BBLower = dec("99"), dec("95"), dec("90")
+		indicators.EMA12, indicators.EMA26 = dec("1"), dec("2")
+	}
+	return indicators
+}
+
+func TestConfidenceNormalization(t *testing.T) {
+	tests := []struct {
+		name           string
+		normalization  string
+		mixed          bool
+		fearGreed      int
+		fearGreedLive  bool // False leaves Fear & Greed out with FEAR_GREED_REQUIRE_LIVE
+		wantConfidence string
+	}{
+		{name: "ratio, all agree", normalization: "ratio", fearGreed: 10, fearGreedLive: true, wantConfidence: "1.0000"},
+		{name: "directional, all agree", normalization: "directional", fearGreed: 10, fearGreedLive: true, wantConfidence: "1.0000"},
+		{name: "directional, all active agree", normalization: "directional", fearGreed: 10, wantConfidence: "1.0000"},
+		{name: "ratio, mixed", normalization: "ratio", mixed: true, fearGreed: 50, fearGreedLive: true, wantConfidence: "0.5000"},               // 0.75 * 2/3
+		{name: "directional, mixed", normalization: "directional", mixed: true, fearGreed: 50, fearGreedLive: true, wantConfidence: "0.5500"},   // 0.55 / 1
+		{name: "directional, mixed without fear and greed", normalization: "directional", mixed: true, fearGreed: 50, wantConfidence: "0.6471"}, // 0.55 / 0.85
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.ConfidenceNormalization = tt.normalization
+			cfg.FearGreedRequireLive = true
+			sg := NewSignalGenerator(nil, cfg)
+
+			marketData := &MarketData{
+				Symbol:             "BTC",
+				Price:              dec("100"),
+				FearGreedIndex:     tt.fearGreed,
+				FearGreedAvailable: tt.fearGreedLive,
+			}
+			decision := sg.analyzeMarketConditions(marketData, confluenceIndicators(tt.mixed))
+			if decision.Action != "BUY" {
+				t.Fatalf("action = %s, want BUY", decision.Action)
+			}
+			if got := decision.Confidence.StringFixed(4); got != tt.wantConfidence {
+				t.Errorf("confidence = %s, want %s", got, tt.wantConfidence)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package services
+
+import "crypto-signal-bot/internal/models"
+
+// OnSignalSent registers hook to be called with every signal whose
+// notification was sent. Hooks run on the sending goroutine and must not block.
+func (bs *BotService) OnSignalSent(hook func(*models.TradingSignal)) {
+	bs.signalHooksMu.Lock()
+	defer bs.signalHooksMu.Unlock()
+
+	bs.signalHooks = append(bs.signalHooks, hook)
+}
+
+// publishSignal hands a sent signal to the registered hooks
+func (bs *BotService) publishSignal(signal *models.TradingSignal) {
+	bs.signalHooksMu.RLock()
+	hooks := bs.signalHooks
+	bs.signalHooksMu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(signal)
+	}
+}
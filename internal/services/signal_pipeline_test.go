@@ -0,0 +1,241 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/database"
+	"crypto-signal-bot/internal/models"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// trendKlines builds n 15m klines ending now whose closes move by step per
+// candle, pulling back on every other one so RSI stays off its 0/100 bounds
+func trendKlines(n int, step float64) [][]interface{} {
+	start := time.Now().Truncate(15 * time.Minute).Add(-time.Duration(n) * 15 * time.Minute)
+	klines := make([][]interface{}, n)
+	previous := 100.0
+	for i := range klines {
+		close := 100 + float64(i)*step
+		if i%2 == 1 {
+			close -= step * 1.6
+		}
+		high, low := previous, close
+		if close > previous {
+			high, low = close, previous
+		}
+		klines[i] = []interface{}{
+			start.Add(time.Duration(i) * 15 * time.Minute).UnixMilli(),
+			strconv.FormatFloat(previous, 'f', -1, 64),
+			strconv.FormatFloat(high*1.002, 'f', -1, 64),
+			strconv.FormatFloat(low*0.998, 'f', -1, 64),
+			strconv.FormatFloat(close, 'f', -1, 64),
+			"1000",
+		}
+		previous = close
+	}
+	return klines
+}
+
+// staticMarketData wraps klines in a fresh quote at their last close
+func staticMarketData(symbol string, klines [][]interface{}) *MarketData {
+	price, _ := klineDecimal(klines[len(klines)-1][4])
+	return &MarketData{
+		Symbol:         symbol,
+		Price:          price,
+		KlineData:      klines,
+		Interval:       "15m",
+		Timestamp:      time.Now(),
+		FearGreedIndex: 50,
+		PriceSource:    "binance",
+		KlineSource:    "binance",
+	}
+}
+
+func TestGenerateSignal(t *testing.T) {
+	tests := []struct {
+		name          string
+		klines        [][]interface{}
+		minConfidence float64
+		clearRSI      bool
+		wantAction    string // Empty when no signal is generated
+		wantRejected  string
+	}{
+		{name: "oversold downtrend buys", klines: trendKlines(100, -0.5), minConfidence: 0.4, wantAction: "BUY"},
+		{name: "overbought uptrend sells", klines: trendKlines(100, 0.5), minConfidence: 0.4, wantAction: "SELL"},
+		{name: "below the confidence threshold", klines: trendKlines(100, -0.5), minConfidence: 0.9, wantRejected: RejectConfidence},
+		{name: "uncomputed indicators are rejected", klines: trendKlines(100, -0.5), minConfidence: 0.4, clearRSI: true, wantRejected: RejectInvalidIndicators},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.MinConfidenceThreshold = tt.minConfidence
+			cfg.LogRejectedSignals = true
+			store := database.NewMemoryStore()
+			sg := NewSignalGenerator(store, cfg)
+
+			marketData := staticMarketData("BTC", tt.klines)
+			indicators, err := NewTechnicalAnalyzer(cfg).AnalyzeMarketData(marketData)
+			if err != nil {
+				t.Fatalf("AnalyzeMarketData: %v", err)
+			}
+			if tt.clearRSI {
+				indicators.RSI = decimal.Zero
+			}
+
+			signal, err := sg.GenerateSignal(marketData, indicators, &models.Cryptocurrency{ID: uuid.New(), Symbol: "BTC"})
+			if err != nil {
+				t.Fatalf("GenerateSignal: %v", err)
+			}
+
+			stored, _ := store.GetActiveSignals()
+			if tt.wantAction == "" {
+				if signal != nil {
+					t.Errorf("got %s signal, want none", signal.Action)
+				}
+				if len(stored) != 0 {
+					t.Errorf("stored %d signals, want none", len(stored))
+				}
+			} else {
+				if signal == nil {
+					t.Fatalf("got no signal, want %s", tt.wantAction)
+				}
+				if signal.Action != tt.wantAction {
+					t.Errorf("action = %s, want %s", signal.Action, tt.wantAction)
+				}
+				if len(stored) != 1 || stored[0].ID != signal.ID {
+					t.Errorf("stored %d signals, want the generated one", len(stored))
+				}
+			}
+
+			if tt.wantRejected != "" {
+				rejected, _ := store.GetRejectedSignals(tt.wantRejected, 10)
+				if len(rejected) != 1 {
+					t.Errorf("got %d %s rejections, want 1", len(rejected), tt.wantRejected)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeCryptocurrency(t *testing.T) {
+	tests := []struct {
+		name           string
+		marketData     *MarketData // Nil leaves the symbol without data
+		wantAction     string
+		wantMarketFail bool
+	}{
+		{name: "missing market data", wantMarketFail: true},
+		{
+			name: "stale quote is skipped",
+			marketData: func() *MarketData {
+				marketData := staticMarketData("BTC", trendKlines(100, -0.5))
+				marketData.Timestamp = time.Now().Add(-time.Hour)
+				return marketData
+			}(),
+		},
+		{
+			name: "recently listed coin is skipped",
+			marketData: func() *MarketData {
+				marketData := staticMarketData("BTC", trendKlines(100, -0.5))
+				listedAt := time.Now().Add(-24 * time.Hour)
+				marketData.ListedAt = &listedAt
+				return marketData
+			}(),
+		},
+		{name: "oversold coin yields a buy candidate", marketData: staticMarketData("BTC", trendKlines(100, -0.5)), wantAction: "BUY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.MinConfidenceThreshold = 0.4
+			store := database.NewMemoryStore()
+			bs := NewBotService(store, cfg)
+			source := NewStaticMarketDataSource()
+			if tt.marketData != nil {
+				source.Set("BTC", tt.marketData)
+			}
+			bs.SetMarketDataSource(source)
+
+			candidate, err := bs.analyzeCryptocurrency(&models.Cryptocurrency{ID: uuid.New(), Symbol: "BTC"}, "15m")
+			var marketErr *marketDataError
+			if errors.As(err, &marketErr) != tt.wantMarketFail {
+				t.Fatalf("err = %v, want market data failure %v", err, tt.wantMarketFail)
+			}
+			if tt.wantMarketFail {
+				return
+			}
+			if err != nil {
+				t.Fatalf("analyzeCryptocurrency: %v", err)
+			}
+
+			if tt.wantAction == "" {
+				if candidate != nil {
+					t.Errorf("got %s candidate, want none", candidate.signal.Action)
+				}
+				return
+			}
+			if candidate == nil {
+				t.Fatalf("got no candidate, want %s", tt.wantAction)
+			}
+			if candidate.signal.Action != tt.wantAction {
+				t.Errorf("action = %s, want %s", candidate.signal.Action, tt.wantAction)
+			}
+			// Candidates are only stored once committed
+			if stored, _ := store.GetActiveSignals(); len(stored) != 0 {
+				t.Errorf("stored %d signals before commit, want none", len(stored))
+			}
+		})
+	}
+}
+
+func TestLearningEngineOutcomeScoring(t *testing.T) {
+	tests := []struct {
+		name          string
+		predicted     string // Empty saves no learning record with the signal
+		confidence    string
+		actual        string
+		wantAccuracy  string
+		wantPredicted string
+	}{
+		{name: "confident hit", predicted: "profit", confidence: "0.8", actual: "profit", wantAccuracy: "0.96", wantPredicted: "profit"},
+		{name: "confident miss", predicted: "profit", confidence: "0.8", actual: "loss", wantAccuracy: "0.36", wantPredicted: "profit"},
+		{name: "outcome without a prediction", actual: "loss", wantAccuracy: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := database.NewMemoryStore()
+			le := NewLearningEngine(store, testConfig())
+			signal := &models.TradingSignal{ID: uuid.New(), Action: "BUY"}
+
+			if tt.predicted != "" {
+				if err := le.SaveLearningData(signal, &FeatureVector{}, tt.predicted, dec(tt.confidence)); err != nil {
+					t.Fatalf("SaveLearningData: %v", err)
+				}
+			}
+			if err := le.UpdateLearningDataWithOutcome(signal.ID, tt.actual, dec("2.5"), 45); err != nil {
+				t.Fatalf("UpdateLearningDataWithOutcome: %v", err)
+			}
+
+			record, err := store.GetLearningDataBySignal(signal.ID)
+			if err != nil {
+				t.Fatalf("GetLearningDataBySignal: %v", err)
+			}
+			if record.ActualOutcome != tt.actual || !record.ActualPnLPercentage.Equal(dec("2.5")) || record.ActualDurationMinutes != 45 {
+				t.Errorf("outcome = %s %s%% %dm, want %s 2.5%% 45m", record.ActualOutcome, record.ActualPnLPercentage, record.ActualDurationMinutes, tt.actual)
+			}
+			if record.PredictedOutcome != tt.wantPredicted {
+				t.Errorf("predicted = %q, want %q", record.PredictedOutcome, tt.wantPredicted)
+			}
+			if !record.PredictionAccuracy.Equal(dec(tt.wantAccuracy)) {
+				t.Errorf("accuracy = %s, want %s", record.PredictionAccuracy, tt.wantAccuracy)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// Signal priority levels recorded on models.TradingSignal.Priority
+const (
+	SignalPriorityLow    = "low"
+	SignalPriorityMedium = "medium"
+	SignalPriorityHigh   = "high"
+)
+
+// minHighPrioritySignals is the fewest agreeing indicators a high-priority signal needs,
+// so a single strong indicator cannot qualify on a 1/1 agreement ratio
+const minHighPrioritySignals = 3
+
+// signalPriorityRank orders priorities for "at least" comparisons
+var signalPriorityRank = map[string]int{
+	SignalPriorityLow:    1,
+	SignalPriorityMedium: 2,
+	SignalPriorityHigh:   3,
+}
+
+// ParseSignalPriority normalizes a priority name and rejects unknown values
+func ParseSignalPriority(value string) (string, error) {
+	priority := strings.ToLower(strings.TrimSpace(value))
+	if _, ok := signalPriorityRank[priority]; !ok {
+		return "", fmt.Errorf("priority must be low, medium or high, got %q", value)
+	}
+	return priority, nil
+}
+
+// priorityAtLeast reports whether priority ranks at or above min; unknown
+// priorities never qualify
+func priorityAtLeast(priority, min string) bool {
+	rank, ok := signalPriorityRank[priority]
+	return ok && rank >= signalPriorityRank[min]
+}
+
+// signalPriorityLabel returns the notification header badge for a priority
+func signalPriorityLabel(priority string) string {
+	switch priority {
+	case SignalPriorityHigh:
+		return "🔥 HIGH"
+	case SignalPriorityMedium:
+		return "⭐ MEDIUM"
+	case SignalPriorityLow:
+		return "▫️ LOW"
+	default:
+		return ""
+	}
+}
+
+// classifyPriority derives a priority from the final confidence and how many of
+// the triggered indicators agree with the chosen action
+func (sg *SignalGenerator) classifyPriority(confidence decimal.Decimal, agreeing, total int) string {
+	agreement := 0.0
+	if total > 0 {
+		agreement = float64(agreeing) / float64(total)
+	}
+
+	if confidence.GreaterThanOrEqual(decimal.NewFromFloat(sg.cfg.PriorityHighConfidence)) &&
+		agreement >= sg.cfg.PriorityHighAgreement && agreeing >= minHighPrioritySignals {
+		return SignalPriorityHigh
+	}
+	if confidence.GreaterThanOrEqual(decimal.NewFromFloat(sg.cfg.PriorityMediumConfidence)) {
+		return SignalPriorityMedium
+	}
+	return SignalPriorityLow
+}
+
+// priorityWebhookTimeout bounds the extra channel so it cannot stall Telegram delivery
+const priorityWebhookTimeout = 10 * time.Second
+
+// sendPriorityWebhook posts a signal message to the Discord-compatible priority webhook
+func (ns *NotificationService) sendPriorityWebhook(message string) error {
+	body, err := json.Marshal(map[string]string{"content": message})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: priorityWebhookTimeout}
+	resp, err := client.Post(ns.cfg.PriorityWebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post priority webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("priority webhook returned %s - %s", resp.Status, string(respBody))
+	}
+
+	logrus.Info("✅ Priority webhook message sent")
+	return nil
+}
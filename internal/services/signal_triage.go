@@ -0,0 +1,121 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// signalSnoozeDuration is how long the snooze button mutes a coin's notifications
+const signalSnoozeDuration = time.Hour
+
+// Callback data prefixes of the buttons under a signal notification
+const (
+	signalTakenPrefix   = "signal_taken_"
+	signalIgnoredPrefix = "signal_ignored_"
+	snoozeCoinPrefix    = "snooze_coin_"
+)
+
+// Values of TradingSignal.UserAction
+const (
+	signalActionTaken   = "taken"
+	signalActionIgnored = "ignored"
+)
+
+// coinSnoozes holds per-coin notification cooldowns set from signal messages
+type coinSnoozes struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+// SnoozeCoin mutes signal notifications of a coin for signalSnoozeDuration
+// and returns when the snooze ends. Signals are still generated and stored.
+func (bs *BotService) SnoozeCoin(symbol string) time.Time {
+	bs.snoozes.mu.Lock()
+	defer bs.snoozes.mu.Unlock()
+
+	if bs.snoozes.until == nil {
+		bs.snoozes.until = make(map[string]time.Time)
+	}
+	until := time.Now().Add(signalSnoozeDuration)
+	bs.snoozes.until[symbol] = until
+	return until
+}
+
+// IsCoinSnoozed reports whether a coin's signal notifications are muted
+func (bs *BotService) IsCoinSnoozed(symbol string) bool {
+	bs.snoozes.mu.Lock()
+	defer bs.snoozes.mu.Unlock()
+
+	until, exists := bs.snoozes.until[symbol]
+	if !exists {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(bs.snoozes.until, symbol)
+		return false
+	}
+	return true
+}
+
+// snoozedCoins returns the coins whose snooze is still running and when it ends
+func (bs *BotService) snoozedCoins() map[string]time.Time {
+	bs.snoozes.mu.Lock()
+	defer bs.snoozes.mu.Unlock()
+
+	active := make(map[string]time.Time)
+	now := time.Now()
+	for symbol, until := range bs.snoozes.until {
+		if now.Before(until) {
+			active[symbol] = until
+		}
+	}
+	return active
+}
+
+// MarkSignal records whether the user took or ignored a signal, so feedback
+// tracking and the learning data can tell acted-on signals from skipped ones
+func (bs *BotService) MarkSignal(refCode, action string) (*models.TradingSignal, error) {
+	if bs.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	if action != signalActionTaken && action != signalActionIgnored {
+		return nil, fmt.Errorf("action must be %s or %s, got %q", signalActionTaken, signalActionIgnored, action)
+	}
+
+	signal, err := bs.db.GetSignalByRefCode(refCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.db.SetSignalUserAction(signal.ID, action); err != nil {
+		return nil, err
+	}
+	signal.UserAction = action
+	return signal, nil
+}
+
+// signalTriageKeyboard returns the buttons shown under a BUY/SELL notification,
+// or nil when the signal can't be referenced from a callback
+func signalTriageKeyboard(signal *models.TradingSignal) *tgbotapi.InlineKeyboardMarkup {
+	if signal.RefCode == "" || signal.Crypto == nil || (signal.Action != "BUY" && signal.Action != "SELL") {
+		return nil
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Taken", signalTakenPrefix+signal.RefCode),
+			tgbotapi.NewInlineKeyboardButtonData("🚫 Ignored", signalIgnoredPrefix+signal.RefCode),
+		),
+		snoozeKeyboardRow(signal.Crypto.Symbol),
+	)
+	return &keyboard
+}
+
+func snoozeKeyboardRow(symbol string) []tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔕 Snooze coin 1h", snoozeCoinPrefix+symbol),
+	)
+}
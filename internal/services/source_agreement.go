@@ -0,0 +1,95 @@
+package services
+
+import (
+	"math"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// sourceQuote is one provider's view of a coin, collected by GetMarketData
+type sourceQuote struct {
+	source    string
+	price     decimal.Decimal
+	change24h *float64 // nil when the provider doesn't report it
+}
+
+// SourceAgreement compares the quotes of the providers that answered
+type SourceAgreement struct {
+	Sources            []string
+	PriceSpreadPercent float64 // (highest - lowest) / lowest price, in percent
+	ChangeSpreadPoints float64 // Highest minus lowest 24h change, in percentage points
+	ChangeSources      int     // Providers that reported a 24h change
+}
+
+// compareSources measures how far the providers' prices and 24h changes are
+// apart; nil when fewer than two reported a price
+func compareSources(quotes []sourceQuote) *SourceAgreement {
+	agreement := &SourceAgreement{}
+	var lowest, highest decimal.Decimal
+	lowChange, highChange := math.Inf(1), math.Inf(-1)
+
+	for _, quote := range quotes {
+		if !quote.price.IsPositive() {
+			continue
+		}
+		if len(agreement.Sources) == 0 || quote.price.LessThan(lowest) {
+			lowest = quote.price
+		}
+		if len(agreement.Sources) == 0 || quote.price.GreaterThan(highest) {
+			highest = quote.price
+		}
+		agreement.Sources = append(agreement.Sources, quote.source)
+
+		if quote.change24h != nil {
+			lowChange = math.Min(lowChange, *quote.change24h)
+			highChange = math.Max(highChange, *quote.change24h)
+			agreement.ChangeSources++
+		}
+	}
+
+	if len(agreement.Sources) < 2 {
+		return nil
+	}
+
+	agreement.PriceSpreadPercent = highest.Sub(lowest).Div(lowest).Mul(decimal.NewFromInt(100)).InexactFloat64()
+	if agreement.ChangeSources >= 2 {
+		agreement.ChangeSpreadPoints = highChange - lowChange
+	}
+	return agreement
+}
+
+// lastKlineClose returns the close of the latest kline, the kline provider's current price
+func lastKlineClose(klines [][]interface{}) decimal.Decimal {
+	if len(klines) == 0 || len(klines[len(klines)-1]) <= 4 {
+		return decimal.Zero
+	}
+	closePrice, err := klineDecimal(klines[len(klines)-1][4])
+	if err != nil {
+		return decimal.Zero
+	}
+	return closePrice
+}
+
+// sourceAgreementLevel classifies an agreement as "agree", "diverge" or ""
+// (in between, or not enough sources). Agreement needs the prices within
+// SOURCE_AGREEMENT_TOLERANCE_PERCENT and, when two providers report it, the
+// 24h changes within SOURCE_AGREEMENT_CHANGE_TOLERANCE points.
+func (sg *SignalGenerator) sourceAgreementLevel(agreement *SourceAgreement) string {
+	if agreement == nil {
+		return ""
+	}
+	if agreement.PriceSpreadPercent >= sg.cfg.SourceDivergencePercent {
+		return "diverge"
+	}
+	if agreement.PriceSpreadPercent <= sg.cfg.SourceAgreementTolerancePercent &&
+		(agreement.ChangeSources < 2 || agreement.ChangeSpreadPoints <= sg.cfg.SourceAgreementChangeTolerance) {
+		return "agree"
+	}
+	return ""
+}
+
+// describeSources renders the providers of an agreement for reasoning text
+func describeSources(agreement *SourceAgreement) string {
+	return strings.Join(agreement.Sources, "/")
+}
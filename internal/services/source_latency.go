@@ -0,0 +1,109 @@
+package services
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencySamples is how many recent requests the average latency covers
+const latencySamples = 20
+
+// SourceLatency summarizes recent request times to one data provider
+type SourceLatency struct {
+	Source     string    `json:"source"`
+	LastMillis int64     `json:"last_ms"`
+	AvgMillis  int64     `json:"avg_ms"`
+	Requests   int       `json:"requests"`
+	Failures   int       `json:"failures"` // Transport errors and 5xx responses
+	LastAt     time.Time `json:"last_at"`
+}
+
+// sourceLatencies keeps recent request durations per provider
+type sourceLatencies struct {
+	mu      sync.Mutex
+	samples map[string][]time.Duration
+	stats   map[string]*SourceLatency
+}
+
+func (l *sourceLatencies) record(source string, duration time.Duration, failed bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stats == nil {
+		l.samples = make(map[string][]time.Duration)
+		l.stats = make(map[string]*SourceLatency)
+	}
+
+	samples := append(l.samples[source], duration)
+	if len(samples) > latencySamples {
+		samples = samples[len(samples)-latencySamples:]
+	}
+	l.samples[source] = samples
+
+	var total time.Duration
+	for _, sample := range samples {
+		total += sample
+	}
+
+	stat, exists := l.stats[source]
+	if !exists {
+		stat = &SourceLatency{Source: source}
+		l.stats[source] = stat
+	}
+	stat.LastMillis = duration.Milliseconds()
+	stat.AvgMillis = (total / time.Duration(len(samples))).Milliseconds()
+	stat.Requests++
+	if failed {
+		stat.Failures++
+	}
+	stat.LastAt = time.Now()
+}
+
+func (l *sourceLatencies) snapshot() []SourceLatency {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	latencies := make([]SourceLatency, 0, len(l.stats))
+	for _, stat := range l.stats {
+		latencies = append(latencies, *stat)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i].Source < latencies[j].Source })
+	return latencies
+}
+
+// latencySource names the provider behind an API host
+func latencySource(host string) string {
+	switch {
+	case strings.HasSuffix(host, "binance.com"):
+		return "binance"
+	case strings.HasSuffix(host, "coingecko.com"):
+		return providerCoinGecko
+	case strings.HasSuffix(host, "coinmarketcap.com"):
+		return providerCoinMarketCap
+	case strings.HasSuffix(host, "alternative.me"):
+		return "fear_greed"
+	}
+	return host
+}
+
+// latencyTransport times every request made through the data collector
+type latencyTransport struct {
+	next      http.RoundTripper
+	latencies *sourceLatencies
+}
+
+func (t *latencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	started := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	failed := err != nil || resp.StatusCode >= 500
+	t.latencies.record(latencySource(req.URL.Hostname()), time.Since(started), failed)
+	return resp, err
+}
+
+// GetSourceLatencies returns recent request times per data provider
+func (dc *DataCollector) GetSourceLatencies() []SourceLatency {
+	return dc.latencies.snapshot()
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Startup probe names, as listed in STARTUP_CRITICAL_PROBES
+const (
+	probeTelegram    = "telegram"
+	probeDatabase    = "database"
+	probeDataSources = "data_sources"
+)
+
+// defaultProbeTimeout applies when STARTUP_PROBE_TIMEOUT_SECONDS is not positive
+const defaultProbeTimeout = 10 * time.Second
+
+// probeResult is the outcome of one startup probe
+type probeResult struct {
+	name     string
+	err      error
+	duration time.Duration
+}
+
+// startupProbes lists the checks run at startup; the database one only when a store is configured
+func (bs *BotService) startupProbes() map[string]func() error {
+	probes := map[string]func() error{
+		probeTelegram: bs.notificationService.TestConnection,
+		// A single ticker request instead of a full market data fetch
+		probeDataSources: func() error {
+			_, err := bs.dataCollector.GetCurrentPrice("BTC")
+			return err
+		},
+	}
+	if bs.db != nil {
+		probes[probeDatabase] = bs.db.TestConnection
+	}
+	return probes
+}
+
+// runProbes runs every probe concurrently and collects the results until all
+// have finished or timeout elapses; probes still running count as failed
+func runProbes(probes map[string]func() error, timeout time.Duration) map[string]probeResult {
+	results := make(chan probeResult, len(probes))
+	for name, probe := range probes {
+		go func(name string, probe func() error) {
+			started := time.Now()
+			err := probe()
+			results <- probeResult{name: name, err: err, duration: time.Since(started)}
+		}(name, probe)
+	}
+
+	collected := make(map[string]probeResult, len(probes))
+	deadline := time.After(timeout)
+	for len(collected) < len(probes) {
+		select {
+		case result := <-results:
+			collected[result.name] = result
+		case <-deadline:
+			for name := range probes {
+				if _, done := collected[name]; !done {
+					collected[name] = probeResult{name: name, err: fmt.Errorf("timed out after %s", timeout), duration: timeout}
+				}
+			}
+		}
+	}
+	return collected
+}
+
+// testConnections probes Telegram, the database and the data sources in
+// parallel within STARTUP_PROBE_TIMEOUT_SECONDS. Failures are logged; only
+// the probes listed in STARTUP_CRITICAL_PROBES fail startup.
+func (bs *BotService) testConnections() error {
+	logrus.Info("Testing connections...")
+
+	if bs.db == nil {
+		logrus.Warn("⚠️ Database not available, skipping database test")
+	}
+
+	timeout := time.Duration(bs.cfg.StartupProbeTimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	results := runProbes(bs.startupProbes(), timeout)
+
+	critical := make(map[string]bool)
+	for _, name := range bs.cfg.StartupCriticalProbes {
+		critical[strings.ToLower(name)] = true
+	}
+
+	var failed []string
+	for name, result := range results {
+		if result.err == nil {
+			logrus.Infof("✅ %s probe passed in %s", name, result.duration.Round(time.Millisecond))
+			continue
+		}
+		if critical[name] {
+			logrus.Errorf("%s probe failed: %v", name, result.err)
+			failed = append(failed, fmt.Sprintf("%s: %v", name, result.err))
+			bs.RecordSystemLog("error", "startup", fmt.Sprintf("%s probe failed: %v", name, result.err), nil)
+		} else {
+			logrus.Warnf("%s probe failed (continuing): %v", name, result.err)
+			bs.RecordSystemLog("warning", "startup", fmt.Sprintf("%s probe failed (continuing): %v", name, result.err), nil)
+		}
+	}
+
+	bs.telegramReady.Store(results[probeTelegram].err == nil)
+	if result, probed := results[probeDatabase]; probed {
+		bs.databaseReady.Store(result.err == nil)
+	}
+	bs.dataSourcesReady.Store(results[probeDataSources].err == nil)
+
+	if len(failed) > 0 {
+		return fmt.Errorf("critical startup probes failed: %s", strings.Join(failed, "; "))
+	}
+
+	logrus.Info("✅ Startup connection probes finished")
+	return nil
+}
+
+// refreshReadiness updates the readiness of the database and data sources
+// after an analysis cycle, so a dependency that fails after startup stops
+// reporting ready. dataSucceeded and dataFailed count the coins whose market
+// data could and couldn't be fetched.
+func (bs *BotService) refreshReadiness(dataSucceeded, dataFailed int) {
+	if dataSucceeded > 0 {
+		bs.dataSourcesReady.Store(true)
+	} else if dataFailed > 0 {
+		bs.dataSourcesReady.Store(false)
+	}
+
+	if bs.db == nil {
+		return
+	}
+	err := bs.db.TestConnection()
+	if err != nil && bs.databaseReady.Load() {
+		logrus.Warn("Database stopped answering, reporting not ready: ", err)
+	}
+	bs.databaseReady.Store(err == nil)
+}
+
+// recordTelegramDelivery keeps Telegram readiness in line with the outcome
+// of the latest delivery
+func (bs *BotService) recordTelegramDelivery(err error) {
+	bs.telegramReady.Store(err == nil)
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/database"
+	"crypto-signal-bot/internal/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidStrategyProfile wraps validation failures of a strategy profile
+var ErrInvalidStrategyProfile = errors.New("invalid strategy profile")
+
+// strategyIndicators are the indicator blocks a profile can switch on or off
+var strategyIndicators = []string{"rsi", "macd", "bollinger", "fear_greed", "trend"}
+
+// StrategySettings are the settings the generator actually uses for a coin:
+// the global config, as adjusted by learning, with the coin's profile overrides applied
+type StrategySettings struct {
+	MinConfidence      float64   `json:"min_confidence"`
+	RSIOversold        float64   `json:"rsi_oversold"`
+	RSIOverbought      float64   `json:"rsi_overbought"`
+	StopLossPercentage float64   `json:"stop_loss_percentage"`
+	TakeProfitLevels   []float64 `json:"take_profit_levels"`
+	EnabledIndicators  []string  `json:"enabled_indicators"`
+
+	// Direction-specific SL/TP from BUY_/SELL_ config; unset means the values above
+	BuyStopLossPercentage  float64   `json:"buy_stop_loss_percentage,omitempty"`
+	SellStopLossPercentage float64   `json:"sell_stop_loss_percentage,omitempty"`
+	BuyTakeProfitLevels    []float64 `json:"buy_take_profit_levels,omitempty"`
+	SellTakeProfitLevels   []float64 `json:"sell_take_profit_levels,omitempty"`
+}
+
+// stopLossFor returns the stop loss percentage of a signal direction
+func (s StrategySettings) stopLossFor(action string) float64 {
+	switch {
+	case action == "BUY" && s.BuyStopLossPercentage > 0:
+		return s.BuyStopLossPercentage
+	case action == "SELL" && s.SellStopLossPercentage > 0:
+		return s.SellStopLossPercentage
+	}
+	return s.StopLossPercentage
+}
+
+// takeProfitLevelsFor returns the take-profit levels of a signal direction
+func (s StrategySettings) takeProfitLevelsFor(action string) []float64 {
+	switch {
+	case action == "BUY" && len(s.BuyTakeProfitLevels) > 0:
+		return s.BuyTakeProfitLevels
+	case action == "SELL" && len(s.SellTakeProfitLevels) > 0:
+		return s.SellTakeProfitLevels
+	}
+	return s.TakeProfitLevels
+}
+
+// indicatorEnabled reports whether an indicator block contributes to the decision
+func (s StrategySettings) indicatorEnabled(name string) bool {
+	for _, enabled := range s.EnabledIndicators {
+		if enabled == name {
+			return true
+		}
+	}
+	return false
+}
+
+// settingsFor resolves the effective strategy settings of a symbol
+func (sg *SignalGenerator) settingsFor(symbol string) StrategySettings {
+	learned := sg.learnedParams()
+	settings := StrategySettings{
+		MinConfidence:      learned.MinConfidence,
+		RSIOversold:        learned.RSIOversold,
+		RSIOverbought:      learned.RSIOverbought,
+		StopLossPercentage: sg.cfg.StopLossPercentage,
+		TakeProfitLevels:   sg.cfg.TakeProfitLevels,
+		EnabledIndicators:  strategyIndicators,
+
+		BuyStopLossPercentage:  sg.cfg.BuyStopLossPercentage,
+		SellStopLossPercentage: sg.cfg.SellStopLossPercentage,
+		BuyTakeProfitLevels:    sg.cfg.BuyTakeProfitLevels,
+		SellTakeProfitLevels:   sg.cfg.SellTakeProfitLevels,
+	}
+
+	sg.profilesMu.RLock()
+	profile, exists := sg.profiles[symbol]
+	sg.profilesMu.RUnlock()
+	if !exists {
+		return settings
+	}
+
+	if profile.MinConfidence != nil {
+		settings.MinConfidence = *profile.MinConfidence
+	}
+	if profile.RSIOversold != nil {
+		settings.RSIOversold = *profile.RSIOversold
+	}
+	if profile.RSIOverbought != nil {
+		settings.RSIOverbought = *profile.RSIOverbought
+	}
+	// A coin's own SL/TP applies to both directions
+	if profile.StopLossPercentage != nil {
+		settings.StopLossPercentage = *profile.StopLossPercentage
+		settings.BuyStopLossPercentage, settings.SellStopLossPercentage = 0, 0
+	}
+	if len(profile.TakeProfitLevels) > 0 {
+		settings.TakeProfitLevels = profile.TakeProfitLevels
+		settings.BuyTakeProfitLevels, settings.SellTakeProfitLevels = nil, nil
+	}
+	if len(profile.EnabledIndicators) > 0 {
+		settings.EnabledIndicators = profile.EnabledIndicators
+	}
+
+	return settings
+}
+
+// setStrategyProfile swaps a coin's overrides in place; the next analysis of
+// the coin picks them up
+func (sg *SignalGenerator) setStrategyProfile(profile *models.StrategyProfile) {
+	sg.profilesMu.Lock()
+	defer sg.profilesMu.Unlock()
+
+	if sg.profiles == nil {
+		sg.profiles = make(map[string]*models.StrategyProfile)
+	}
+	stored := *profile
+	sg.profiles[profile.Symbol] = &stored
+}
+
+func (sg *SignalGenerator) removeStrategyProfile(symbol string) {
+	sg.profilesMu.Lock()
+	defer sg.profilesMu.Unlock()
+	delete(sg.profiles, symbol)
+}
+
+func (sg *SignalGenerator) strategyProfile(symbol string) (*models.StrategyProfile, bool) {
+	sg.profilesMu.RLock()
+	defer sg.profilesMu.RUnlock()
+
+	profile, exists := sg.profiles[symbol]
+	if !exists {
+		return nil, false
+	}
+	stored := *profile
+	return &stored, true
+}
+
+// validateStrategyProfile checks the overrides, both on their own and against
+// the global settings they are combined with, and normalizes indicator names
+func (bs *BotService) validateStrategyProfile(profile *models.StrategyProfile) error {
+	if profile.MinConfidence != nil && (*profile.MinConfidence < 0 || *profile.MinConfidence > 1) {
+		return fmt.Errorf("%w: min_confidence must be between 0 and 1", ErrInvalidStrategyProfile)
+	}
+	for name, value := range map[string]*float64{"rsi_oversold": profile.RSIOversold, "rsi_overbought": profile.RSIOverbought} {
+		if value != nil && (*value <= 0 || *value >= 100) {
+			return fmt.Errorf("%w: %s must be between 0 and 100", ErrInvalidStrategyProfile, name)
+		}
+	}
+
+	learned := bs.signalGenerator.learnedParams()
+	oversold, overbought := learned.RSIOversold, learned.RSIOverbought
+	if profile.RSIOversold != nil {
+		oversold = *profile.RSIOversold
+	}
+	if profile.RSIOverbought != nil {
+		overbought = *profile.RSIOverbought
+	}
+	if oversold >= overbought {
+		return fmt.Errorf("%w: rsi_oversold (%.2f) must be below rsi_overbought (%.2f)", ErrInvalidStrategyProfile, oversold, overbought)
+	}
+
+	if profile.StopLossPercentage != nil && (*profile.StopLossPercentage <= 0 || *profile.StopLossPercentage >= 100) {
+		return fmt.Errorf("%w: stop_loss_percentage must be between 0 and 100", ErrInvalidStrategyProfile)
+	}
+
+	for i, level := range profile.TakeProfitLevels {
+		if level <= 0 {
+			return fmt.Errorf("%w: take_profit_levels must be positive", ErrInvalidStrategyProfile)
+		}
+		if bs.cfg.TakeProfitMode != "absolute" && level >= 100 {
+			return fmt.Errorf("%w: take_profit_levels must be below 100%% or SELL targets fall to zero", ErrInvalidStrategyProfile)
+		}
+		if i > 0 && level <= profile.TakeProfitLevels[i-1] {
+			return fmt.Errorf("%w: take_profit_levels must be in ascending order", ErrInvalidStrategyProfile)
+		}
+	}
+
+	seen := make(map[string]bool)
+	indicators := make([]string, 0, len(profile.EnabledIndicators))
+	for _, name := range profile.EnabledIndicators {
+		name = strings.ToLower(strings.TrimSpace(name))
+		known := false
+		for _, indicator := range strategyIndicators {
+			if name == indicator {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: unknown indicator %q, use %s", ErrInvalidStrategyProfile, name, strings.Join(strategyIndicators, ", "))
+		}
+		if !seen[name] {
+			seen[name] = true
+			indicators = append(indicators, name)
+		}
+	}
+	sort.Strings(indicators)
+	profile.EnabledIndicators = indicators
+
+	return nil
+}
+
+// GetStrategyProfile returns a watched coin's overrides (empty when it has
+// none) together with the settings in effect for it
+func (bs *BotService) GetStrategyProfile(symbol string) (*models.StrategyProfile, StrategySettings, error) {
+	if !bs.isWatched(symbol) {
+		return nil, StrategySettings{}, database.ErrCryptocurrencyNotFound
+	}
+
+	profile, exists := bs.signalGenerator.strategyProfile(symbol)
+	if !exists {
+		profile = &models.StrategyProfile{Symbol: symbol}
+	}
+	return profile, bs.signalGenerator.settingsFor(symbol), nil
+}
+
+// SetStrategyProfile validates and persists a coin's overrides, replacing any
+// previous ones, and applies them to signal generation without a restart
+func (bs *BotService) SetStrategyProfile(profile *models.StrategyProfile) (StrategySettings, error) {
+	if bs.db == nil {
+		return StrategySettings{}, fmt.Errorf("database not available")
+	}
+	if !bs.isWatched(profile.Symbol) {
+		return StrategySettings{}, database.ErrCryptocurrencyNotFound
+	}
+	if err := bs.validateStrategyProfile(profile); err != nil {
+		return StrategySettings{}, err
+	}
+
+	if err := bs.db.UpsertStrategyProfile(profile); err != nil {
+		return StrategySettings{}, err
+	}
+	bs.signalGenerator.setStrategyProfile(profile)
+
+	logrus.Info("Updated strategy profile for ", profile.Symbol)
+	return bs.signalGenerator.settingsFor(profile.Symbol), nil
+}
+
+// loadStrategyProfiles applies the stored overrides at startup. Profiles that
+// no longer validate (e.g. after a config change) are skipped with a warning.
+// Without a store the global settings apply.
+func (bs *BotService) loadStrategyProfiles() error {
+	if bs.db == nil {
+		return nil
+	}
+
+	profiles, err := bs.db.GetStrategyProfiles()
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range profiles {
+		if err := bs.validateStrategyProfile(profile); err != nil {
+			logrus.Warn("Ignoring strategy profile for ", profile.Symbol, ": ", err)
+			continue
+		}
+		bs.signalGenerator.setStrategyProfile(profile)
+	}
+
+	logrus.Info("✅ Loaded ", len(profiles), " strategy profiles")
+	return nil
+}
+
+func (bs *BotService) isWatched(symbol string) bool {
+	for _, crypto := range bs.watchlist() {
+		if crypto.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
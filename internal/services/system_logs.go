@@ -0,0 +1,56 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidLogLevel wraps unknown system log levels
+var ErrInvalidLogLevel = errors.New("invalid log level")
+
+// SystemLogLevels are the levels the system_logs table accepts
+var SystemLogLevels = []string{"debug", "info", "warning", "error", "fatal"}
+
+// ParseSystemLogLevel normalizes a level filter; "" means every level and
+// "warn" is accepted for "warning"
+func ParseSystemLogLevel(value string) (string, error) {
+	level := strings.ToLower(strings.TrimSpace(value))
+	if level == "warn" {
+		level = "warning"
+	}
+	if level == "" {
+		return "", nil
+	}
+	for _, known := range SystemLogLevels {
+		if level == known {
+			return level, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q, use one of %s", ErrInvalidLogLevel, value, strings.Join(SystemLogLevels, ", "))
+}
+
+// RecordSystemLog stores an operational event in system_logs for remote
+// viewing. The write runs in the background so an unavailable database never
+// holds up the caller; the event is expected to be logged via logrus as well.
+func (bs *BotService) RecordSystemLog(level, component, message string, context map[string]interface{}) {
+	if bs.db == nil {
+		return
+	}
+	go func() {
+		if err := bs.db.LogSystem(level, component, message, context); err != nil {
+			logrus.Debug("Failed to write system log: ", err)
+		}
+	}()
+}
+
+// GetSystemLogs returns the latest system log entries, optionally of one level
+func (bs *BotService) GetSystemLogs(level string, limit int) ([]models.SystemLog, error) {
+	if bs.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	return bs.db.GetSystemLogs(level, limit)
+}
@@ -2,8 +2,13 @@ package services
 
 import (
 	"crypto-signal-bot/internal/config"
+	"crypto-signal-bot/internal/models"
+	"encoding/json"
+	"fmt"
 	"math"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
@@ -21,21 +26,62 @@ type TechnicalIndicators struct {
 	BBUpper       decimal.Decimal
 	BBMiddle      decimal.Decimal
 	BBLower       decimal.Decimal
-	SMA20         decimal.Decimal
-	EMA12         decimal.Decimal
-	EMA26         decimal.Decimal
+	SMA20         decimal.Decimal // SMA over BB_PERIOD (20 by default)
+	EMA12         decimal.Decimal // EMA over MACD_FAST_PERIOD (12 by default)
+	EMA26         decimal.Decimal // EMA over MACD_SLOW_PERIOD (26 by default)
 	Volume        decimal.Decimal
 	
 	// Additional indicators for decision making
 	StochK        decimal.Decimal
 	StochD        decimal.Decimal
 	Williams      decimal.Decimal
+	ATR           decimal.Decimal // Average True Range (14), in price units
 	
 	// Price action
 	CurrentPrice  decimal.Decimal
 	PreviousPrice decimal.Decimal
 	HighestHigh   decimal.Decimal
 	LowestLow     decimal.Decimal
+	CloseHistory  []decimal.Decimal // Close prices the indicators were computed from, oldest first
+	VolumeNodes   []decimal.Decimal // High-volume node prices (S/R levels), ascending
+	SwingHighs    []decimal.Decimal // Recent pivot highs, ascending
+	SwingLows     []decimal.Decimal // Recent pivot lows, ascending
+	FibSwing      *FibSwing         // Swing range Fibonacci levels are drawn on; nil without clear pivots
+	BaseTrend     string            // Trend of the analyzed klines by the same rule as HTFTrend; empty if unavailable
+	HTFTrend      string            // Higher-timeframe trend: bullish, bearish, neutral; empty if unavailable
+	DailyTrend    string            // 1d trend: bullish, bearish, neutral; empty if unavailable
+	Context       *models.SignalContext // Recent candles and indicator series, if STORE_SIGNAL_CONTEXT is on
+
+	// Indicators left uncomputed for lack of candles, with the candles each needs.
+	// Their fields stay zero, which must not be read as a value.
+	Unavailable map[string]int
+}
+
+// Indicator names used for availability and INDICATOR_MIN_KLINES; the first
+// four match the strategy indicator blocks they feed
+const (
+	indicatorRSI        = "rsi"
+	indicatorMACD       = "macd"
+	indicatorBollinger  = "bollinger"
+	indicatorTrend      = "trend" // SMA20 and EMA12/26
+	indicatorStochastic = "stochastic"
+	indicatorWilliams   = "williams"
+	indicatorATR        = "atr"
+)
+
+// available reports whether an indicator was computed from enough candles.
+// Names that are not candle indicators (e.g. fear_greed) are always available.
+func (ti *TechnicalIndicators) available(name string) bool {
+	_, missing := ti.Unavailable[name]
+	return !missing
+}
+
+// availableValue returns value, or nil when its indicator is unavailable
+func (ti *TechnicalIndicators) availableValue(name string, value *decimal.Decimal) *decimal.Decimal {
+	if !ti.available(name) {
+		return nil
+	}
+	return value
 }
 
 type OHLCV struct {
@@ -53,6 +99,31 @@ func NewTechnicalAnalyzer(cfg *config.Config) *TechnicalAnalyzer {
 	}
 }
 
+// requiredKlines returns the candles each indicator needs: what its periods
+// take to produce a real value, or more when INDICATOR_MIN_KLINES asks so
+func (ta *TechnicalAnalyzer) requiredKlines() map[string]int {
+	trend := ta.cfg.MACDSlowPeriod
+	if ta.cfg.BBPeriod > trend {
+		trend = ta.cfg.BBPeriod
+	}
+
+	required := map[string]int{
+		indicatorRSI:        ta.cfg.RSIPeriod + 1,                                // First change needs a previous close
+		indicatorMACD:       ta.cfg.MACDSlowPeriod + ta.cfg.MACDSignalPeriod - 1, // Slow EMA, then a signal EMA over the MACD values
+		indicatorBollinger:  ta.cfg.BBPeriod,
+		indicatorTrend:      trend, // Slow EMA and the Bollinger SMA
+		indicatorStochastic: 14 + 3 - 1, // %D averages the last 3 %K windows
+		indicatorWilliams:   14,
+		indicatorATR:        14 + 1,
+	}
+	for name, minimum := range ta.cfg.IndicatorMinKlines {
+		if current, known := required[name]; known && minimum > current {
+			required[name] = minimum
+		}
+	}
+	return required
+}
+
 func (ta *TechnicalAnalyzer) AnalyzeMarketData(marketData *MarketData) (*TechnicalIndicators, error) {
 	logrus.Debug("Analyzing technical indicators for: ", marketData.Symbol)
 
@@ -62,14 +133,35 @@ func (ta *TechnicalAnalyzer) AnalyzeMarketData(marketData *MarketData) (*Technic
 		return nil, err
 	}
 
-	if len(ohlcvData) < 26 {
-		logrus.Warn("Insufficient data for technical analysis, need at least 26 periods")
-		return nil, err
-	}
-
 	indicators := &TechnicalIndicators{
 		CurrentPrice: marketData.Price,
 		Volume:       marketData.Volume24h,
+		Unavailable:  make(map[string]int),
+	}
+
+	for name, required := range ta.requiredKlines() {
+		if len(ohlcvData) < required {
+			indicators.Unavailable[name] = required
+		}
+	}
+
+	// Without any of the decision indicators there is nothing to analyze
+	fewestRequired := 0
+	for _, name := range []string{indicatorRSI, indicatorMACD, indicatorBollinger, indicatorTrend} {
+		required, missing := indicators.Unavailable[name]
+		if !missing {
+			fewestRequired = 0
+			break
+		}
+		if fewestRequired == 0 || required < fewestRequired {
+			fewestRequired = required
+		}
+	}
+	if fewestRequired > 0 {
+		return nil, fmt.Errorf("insufficient data for technical analysis: %d candles, need at least %d", len(ohlcvData), fewestRequired)
+	}
+	if len(indicators.Unavailable) > 0 {
+		logrus.Debugf("%s: %d candles, unavailable indicators: %v", marketData.Symbol, len(ohlcvData), indicators.Unavailable)
 	}
 
 	// Extract close prices for calculations
@@ -83,29 +175,68 @@ func (ta *TechnicalAnalyzer) AnalyzeMarketData(marketData *MarketData) (*Technic
 		lowPrices[i] = ohlcv.Low
 	}
 
-	// Calculate RSI (14 periods)
-	indicators.RSI = ta.calculateRSI(closePrices, 14)
+	indicators.CloseHistory = closePrices
+
+	// Heikin-Ashi feeds the oscillators and averages; ATR and price levels
+	// (swings, volume nodes, 20-period extremes) stay on the real candles
+	indicatorCloses, indicatorHighs, indicatorLows := closePrices, highPrices, lowPrices
+	if ta.cfg.CandleType == "heikin_ashi" {
+		haData := heikinAshiCandles(ohlcvData)
+		indicatorCloses = make([]decimal.Decimal, len(haData))
+		indicatorHighs = make([]decimal.Decimal, len(haData))
+		indicatorLows = make([]decimal.Decimal, len(haData))
+		for i, candle := range haData {
+			indicatorCloses[i] = candle.Close
+			indicatorHighs[i] = candle.High
+			indicatorLows[i] = candle.Low
+		}
+	}
 
-	// Calculate MACD (12, 26, 9)
-	indicators.EMA12 = ta.calculateEMA(closePrices, 12)
-	indicators.EMA26 = ta.calculateEMA(closePrices, 26)
-	indicators.MACDLine = indicators.EMA12.Sub(indicators.EMA26)
-	
-	// Calculate MACD Signal line (9-period EMA of MACD line)
-	macdValues := ta.calculateMACDHistory(closePrices, 12, 26)
-	indicators.MACDSignal = ta.calculateEMA(macdValues, 9)
-	indicators.MACDHistogram = indicators.MACDLine.Sub(indicators.MACDSignal)
-
-	// Calculate Bollinger Bands (20 periods, 2 std dev)
-	indicators.SMA20 = ta.calculateSMA(closePrices, 20)
-	indicators.BBMiddle = indicators.SMA20
-	stdDev := ta.calculateStandardDeviation(closePrices, 20)
-	indicators.BBUpper = indicators.BBMiddle.Add(stdDev.Mul(decimal.NewFromInt(2)))
-	indicators.BBLower = indicators.BBMiddle.Sub(stdDev.Mul(decimal.NewFromInt(2)))
+	// Each indicator is only computed with enough candles; the rest stay in Unavailable
+
+	// Calculate RSI (RSI_PERIOD, 14 by default)
+	if indicators.available(indicatorRSI) {
+		indicators.RSI = ta.calculateRSI(indicatorCloses, ta.cfg.RSIPeriod)
+	}
+
+	// The fast/slow EMAs feed both the trend and MACD; the BB SMA both the trend and the bands
+	if indicators.available(indicatorTrend) || indicators.available(indicatorMACD) {
+		indicators.EMA12 = ta.calculateEMA(indicatorCloses, ta.cfg.MACDFastPeriod)
+		indicators.EMA26 = ta.calculateEMA(indicatorCloses, ta.cfg.MACDSlowPeriod)
+	}
+	if indicators.available(indicatorTrend) || indicators.available(indicatorBollinger) {
+		indicators.SMA20 = ta.calculateSMA(indicatorCloses, ta.cfg.BBPeriod)
+	}
+
+	// Calculate MACD (12, 26, 9 by default)
+	if indicators.available(indicatorMACD) {
+		indicators.MACDLine = indicators.EMA12.Sub(indicators.EMA26)
+
+		// Calculate MACD Signal line (EMA of the MACD line)
+		macdValues := ta.calculateMACDHistory(indicatorCloses, ta.cfg.MACDFastPeriod, ta.cfg.MACDSlowPeriod)
+		indicators.MACDSignal = ta.calculateEMA(macdValues, ta.cfg.MACDSignalPeriod)
+		indicators.MACDHistogram = indicators.MACDLine.Sub(indicators.MACDSignal)
+	}
+
+	// Calculate Bollinger Bands (20 periods, 2 std dev by default)
+	if indicators.available(indicatorBollinger) {
+		indicators.BBMiddle = indicators.SMA20
+		stdDev := ta.calculateStandardDeviation(indicatorCloses, ta.cfg.BBPeriod)
+		width := stdDev.Mul(decimal.NewFromFloat(ta.cfg.BBStdDev))
+		indicators.BBUpper = indicators.BBMiddle.Add(width)
+		indicators.BBLower = indicators.BBMiddle.Sub(width)
+	}
 
 	// Calculate additional indicators
-	indicators.StochK, indicators.StochD = ta.calculateStochastic(highPrices, lowPrices, closePrices, 14, 3)
-	indicators.Williams = ta.calculateWilliamsR(highPrices, lowPrices, closePrices, 14)
+	if indicators.available(indicatorStochastic) {
+		indicators.StochK, indicators.StochD = ta.calculateStochastic(indicatorHighs, indicatorLows, indicatorCloses, 14, 3)
+	}
+	if indicators.available(indicatorWilliams) {
+		indicators.Williams = ta.calculateWilliamsR(indicatorHighs, indicatorLows, indicatorCloses, 14)
+	}
+	if indicators.available(indicatorATR) {
+		indicators.ATR = ta.calculateATR(highPrices, lowPrices, closePrices, 14)
+	}
 
 	// Price action analysis
 	if len(closePrices) > 1 {
@@ -114,33 +245,138 @@ func (ta *TechnicalAnalyzer) AnalyzeMarketData(marketData *MarketData) (*Technic
 	indicators.HighestHigh = ta.findHighest(highPrices, 20)
 	indicators.LowestLow = ta.findLowest(lowPrices, 20)
 
+	if ta.cfg.HTFConfirmationEnabled {
+		indicators.BaseTrend = ta.calculateEMATrend(closePrices)
+	}
+	if len(marketData.HTFKlineData) > 0 {
+		indicators.HTFTrend = ta.calculateHTFTrend(marketData.HTFKlineData)
+	}
+	if len(marketData.DailyKlineData) > 0 {
+		indicators.DailyTrend = ta.calculateDailyTrend(marketData.DailyKlineData, ta.cfg.DailyTrendEMAPeriod)
+	}
+
+	if ta.cfg.UseVolumeProfile {
+		indicators.VolumeNodes = ta.calculateVolumeNodes(ohlcvData, ta.cfg.VolumeProfileBins, ta.cfg.VolumeProfileNodes)
+	}
+
+	if ta.cfg.UseSwingLevels {
+		indicators.SwingHighs, indicators.SwingLows = ta.calculateSwingLevels(ohlcvData, ta.cfg.SwingLookback, ta.cfg.SwingPivotStrength)
+	}
+
+	if ta.cfg.UseFibTargets {
+		indicators.FibSwing = calculateFibSwing(ohlcvData, ta.cfg.SwingLookback, ta.cfg.SwingPivotStrength)
+	}
+
+	if ta.cfg.StoreSignalContext && ta.cfg.SignalContextCandles > 0 {
+		indicators.Context = ta.buildSignalContext(ohlcvData, indicatorCloses, ta.cfg.SignalContextCandles)
+	}
+
 	logrus.Debug("Technical analysis completed for: ", marketData.Symbol)
 	return indicators, nil
 }
 
+// heikinAshiCandles converts candles to Heikin-Ashi. Each HA close is the
+// candle's OHLC average and each HA open the midpoint of the previous HA
+// candle's body, seeded with the first real candle's (open + close) / 2.
+func heikinAshiCandles(candles []OHLCV) []OHLCV {
+	ha := make([]OHLCV, len(candles))
+	two := decimal.NewFromInt(2)
+	four := decimal.NewFromInt(4)
+
+	for i, candle := range candles {
+		haClose := candle.Open.Add(candle.High).Add(candle.Low).Add(candle.Close).Div(four)
+		haOpen := candle.Open.Add(candle.Close).Div(two)
+		if i > 0 {
+			haOpen = ha[i-1].Open.Add(ha[i-1].Close).Div(two)
+		}
+
+		ha[i] = OHLCV{
+			Open:      haOpen,
+			High:      decimal.Max(candle.High, haOpen, haClose),
+			Low:       decimal.Min(candle.Low, haOpen, haClose),
+			Close:     haClose,
+			Volume:    candle.Volume,
+			Timestamp: candle.Timestamp,
+		}
+	}
+	return ha
+}
+
+// buildSignalContext captures the last n candles with each indicator recomputed
+// as of that candle, so a signal's chart can be reconstructed later. Points
+// without enough history are zero, matching the scalar calculations.
+func (ta *TechnicalAnalyzer) buildSignalContext(ohlcvData []OHLCV, closePrices []decimal.Decimal, n int) *models.SignalContext {
+	start := len(ohlcvData) - n
+	if start < 0 {
+		start = 0
+	}
+
+	context := &models.SignalContext{
+		Candles:    make([]models.ContextCandle, 0, len(ohlcvData)-start),
+		Indicators: make(map[string][]decimal.Decimal),
+	}
+
+	bandWidth := decimal.NewFromFloat(ta.cfg.BBStdDev)
+	for i := start; i < len(ohlcvData); i++ {
+		candle := ohlcvData[i]
+		context.Candles = append(context.Candles, models.ContextCandle{
+			OpenTime: time.UnixMilli(candle.Timestamp).UTC(),
+			Open:     candle.Open,
+			High:     candle.High,
+			Low:      candle.Low,
+			Close:    candle.Close,
+			Volume:   candle.Volume,
+		})
+
+		prices := closePrices[:i+1]
+		ema12 := ta.calculateEMA(prices, ta.cfg.MACDFastPeriod)
+		ema26 := ta.calculateEMA(prices, ta.cfg.MACDSlowPeriod)
+		sma20 := ta.calculateSMA(prices, ta.cfg.BBPeriod)
+		stdDev := ta.calculateStandardDeviation(prices, ta.cfg.BBPeriod)
+
+		macd := decimal.Zero
+		if !ema26.IsZero() {
+			macd = ema12.Sub(ema26)
+		}
+
+		context.Indicators["rsi"] = append(context.Indicators["rsi"], ta.calculateRSI(prices, ta.cfg.RSIPeriod))
+		context.Indicators["ema_12"] = append(context.Indicators["ema_12"], ema12)
+		context.Indicators["ema_26"] = append(context.Indicators["ema_26"], ema26)
+		context.Indicators["macd_line"] = append(context.Indicators["macd_line"], macd)
+		context.Indicators["sma_20"] = append(context.Indicators["sma_20"], sma20)
+		context.Indicators["bb_upper"] = append(context.Indicators["bb_upper"], sma20.Add(stdDev.Mul(bandWidth)))
+		context.Indicators["bb_lower"] = append(context.Indicators["bb_lower"], sma20.Sub(stdDev.Mul(bandWidth)))
+	}
+
+	return context
+}
+
 func (ta *TechnicalAnalyzer) parseKlineData(klineData [][]interface{}) ([]OHLCV, error) {
 	var ohlcvData []OHLCV
+	skipped := 0
 
 	for _, kline := range klineData {
 		if len(kline) < 6 {
 			continue
 		}
 
-		// Parse timestamp
-		timestamp, _ := kline[0].(float64)
-		
-		// Parse OHLCV values
-		openStr, _ := kline[1].(string)
-		highStr, _ := kline[2].(string)
-		lowStr, _ := kline[3].(string)
-		closeStr, _ := kline[4].(string)
-		volumeStr, _ := kline[5].(string)
-
-		open, _ := decimal.NewFromString(openStr)
-		high, _ := decimal.NewFromString(highStr)
-		low, _ := decimal.NewFromString(lowStr)
-		close, _ := decimal.NewFromString(closeStr)
-		volume, _ := decimal.NewFromString(volumeStr)
+		// Fields may be strings (Binance spot), numbers or json.Number depending on the source
+		timestamp, err := klineInt(kline[0])
+		if err != nil {
+			skipped++
+			continue
+		}
+		var values [5]decimal.Decimal
+		for i := range values {
+			if values[i], err = klineDecimal(kline[i+1]); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			skipped++
+			continue
+		}
+		open, high, low, close, volume := values[0], values[1], values[2], values[3], values[4]
 
 		ohlcv := OHLCV{
 			Open:      open,
@@ -148,15 +384,48 @@ func (ta *TechnicalAnalyzer) parseKlineData(klineData [][]interface{}) ([]OHLCV,
 			Low:       low,
 			Close:     close,
 			Volume:    volume,
-			Timestamp: int64(timestamp),
+			Timestamp: timestamp,
 		}
 
 		ohlcvData = append(ohlcvData, ohlcv)
 	}
 
+	if skipped > 0 {
+		logrus.Warnf("Skipped %d of %d klines with unparseable fields", skipped, len(klineData))
+	}
+
 	return ohlcvData, nil
 }
 
+// klineDecimal converts a kline price/volume field, which upstreams encode as
+// a string, a JSON number or json.Number
+func klineDecimal(value interface{}) (decimal.Decimal, error) {
+	switch v := value.(type) {
+	case string:
+		return decimal.NewFromString(v)
+	case json.Number:
+		return decimal.NewFromString(v.String())
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	case int64:
+		return decimal.NewFromInt(v), nil
+	case int:
+		return decimal.NewFromInt(int64(v)), nil
+	default:
+		return decimal.Zero, fmt.Errorf("unsupported kline value type %T", value)
+	}
+}
+
+// klineInt converts a kline timestamp field (milliseconds) from any of the
+// encodings klineDecimal accepts
+func klineInt(value interface{}) (int64, error) {
+	d, err := klineDecimal(value)
+	if err != nil {
+		return 0, err
+	}
+	return d.IntPart(), nil
+}
+
 func (ta *TechnicalAnalyzer) calculateRSI(prices []decimal.Decimal, period int) decimal.Decimal {
 	if len(prices) < period+1 {
 		return decimal.Zero
@@ -246,13 +515,13 @@ func (ta *TechnicalAnalyzer) calculateMACDHistory(prices []decimal.Decimal, fast
 		return macdValues
 	}
 
-	// Calculate EMAs for each point to get MACD history
+	// EMAs at each point give the MACD history; one pass per EMA instead of
+	// recomputing both from the start for every point
+	emaFast := ta.calculateEMASeries(prices, fastPeriod)
+	emaSlow := ta.calculateEMASeries(prices, slowPeriod)
 	for i := slowPeriod - 1; i < len(prices); i++ {
 		if i >= fastPeriod-1 {
-			subPrices := prices[:i+1]
-			ema12 := ta.calculateEMA(subPrices, fastPeriod)
-			ema26 := ta.calculateEMA(subPrices, slowPeriod)
-			macd := ema12.Sub(ema26)
+			macd := emaFast[i].Sub(emaSlow[i])
 			macdValues = append(macdValues, macd)
 		}
 	}
@@ -260,6 +529,30 @@ func (ta *TechnicalAnalyzer) calculateMACDHistory(prices []decimal.Decimal, fast
 	return macdValues
 }
 
+// calculateEMASeries returns calculateEMA(prices[:i+1], period) at every index
+// i, computed in one pass; entries before period-1 are zero
+func (ta *TechnicalAnalyzer) calculateEMASeries(prices []decimal.Decimal, period int) []decimal.Decimal {
+	series := make([]decimal.Decimal, len(prices))
+	if period < 1 || len(prices) < period {
+		return series
+	}
+
+	sum := decimal.Zero
+	for i := 0; i < period; i++ {
+		sum = sum.Add(prices[i])
+	}
+	ema := sum.Div(decimal.NewFromInt(int64(period)))
+	series[period-1] = ema
+
+	multiplier := decimal.NewFromInt(2).Div(decimal.NewFromInt(int64(period + 1)))
+	for i := period; i < len(prices); i++ {
+		ema = prices[i].Sub(ema).Mul(multiplier).Add(ema)
+		series[i] = ema
+	}
+
+	return series
+}
+
 func (ta *TechnicalAnalyzer) calculateStandardDeviation(prices []decimal.Decimal, period int) decimal.Decimal {
 	if len(prices) < period {
 		return decimal.Zero
@@ -280,23 +573,31 @@ func (ta *TechnicalAnalyzer) calculateStandardDeviation(prices []decimal.Decimal
 	return stdDev
 }
 
+// calculateStochastic returns the latest %K over kPeriod candles and %D, the
+// SMA of the last dPeriod %K values. It needs kPeriod+dPeriod-1 candles.
 func (ta *TechnicalAnalyzer) calculateStochastic(highs, lows, closes []decimal.Decimal, kPeriod, dPeriod int) (decimal.Decimal, decimal.Decimal) {
-	if len(closes) < kPeriod {
+	if kPeriod < 1 || dPeriod < 1 || len(closes) < kPeriod+dPeriod-1 {
 		return decimal.Zero, decimal.Zero
 	}
 
-	// Calculate %K
-	currentClose := closes[len(closes)-1]
-	highestHigh := ta.findHighest(highs[len(highs)-kPeriod:], kPeriod)
-	lowestLow := ta.findLowest(lows[len(lows)-kPeriod:], kPeriod)
-
-	stochK := decimal.Zero
-	if !highestHigh.Equal(lowestLow) {
-		stochK = currentClose.Sub(lowestLow).Div(highestHigh.Sub(lowestLow)).Mul(decimal.NewFromInt(100))
+	// %K of the window ending at candle end (inclusive)
+	stochKAt := func(end int) decimal.Decimal {
+		highestHigh := ta.findHighest(highs[end+1-kPeriod:end+1], kPeriod)
+		lowestLow := ta.findLowest(lows[end+1-kPeriod:end+1], kPeriod)
+		if highestHigh.Equal(lowestLow) {
+			return decimal.Zero
+		}
+		return closes[end].Sub(lowestLow).Div(highestHigh.Sub(lowestLow)).Mul(decimal.NewFromInt(100))
 	}
 
-	// For %D, we'd need historical %K values, simplified here
-	stochD := stochK // Simplified - in practice, this should be SMA of %K
+	last := len(closes) - 1
+	stochK := stochKAt(last)
+
+	sumK := stochK
+	for end := last - dPeriod + 1; end < last; end++ {
+		sumK = sumK.Add(stochKAt(end))
+	}
+	stochD := sumK.Div(decimal.NewFromInt(int64(dPeriod)))
 
 	return stochK, stochD
 }
@@ -318,6 +619,38 @@ func (ta *TechnicalAnalyzer) calculateWilliamsR(highs, lows, closes []decimal.De
 	return williamsR
 }
 
+// calculateATR returns Wilder's Average True Range: the true range averaged
+// over the first period candles, then smoothed by (prev*(n-1) + tr) / n
+func (ta *TechnicalAnalyzer) calculateATR(highs, lows, closes []decimal.Decimal, period int) decimal.Decimal {
+	if len(closes) <= period {
+		return decimal.Zero
+	}
+
+	trueRange := func(i int) decimal.Decimal {
+		tr := highs[i].Sub(lows[i])
+		if fromHigh := highs[i].Sub(closes[i-1]).Abs(); fromHigh.GreaterThan(tr) {
+			tr = fromHigh
+		}
+		if fromLow := lows[i].Sub(closes[i-1]).Abs(); fromLow.GreaterThan(tr) {
+			tr = fromLow
+		}
+		return tr
+	}
+
+	n := decimal.NewFromInt(int64(period))
+	atr := decimal.Zero
+	for i := 1; i <= period; i++ {
+		atr = atr.Add(trueRange(i))
+	}
+	atr = atr.Div(n)
+
+	for i := period + 1; i < len(closes); i++ {
+		atr = atr.Mul(n.Sub(decimal.NewFromInt(1))).Add(trueRange(i)).Div(n)
+	}
+
+	return atr
+}
+
 func (ta *TechnicalAnalyzer) findHighest(prices []decimal.Decimal, period int) decimal.Decimal {
 	if len(prices) == 0 {
 		return decimal.Zero
@@ -357,3 +690,187 @@ func (ta *TechnicalAnalyzer) findLowest(prices []decimal.Decimal, period int) de
 
 	return lowest
 }
+
+// calculateSwingLevels finds pivot highs and lows within the last lookback
+// candles: a pivot high is above the `strength` candles before it and not
+// below the ones after it (lows mirrored). Levels are returned ascending.
+func (ta *TechnicalAnalyzer) calculateSwingLevels(data []OHLCV, lookback, strength int) (highs, lows []decimal.Decimal) {
+	highPivots, lowPivots := findSwingPivots(data, lookback, strength)
+	for _, pivot := range highPivots {
+		highs = append(highs, pivot.price)
+	}
+	for _, pivot := range lowPivots {
+		lows = append(lows, pivot.price)
+	}
+
+	sort.Slice(highs, func(i, j int) bool { return highs[i].LessThan(highs[j]) })
+	sort.Slice(lows, func(i, j int) bool { return lows[i].LessThan(lows[j]) })
+	return highs, lows
+}
+
+// swingPivot is a pivot high or low and the candle it formed on
+type swingPivot struct {
+	index int
+	price decimal.Decimal
+}
+
+// findSwingPivots returns the pivot highs and lows of the last lookback
+// candles in time order: candles whose high (low) exceeds the strength
+// candles on each side
+func findSwingPivots(data []OHLCV, lookback, strength int) (highs, lows []swingPivot) {
+	if strength < 1 || lookback <= 2*strength {
+		return nil, nil
+	}
+	if len(data) > lookback {
+		data = data[len(data)-lookback:]
+	}
+
+	for i := strength; i < len(data)-strength; i++ {
+		isHigh, isLow := true, true
+		for j := 1; j <= strength; j++ {
+			if !data[i].High.GreaterThan(data[i-j].High) || data[i].High.LessThan(data[i+j].High) {
+				isHigh = false
+			}
+			if !data[i].Low.LessThan(data[i-j].Low) || data[i].Low.GreaterThan(data[i+j].Low) {
+				isLow = false
+			}
+		}
+		if isHigh {
+			highs = append(highs, swingPivot{index: i, price: data[i].High})
+		}
+		if isLow {
+			lows = append(lows, swingPivot{index: i, price: data[i].Low})
+		}
+	}
+	return highs, lows
+}
+
+// calculateVolumeNodes buckets each candle's typical price into equal-width bins
+// weighted by volume and returns the midpoints of the heaviest local peaks,
+// ascending by price
+func (ta *TechnicalAnalyzer) calculateVolumeNodes(data []OHLCV, bins, maxNodes int) []decimal.Decimal {
+	if len(data) == 0 || bins < 3 || maxNodes <= 0 {
+		return nil
+	}
+
+	low := data[0].Low
+	high := data[0].High
+	for _, candle := range data {
+		if candle.Low.LessThan(low) {
+			low = candle.Low
+		}
+		if candle.High.GreaterThan(high) {
+			high = candle.High
+		}
+	}
+
+	priceRange := high.Sub(low)
+	if !priceRange.IsPositive() {
+		return nil
+	}
+	binWidth := priceRange.Div(decimal.NewFromInt(int64(bins)))
+
+	volumes := make([]float64, bins)
+	totalVolume := 0.0
+	for _, candle := range data {
+		typical := candle.High.Add(candle.Low).Add(candle.Close).Div(decimal.NewFromInt(3))
+		idx := int(typical.Sub(low).Div(binWidth).IntPart())
+		if idx >= bins {
+			idx = bins - 1
+		}
+		if idx < 0 {
+			idx = 0
+		}
+		volume := candle.Volume.InexactFloat64()
+		volumes[idx] += volume
+		totalVolume += volume
+	}
+
+	// A node is a local peak carrying more than the average bin volume
+	average := totalVolume / float64(bins)
+	var peaks []int
+	for i, volume := range volumes {
+		if volume <= average {
+			continue
+		}
+		if (i > 0 && volumes[i-1] > volume) || (i < bins-1 && volumes[i+1] > volume) {
+			continue
+		}
+		peaks = append(peaks, i)
+	}
+
+	sort.Slice(peaks, func(a, b int) bool {
+		return volumes[peaks[a]] > volumes[peaks[b]]
+	})
+	if len(peaks) > maxNodes {
+		peaks = peaks[:maxNodes]
+	}
+	sort.Ints(peaks)
+
+	nodes := make([]decimal.Decimal, len(peaks))
+	for i, idx := range peaks {
+		nodes[i] = low.Add(binWidth.Mul(decimal.NewFromFloat(float64(idx) + 0.5)))
+	}
+
+	return nodes
+}
+
+// calculateHTFTrend classifies the higher-timeframe trend of its klines
+func (ta *TechnicalAnalyzer) calculateHTFTrend(klineData [][]interface{}) string {
+	ohlcvData, err := ta.parseKlineData(klineData)
+	if err != nil {
+		return ""
+	}
+
+	closes := make([]decimal.Decimal, len(ohlcvData))
+	for i, ohlcv := range ohlcvData {
+		closes[i] = ohlcv.Close
+	}
+	return ta.calculateEMATrend(closes)
+}
+
+// calculateEMATrend classifies a trend from the 12/26 EMAs of the closes.
+// It is only bullish or bearish when the EMAs and the last close all agree.
+func (ta *TechnicalAnalyzer) calculateEMATrend(closes []decimal.Decimal) string {
+	if len(closes) < 26 {
+		return ""
+	}
+
+	emaFast := ta.calculateEMA(closes, 12)
+	emaSlow := ta.calculateEMA(closes, 26)
+	lastClose := closes[len(closes)-1]
+
+	if emaFast.GreaterThan(emaSlow) && lastClose.GreaterThan(emaSlow) {
+		return "bullish"
+	}
+	if emaFast.LessThan(emaSlow) && lastClose.LessThan(emaSlow) {
+		return "bearish"
+	}
+	return "neutral"
+}
+
+// calculateDailyTrend classifies the daily trend from the last close against
+// an EMA: bullish above a rising EMA, bearish below a falling one
+func (ta *TechnicalAnalyzer) calculateDailyTrend(klineData [][]interface{}, period int) string {
+	ohlcvData, err := ta.parseKlineData(klineData)
+	if err != nil || period < 2 || len(ohlcvData) < period+1 {
+		return ""
+	}
+
+	closes := make([]decimal.Decimal, len(ohlcvData))
+	for i, ohlcv := range ohlcvData {
+		closes[i] = ohlcv.Close
+	}
+
+	ema := ta.calculateEMA(closes, period)
+	previousEMA := ta.calculateEMA(closes[:len(closes)-1], period)
+	lastClose := closes[len(closes)-1]
+
+	if lastClose.GreaterThan(ema) && ema.GreaterThan(previousEMA) {
+		return "bullish"
+	}
+	if lastClose.LessThan(ema) && ema.LessThan(previousEMA) {
+		return "bearish"
+	}
+	return "neutral"
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestHeikinAshiCandles(t *testing.T) {
+	candles := []OHLCV{
+		{Open: dec("10"), High: dec("12"), Low: dec("9"), Close: dec("11")},
+		{Open: dec("11"), High: dec("14"), Low: dec("10"), Close: dec("13")},
+		{Open: dec("13"), High: dec("13.5"), Low: dec("11"), Close: dec("12")},
+		{Open: dec("8"), High: dec("8.5"), Low: dec("7"), Close: dec("8")}, // Gap down below the previous HA body
+	}
+
+	// [open, high, low, close]: the first open is the raw body midpoint, later
+	// opens the previous HA body midpoint; close is the OHLC average
+	want := [][4]string{
+		{"10.5", "12", "9", "10.5"},
+		{"10.5", "14", "10", "12"},
+		{"11.25", "13.5", "11", "12.375"},
+		{"11.8125", "11.8125", "7", "7.875"}, // High taken from the HA open
+	}
+
+	ha := heikinAshiCandles(candles)
+	if len(ha) != len(want) {
+		t.Fatalf("got %d candles, want %d", len(ha), len(want))
+	}
+	for i, values := range want {
+		got := [4]decimal.Decimal{ha[i].Open, ha[i].High, ha[i].Low, ha[i].Close}
+		for j, field := range []string{"open", "high", "low", "close"} {
+			if !got[j].Equal(dec(values[j])) {
+				t.Errorf("candle %d %s = %s, want %s", i, field, got[j], values[j])
+			}
+		}
+	}
+}
+
+func TestCalculateStochastic(t *testing.T) {
+	decs := func(values ...string) []decimal.Decimal {
+		out := make([]decimal.Decimal, len(values))
+		for i, value := range values {
+			out[i] = dec(value)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name                string
+		highs, lows, closes []decimal.Decimal
+		wantK, wantD        string
+	}{
+		{
+			// %K(last 3) = (12-9)/(13-9) = 75, %K(previous 3) = (10-8)/(12-8) = 50
+			name:   "rising closes",
+			highs:  decs("10", "12", "11", "13"),
+			lows:   decs("8", "9", "9", "10"),
+			closes: decs("9", "11", "10", "12"),
+			wantK:  "75",
+			wantD:  "62.5",
+		},
+		{
+			name:   "flat range",
+			highs:  decs("5", "5", "5", "5"),
+			lows:   decs("5", "5", "5", "5"),
+			closes: decs("5", "5", "5", "5"),
+			wantK:  "0",
+			wantD:  "0",
+		},
+		{
+			// Only the latest window is flat: its %K is 0, the previous (5-4)/(6-4) = 50
+			name:   "flat latest window",
+			highs:  decs("6", "5", "5", "5"),
+			lows:   decs("4", "5", "5", "5"),
+			closes: decs("5", "5", "5", "5"),
+			wantK:  "0",
+			wantD:  "25",
+		},
+		{
+			name:   "too few candles",
+			highs:  decs("10", "12", "11"),
+			lows:   decs("8", "9", "9"),
+			closes: decs("9", "11", "10"),
+			wantK:  "0",
+			wantD:  "0",
+		},
+	}
+
+	ta := NewTechnicalAnalyzer(testConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, d := ta.calculateStochastic(tt.highs, tt.lows, tt.closes, 3, 2)
+			if !k.Equal(dec(tt.wantK)) {
+				t.Errorf("%%K = %s, want %s", k, tt.wantK)
+			}
+			if !d.Equal(dec(tt.wantD)) {
+				t.Errorf("%%D = %s, want %s", d, tt.wantD)
+			}
+		})
+	}
+}
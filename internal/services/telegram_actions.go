@@ -1,22 +1,34 @@
 package services
 
 import (
+	"crypto-signal-bot/internal/database"
 	"crypto-signal-bot/internal/models"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
 
-// runManualAnalysis triggers manual market analysis
-func (ns *NotificationService) runManualAnalysis(chatID int64) {
-	if ns.botService == nil {
+// runManualAnalysis triggers manual market analysis. force bypasses the daily
+// signal cap and is limited to the owner chat.
+func (ns *NotificationService) runManualAnalysis(chatID int64, force bool) {
+	if force && !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk analisis paksa")
+		return
+	}
+
+	botService := ns.getBotService()
+	if botService == nil {
 		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
 		return
 	}
+	limitsBypassed := force && botService.DailyLimitReached()
 
 	// Send "analyzing" message
 	msg := tgbotapi.NewMessage(chatID, "🔍 *Memulai analisis manual...*\n\nMohon tunggu, sedang menganalisis market...")
@@ -25,7 +37,7 @@ func (ns *NotificationService) runManualAnalysis(chatID int64) {
 
 	// Run analysis
 	go func() {
-		err := ns.botService.RunAnalysis()
+		err := botService.RunManualAnalysis(force)
 		
 		var resultMessage string
 		if err != nil {
@@ -39,9 +51,12 @@ func (ns *NotificationService) runManualAnalysis(chatID int64) {
 
 Analisis berikutnya akan berjalan otomatis sesuai jadwal.`,
 				time.Now().Format("15:04 02/01/2006"),
-				len(ns.botService.cryptoList),
+				botService.watchlistSize(),
 			)
 		}
+		if err == nil && limitsBypassed {
+			resultMessage += "\n\n⚠️ _Batas sinyal harian dilewati (analisis paksa)_"
+		}
 
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
@@ -57,17 +72,442 @@ Analisis berikutnya akan berjalan otomatis sesuai jadwal.`,
 	}()
 }
 
+// runManualOptimization runs learning optimization on demand and reports the changes
+func (ns *NotificationService) runManualOptimization(chatID int64) {
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	result, err := botService.RunLearningOptimization()
+	if err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Optimasi gagal: %s", err.Error()))
+		return
+	}
+
+	var message strings.Builder
+	message.WriteString("🧠 *Optimasi Learning Selesai*\n\n")
+	message.WriteString(fmt.Sprintf("📊 *Win Rate:* %.1f%%\n", result.Metrics.WinRate.InexactFloat64()))
+	message.WriteString(fmt.Sprintf("📈 *Total Sinyal:* %d\n\n", result.Metrics.TotalSignals))
+
+	if len(result.Changes) == 0 {
+		message.WriteString("_Tidak ada parameter yang diubah_")
+	} else {
+		message.WriteString("🔧 *Perubahan:*\n")
+		for _, change := range result.Changes {
+			message.WriteString(fmt.Sprintf("• `%s`: %.4g → %.4g\n", change.Parameter, change.OldValue, change.NewValue))
+		}
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🧠 Learning Stats", "learning_stats"),
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Menu Utama", "main_menu"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, message.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	ns.telegramBot.Send(msg)
+}
+
+// sendSignalByRef looks up a signal by its reference code and sends its details
+func (ns *NotificationService) sendSignalByRef(chatID int64, code string) {
+	botService := ns.getBotService()
+	if botService == nil || botService.db == nil {
+		ns.sendErrorMessage(chatID, "Database tidak tersedia")
+		return
+	}
+
+	if code == "" {
+		ns.sendErrorMessage(chatID, "Gunakan: /signal <kode>\nContoh: /signal BTC-240612-A3F")
+		return
+	}
+
+	signal, err := botService.db.GetSignalByRefCode(strings.ToUpper(code))
+	if err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Sinyal `%s` tidak ditemukan", code))
+		return
+	}
+
+	symbol := strings.SplitN(signal.RefCode, "-", 2)[0]
+	var coin *models.Cryptocurrency
+	for _, crypto := range botService.watchlist() {
+		if crypto.ID == signal.CryptoID {
+			symbol = crypto.Symbol
+			coin = crypto
+			break
+		}
+	}
+
+	message := fmt.Sprintf(`🔖 *Sinyal %s*
+
+🪙 *%s/USDT*
+📈 *Action:* %s
+💵 *Entry Price:* $%s
+🎯 *Confidence:* %.1f%%
+📊 *Status:* %s`,
+		signal.RefCode,
+		symbol,
+		signal.Action,
+		ns.formatPrice(coin, signal.EntryPrice),
+		signal.ConfidenceScore.InexactFloat64()*100,
+		signal.Status,
+	)
+
+	if signal.StopLoss != nil {
+		message += fmt.Sprintf("\n• Stop Loss: $%s", ns.formatPrice(coin, *signal.StopLoss))
+	}
+	if signal.TakeProfit1 != nil {
+		message += fmt.Sprintf("\n• Take Profit 1: $%s", ns.formatPrice(coin, *signal.TakeProfit1))
+	}
+	if signal.TakeProfit2 != nil {
+		message += fmt.Sprintf("\n• Take Profit 2: $%s", ns.formatPrice(coin, *signal.TakeProfit2))
+	}
+
+	message += fmt.Sprintf("\n\n⏰ %s WIB", signal.CreatedAt.Format("15:04 02/01/2006"))
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
+// isOwnerChat reports whether chatID is the configured TELEGRAM_CHAT_ID, the
+// only chat allowed to run destructive commands
+func (ns *NotificationService) isOwnerChat(chatID int64) bool {
+	return ns.cfg.TelegramChatID != "" && strconv.FormatInt(chatID, 10) == ns.cfg.TelegramChatID
+}
+
+// confirmKillSwitch asks for a second tap before the emergency stop fires
+func (ns *NotificationService) confirmKillSwitch(chatID int64) {
+	if !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk kill switch")
+		return
+	}
+
+	message := `🛑 *Kill Switch*
+
+Ini akan:
+• Menghentikan analisis dan semua jadwal
+• Membatalkan semua sinyal aktif
+• Menahan notifikasi sinyal sampai /rearm
+
+Yakin ingin melanjutkan?`
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🛑 Ya, Hentikan Semua", "killswitch_confirm"),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batal", "main_menu"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	ns.telegramBot.Send(msg)
+}
+
+// engageKillSwitch runs the emergency stop after the confirmation tap; the
+// bot service sends the confirmation notification itself
+func (ns *NotificationService) engageKillSwitch(chatID int64) {
+	if !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk kill switch")
+		return
+	}
+
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	if _, err := botService.EngageKillSwitch(); err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Kill switch gagal: %s", err.Error()))
+	}
+}
+
+// rearmKillSwitch handles /rearm to resume after an emergency stop
+func (ns *NotificationService) rearmKillSwitch(chatID int64) {
+	if !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk re-arm")
+		return
+	}
+
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	if err := botService.RearmKillSwitch(); err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Re-arm gagal: %s", err.Error()))
+	}
+}
+
+// sendNextTick handles /nexttick, showing when the regular analysis runs next
+func (ns *NotificationService) sendNextTick(chatID int64) {
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	next, err := botService.NextAnalysisTime()
+	if err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Gagal membaca jadwal: %s", err.Error()))
+		return
+	}
+
+	message := fmt.Sprintf("⏰ *Analisis Berikutnya*\n\n🕐 %s (dalam %s)\n\nGunakan /runat HH:MM untuk menjadwalkan analisis tambahan.",
+		next.Format("15:04:05 02/01/2006"),
+		time.Until(next).Round(time.Second),
+	)
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
+// scheduleAnalysisAt handles /runat HH:MM, queueing a one-off analysis
+func (ns *NotificationService) scheduleAnalysisAt(chatID int64, clock string) {
+	if !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk menjadwalkan analisis")
+		return
+	}
+
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	at, err := nextClockTime(clock, time.Now())
+	if err != nil {
+		ns.sendErrorMessage(chatID, "Gunakan: /runat HH:MM\nContoh: /runat 19:30")
+		return
+	}
+
+	if err := botService.ScheduleAnalysisAt(at); err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Gagal menjadwalkan analisis: %s", err.Error()))
+		return
+	}
+
+	message := fmt.Sprintf("✅ *Analisis Dijadwalkan*\n\n🕐 %s (dalam %s)\n\nJadwal sekali jalan ini dihapus setelah analisis selesai.",
+		at.Format("15:04 02/01/2006"),
+		time.Until(at).Round(time.Minute),
+	)
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
+// resumeFromDrawdown handles /resume after the drawdown guard paused signal generation
+func (ns *NotificationService) resumeFromDrawdown(chatID int64) {
+	if !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk melanjutkan bot")
+		return
+	}
+
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	if err := botService.ResumeFromDrawdown(); err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Resume gagal: %s", err.Error()))
+	}
+}
+
+// toggleDryRun handles /dryrun on|off; without an argument, or when the
+// state doesn't change, it shows the current state
+func (ns *NotificationService) toggleDryRun(chatID int64, arg string) {
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	if arg != "" {
+		if !ns.isOwnerChat(chatID) {
+			ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk mengubah dry run")
+			return
+		}
+		if arg != "on" && arg != "off" {
+			ns.sendErrorMessage(chatID, "Gunakan: /dryrun on|off")
+			return
+		}
+		// SetDryRun announces the change to the signal chat
+		if enabled := arg == "on"; enabled != botService.IsDryRun() {
+			botService.SetDryRun(enabled)
+			return
+		}
+	}
+
+	message := "✅ Dry run nonaktif: sinyal disimpan dan dikirim seperti biasa."
+	if notice := botService.dryRunNotice(); notice != "" {
+		message = strings.TrimSpace(notice)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
+// recordFeedback handles /feedback <ref> win|loss <pnl%> to record a real trade outcome
+func (ns *NotificationService) recordFeedback(chatID int64, args []string) {
+	botService := ns.getBotService()
+	if botService == nil || botService.db == nil {
+		ns.sendErrorMessage(chatID, "Database tidak tersedia")
+		return
+	}
+
+	usage := "Gunakan: /feedback <kode> win|loss <pnl%>\nContoh: /feedback BTC-240612-A3F win 3.5"
+	if len(args) != 3 {
+		ns.sendErrorMessage(chatID, usage)
+		return
+	}
+
+	refCode := strings.ToUpper(args[0])
+	result := strings.ToLower(args[1])
+	pnl, err := decimal.NewFromString(strings.TrimSuffix(args[2], "%"))
+	if err != nil {
+		ns.sendErrorMessage(chatID, usage)
+		return
+	}
+
+	signal, err := botService.RecordSignalFeedback(refCode, result, pnl)
+	if err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Gagal menyimpan feedback `%s`: %s", refCode, err.Error()))
+		return
+	}
+
+	emoji := "✅"
+	if result == "loss" {
+		emoji = "❌"
+	}
+
+	message := fmt.Sprintf(`%s *Feedback tersimpan*
+
+🔖 *Sinyal:* %s (%s)
+📊 *Hasil:* %s
+💰 *PnL:* %s%%
+
+Data ini dipakai sebagai ground truth untuk learning.`,
+		emoji,
+		signal.RefCode,
+		signal.Action,
+		strings.ToUpper(result),
+		pnl.StringFixed(2),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
+// markSignal handles the Taken/Ignored buttons of a signal notification and
+// leaves only the snooze button on the message
+func (ns *NotificationService) markSignal(message *tgbotapi.Message, refCode, action string) {
+	chatID := message.Chat.ID
+	if !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk menandai sinyal")
+		return
+	}
+
+	botService := ns.getBotService()
+	if botService == nil || botService.db == nil {
+		ns.sendErrorMessage(chatID, "Database tidak tersedia")
+		return
+	}
+
+	signal, err := botService.MarkSignal(refCode, action)
+	if err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Gagal menandai sinyal `%s`: %s", refCode, err.Error()))
+		return
+	}
+
+	if symbol := signalSymbol(botService, signal); symbol != "" {
+		edit := tgbotapi.NewEditMessageReplyMarkup(chatID, message.MessageID, tgbotapi.NewInlineKeyboardMarkup(snoozeKeyboardRow(symbol)))
+		if _, err := ns.telegramBot.Request(edit); err != nil {
+			logrus.Warn("Failed to update signal buttons: ", err)
+		}
+	}
+
+	reply := fmt.Sprintf("🚫 Sinyal `%s` ditandai *diabaikan*.", signal.RefCode)
+	if action == signalActionTaken {
+		reply = fmt.Sprintf("✅ Sinyal `%s` ditandai *diambil*.\n\nCatat hasilnya nanti dengan:\n`/feedback %s win|loss <pnl%%>`",
+			signal.RefCode, signal.RefCode)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, reply)
+	msg.ParseMode = "Markdown"
+	msg.ReplyToMessageID = message.MessageID
+	ns.telegramBot.Send(msg)
+}
+
+// signalSymbol resolves the watched coin a signal belongs to
+func signalSymbol(bs *BotService, signal *models.TradingSignal) string {
+	if signal.Crypto != nil {
+		return signal.Crypto.Symbol
+	}
+	for _, crypto := range bs.watchlist() {
+		if crypto.ID == signal.CryptoID {
+			return crypto.Symbol
+		}
+	}
+	return ""
+}
+
+// snoozeCoin handles the snooze button of a signal notification
+func (ns *NotificationService) snoozeCoin(chatID int64, symbol string) {
+	if !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk snooze coin")
+		return
+	}
+
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	until := botService.SnoozeCoin(symbol)
+	logrus.Info("Snoozed notifications for ", symbol, " until ", until.Format(time.RFC3339))
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🔕 Notifikasi sinyal *%s* dibisukan sampai %s.", symbol, until.Format("15:04")))
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
 // addCoinToWatch adds a new cryptocurrency to watchlist
 func (ns *NotificationService) addCoinToWatch(chatID int64, symbol string) {
-	if ns.botService == nil {
+	botService := ns.getBotService()
+	if botService == nil {
 		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
 		return
 	}
 
+	if err := botService.checkWatchable(symbol); err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("%s tidak bisa ditambahkan: stablecoin dan wrapped token tidak dianalisis sebagai peluang trading (lihat `EXCLUDED_SYMBOLS`)", symbol))
+		return
+	}
+
 	// Check if coin already exists
-	for _, crypto := range ns.botService.cryptoList {
+	for _, crypto := range botService.watchlist() {
 		if crypto.Symbol == symbol {
 			message := fmt.Sprintf("⚠️ *%s sudah ada dalam watchlist*", symbol)
+
+			// Adding a pruned coin again puts it back into analysis
+			if !crypto.IsActive {
+				if err := botService.reactivateCrypto(crypto); err != nil {
+					ns.sendErrorMessage(chatID, fmt.Sprintf("Gagal mengaktifkan %s: %s", symbol, err.Error()))
+					return
+				}
+				message = fmt.Sprintf("✅ *%s diaktifkan kembali*", symbol)
+			}
+			botService.forgetRemovedDefault(symbol)
 			
 			keyboard := tgbotapi.NewInlineKeyboardMarkup(
 				tgbotapi.NewInlineKeyboardRow(
@@ -93,14 +533,20 @@ func (ns *NotificationService) addCoinToWatch(chatID int64, symbol string) {
 		CreatedAt: time.Now(),
 	}
 
-	// Add to database
-	if err := ns.botService.db.CreateCryptocurrency(newCrypto); err != nil {
+	// Resolve CMC/CoinGecko metadata so the coin is stored complete
+	if _, err := botService.enrichCryptocurrency(newCrypto); err != nil {
+		logrus.Warn("Failed to resolve metadata for ", symbol, ": ", err)
+	}
+
+	// Add to database; a coin added concurrently comes back as the stored row
+	if err := botService.db.CreateCryptocurrency(newCrypto); err != nil {
 		ns.sendErrorMessage(chatID, fmt.Sprintf("Gagal menambahkan %s: %s", symbol, err.Error()))
 		return
 	}
 
 	// Add to bot's crypto list
-	ns.botService.cryptoList = append(ns.botService.cryptoList, newCrypto)
+	botService.watchCrypto(newCrypto)
+	botService.forgetRemovedDefault(symbol)
 
 	message := fmt.Sprintf(`✅ *%s berhasil ditambahkan!*
 
@@ -112,7 +558,7 @@ Bot sekarang memantau %d cryptocurrency.`,
 		symbol,
 		newCrypto.Name,
 		symbol,
-		len(ns.botService.cryptoList),
+		botService.watchlistSize(),
 	)
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
@@ -135,23 +581,14 @@ Bot sekarang memantau %d cryptocurrency.`,
 
 // removeCoinFromWatch removes a cryptocurrency from watchlist
 func (ns *NotificationService) removeCoinFromWatch(chatID int64, symbol string) {
-	if ns.botService == nil {
+	botService := ns.getBotService()
+	if botService == nil {
 		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
 		return
 	}
 
 	// Find and remove from crypto list
-	found := false
-	for i, crypto := range ns.botService.cryptoList {
-		if crypto.Symbol == symbol {
-			// Remove from slice
-			ns.botService.cryptoList = append(ns.botService.cryptoList[:i], ns.botService.cryptoList[i+1:]...)
-			found = true
-			break
-		}
-	}
-
-	if !found {
+	if !botService.unwatchCrypto(symbol) {
 		message := fmt.Sprintf("⚠️ *%s tidak ditemukan dalam watchlist*", symbol)
 		msg := tgbotapi.NewMessage(chatID, message)
 		msg.ParseMode = "Markdown"
@@ -159,11 +596,14 @@ func (ns *NotificationService) removeCoinFromWatch(chatID int64, symbol string)
 		return
 	}
 
+	// Keep a removed default coin from being seeded again on restart
+	botService.rememberRemovedDefault(symbol)
+
 	message := fmt.Sprintf(`✅ *%s berhasil dihapus dari watchlist*
 
 Bot sekarang memantau %d cryptocurrency.`,
 		symbol,
-		len(ns.botService.cryptoList),
+		botService.watchlistSize(),
 	)
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
@@ -181,6 +621,99 @@ Bot sekarang memantau %d cryptocurrency.`,
 	logrus.Infof("Removed cryptocurrency from watchlist: %s", symbol)
 }
 
+// deleteCoin permanently removes a cryptocurrency; only the configured owner chat may do this
+func (ns *NotificationService) deleteCoin(chatID int64, symbol string) {
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	if !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk menghapus coin")
+		return
+	}
+
+	if symbol == "" {
+		ns.sendErrorMessage(chatID, "Gunakan: /delcoin <symbol>\nContoh: /delcoin DOGE")
+		return
+	}
+
+	if err := botService.DeleteCryptocurrency(symbol); err != nil {
+		if errors.Is(err, database.ErrCryptocurrencyNotFound) {
+			message := fmt.Sprintf("⚠️ *%s tidak ditemukan di database*", symbol)
+			msg := tgbotapi.NewMessage(chatID, message)
+			msg.ParseMode = "Markdown"
+			ns.telegramBot.Send(msg)
+			return
+		}
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Gagal menghapus %s: %s", symbol, err.Error()))
+		return
+	}
+
+	message := fmt.Sprintf(`🗑️ *%s dihapus permanen*
+
+Riwayat sinyal tetap disimpan namun tidak lagi terhubung ke coin ini.
+Bot sekarang memantau %d cryptocurrency.`,
+		symbol,
+		botService.watchlistSize(),
+	)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("💰 Lihat Coins", "coins_list"),
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Menu Utama", "main_menu"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	ns.telegramBot.Send(msg)
+
+	logrus.Infof("Deleted cryptocurrency via Telegram: %s", symbol)
+}
+
+// resetWatchlist restores every default coin the user removed; owner only
+func (ns *NotificationService) resetWatchlist(chatID int64) {
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	if !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk mereset watchlist")
+		return
+	}
+
+	restored, err := botService.ResetWatchlist()
+	if err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Gagal mereset watchlist: %s", err.Error()))
+		return
+	}
+
+	message := "✅ *Semua coin default sudah ada dalam watchlist*"
+	if len(restored) > 0 {
+		message = fmt.Sprintf("♻️ *Watchlist direset*\n\nDikembalikan: %s", strings.Join(restored, ", "))
+	}
+	message += fmt.Sprintf("\n\nBot sekarang memantau %d cryptocurrency.", botService.watchlistSize())
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("💰 Lihat Coins", "coins_list"),
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Menu Utama", "main_menu"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	ns.telegramBot.Send(msg)
+
+	logrus.Infof("Watchlist reset via Telegram, restored: %v", restored)
+}
+
 // sendSettingsMenu sends settings configuration menu
 func (ns *NotificationService) sendSettingsMenu(chatID int64) {
 	message := `⚙️ *Pengaturan Bot*
@@ -210,6 +743,12 @@ _Pengaturan lanjutan akan tersedia di versi mendatang_`
 
 // sendDailySummaryNow sends daily summary immediately
 func (ns *NotificationService) sendDailySummaryNow(chatID int64) {
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
 	message := fmt.Sprintf(`📋 *Daily Summary - %s*
 
 📊 *Statistik Hari Ini:*
@@ -229,10 +768,10 @@ func (ns *NotificationService) sendDailySummaryNow(chatID int64) {
 
 _Summary lengkap dikirim otomatis setiap hari pukul 23:00_`,
 		time.Now().Format("02/01/2006"),
-		ns.botService.totalSignalsToday,
-		len(ns.botService.cryptoList),
+		botService.totalSignalsToday.Load(),
+		botService.watchlistSize(),
 		func() string {
-			if ns.botService.isRunning {
+			if botService.isRunning.Load() {
 				return "🟢 Running"
 			}
 			return "🔴 Stopped"
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// readOnlyCommands are the commands that only show information and so may be
+// opened to every chat through TELEGRAM_PUBLIC_COMMANDS. Anything that changes
+// state (analysis, coins, kill switch, ...) always requires authorization.
+var readOnlyCommands = map[string]bool{
+	"start":       true,
+	"help":        true,
+	"menu":        true,
+	"status":      true,
+	"coins":       true,
+	"performance": true,
+	"signal":      true,
+	"signals":     true,
+	"perf":        true,
+	"simulate":    true,
+	"exposure":    true,
+	"chart":       true,
+	"nexttick":    true,
+}
+
+// callbackCommand maps a menu button to the read-only command it mirrors;
+// "" for buttons that change state
+func callbackCommand(data string) string {
+	switch {
+	case data == "main_menu", data == "settings", data == "daily_summary", data == "learning_stats":
+		return "menu"
+	case data == "bot_status":
+		return "status"
+	case data == "coins_list", strings.HasPrefix(data, "coins_page_"):
+		return "coins"
+	case data == "performance":
+		return "performance"
+	default:
+		return ""
+	}
+}
+
+// isAuthorizedChat reports whether chatID is the owner chat or listed in
+// AUTHORIZED_CHAT_IDS
+func (ns *NotificationService) isAuthorizedChat(chatID int64) bool {
+	if ns.isOwnerChat(chatID) {
+		return true
+	}
+	id := strconv.FormatInt(chatID, 10)
+	for _, authorized := range ns.cfg.AuthorizedChatIDs {
+		if authorized == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isPublicCommand reports whether a read-only command is opened to every chat
+func (ns *NotificationService) isPublicCommand(command string) bool {
+	if !readOnlyCommands[command] {
+		return false
+	}
+	for _, public := range ns.cfg.TelegramPublicCommands {
+		if strings.EqualFold(strings.TrimPrefix(public, "/"), command) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeChat lets a command or button through for authorized chats and for
+// public read-only commands. Anyone else gets a polite refusal, and the attempt
+// is logged to system_logs so probing shows up in /logs.
+func (ns *NotificationService) authorizeChat(chatID int64, from *tgbotapi.User, command, action string) bool {
+	if ns.isAuthorizedChat(chatID) || (command != "" && ns.isPublicCommand(command)) {
+		return true
+	}
+
+	username := ""
+	if from != nil {
+		username = from.UserName
+	}
+	logrus.Warnf("Unauthorized Telegram %s from chat %d (@%s)", action, chatID, username)
+	if botService := ns.getBotService(); botService != nil {
+		botService.RecordSystemLog("warning", "telegram_auth", fmt.Sprintf("Unauthorized %s from chat %d", action, chatID), map[string]interface{}{
+			"chat_id":  chatID,
+			"username": username,
+			"action":   action,
+		})
+	}
+
+	message := fmt.Sprintf("🔒 *Maaf, chat ini tidak memiliki izin*\n\nBot ini hanya bisa digunakan oleh chat yang terdaftar. Jika Anda perlu akses, kirimkan chat ID Anda ke pemilik bot: `%d`", chatID)
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+	return false
+}
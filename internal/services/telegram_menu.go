@@ -6,6 +6,8 @@ import (
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
 )
 
 // sendWelcomeMessage sends welcome message with main menu
@@ -77,19 +79,20 @@ Pilih opsi yang ingin Anda akses:`
 
 // sendBotStatus sends current bot status
 func (ns *NotificationService) sendBotStatus(chatID int64) {
-	if ns.botService == nil {
+	botService := ns.getBotService()
+	if botService == nil {
 		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
 		return
 	}
 
 	status := "🔴 Stopped"
-	if ns.botService.isRunning {
+	if botService.isRunning.Load() {
 		status = "🟢 Running"
 	}
 
 	lastAnalysis := "Belum pernah"
-	if !ns.botService.lastAnalysisTime.IsZero() {
-		lastAnalysis = ns.botService.lastAnalysisTime.Format("15:04 02/01/2006")
+	if lastAnalysisAt := botService.lastAnalysis(); !lastAnalysisAt.IsZero() {
+		lastAnalysis = lastAnalysisAt.Format("15:04 02/01/2006")
 	}
 
 	message := fmt.Sprintf(`📊 *Status Bot*
@@ -100,11 +103,12 @@ func (ns *NotificationService) sendBotStatus(chatID int64) {
 🕐 *Analisis Terakhir:* %s
 ⏰ *Waktu Sekarang:* %s`,
 		status,
-		len(ns.botService.cryptoList),
-		ns.botService.totalSignalsToday,
+		botService.watchlistSize(),
+		botService.totalSignalsToday.Load(),
 		lastAnalysis,
 		time.Now().Format("15:04 02/01/2006"),
 	)
+	message += botService.dryRunNotice()
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
@@ -123,33 +127,76 @@ func (ns *NotificationService) sendBotStatus(chatID int64) {
 	ns.telegramBot.Send(msg)
 }
 
+// coinsPerPage caps the remove buttons per message to stay within Telegram's keyboard limits
+const coinsPerPage = 8
+
 // sendCoinsList sends list of monitored coins
 func (ns *NotificationService) sendCoinsList(chatID int64) {
-	if ns.botService == nil {
+	ns.sendCoinsPage(chatID, 0)
+}
+
+// sendCoinsPage sends one page of monitored coins, each with a remove button
+func (ns *NotificationService) sendCoinsPage(chatID int64, page int) {
+	botService := ns.getBotService()
+	if botService == nil {
 		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
 		return
 	}
 
+	cryptoList := botService.watchlist()
+	totalPages := (len(cryptoList) + coinsPerPage - 1) / coinsPerPage
+	if page >= totalPages {
+		page = totalPages - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+
+	start := page * coinsPerPage
+	end := start + coinsPerPage
+	if end > len(cryptoList) {
+		end = len(cryptoList)
+	}
+
 	var coinsList strings.Builder
 	coinsList.WriteString("💰 *Daftar Cryptocurrency yang Dipantau:*\n\n")
 
-	for i, crypto := range ns.botService.cryptoList {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i := start; i < end; i++ {
+		crypto := cryptoList[i]
 		status := "🟢"
 		if !crypto.IsActive {
 			status = "🔴"
 		}
 		coinsList.WriteString(fmt.Sprintf("%d. %s *%s* - %s\n", 
 			i+1, status, crypto.Symbol, crypto.Name))
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Remove "+crypto.Symbol, "remove_coin_"+crypto.Symbol),
+		))
 	}
 
-	if len(ns.botService.cryptoList) == 0 {
+	if len(cryptoList) == 0 {
 		coinsList.WriteString("Tidak ada cryptocurrency yang dipantau.")
 	}
 
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+	if totalPages > 1 {
+		coinsList.WriteString(fmt.Sprintf("\n_Halaman %d dari %d_", page+1, totalPages))
+
+		var navRow []tgbotapi.InlineKeyboardButton
+		if page > 0 {
+			navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("⬅️ Sebelumnya", fmt.Sprintf("coins_page_%d", page-1)))
+		}
+		if page < totalPages-1 {
+			navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("Berikutnya ➡️", fmt.Sprintf("coins_page_%d", page+1)))
+		}
+		rows = append(rows, navRow)
+	}
+
+	rows = append(rows,
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("➕ Tambah Coin", "add_coin"),
-			tgbotapi.NewInlineKeyboardButtonData("🔄 Refresh", "coins_list"),
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Refresh", fmt.Sprintf("coins_page_%d", page)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🏠 Menu Utama", "main_menu"),
@@ -158,7 +205,7 @@ func (ns *NotificationService) sendCoinsList(chatID int64) {
 
 	msg := tgbotapi.NewMessage(chatID, coinsList.String())
 	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
 
 	ns.telegramBot.Send(msg)
 }
@@ -216,6 +263,12 @@ func (ns *NotificationService) sendPerformanceReport(chatID int64) {
 
 _Data akan tersedia setelah bot berjalan beberapa waktu_`
 
+	if botService := ns.getBotService(); botService != nil {
+		if metrics, err := botService.GetPerformanceMetrics(); err == nil && metrics.TotalSignals > 0 {
+			message = formatPerformanceReport(metrics)
+		}
+	}
+
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔄 Refresh", "performance"),
@@ -233,6 +286,504 @@ _Data akan tersedia setelah bot berjalan beberapa waktu_`
 	ns.telegramBot.Send(msg)
 }
 
+// rollupPeriodLabels are the Indonesian headings for each rollup period
+var rollupPeriodLabels = map[string]string{
+	"daily":   "Harian",
+	"weekly":  "Mingguan",
+	"monthly": "Bulanan",
+	"all":     "Sepanjang Waktu",
+}
+
+// sendPerformanceRollup handles /perf [daily|weekly|monthly|all]
+func (ns *NotificationService) sendPerformanceRollup(chatID int64, period string) {
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	if period == "" {
+		period = "weekly"
+	}
+	label, ok := rollupPeriodLabels[period]
+	if !ok {
+		ns.sendErrorMessage(chatID, "Gunakan: /perf daily|weekly|monthly|all\nContoh: /perf weekly")
+		return
+	}
+
+	rollups, err := botService.GetPerformanceRollup(period)
+	if err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Gagal mengambil performa: %s", err.Error()))
+		return
+	}
+
+	message := fmt.Sprintf("📅 *Performa %s*\n", label)
+	if len(rollups) == 0 {
+		message += "\n_Belum ada sinyal yang selesai pada periode ini_"
+	}
+	for _, rollup := range rollups {
+		heading := rollup.PeriodStart.Format("02/01/2006")
+		switch period {
+		case "weekly":
+			heading = "Minggu " + heading
+		case "monthly":
+			heading = rollup.PeriodStart.Format("01/2006")
+		case "all":
+			heading = "Sejak " + heading
+		}
+
+		message += fmt.Sprintf("\n*%s*\n• Sinyal: %d (%d W / %d L) • Win Rate: %.1f%%\n• PnL: %.2f%% (avg %.2f%%) • Best/Worst: %.2f%% / %.2f%%\n",
+			heading,
+			rollup.Signals,
+			rollup.Wins,
+			rollup.Losses,
+			rollup.WinRate.InexactFloat64(),
+			rollup.TotalPnL.InexactFloat64(),
+			rollup.AvgPnL.InexactFloat64(),
+			rollup.BestPnL.InexactFloat64(),
+			rollup.WorstPnL.InexactFloat64(),
+		)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
+// sendThresholdSimulation handles /simulate <parameter> <value>
+func (ns *NotificationService) sendThresholdSimulation(chatID int64, args []string) {
+	usage := tgbotapi.EscapeText(tgbotapi.ModeMarkdown, fmt.Sprintf("Gunakan: /simulate <parameter> <nilai>\nParameter: %s\nContoh: /simulate min_confidence 0.8",
+		strings.Join(SimulationParameterNames(), ", ")))
+	if len(args) != 2 {
+		ns.sendErrorMessage(chatID, usage)
+		return
+	}
+	threshold, err := decimal.NewFromString(args[1])
+	if err != nil {
+		ns.sendErrorMessage(chatID, usage)
+		return
+	}
+
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	result, err := botService.SimulateThreshold(strings.ToLower(args[0]), threshold)
+	if err != nil {
+		ns.sendErrorMessage(chatID, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, fmt.Sprintf("Simulasi gagal: %s", err.Error())))
+		return
+	}
+
+	message := fmt.Sprintf("🧪 *Simulasi %s = %s* (saat ini %s)\n", tgbotapi.EscapeText(tgbotapi.ModeMarkdown, result.Parameter), result.Threshold.String(), result.Current.String())
+	if result.Baseline.Signals == 0 {
+		message += "\n_Belum ada sinyal yang selesai untuk disimulasikan_"
+	} else {
+		message += fmt.Sprintf("\n*Sinyal tersaring:* %d dari %d", result.Filtered, result.Baseline.Signals)
+		if result.Unscored > 0 {
+			message += fmt.Sprintf(" (%d tanpa skor, tetap dihitung)", result.Unscored)
+		}
+		for _, row := range []struct {
+			label string
+			stats SimulationStats
+		}{{"Saat ini", result.Baseline}, {"Simulasi", result.Simulated}} {
+			message += fmt.Sprintf("\n\n*%s*\n• Sinyal: %d (%d W / %d L) • Win Rate: %.1f%%\n• PnL: %.2f%% (avg %.2f%%)",
+				row.label,
+				row.stats.Signals,
+				row.stats.Wins,
+				row.stats.Losses,
+				row.stats.WinRate.InexactFloat64(),
+				row.stats.TotalPnL.InexactFloat64(),
+				row.stats.AvgPnL.InexactFloat64(),
+			)
+		}
+		message += "\n\n_Berdasarkan hasil sinyal tersimpan, bukan backtest kline_"
+	}
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
+// sendChart handles /chart <symbol> [interval]: a candlestick image with the
+// latest active signal's levels, legend and values in the caption
+func (ns *NotificationService) sendChart(chatID int64, args []string) {
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	if len(args) == 0 || len(args) > 2 {
+		ns.sendErrorMessage(chatID, "Gunakan: /chart <symbol> [interval]\nContoh: /chart BTC 15m")
+		return
+	}
+	interval := ns.cfg.KlineInterval
+	if len(args) == 2 {
+		interval = args[1]
+	}
+
+	chart, err := botService.GetChart(args[0], interval)
+	if err != nil {
+		ns.sendErrorMessage(chatID, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, fmt.Sprintf("Gagal membuat grafik: %s", err.Error())))
+		return
+	}
+
+	caption := fmt.Sprintf("📈 *%s* · %s · $%s\n🟡 SMA20  🟣 SMA50  🔵 Bollinger (20, 2)",
+		chart.Symbol, chart.Interval, chart.Last.Close.String())
+	if signal := chart.Signal; signal != nil {
+		caption += fmt.Sprintf("\n\n*%s* `%s` · garis putus-putus:\n🔷 Entry $%s", signal.Action, signal.RefCode, signal.EntryPrice.String())
+		if signal.StopLoss != nil {
+			caption += fmt.Sprintf("\n🟥 SL $%s", signal.StopLoss.String())
+		}
+		if signal.TakeProfit1 != nil {
+			caption += fmt.Sprintf("\n🟩 TP1 $%s", signal.TakeProfit1.String())
+		}
+		if signal.TakeProfit2 != nil {
+			caption += fmt.Sprintf("  TP2 $%s", signal.TakeProfit2.String())
+		}
+	}
+
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("%s-%s.png", chart.Symbol, chart.Interval),
+		Bytes: chart.PNG,
+	})
+	photo.Caption = caption
+	photo.ParseMode = "Markdown"
+	if _, err := ns.telegramBot.Send(photo); err != nil {
+		logrus.Error("Failed to send chart: ", err)
+	}
+}
+
+// exposureTiltLabels renders an exposure tilt for Telegram
+var exposureTiltLabels = map[string]string{
+	"long":    "📈 Long",
+	"short":   "📉 Short",
+	"neutral": "⚖️ Netral",
+}
+
+// diagnosticsChannelLabels names the notification channels in /diagnostics
+var diagnosticsChannelLabels = []struct {
+	Key   string
+	Label string
+}{
+	{"telegram", "Telegram"},
+	{"whatsapp", "WhatsApp"},
+	{"discord", "Discord"},
+	{"priority_webhook", "Priority webhook"},
+}
+
+// sendDiagnostics handles /diagnostics: everything relevant for support in one
+// message. Limited to the owner chat.
+func (ns *NotificationService) sendDiagnostics(chatID int64) {
+	if !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk diagnostics")
+		return
+	}
+
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	d := botService.GetDiagnostics()
+
+	status := "🔴 Stopped"
+	if d.Running {
+		status = "🟢 Running"
+	}
+	database := d.DatabaseMode
+	if !d.DatabaseReady {
+		database += " (belum terhubung)"
+	}
+
+	var channels []string
+	for _, channel := range diagnosticsChannelLabels {
+		if d.Channels[channel.Key] {
+			channels = append(channels, channel.Label)
+		}
+	}
+	if len(channels) == 0 {
+		channels = append(channels, "tidak ada")
+	}
+
+	lastAnalysis := "Belum pernah"
+	if !d.LastAnalysis.IsZero() {
+		lastAnalysis = d.LastAnalysis.Format("15:04 02/01/2006")
+	}
+	if d.LastCycle != nil {
+		lastAnalysis += fmt.Sprintf(" (%.0fs, %d coin)", d.LastCycle.Seconds, d.LastCycle.Coins)
+	}
+
+	signalCap := "tanpa batas"
+	if d.MaxSignalsPerDay > 0 {
+		signalCap = fmt.Sprintf("%d", d.MaxSignalsPerDay)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(`🩺 *Diagnostics*
+
+🏷 *Versi:* %s
+⏱ *Uptime:* %s
+🤖 *Status:* %s
+🗄 *Database:* %s
+📡 *Channel:* %s
+💰 *Watchlist:* %d aktif / %d total
+🕐 *Analisis Terakhir:* %s
+📈 *Sinyal Hari Ini:* %d / %s`,
+		Version,
+		d.Uptime.Round(time.Second),
+		status,
+		database,
+		strings.Join(channels, ", "),
+		d.WatchlistActive,
+		d.WatchlistTotal,
+		lastAnalysis,
+		d.SignalsToday,
+		signalCap,
+	))
+
+	b.WriteString("\n\n🌐 *Latensi Sumber Data:*")
+	if len(d.SourceLatencies) == 0 {
+		b.WriteString("\n• Belum ada request")
+	}
+	for _, latency := range d.SourceLatencies {
+		b.WriteString(fmt.Sprintf("\n• %s: %dms (rata-rata %dms, %d gagal dari %d)",
+			strings.ReplaceAll(latency.Source, "_", "\\_"), latency.LastMillis, latency.AvgMillis, latency.Failures, latency.Requests))
+	}
+
+	var pauses []string
+	if d.KillSwitchEngaged {
+		pauses = append(pauses, "🛑 Kill switch aktif (/rearm)")
+	}
+	if d.DrawdownPaused {
+		pauses = append(pauses, "📉 Dijeda karena drawdown (/resume)")
+	}
+	if d.DryRun {
+		pauses = append(pauses, "🧪 Dry run aktif, sinyal tidak disimpan/dikirim (/dryrun off)")
+	}
+	for _, provider := range d.ExhaustedProviders {
+		pauses = append(pauses, fmt.Sprintf("⛔ Kuota %s habis, dilewati sampai pengecekan ulang", provider))
+	}
+	for symbol, until := range d.SnoozedCoins {
+		pauses = append(pauses, fmt.Sprintf("🔕 %s di-snooze sampai %s", symbol, until.Format("15:04")))
+	}
+
+	b.WriteString("\n\n⏸ *Jeda Aktif:*")
+	if len(pauses) == 0 {
+		b.WriteString("\n• Tidak ada")
+	}
+	for _, pause := range pauses {
+		b.WriteString("\n• " + pause)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.String())
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
+// sendExposure handles /exposure: active signals per coin and the net tilt
+func (ns *NotificationService) sendExposure(chatID int64) {
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	exposure, err := botService.GetExposure()
+	if err != nil {
+		ns.sendErrorMessage(chatID, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, fmt.Sprintf("Gagal mengambil exposure: %s", err.Error())))
+		return
+	}
+
+	message := "📊 *Exposure Sinyal Aktif*\n"
+	if exposure.TotalActive == 0 {
+		message += "\n_Tidak ada sinyal aktif_"
+	} else {
+		for _, coin := range exposure.Coins {
+			message += fmt.Sprintf("\n*%s* - %d BUY / %d SELL · %s", coin.Symbol, coin.Buy, coin.Sell, exposureTiltLabels[coin.Tilt])
+		}
+		message += fmt.Sprintf("\n\n*Total:* %d aktif (%d BUY / %d SELL)\n*Net:* %+d · %s",
+			exposure.TotalActive, exposure.TotalBuy, exposure.TotalSell, exposure.Net, exposureTiltLabels[exposure.Tilt])
+	}
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
+// recentSignalsLimit caps how many signals /signals lists
+const recentSignalsLimit = 10
+
+// sendRecentSignals handles /signals [low|medium|high]
+func (ns *NotificationService) sendRecentSignals(chatID int64, priorityArg string) {
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	priority := ""
+	if priorityArg != "" {
+		parsed, err := ParseSignalPriority(priorityArg)
+		if err != nil {
+			ns.sendErrorMessage(chatID, "Gunakan: /signals [low|medium|high]\nContoh: /signals high")
+			return
+		}
+		priority = parsed
+	}
+
+	signals, err := botService.GetRecentSignals(priority, recentSignalsLimit)
+	if err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Gagal mengambil sinyal: %s", err.Error()))
+		return
+	}
+
+	message := "📋 *Sinyal Terbaru*"
+	if priority != "" {
+		message = fmt.Sprintf("📋 *Sinyal Terbaru - Prioritas %s*", signalPriorityLabel(priority))
+	}
+	message += "\n"
+	if len(signals) == 0 {
+		message += "\n_Belum ada sinyal_"
+	}
+	for _, signal := range signals {
+		ref := signal.RefCode
+		if ref == "" {
+			ref = signal.ID.String()[:8]
+		}
+		message += fmt.Sprintf("\n• `%s` %s @ $%s • %.1f%%",
+			ref,
+			signal.Action,
+			signal.EntryPrice.String(),
+			signal.ConfidenceScore.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+		)
+		if label := signalPriorityLabel(signal.Priority); label != "" {
+			message += " • " + label
+		}
+		message += " • " + signal.CreatedAt.Format("15:04 02/01")
+	}
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
+// systemLogsLimit caps how many entries /logs lists
+const systemLogsLimit = 10
+
+// systemLogMessageMax truncates long log messages in /logs
+const systemLogMessageMax = 200
+
+// sendSystemLogs handles /logs [level]: the latest system log entries.
+// Limited to the owner chat.
+func (ns *NotificationService) sendSystemLogs(chatID int64, levelArg string) {
+	if !ns.isOwnerChat(chatID) {
+		ns.sendErrorMessage(chatID, "Anda tidak memiliki izin untuk melihat log")
+		return
+	}
+
+	botService := ns.getBotService()
+	if botService == nil {
+		ns.sendErrorMessage(chatID, "Bot service tidak tersedia")
+		return
+	}
+
+	level, err := ParseSystemLogLevel(levelArg)
+	if err != nil {
+		ns.sendErrorMessage(chatID, "Gunakan: /logs [debug|info|warning|error|fatal]\nContoh: /logs error")
+		return
+	}
+
+	logs, err := botService.GetSystemLogs(level, systemLogsLimit)
+	if err != nil {
+		ns.sendErrorMessage(chatID, fmt.Sprintf("Gagal mengambil log: %s", err.Error()))
+		return
+	}
+
+	message := "📜 *Log Sistem*"
+	if level != "" {
+		message = fmt.Sprintf("📜 *Log Sistem - %s*", strings.ToUpper(level))
+	}
+	message += "\n"
+	if len(logs) == 0 {
+		message += "\n_Belum ada log_"
+	}
+	for _, entry := range logs {
+		text := entry.Message
+		if runes := []rune(text); len(runes) > systemLogMessageMax {
+			text = string(runes[:systemLogMessageMax]) + "…"
+		}
+		// Inside a code span only backticks would break the Markdown
+		text = strings.ReplaceAll(text, "`", "'")
+		message += fmt.Sprintf("\n• %s *%s* %s\n`%s`",
+			entry.CreatedAt.Format("15:04 02/01"),
+			strings.ToUpper(entry.LogLevel),
+			strings.ReplaceAll(entry.Component, "_", "\\_"),
+			text,
+		)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	msg.ParseMode = "Markdown"
+	ns.telegramBot.Send(msg)
+}
+
+// formatPerformanceReport formats performance metrics with the buy-and-hold benchmark
+func formatPerformanceReport(metrics *PerformanceMetrics) string {
+	message := fmt.Sprintf(`📈 *Laporan Performance*
+
+🎯 *Statistik Sinyal:*
+• Total Sinyal: %d
+• Win Rate: %.1f%%
+• Avg PnL: %.2f%%
+• Total PnL: %.2f%%
+• Best/Worst: %.2f%% / %.2f%%`,
+		metrics.TotalSignals,
+		metrics.WinRate.InexactFloat64(),
+		metrics.AvgPnL.InexactFloat64(),
+		metrics.TotalPnL.InexactFloat64(),
+		metrics.BestPnL.InexactFloat64(),
+		metrics.WorstPnL.InexactFloat64(),
+	)
+
+	if metrics.TimeWeighted != nil && metrics.TimeWeighted.Samples > 0 {
+		message += fmt.Sprintf(`
+
+⏳ *Performa Terkini (half-life %.0f hari):*
+• Win Rate: %.1f%%
+• Avg PnL: %.2f%%`,
+			metrics.TimeWeighted.HalfLifeDays,
+			metrics.TimeWeighted.WinRate.InexactFloat64(),
+			metrics.TimeWeighted.AvgPnL.InexactFloat64(),
+		)
+	}
+
+	if metrics.Benchmark != nil {
+		verdict := "✅ Mengalahkan buy & hold"
+		if metrics.Benchmark.ExcessReturn.LessThan(decimal.Zero) {
+			verdict = "⚠️ Di bawah buy & hold"
+		}
+		message += fmt.Sprintf(`
+
+📊 *Benchmark Buy & Hold (sejak %s):*
+• Rata-rata Coins: %.2f%%
+• BTC: %.2f%%
+• Selisih Strategi: %+.2f%%
+%s`,
+			metrics.Benchmark.PeriodStart.Format("02/01/2006"),
+			metrics.Benchmark.BuyAndHoldReturn.InexactFloat64(),
+			metrics.Benchmark.BTCReturn.InexactFloat64(),
+			metrics.Benchmark.ExcessReturn.InexactFloat64(),
+			verdict,
+		)
+	}
+
+	return message
+}
+
 // sendHelpMessage sends help information
 func (ns *NotificationService) sendHelpMessage(chatID int64) {
 	message := `❓ *Bantuan - Crypto Signal Bot*
@@ -243,6 +794,25 @@ func (ns *NotificationService) sendHelpMessage(chatID int64) {
 /status - Cek status bot
 /coins - Lihat daftar coins
 /performance - Laporan performa
+/perf daily|weekly|monthly|all - Rekap performa per periode
+/simulate min\_confidence 0.8 - Simulasi ambang pada sinyal lalu
+/exposure - Sinyal aktif per coin dan kecenderungan long/short
+/chart BTC 15m - Grafik candlestick dengan MA, BB dan level sinyal
+/diagnostics - Ringkasan kondisi internal bot untuk support
+/logs [level] - Log sistem terbaru, bisa difilter level (mis. error)
+/optimize - Jalankan optimasi learning
+/analyze [force] - Analisis manual; force melewati batas sinyal harian
+/nexttick - Waktu analisis terjadwal berikutnya
+/runat HH:MM - Jadwalkan analisis sekali jalan
+/signal <kode> - Lihat sinyal berdasarkan kode ref
+/signals [low|medium|high] - Sinyal terbaru, bisa difilter prioritas
+/feedback <kode> win|loss <pnl%> - Catat hasil trade sebenarnya
+/delcoin <symbol> - Hapus coin secara permanen
+/resetwatchlist - Kembalikan coin default yang dihapus
+/killswitch - Hentikan darurat: stop semua & batalkan sinyal aktif
+/rearm - Aktifkan kembali setelah kill switch
+/resume - Lanjutkan sinyal setelah jeda drawdown
+/dryrun [on|off] - Mode uji: sinyal hanya dicatat, tidak disimpan/dikirim
 /help - Tampilkan bantuan ini
 
 📱 *Cara Menggunakan:*
@@ -280,7 +850,7 @@ func (ns *NotificationService) sendUnknownCommandMessage(chatID int64) {
 Gunakan /menu untuk melihat semua fitur yang tersedia.
 
 *Available Commands:*
-/start, /menu, /status, /coins, /performance, /help`
+/start, /menu, /status, /diagnostics, /logs, /coins, /performance, /perf, /optimize, /signal, /feedback, /delcoin, /resetwatchlist, /killswitch, /rearm, /dryrun, /help`
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
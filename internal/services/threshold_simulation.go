@@ -0,0 +1,131 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidSimulation wraps validation failures of a threshold simulation
+var ErrInvalidSimulation = errors.New("invalid simulation")
+
+// simulationParameter is a threshold that can be replayed against past signals
+type simulationParameter struct {
+	score   func(signal *models.TradingSignal) *decimal.Decimal // nil when the signal has no score
+	current func(bs *BotService) float64
+}
+
+// simulationParameters maps /simulate parameter names to the signal score they gate
+var simulationParameters = map[string]simulationParameter{
+	"min_confidence": {
+		score:   func(signal *models.TradingSignal) *decimal.Decimal { return &signal.ConfidenceScore },
+		current: func(bs *BotService) float64 { return bs.signalGenerator.learnedParams().MinConfidence },
+	},
+	"min_data_quality": {
+		score:   func(signal *models.TradingSignal) *decimal.Decimal { return signal.DataQuality },
+		current: func(bs *BotService) float64 { return bs.cfg.MinDataQuality },
+	},
+}
+
+// SimulationParameterNames lists the thresholds /simulate accepts
+func SimulationParameterNames() []string {
+	names := make([]string, 0, len(simulationParameters))
+	for name := range simulationParameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SimulationStats summarizes a set of closed signals
+type SimulationStats struct {
+	Signals  int             `json:"signals"`
+	Wins     int             `json:"wins"`
+	Losses   int             `json:"losses"`
+	WinRate  decimal.Decimal `json:"win_rate"`
+	TotalPnL decimal.Decimal `json:"total_pnl"`
+	AvgPnL   decimal.Decimal `json:"avg_pnl"`
+}
+
+func (st *SimulationStats) add(perf *models.SignalPerformance) {
+	st.Signals++
+	switch perf.Outcome {
+	case "profit":
+		st.Wins++
+	case "loss":
+		st.Losses++
+	}
+	if perf.PnLPercentage != nil {
+		st.TotalPnL = st.TotalPnL.Add(*perf.PnLPercentage)
+	}
+}
+
+func (st *SimulationStats) finish() {
+	if st.Signals == 0 {
+		return
+	}
+	count := decimal.NewFromInt(int64(st.Signals))
+	st.WinRate = decimal.NewFromInt(int64(st.Wins)).Div(count).Mul(decimal.NewFromInt(100)).Round(2)
+	st.AvgPnL = st.TotalPnL.Div(count).Round(2)
+	st.TotalPnL = st.TotalPnL.Round(2)
+}
+
+// ThresholdSimulation is the what-if result of applying a threshold to past signals
+type ThresholdSimulation struct {
+	Parameter string          `json:"parameter"`
+	Threshold decimal.Decimal `json:"threshold"`
+	Current   decimal.Decimal `json:"current"`
+	Baseline  SimulationStats `json:"baseline"`  // Every closed signal
+	Simulated SimulationStats `json:"simulated"` // Signals that clear the threshold
+	Filtered  int             `json:"filtered"`
+	Unscored  int             `json:"unscored"` // Signals without the score, kept as-is
+}
+
+// SimulateThreshold replays closed signals against a hypothetical threshold and
+// reports how many it would have filtered and the resulting win rate and PnL.
+// It works on stored outcomes only; no klines are re-fetched.
+func (bs *BotService) SimulateThreshold(parameter string, threshold decimal.Decimal) (*ThresholdSimulation, error) {
+	param, ok := simulationParameters[parameter]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown parameter %q, use %s", ErrInvalidSimulation, parameter, strings.Join(SimulationParameterNames(), ", "))
+	}
+	if threshold.IsNegative() || threshold.GreaterThan(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("%w: threshold must be between 0 and 1", ErrInvalidSimulation)
+	}
+
+	outcomes, err := bs.db.GetPerformanceOutcomes(time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signal outcomes: %w", err)
+	}
+
+	result := &ThresholdSimulation{
+		Parameter: parameter,
+		Threshold: threshold,
+		Current:   decimal.NewFromFloat(param.current(bs)),
+	}
+	for _, perf := range outcomes {
+		result.Baseline.add(perf)
+
+		var score *decimal.Decimal
+		if perf.Signal != nil {
+			score = param.score(perf.Signal)
+		}
+		switch {
+		case score == nil:
+			result.Unscored++
+		case score.LessThan(threshold):
+			result.Filtered++
+			continue
+		}
+		result.Simulated.add(perf)
+	}
+	result.Baseline.finish()
+	result.Simulated.finish()
+
+	return result, nil
+}
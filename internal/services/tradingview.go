@@ -0,0 +1,200 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/database"
+	"crypto-signal-bot/internal/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// Signal sources recorded on models.TradingSignal.Source
+const (
+	SignalSourceInternal    = "internal"
+	SignalSourceTradingView = "tradingview"
+	SignalSourceBurstTest   = "burst_test" // Synthetic signals of the notification burst test
+)
+
+// ErrInvalidTradingViewAlert wraps validation failures of an incoming alert
+var ErrInvalidTradingViewAlert = errors.New("invalid TradingView alert")
+
+// TradingViewAlert is the JSON body configured in a TradingView alert message, e.g.
+// {"secret":"...","symbol":"{{ticker}}","action":"buy","price":{{close}},"interval":"{{interval}}"}
+type TradingViewAlert struct {
+	Secret      string           `json:"secret"`
+	Symbol      string           `json:"symbol"`
+	Action      string           `json:"action"`
+	Price       decimal.Decimal  `json:"price"`
+	StopLoss    *decimal.Decimal `json:"stop_loss,omitempty"`
+	TakeProfit1 *decimal.Decimal `json:"take_profit_1,omitempty"`
+	TakeProfit2 *decimal.Decimal `json:"take_profit_2,omitempty"`
+	Confidence  *decimal.Decimal `json:"confidence,omitempty"` // 0-1; defaults to 1 since the alert was set up deliberately
+	Interval    string           `json:"interval,omitempty"`
+	Priority    string           `json:"priority,omitempty"` // low, medium or high; defaults to medium
+	Message     string           `json:"message,omitempty"`
+}
+
+// signalSourceLabel returns the display name of a signal source
+func signalSourceLabel(source string) string {
+	switch source {
+	case SignalSourceTradingView:
+		return "TradingView"
+	case SignalSourceBurstTest:
+		return "Burst test (synthetic)"
+	default:
+		return source
+	}
+}
+
+// normalizeTradingViewSymbol turns tickers such as BINANCE:BTCUSDT or BTCUSDT.P into BTC
+func normalizeTradingViewSymbol(ticker string) string {
+	symbol := strings.ToUpper(strings.TrimSpace(ticker))
+	if i := strings.LastIndex(symbol, ":"); i >= 0 {
+		symbol = symbol[i+1:]
+	}
+	symbol = strings.TrimSuffix(symbol, ".P")
+	symbol = strings.TrimSuffix(symbol, "PERP")
+	for _, quote := range []string{"USDT", "USDC", "BUSD", "USD"} {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote)
+		}
+	}
+	return symbol
+}
+
+// validate checks the alert and returns its normalized action
+func (a *TradingViewAlert) validate() (string, error) {
+	action := strings.ToUpper(strings.TrimSpace(a.Action))
+	switch action {
+	case "BUY", "LONG":
+		action = "BUY"
+	case "SELL", "SHORT":
+		action = "SELL"
+	default:
+		return "", fmt.Errorf("%w: action must be buy or sell, got %q", ErrInvalidTradingViewAlert, a.Action)
+	}
+
+	if normalizeTradingViewSymbol(a.Symbol) == "" {
+		return "", fmt.Errorf("%w: symbol is required", ErrInvalidTradingViewAlert)
+	}
+	if !a.Price.IsPositive() {
+		return "", fmt.Errorf("%w: price must be positive", ErrInvalidTradingViewAlert)
+	}
+
+	// Stop loss must sit on the losing side of entry and take profits on the winning side
+	if a.StopLoss != nil {
+		if (action == "BUY" && !a.StopLoss.LessThan(a.Price)) || (action == "SELL" && !a.StopLoss.GreaterThan(a.Price)) {
+			return "", fmt.Errorf("%w: stop_loss %s is on the wrong side of price %s", ErrInvalidTradingViewAlert, a.StopLoss, a.Price)
+		}
+	}
+	for _, tp := range []*decimal.Decimal{a.TakeProfit1, a.TakeProfit2} {
+		if tp == nil {
+			continue
+		}
+		if (action == "BUY" && !tp.GreaterThan(a.Price)) || (action == "SELL" && !tp.LessThan(a.Price)) {
+			return "", fmt.Errorf("%w: take profit %s is on the wrong side of price %s", ErrInvalidTradingViewAlert, tp, a.Price)
+		}
+	}
+
+	if a.Confidence != nil && (a.Confidence.IsNegative() || a.Confidence.GreaterThan(decimal.NewFromInt(1))) {
+		return "", fmt.Errorf("%w: confidence must be between 0 and 1", ErrInvalidTradingViewAlert)
+	}
+
+	if a.Priority != "" {
+		if _, err := ParseSignalPriority(a.Priority); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidTradingViewAlert, err)
+		}
+	}
+
+	return action, nil
+}
+
+// IngestTradingViewAlert turns an external alert into a tracked signal and
+// sends it through the normal notification channels. The caller is expected
+// to have checked the shared secret.
+func (bs *BotService) IngestTradingViewAlert(alert *TradingViewAlert) (*models.TradingSignal, error) {
+	if bs.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	action, err := alert.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	symbol := normalizeTradingViewSymbol(alert.Symbol)
+	crypto, err := bs.db.GetCryptoBySymbol(symbol)
+	if err != nil {
+		if errors.Is(err, database.ErrCryptocurrencyNotFound) {
+			return nil, fmt.Errorf("%w: %s is not a tracked cryptocurrency", ErrInvalidTradingViewAlert, symbol)
+		}
+		return nil, err
+	}
+
+	confidence := decimal.NewFromInt(1)
+	if alert.Confidence != nil {
+		confidence = *alert.Confidence
+	}
+
+	// External alerts carry no indicator breakdown to classify, so they are
+	// treated as medium unless the alert says otherwise
+	priority := SignalPriorityMedium
+	if alert.Priority != "" {
+		priority, _ = ParseSignalPriority(alert.Priority)
+	}
+
+	timeframe := alert.Interval
+	if timeframe == "" {
+		timeframe = bs.cfg.KlineInterval
+	}
+
+	reasoning := fmt.Sprintf("TradingView alert: %s %s @ %s", action, symbol, alert.Price.String())
+	if alert.Message != "" {
+		reasoning += "\n" + alert.Message
+	}
+
+	signal := &models.TradingSignal{
+		ID:              uuid.New(),
+		CryptoID:        crypto.ID,
+		Source:          SignalSourceTradingView,
+		Priority:        priority,
+		Action:          action,
+		ConfidenceScore: confidence,
+		EntryPrice:      alert.Price,
+		StopLoss:        alert.StopLoss,
+		TakeProfit1:     alert.TakeProfit1,
+		TakeProfit2:     alert.TakeProfit2,
+		Reasoning:       reasoning,
+		MarketConditions: map[string]interface{}{
+			"source": SignalSourceTradingView,
+			"ticker": alert.Symbol,
+		},
+		Timeframe: timeframe,
+		CreatedAt: time.Now(),
+		Status:    "active",
+		Crypto:    crypto,
+	}
+	signal.RefCode = bs.signalGenerator.generateRefCode(symbol, signal.CreatedAt)
+	bs.signalGenerator.roundSignalValues(signal)
+
+	if bs.dryRun.withhold() {
+		logrus.Info("🧪 [DRY RUN] TradingView ", action, " signal for ", symbol, " not stored or sent")
+		return signal, nil
+	}
+
+	if err := bs.db.CreateSignal(signal, newOutboxEntry(signal)); err != nil {
+		return nil, fmt.Errorf("failed to save TradingView signal: %w", err)
+	}
+
+	if err := bs.deliverSignal(signal); err != nil {
+		logrus.Error("Failed to send TradingView signal notification, queued for retry: ", err)
+	}
+
+	logrus.Info("✅ Ingested TradingView ", action, " signal for ", symbol, " (", signal.RefCode, ")")
+	return signal, nil
+}
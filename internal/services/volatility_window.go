@@ -0,0 +1,40 @@
+package services
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const minutesPerDay = 24 * 60
+
+// activeVolatilityWindow returns the configured start ("HH:MM" UTC) of the
+// high-volatility window containing now, if any. Windows may wrap past midnight.
+func activeVolatilityWindow(starts []string, windowMinutes int, now time.Time) (string, bool) {
+	if windowMinutes <= 0 {
+		return "", false
+	}
+
+	now = now.UTC()
+	nowMinutes := now.Hour()*60 + now.Minute()
+	for _, start := range starts {
+		t, err := time.Parse("15:04", start)
+		if err != nil {
+			logrus.Warnf("Ignoring invalid volatility window start %q, expected HH:MM", start)
+			continue
+		}
+		startMinutes := t.Hour()*60 + t.Minute()
+		if (nowMinutes-startMinutes+minutesPerDay)%minutesPerDay < windowMinutes {
+			return start, true
+		}
+	}
+	return "", false
+}
+
+// volatilityWindow reports the active high-volatility window, if the filter is enabled
+func (sg *SignalGenerator) volatilityWindow(now time.Time) (string, bool) {
+	if !sg.cfg.VolatilityWindowEnabled {
+		return "", false
+	}
+	return activeVolatilityWindow(sg.cfg.VolatilityWindowStarts, sg.cfg.VolatilityWindowMinutes, now)
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultWatchlist are the coins seeded into the watchlist on startup
+var defaultWatchlist = []struct {
+	Symbol      string
+	Name        string
+	CoingeckoID string
+}{
+	{"BTC", "Bitcoin", "bitcoin"},
+	{"ETH", "Ethereum", "ethereum"},
+	{"BNB", "Binance Coin", "binancecoin"},
+	{"ADA", "Cardano", "cardano"},
+	{"SOL", "Solana", "solana"},
+	{"DOT", "Polkadot", "polkadot"},
+	{"MATIC", "Polygon", "matic-network"},
+	{"AVAX", "Avalanche", "avalanche-2"},
+	{"LINK", "Chainlink", "chainlink"},
+	{"ATOM", "Cosmos", "cosmos"},
+}
+
+func isDefaultCoin(symbol string) bool {
+	for _, coin := range defaultWatchlist {
+		if coin.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// removedDefaults returns the default coins seeding must skip. When the
+// overrides can't be read every default is seeded, as before.
+func (bs *BotService) removedDefaults() map[string]bool {
+	removed := make(map[string]bool)
+	if bs.db == nil || !bs.cfg.SkipRemovedDefaults {
+		return removed
+	}
+
+	symbols, err := bs.db.GetRemovedDefaults()
+	if err != nil {
+		logrus.Warn("Failed to load removed default coins, seeding all defaults: ", err)
+		return removed
+	}
+	for _, symbol := range symbols {
+		removed[symbol] = true
+	}
+	return removed
+}
+
+// rememberRemovedDefault records that the user took a default coin off the
+// watchlist so the next startup doesn't add it back
+func (bs *BotService) rememberRemovedDefault(symbol string) {
+	if bs.db == nil || !isDefaultCoin(symbol) {
+		return
+	}
+	if err := bs.db.SetDefaultRemoved(symbol, true); err != nil {
+		logrus.Warn("Failed to record removal of default coin ", symbol, ": ", err)
+	}
+}
+
+// forgetRemovedDefault lets seeding manage a default coin again after the user
+// re-added it
+func (bs *BotService) forgetRemovedDefault(symbol string) {
+	if bs.db == nil || !isDefaultCoin(symbol) {
+		return
+	}
+	if err := bs.db.SetDefaultRemoved(symbol, false); err != nil {
+		logrus.Warn("Failed to clear removal of default coin ", symbol, ": ", err)
+	}
+}
+
+// ResetWatchlist forgets every removed default and puts all default coins back
+// into the watchlist, reactivating pruned ones. Returns the restored symbols.
+func (bs *BotService) ResetWatchlist() ([]string, error) {
+	if bs.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	if err := bs.db.ClearRemovedDefaults(); err != nil {
+		return nil, err
+	}
+
+	watched := make(map[string]*models.Cryptocurrency)
+	for _, crypto := range bs.watchlist() {
+		watched[crypto.Symbol] = crypto
+	}
+
+	var restored []string
+	for _, defaultCrypto := range defaultWatchlist {
+		if err := bs.checkWatchable(defaultCrypto.Symbol); err != nil {
+			logrus.Info("Not restoring default coin: ", err)
+			continue
+		}
+		if crypto, exists := watched[defaultCrypto.Symbol]; exists {
+			if crypto.IsActive {
+				continue
+			}
+			if err := bs.reactivateCrypto(crypto); err != nil {
+				logrus.Error("Failed to reactivate ", crypto.Symbol, ": ", err)
+				continue
+			}
+			restored = append(restored, crypto.Symbol)
+			continue
+		}
+
+		// A coin that is still stored comes back as that row
+		crypto := &models.Cryptocurrency{
+			ID:        uuid.New(),
+			Symbol:    defaultCrypto.Symbol,
+			Name:      defaultCrypto.Name,
+			IsActive:  true,
+			CreatedAt: time.Now(),
+		}
+		if err := bs.db.CreateCryptocurrency(crypto); err != nil {
+			logrus.Error("Failed to restore ", defaultCrypto.Symbol, ": ", err)
+			continue
+		}
+		if !crypto.IsActive {
+			if err := bs.reactivateCrypto(crypto); err != nil {
+				logrus.Error("Failed to reactivate ", crypto.Symbol, ": ", err)
+				continue
+			}
+		}
+		if bs.watchCrypto(crypto) {
+			restored = append(restored, crypto.Symbol)
+		}
+	}
+
+	logrus.Info("Watchlist reset: restored ", len(restored), " default coins")
+	return restored, nil
+}
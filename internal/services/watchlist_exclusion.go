@@ -0,0 +1,30 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrExcludedSymbol is returned when a stablecoin or wrapped token from
+// EXCLUDED_SYMBOLS is added to the watchlist
+var ErrExcludedSymbol = errors.New("symbol is excluded from the watchlist")
+
+// isExcludedSymbol reports whether a symbol is on the EXCLUDED_SYMBOLS list
+func (bs *BotService) isExcludedSymbol(symbol string) bool {
+	for _, excluded := range bs.cfg.ExcludedSymbols {
+		if strings.EqualFold(excluded, symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWatchable rejects symbols that must not be analyzed as trading
+// opportunities because they are meant to hold a stable value
+func (bs *BotService) checkWatchable(symbol string) error {
+	if bs.isExcludedSymbol(symbol) {
+		return fmt.Errorf("%w: %s (EXCLUDED_SYMBOLS)", ErrExcludedSymbol, symbol)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"crypto-signal-bot/internal/models"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// marketDataError marks an analysis failure caused by missing market data,
+// the kind that counts toward pruning a coin
+type marketDataError struct {
+	Err error
+}
+
+func (e *marketDataError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *marketDataError) Unwrap() error {
+	return e.Err
+}
+
+// trackMarketDataFailures updates each coin's consecutive failure count after
+// a cycle: coins that returned data reset to zero, the rest count up
+func (bs *BotService) trackMarketDataFailures(succeeded, failed []*models.Cryptocurrency) {
+	for _, crypto := range succeeded {
+		if crypto.ConsecutiveFailures == 0 {
+			continue
+		}
+		crypto.ConsecutiveFailures = 0
+		delete(bs.flaggedCoins, crypto.Symbol)
+		bs.saveFailureCount(crypto)
+	}
+
+	for _, crypto := range failed {
+		crypto.ConsecutiveFailures++
+		bs.saveFailureCount(crypto)
+	}
+}
+
+func (bs *BotService) saveFailureCount(crypto *models.Cryptocurrency) {
+	if bs.db == nil {
+		return
+	}
+	if err := bs.db.UpdateCryptoFailureCount(crypto.ID, crypto.ConsecutiveFailures); err != nil {
+		logrus.Warn("Failed to save failure count for ", crypto.Symbol, ": ", err)
+	}
+}
+
+// PruneWatchlist flags active coins whose market data failed for
+// PRUNE_FAILURE_THRESHOLD cycles in a row and notifies once per coin. With
+// AUTO_PRUNE they are also deactivated, which takes them out of analysis.
+func (bs *BotService) PruneWatchlist() error {
+	threshold := bs.cfg.PruneFailureThreshold
+	if threshold <= 0 {
+		return nil
+	}
+
+	var lines []string
+	for _, crypto := range bs.watchlist() {
+		if !crypto.IsActive || crypto.ConsecutiveFailures < threshold {
+			continue
+		}
+
+		if bs.cfg.AutoPrune {
+			crypto.IsActive = false
+			if bs.db != nil {
+				if err := bs.db.UpdateCryptocurrency(crypto); err != nil {
+					crypto.IsActive = true
+					logrus.Error("Failed to deactivate ", crypto.Symbol, ": ", err)
+					continue
+				}
+			}
+			delete(bs.flaggedCoins, crypto.Symbol)
+			logrus.Warn("Deactivated ", crypto.Symbol, " after ", crypto.ConsecutiveFailures, " cycles without market data")
+			lines = append(lines, fmt.Sprintf("• *%s* - %d siklus gagal, dinonaktifkan", crypto.Symbol, crypto.ConsecutiveFailures))
+			continue
+		}
+
+		if bs.flaggedCoins[crypto.Symbol] {
+			continue
+		}
+		bs.flaggedCoins[crypto.Symbol] = true
+		logrus.Warn("Flagged ", crypto.Symbol, " after ", crypto.ConsecutiveFailures, " cycles without market data")
+		lines = append(lines, fmt.Sprintf("• *%s* - %d siklus gagal", crypto.Symbol, crypto.ConsecutiveFailures))
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	message := "Coin berikut tidak mengembalikan data pasar (kemungkinan delisting):\n\n" + strings.Join(lines, "\n")
+	if bs.cfg.AutoPrune {
+		message += "\n\nTambahkan ulang coin untuk mengaktifkannya kembali."
+	} else {
+		message += "\n\nHapus dari watchlist jika sudah tidak diperdagangkan."
+	}
+	return bs.notificationService.SendSystemNotification("warning", message)
+}
+
+// reactivateCrypto puts a pruned coin back into analysis with a clean failure count
+func (bs *BotService) reactivateCrypto(crypto *models.Cryptocurrency) error {
+	crypto.IsActive = true
+	crypto.ConsecutiveFailures = 0
+	if bs.db != nil {
+		if err := bs.db.UpdateCryptocurrency(crypto); err != nil {
+			crypto.IsActive = false
+			return err
+		}
+	}
+	bs.saveFailureCount(crypto)
+	delete(bs.flaggedCoins, crypto.Symbol)
+	return nil
+}
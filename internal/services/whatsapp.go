@@ -0,0 +1,176 @@
+package services
+
+import (
+	"bytes"
+	"crypto-signal-bot/internal/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	whatsAppTimeout       = 10 * time.Second
+	whatsAppMaxAttempts   = 3                // Tries per message; network errors, 429 and 5xx are retried
+	whatsAppRetryBase     = time.Second      // Backoff before the second attempt, doubled after that
+	whatsAppMaxRetryAfter = 10 * time.Second // Longer Retry-After hints are capped to keep retries short
+	whatsAppTextLimit     = 4096             // Maximum text message body length
+	whatsAppParamLimit    = 1024             // Maximum template body parameter length
+)
+
+// whatsAppMessage is a WhatsApp Cloud API send request
+type whatsAppMessage struct {
+	MessagingProduct string            `json:"messaging_product"`
+	RecipientType    string            `json:"recipient_type"`
+	To               string            `json:"to"`
+	Type             string            `json:"type"`
+	Text             *whatsAppText     `json:"text,omitempty"`
+	Template         *whatsAppTemplate `json:"template,omitempty"`
+}
+
+type whatsAppText struct {
+	PreviewURL bool   `json:"preview_url"`
+	Body       string `json:"body"`
+}
+
+type whatsAppTemplate struct {
+	Name     string `json:"name"`
+	Language struct {
+		Code string `json:"code"`
+	} `json:"language"`
+	Components []whatsAppComponent `json:"components"`
+}
+
+type whatsAppComponent struct {
+	Type       string              `json:"type"`
+	Parameters []whatsAppParameter `json:"parameters"`
+}
+
+type whatsAppParameter struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// truncateRunes shortens s to at most limit characters, marking the cut with an ellipsis
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// buildWhatsAppMessage converts a Telegram-formatted message into a Cloud API
+// request. WhatsApp reads *bold* and _italic_ the same way, so only Telegram's
+// escaped underscores are undone. Templates take the message as their single
+// body parameter, which may not contain line breaks.
+func (ns *NotificationService) buildWhatsAppMessage(message string) whatsAppMessage {
+	body := strings.ReplaceAll(message, `\_`, "_")
+	request := whatsAppMessage{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		To:               ns.cfg.WhatsAppRecipient,
+		Type:             ns.cfg.WhatsAppMessageType,
+	}
+
+	if ns.cfg.WhatsAppMessageType == "template" {
+		var lines []string
+		for _, line := range strings.Split(body, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		template := &whatsAppTemplate{Name: ns.cfg.WhatsAppTemplateName}
+		template.Language.Code = ns.cfg.WhatsAppTemplateLanguage
+		template.Components = []whatsAppComponent{{
+			Type:       "body",
+			Parameters: []whatsAppParameter{{Type: "text", Text: truncateRunes(strings.Join(lines, " | "), whatsAppParamLimit)}},
+		}}
+		request.Template = template
+		return request
+	}
+
+	request.Type = "text"
+	request.Text = &whatsAppText{Body: truncateRunes(body, whatsAppTextLimit)}
+	return request
+}
+
+// sendWhatsAppMessage sends a message through the WhatsApp Cloud API to
+// WHATSAPP_RECIPIENT, retrying rate limits and server errors with a short
+// backoff. The outcome is recorded in notification_logs.
+func (ns *NotificationService) sendWhatsAppMessage(notificationType string, signal *models.TradingSignal, message string) error {
+	body, err := json.Marshal(ns.buildWhatsAppMessage(message))
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: whatsAppTimeout}
+	backoff := whatsAppRetryBase
+	for attempt := 1; ; attempt++ {
+		var messageID string
+		var retryAfter time.Duration
+		messageID, retryAfter, err = ns.postWhatsApp(client, body)
+		if err == nil {
+			logrus.Info("✅ WhatsApp message sent to ", ns.cfg.WhatsAppRecipient, " (", messageID, ")")
+			break
+		}
+		if !IsTransient(err) || attempt >= whatsAppMaxAttempts {
+			break
+		}
+
+		wait := backoff
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		logrus.Warnf("WhatsApp send failed, retrying in %s (attempt %d/%d): %v", wait, attempt, whatsAppMaxAttempts, err)
+		time.Sleep(wait)
+		backoff *= 2
+	}
+
+	ns.logNotification(notificationType, "whatsapp:"+ns.cfg.WhatsAppRecipient, message, signal, err)
+	return err
+}
+
+// postWhatsApp makes one send request and returns the WhatsApp message ID, or
+// the Retry-After hint of a rate limited response alongside the error
+func (ns *NotificationService) postWhatsApp(client *http.Client, body []byte) (string, time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPost, ns.cfg.WhatsAppAPIURL, bytes.NewReader(body))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+ns.cfg.WhatsAppToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, &TransientError{Op: "whatsapp send", Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var retryAfter time.Duration
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+			if retryAfter > whatsAppMaxRetryAfter {
+				retryAfter = whatsAppMaxRetryAfter
+			}
+		}
+		return "", retryAfter, statusError("whatsapp send", resp.StatusCode, fmt.Errorf("WhatsApp API returned %s - %s", resp.Status, string(respBody)))
+	}
+
+	var result struct {
+		Messages []struct {
+			ID string `json:"id"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil || len(result.Messages) == 0 {
+		return "", 0, nil
+	}
+	return result.Messages[0].ID, 0, nil
+}
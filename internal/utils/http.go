@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// debugBodyLimit caps how much of a response body DEBUG_HTTP logs
+const debugBodyLimit = 2048
+
+// secretQueryParams are query parameter name fragments whose values are
+// redacted from logged URLs (e.g. x_cg_demo_api_key)
+var secretQueryParams = []string{"key", "token", "secret", "signature", "password"}
+
+// NewHTTPClient returns the HTTP client shared by the data providers and REST
+// calls. With debug set every request URL (secrets redacted) and the start of
+// each response body are logged at debug level.
+func NewHTTPClient(timeout time.Duration, debug bool) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if debug {
+		client.Transport = &debugTransport{next: http.DefaultTransport}
+	}
+	return client
+}
+
+// debugTransport logs outbound requests and their raw responses
+type debugTransport struct {
+	next http.RoundTripper
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	started := time.Now()
+	target := RedactURL(req.URL)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		logrus.Debugf("HTTP %s %s failed after %s: %v", req.Method, target, time.Since(started), err)
+		return nil, err
+	}
+
+	// Read the whole body so the caller still gets it, then log only the start
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		logrus.Debugf("HTTP %s %s -> %s in %s, body unreadable: %v", req.Method, target, resp.Status, time.Since(started), readErr)
+		return resp, nil
+	}
+
+	logged := string(body)
+	if len(body) > debugBodyLimit {
+		logged = string(body[:debugBodyLimit]) + "... (truncated)"
+	}
+	logrus.Debugf("HTTP %s %s -> %s in %s (%d bytes): %s", req.Method, target, resp.Status, time.Since(started), len(body), logged)
+	return resp, nil
+}
+
+// RedactURL renders a URL with credentials and secret-looking query values masked
+func RedactURL(u *url.URL) string {
+	redacted := *u
+	if redacted.User != nil {
+		redacted.User = url.User("REDACTED")
+	}
+
+	query := redacted.Query()
+	for name := range query {
+		lower := strings.ToLower(name)
+		for _, secret := range secretQueryParams {
+			if strings.Contains(lower, secret) {
+				query.Set(name, "REDACTED")
+				break
+			}
+		}
+	}
+	redacted.RawQuery = query.Encode()
+
+	return redacted.String()
+}
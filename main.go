@@ -6,12 +6,14 @@ import (
 	"crypto-signal-bot/internal/database"
 	"crypto-signal-bot/internal/scheduler"
 	"crypto-signal-bot/internal/services"
+	"errors"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
 
@@ -27,43 +29,49 @@ func main() {
 	// Setup logging
 	setupLogging(cfg.LogLevel)
 
+	if err := cfg.Validate(); err != nil {
+		logrus.Fatal("Invalid configuration: ", err)
+	}
+
+	// Decimals encode as quoted strings in plain notation (e.g. "0.00000123"),
+	// never as JSON numbers that clients would parse into lossy floats
+	decimal.MarshalJSONWithoutQuotes = false
+
 	// Skip PID file for easier development and testing
 
 	logrus.Info("🚀 Starting Personal Crypto Signal Bot (Production Mode)...")
 
-	// Initialize database with retry mechanism and graceful degradation
-	var db *database.SupabaseClient
-	var err error
-
-	maxRetries := 3
-	for i := 0; i < maxRetries; i++ {
-		db, err = database.NewSupabaseClient(cfg)
-		if err != nil {
-			logrus.Warnf("Database connection attempt %d/%d failed: %v", i+1, maxRetries, err)
-			if i == maxRetries-1 {
-				logrus.Warn("⚠️ Running in degraded mode without database")
-				db = nil
-				break
-			}
-			time.Sleep(time.Duration(i+1) * 2 * time.Second)
-			continue
-		}
-		logrus.Info("✅ Database connected successfully")
-		break
+	// Initialize storage; Supabase with retry mechanism and graceful degradation
+	var store database.Store
+	var supabase *database.SupabaseClient
+
+	if cfg.StorageBackend == "memory" {
+		logrus.Warn("⚠️ Using in-memory storage - data will not persist across restarts")
+		store = database.NewMemoryStore()
+	} else if supabase = connectDatabase(cfg); supabase != nil {
+		store = supabase
 	}
 
-	if db != nil {
-		defer db.Close()
+	// Keep store a nil interface in degraded mode so services' nil checks hold
+	if store != nil {
+		defer store.Close()
 	}
 
 	// Initialize services
-	botService := services.NewBotService(db, cfg)
+	botService := services.NewBotService(store, cfg)
+
+	// Upgrade from the REST API (or an unreachable start) once the database answers
+	if supabase != nil {
+		supabase.StartReconnect(time.Duration(cfg.DBReconnectIntervalSeconds)*time.Second, botService.OnDatabaseConnected)
+	}
 
 	// Initialize scheduler
 	schedulerService := scheduler.NewScheduler(cfg, botService)
+	botService.SetSchedulerControls(schedulerService.Stop, schedulerService.Resume)
+	botService.SetAnalysisScheduler(schedulerService)
 
 	// Initialize API server
-	apiServer := api.NewServer(cfg, db, botService, schedulerService)
+	apiServer := api.NewServer(cfg, store, botService, schedulerService)
 
 	// Start API server
 	logrus.Info("🌐 Starting API server on port ", cfg.APIPort)
@@ -101,9 +109,13 @@ func main() {
 
 	// Run initial market analysis in background
 	go func() {
-		time.Sleep(10 * time.Second) // Wait for services to start
-		logrus.Info("📊 Running initial market analysis...")
-		if err := botService.RunAnalysis(); err != nil {
+		// Wait for services to report ready instead of a fixed delay
+		warmupStart := time.Now()
+		if !botService.WaitUntilWarmedUp(time.Duration(cfg.WarmupMaxSeconds) * time.Second) {
+			logrus.Warnf("⚠️ Services not ready after %ds warmup, running initial analysis anyway", cfg.WarmupMaxSeconds)
+		}
+		logrus.Infof("📊 Running initial market analysis (warmup took %s)...", time.Since(warmupStart).Round(time.Millisecond))
+		if err := botService.RunAnalysis(false); err != nil {
 			logrus.Error("Initial market analysis failed: ", err)
 		}
 	}()
@@ -120,6 +132,9 @@ func main() {
 	logrus.Info("🛑 Shutting down...")
 
 	// Graceful shutdown
+	// Stop taking Telegram commands before anything they use is torn down
+	botService.StopAcceptingCommands()
+
 	// Stop scheduler
 	schedulerService.Stop()
 
@@ -138,6 +153,42 @@ func main() {
 
 // PID file functionality removed for easier development and testing
 
+// connectDatabase retries a temporarily unreachable Supabase with exponential
+// backoff. A misconfiguration returns nil right away (degraded mode without
+// database); when retries run out the bot starts with a deferred client that
+// keeps reconnecting in the background.
+func connectDatabase(cfg *config.Config) *database.SupabaseClient {
+	delay := time.Duration(cfg.DBRetryBaseDelaySeconds) * time.Second
+	deadline := time.Now().Add(time.Duration(cfg.DBRetryMaxWaitSeconds) * time.Second)
+
+	for attempt := 1; ; attempt++ {
+		db, err := database.NewSupabaseClient(cfg)
+		if err == nil {
+			logrus.Info("✅ Database connected successfully")
+			return db
+		}
+
+		if errors.Is(err, database.ErrMisconfigured) {
+			logrus.Error("❌ Database is misconfigured, not retrying: ", err)
+			logrus.Warn("⚠️ Running in degraded mode without database")
+			return nil
+		}
+
+		logrus.Warnf("Database connection attempt %d/%d failed: %v", attempt, cfg.DBConnectRetries, err)
+		remaining := time.Until(deadline)
+		if attempt >= cfg.DBConnectRetries || remaining <= 0 {
+			logrus.Warn("⚠️ Database still unreachable, starting without it and reconnecting in the background")
+			return database.NewDeferredSupabaseClient(cfg)
+		}
+
+		if delay > remaining {
+			delay = remaining
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
 func setupLogging(level string) {
 	logrus.SetFormatter(&logrus.TextFormatter{
 		FullTimestamp: true,